@@ -32,54 +32,37 @@ var (
 func NewCleanCommand() *cobra.Command {
 	validateOperationTypeMapping()
 
-	var (
-		dryRun           bool
-		verbose          bool
-		jsonOutput       bool
-		skipConfirmation bool
-		mode             string
-		profile          string
-		configPath       string
-		retries          int
-		retryProfile     string
-		concurrency      int
-	)
+	var opts cleanOptions
 
 	cmd := &cobra.Command{
 		Use:   "clean",
 		Short: "Clean system caches and package managers",
 		Long:  `Interactively select and clean system caches, package managers, and temporary data.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCleanCommand(
-				cmd,
-				args,
-				dryRun,
-				verbose,
-				jsonOutput,
-				skipConfirmation,
-				mode,
-				profile,
-				configPath,
-				retries,
-				retryProfile,
-				concurrency,
-			)
+			return runCleanCommand(cmd, args, opts)
 		},
 	}
 
 	cmd.Flags().
-		BoolVar(&dryRun, "dry-run", false, "Simulate deletion without actually removing anything")
-	cmd.Flags().BoolVar(&verbose, "verbose", false, "Enable verbose output for cleaner operations")
+		BoolVar(&opts.DryRun, "dry-run", false, "Simulate deletion without actually removing anything")
+	cmd.Flags().BoolVar(&opts.Verbose, "verbose", false, "Enable verbose output for cleaner operations")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", outputUsage())
+	cmd.Flags().
+		BoolVar(&opts.JSONOutput, "json", false, "Output results in JSON format (shorthand for --output json)")
+	cmd.Flags().StringVar(&opts.Mode, "mode", "", "Preset mode: quick, standard, or aggressive")
+	cmd.Flags().StringVarP(&opts.Profile, "profile", "p", "", "Use a specific configuration profile")
+	cmd.Flags().StringVarP(&opts.ConfigPath, "config", "c", "", "Path to configuration file")
+	cmd.Flags().BoolVarP(&opts.SkipConfirmation, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().IntVar(&opts.Retries, "retries", 3, "Number of retry attempts per cleaner (0=disabled)")
+	cmd.Flags().
+		StringVar(&opts.RetryProfile, "retry-profile", "", "Retry strategy preset: default, aggressive, conservative, or none (overrides --retries)")
+	cmd.Flags().IntVarP(&opts.Concurrency, "concurrency", "C", 0, "Max cleaners running concurrently (0=unlimited)")
 	cmd.Flags().
-		BoolVar(&jsonOutput, "json", false, "Output results in JSON format (non-interactive)")
-	cmd.Flags().StringVar(&mode, "mode", "", "Preset mode: quick, standard, or aggressive")
-	cmd.Flags().StringVarP(&profile, "profile", "p", "", "Use a specific configuration profile")
-	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file")
-	cmd.Flags().BoolVarP(&skipConfirmation, "yes", "y", false, "Skip confirmation prompt")
-	cmd.Flags().IntVar(&retries, "retries", 3, "Number of retry attempts per cleaner (0=disabled)")
+		StringToIntVar(&opts.ClassLimits, "class-limit", nil, classLimitUsage)
 	cmd.Flags().
-		StringVar(&retryProfile, "retry-profile", "", "Retry strategy preset: default, aggressive, conservative, or none (overrides --retries)")
-	cmd.Flags().IntVarP(&concurrency, "concurrency", "C", 0, "Max cleaners running concurrently (0=unlimited)")
+		BoolVar(&opts.Accurate, "accurate", false, "Run cleaners sharing a filesystem one at a time for exact measured freed space")
+	cmd.Flags().
+		BoolVar(&opts.Resume, "resume", false, "Continue the last interrupted run with its original options")
 
 	return cmd
 }
@@ -121,28 +104,50 @@ func printCleanStart(dryRun bool) {
 }
 
 // runCleanCommand executes the clean command with multi-cleaner TUI.
-func runCleanCommand(
-	_ *cobra.Command,
-	_ []string,
-	dryRun, verbose, jsonOutput, skipConfirmation bool,
-	mode, profile, configPath string,
-	retries int, retryProfile string,
-	concurrency int,
-) error {
+// Non-dry-run runs are checkpointed after every finished cleaner, so an
+// interrupted run prints its partial results and can be continued with
+// --resume.
+func runCleanCommand(cmd *cobra.Command, _ []string, opts cleanOptions) error {
 	ctx := context.Background()
+	if cmd != nil && cmd.Context() != nil {
+		ctx = cmd.Context()
+	}
+
+	var resumed *execution.Checkpoint
 
-	cfg, err := loadConfigFromPath(configPath)
+	if opts.Resume {
+		saved, cp, err := loadResumeCheckpoint(opts)
+		if err != nil {
+			return err
+		}
+
+		opts, resumed = saved, &cp
+	}
+
+	opts.Output = resolveOutput(opts.Output, opts.JSONOutput)
+	machine := opts.Output != ""
+
+	var report *reportWriter
+
+	if machine {
+		var err error
+		if report, err = newReportWriter(opts.Output); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfigFromPath(opts.ConfigPath)
 	if err != nil {
 		return errorfamily.WrapRejectionf(
 			err, "clean.config_load",
-			"failed to load configuration for mode=%v, profile=%v", mode, profile,
+			"failed to load configuration for mode=%v, profile=%v", opts.Mode, opts.Profile,
 		)
 	}
 
 	container, cleanup := di.New()
 	defer cleanup()
 
-	settings := di.RunSettings{Verbose: verbose, DryRun: dryRun, MaxConcurrency: concurrency}
+	settings := di.RunSettings{Verbose: opts.Verbose, DryRun: opts.DryRun, MaxConcurrency: opts.Concurrency}
 	if err := di.RegisterAllServices(container.Injector(), cfg, settings); err != nil {
 		return errorfamily.WrapRejection(err, "clean.di_register", "failed to register DI services")
 	}
@@ -152,29 +157,51 @@ func runCleanCommand(
 		return errorfamily.WrapRejection(err, "clean.di_resolve", "failed to resolve cleaner registry from DI")
 	}
 
-	printDryRunHeader(dryRun)
+	if opts.Profile != "" {
+		if err := applyProfileSettings(opts.Profile, cfg, registry); err != nil {
+			return errorfamily.WrapRejectionf(err, "clean.profile_settings", "profile=%v", opts.Profile)
+		}
+	}
 
-	availableConfigs := getAvailableConfigs(ctx, registry)
-	if len(availableConfigs) == 0 {
-		return ErrNoCleanersAvailable
+	if !machine {
+		printDryRunHeader(opts.DryRun)
 	}
 
-	fmt.Printf("✅ Found %d available cleaner(s)\n\n", len(availableConfigs))
+	var selectedNames []string
 
-	selectedCleaners, err := selectCleaners(profile, mode, cfg, availableConfigs, jsonOutput)
-	if err != nil {
-		return errorfamily.WrapRejectionf(err, "clean.select_cleaners", "mode=%v, profile=%v", mode, profile)
-	}
+	if resumed != nil {
+		if !machine {
+			printResumeHeader(*resumed)
+		}
 
-	if selectedCleaners == nil {
-		fmt.Println("❌ No cleaners selected. Nothing to clean.")
+		selectedNames = resumed.Pending
+	} else {
+		availableConfigs := getAvailableConfigs(ctx, registry)
+		if len(availableConfigs) == 0 {
+			return ErrNoCleanersAvailable
+		}
 
-		return nil
+		if !machine {
+			fmt.Printf("✅ Found %d available cleaner(s)\n\n", len(availableConfigs))
+		}
+
+		selectedCleaners, err := selectCleaners(opts.Profile, opts.Mode, cfg, availableConfigs, machine)
+		if err != nil {
+			return errorfamily.WrapRejectionf(err, "clean.select_cleaners", "mode=%v, profile=%v", opts.Mode, opts.Profile)
+		}
+
+		if selectedCleaners == nil {
+			fmt.Println("❌ No cleaners selected. Nothing to clean.")
+
+			return nil
+		}
+
+		selectedNames = cleanerTypesToNames(selectedCleaners)
 	}
 
-	confirmed, err := confirmExecution(skipConfirmation, dryRun)
+	confirmed, err := confirmExecution(opts.SkipConfirmation, opts.DryRun)
 	if err != nil {
-		return errorfamily.WrapRejectionf(err, "clean.confirm", "mode=%v, profile=%v", mode, profile)
+		return errorfamily.WrapRejectionf(err, "clean.confirm", "mode=%v, profile=%v", opts.Mode, opts.Profile)
 	}
 
 	if !confirmed {
@@ -183,7 +210,9 @@ func runCleanCommand(
 		return nil
 	}
 
-	printCleanStart(dryRun)
+	if !machine {
+		printCleanStart(opts.DryRun)
+	}
 
 	diskBefore, diskErr := cleaner.GetDiskUsage("/")
 
@@ -192,13 +221,37 @@ func runCleanCommand(
 		diskBeforePtr = &diskBefore
 	}
 
-	printDiskUsage(diskBeforePtr, jsonOutput)
+	printDiskUsage(diskBeforePtr, machine)
 
-	selectedNames := cleanerTypesToNames(selectedCleaners)
-
-	runOpts, err := buildRunOptions(verbose, concurrency, retries, retryProfile)
+	runOpts, err := buildRunOptions(opts.Verbose, opts.Concurrency, opts.Retries, opts.RetryProfile, opts.ClassLimits)
 	if err != nil {
-		return errorfamily.WrapRejectionf(err, "clean.invalid_options", "mode=%v, profile=%v", mode, profile)
+		return errorfamily.WrapRejectionf(err, "clean.invalid_options", "mode=%v, profile=%v", opts.Mode, opts.Profile)
+	}
+
+	if opts.Profile != "" {
+		runOpts = append(runOpts,
+			execution.WithDependencies(getProfileDependencies(opts.Profile, cfg)),
+			execution.WithResourceOverrides(getProfileResourceOverrides(opts.Profile, cfg)),
+		)
+	}
+
+	var checkpoint *execution.Checkpointer
+
+	if !opts.DryRun {
+		checkpoint, err = newCleanCheckpointer(opts, resumed, selectedNames)
+		if err != nil {
+			return err
+		}
+
+		runOpts = append(runOpts,
+			execution.WithCheckpoint(checkpoint),
+			execution.WithSpaceMeasurement(opts.Accurate),
+		)
+	}
+
+	if machine {
+		report.start(format.CommandClean, opts.DryRun)
+		runOpts = append(runOpts, report.observer())
 	}
 
 	wr, err := execution.RunCleaners(ctx, registry, selectedNames, runOpts...)
@@ -206,39 +259,31 @@ func runCleanCommand(
 		return fmt.Errorf("clean workflow execution failed: %w", err)
 	}
 
-	if jsonOutput {
-		return outputJSON(wr, dryRun)
-	}
+	checkpointSaved := checkpoint != nil && checkpoint.Err() == nil
 
-	displayResults(wr, dryRun, diskBeforePtr)
+	if checkpoint != nil && checkpoint.Err() != nil && !machine {
+		fmt.Printf("⚠️  Could not save checkpoint: %v\n", checkpoint.Err())
+	}
 
-	return nil
-}
+	snapshots := cleanSnapshotWarnings(ctx, registry, wr)
 
-func outputJSON(wr *execution.WorkflowResult, dryRun bool) error {
-	skipped := make(map[string]error)
-	for _, s := range wr.Skipped() {
-		skipped[s.Name] = s.Err
+	if machine {
+		if err := report.finish(buildReport(format.CommandClean, opts.DryRun, wr, snapshots)); err != nil {
+			return err
+		}
+	} else {
+		displayResults(wr, opts.DryRun, diskBeforePtr)
+		printSnapshotWarnings(snapshots)
 	}
 
-	failed := make(map[string]error)
-	for _, s := range wr.Failed() {
-		failed[s.Name] = s.Err
-	}
+	if wr.Interrupted {
+		if !machine {
+			displayInterrupted(wr, checkpointSaved)
+		}
 
-	jsonBytes, err := format.CleanResultsToJSON(
-		wr.CleanResultsMap(),
-		wr.Duration,
-		dryRun,
-		skipped,
-		failed,
-	)
-	if err != nil {
-		return errorfamily.WrapCorruption(err, "clean.json_output", "failed to generate JSON output")
+		return ErrCleanInterrupted
 	}
 
-	fmt.Println(string(jsonBytes))
-
 	return nil
 }
 
@@ -282,6 +327,8 @@ var operationTypeToCleanerType = map[domain.OperationType]CleanerType{ //nolint:
 	domain.OperationTypeProjectExecutables:           CleanerTypeProjectExecutables,
 	domain.OperationTypeCompiledBinaries:             CleanerTypeCompiledBinaries,
 	domain.OperationTypeGolangciLintCache:            CleanerTypeGolangciLintCache,
+	domain.OperationTypeNixGCRoots:                   CleanerTypeNixGCRoots,
+	domain.OperationTypeNixOptimise:                  CleanerTypeNixOptimise,
 }
 
 // validateOperationTypeMapping panics at package init if operationTypeToCleanerType
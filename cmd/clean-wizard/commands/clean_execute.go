@@ -9,6 +9,7 @@ import (
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
 	"github.com/LarsArtmann/clean-wizard/internal/execution"
 	"github.com/LarsArtmann/clean-wizard/internal/format"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
 )
 
 // displayResults renders the final cleanup results to the terminal.
@@ -100,7 +101,10 @@ func displayWarnings(wr *execution.WorkflowResult) {
 
 		for _, s := range skipped {
 			fmt.Printf("     ℹ️  Skipped %s: %s\n", s.Name, s.Err.Error())
+			printHint(s.Name, s.Err)
 		}
+
+		fmt.Println("     💡 Run 'clean-wizard doctor' to see what they are missing")
 	}
 
 	if len(failed) > 0 {
@@ -108,26 +112,51 @@ func displayWarnings(wr *execution.WorkflowResult) {
 
 		for _, f := range failed {
 			fmt.Printf("     ❌ %s failed: %s\n", f.Name, f.Err.Error())
+			printHint(f.Name, f.Err)
 		}
 	}
 }
 
-// printCleanResultsTable prints clean results as a formatted table.
+// printHint prints the remediation hint for a cleaner's error, if any.
+func printHint(cleanerName string, err error) {
+	if hint := hints.ForError(cleanerName, err); hint != "" {
+		fmt.Println(InfoStyle.Render("        → " + hint))
+	}
+}
+
+// printCleanResultsTable prints clean results as a formatted table. When
+// freed space was measured, the claimed and measured bytes are shown side by side.
 func printCleanResultsTable(
 	results map[string]domain.CleanResult,
 	totalBytes uint64,
 	totalItems uint,
 	duration time.Duration,
 ) {
-	var rows [][]string
+	var (
+		rows          [][]string
+		measuredTotal int64
+		anyMeasured   bool
+	)
+
+	for _, result := range results {
+		if result.MeasuredFreedBytes != nil {
+			measuredTotal += *result.MeasuredFreedBytes
+			anyMeasured = true
+		}
+	}
 
 	for name, result := range results {
-		if result.FreedBytes > 0 || result.ItemsRemoved > 0 {
-			rows = append(rows, []string{
+		if result.FreedBytes > 0 || result.ItemsRemoved > 0 || result.MeasuredFreedBytes != nil {
+			row := []string{
 				name,
 				strconv.FormatUint(uint64(result.ItemsRemoved), 10),
 				format.Bytes(int64(result.FreedBytes)),
-			})
+			}
+			if anyMeasured {
+				row = append(row, formatMeasured(result.MeasuredFreedBytes))
+			}
+
+			rows = append(rows, row)
 		}
 	}
 
@@ -138,9 +167,25 @@ func printCleanResultsTable(
 	}
 
 	t := newResultsTable(rows...)
+	if anyMeasured {
+		t.Headers("Cleaner", "Items", "Claimed", "Measured")
+	}
 
 	fmt.Println(t)
 	fmt.Println()
+
+	if anyMeasured {
+		fmt.Printf(
+			"📊 Total: %s freed (%s measured), %s items in %s\n",
+			format.Bytes(int64(totalBytes)),
+			format.Bytes(measuredTotal),
+			strconv.FormatUint(uint64(totalItems), 10),
+			format.Duration(duration),
+		)
+
+		return
+	}
+
 	fmt.Printf(
 		"📊 Total: %s freed, %s items in %s\n",
 		format.Bytes(int64(totalBytes)),
@@ -148,3 +193,16 @@ func printCleanResultsTable(
 		format.Duration(duration),
 	)
 }
+
+// formatMeasured renders a measured freed size; "—" if it was not measured.
+func formatMeasured(measured *int64) string {
+	if measured == nil {
+		return "—"
+	}
+
+	if *measured < 0 {
+		return "-" + format.Bytes(-*measured)
+	}
+
+	return format.Bytes(*measured)
+}
@@ -15,20 +15,11 @@ func TestRunCleanCommand_DryRun_JSON(t *testing.T) {
 		t.Skip("integration test: uses real system cleaners (slow)")
 	}
 
-	err := runCleanCommand(
-		nil,
-		nil,
-		true,  // dryRun
-		false, // verbose
-		true,  // jsonOutput
-		true,  // skipConfirmation
-		"",    // mode
-		"",    // profile
-		"",    // configPath
-		0,     // retries
-		"",    // retryProfile
-		0,     // concurrency
-	)
+	err := runCleanCommand(nil, nil, cleanOptions{
+		DryRun:           true,
+		JSONOutput:       true,
+		SkipConfirmation: true,
+	})
 
 	// Should not error — dry-run is safe and non-destructive
 	require.NoError(t, err)
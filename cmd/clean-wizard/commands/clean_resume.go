@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/execution"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// Classified errors for resumable clean runs.
+var (
+	ErrNoCheckpoint = errorfamily.NewRejection(
+		"clean.no_checkpoint",
+		"no interrupted clean run to resume",
+	)
+	ErrCleanInterrupted = errorfamily.NewTransient(
+		"clean.interrupted",
+		"clean run was interrupted; run 'clean-wizard clean --resume' to continue",
+	)
+)
+
+// cleanOptions holds the flags of the clean command. It is stored in the
+// checkpoint so that `clean --resume` continues with the same options.
+type cleanOptions struct {
+	DryRun           bool           `json:"dry_run"`
+	Verbose          bool           `json:"verbose"`
+	JSONOutput       bool           `json:"json_output"`
+	Output           string         `json:"output,omitempty"`
+	SkipConfirmation bool           `json:"skip_confirmation"`
+	Mode             string         `json:"mode,omitempty"`
+	Profile          string         `json:"profile,omitempty"`
+	ConfigPath       string         `json:"config_path,omitempty"`
+	Retries          int            `json:"retries"`
+	RetryProfile     string         `json:"retry_profile,omitempty"`
+	Concurrency      int            `json:"concurrency"`
+	ClassLimits      map[string]int `json:"class_limits,omitempty"`
+	Accurate         bool           `json:"accurate,omitempty"`
+	Resume           bool           `json:"-"`
+}
+
+// loadResumeCheckpoint loads the checkpoint of the last interrupted run and
+// returns the options it was started with. A --yes given when resuming is
+// honoured even if the original run asked for confirmation.
+func loadResumeCheckpoint(current cleanOptions) (cleanOptions, execution.Checkpoint, error) {
+	path, err := state.Path(execution.CheckpointFileName)
+	if err != nil {
+		return current, execution.Checkpoint{}, err
+	}
+
+	cp, err := execution.LoadCheckpoint(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return current, cp, ErrNoCheckpoint
+	}
+
+	if err != nil {
+		return current, cp, err
+	}
+
+	var saved cleanOptions
+	if len(cp.Options) > 0 {
+		if err := json.Unmarshal(cp.Options, &saved); err != nil {
+			return current, cp, errorfamily.WrapCorruption(
+				err, "clean.checkpoint_options", "checkpoint has unreadable options",
+			)
+		}
+	}
+
+	saved.Resume = true
+	saved.SkipConfirmation = saved.SkipConfirmation || current.SkipConfirmation
+
+	return saved, cp, nil
+}
+
+// newCleanCheckpointer creates the checkpointer for a non-dry-run clean. A
+// resumed run keeps writing to its loaded checkpoint; a fresh run replaces
+// any stale checkpoint left behind by an earlier interruption.
+func newCleanCheckpointer(
+	opts cleanOptions,
+	resumed *execution.Checkpoint,
+	selected []string,
+) (*execution.Checkpointer, error) {
+	path, err := state.Path(execution.CheckpointFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if resumed != nil {
+		return execution.NewCheckpointer(path, *resumed), nil
+	}
+
+	snapshot, err := json.Marshal(opts)
+	if err != nil {
+		return nil, errorfamily.WrapCorruption(err, "clean.checkpoint_options", "failed to encode clean options")
+	}
+
+	return execution.NewCheckpointer(path, execution.NewCheckpoint(selected, snapshot)), nil
+}
+
+// printResumeHeader summarizes what a resumed run still has to do.
+func printResumeHeader(cp execution.Checkpoint) {
+	fmt.Printf(
+		"⏯️  Resuming interrupted run from %s: %d of %d cleaner(s) left (%s)\n\n",
+		cp.StartedAt.Format("2006-01-02 15:04"),
+		len(cp.Pending),
+		len(cp.Selected),
+		strings.Join(cp.Pending, ", "),
+	)
+}
+
+// displayInterrupted lists the cleaners an interrupted run did not finish.
+func displayInterrupted(wr *execution.WorkflowResult, checkpointSaved bool) {
+	fmt.Println()
+	fmt.Println(WarningStyle.Render("⏸️  Interrupted: the results above are partial"))
+	fmt.Printf("   • %d cleaner(s) did not finish: %s\n", len(wr.Pending), strings.Join(wr.Pending, ", "))
+
+	if checkpointSaved {
+		fmt.Println(InfoStyle.Render("💡 Run 'clean-wizard clean --resume' to continue where this run stopped."))
+	}
+}
@@ -3,8 +3,10 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"charm.land/huh/v2"
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
 )
 
@@ -208,15 +210,10 @@ func getProfileCleaners(
 			continue
 		}
 
-		opType := domain.GetOperationType(op.Name)
-
-		cleanerType, ok := operationTypeToCleanerType[opType]
-		if !ok {
-			continue
-		}
-
-		if availableSet[cleanerType] {
-			cleaners = append(cleaners, cleanerType)
+		for _, cleanerType := range operationCleanerTypes(op) {
+			if availableSet[cleanerType] && !slices.Contains(cleaners, cleanerType) {
+				cleaners = append(cleaners, cleanerType)
+			}
 		}
 	}
 
@@ -227,3 +224,115 @@ func getProfileCleaners(
 
 	return cleaners, nil
 }
+
+// getProfileDependencies translates the after/requires/exclusive_groups fields
+// of a profile's operations into cleaner dependencies keyed by registry name.
+// Operation names in after/requires are resolved like the operations themselves.
+func getProfileDependencies(profileName string, cfg *domain.Config) map[string]cleaner.Dependencies {
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return nil
+	}
+
+	deps := make(map[string]cleaner.Dependencies)
+
+	for _, op := range profile.Operations {
+		d := cleaner.Dependencies{
+			After:           operationNamesToRegistryNames(op.After),
+			Requires:        operationNamesToRegistryNames(op.Requires),
+			ExclusiveGroups: op.ExclusiveGroups,
+		}
+		if d.IsEmpty() {
+			continue
+		}
+
+		name := getRegistryName(operationNameToCleanerType(op.Name))
+		deps[name] = deps[name].Merge(d)
+	}
+
+	return deps
+}
+
+// getProfileResourceOverrides translates the resource_class and timeout
+// fields of a profile's operations into overrides keyed by registry name.
+func getProfileResourceOverrides(profileName string, cfg *domain.Config) map[string]cleaner.ResourceOverride {
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return nil
+	}
+
+	overrides := make(map[string]cleaner.ResourceOverride)
+
+	for _, op := range profile.Operations {
+		o := cleaner.ParseResourceOverride(op.ResourceClass, op.Timeout)
+		if o.IsEmpty() {
+			continue
+		}
+
+		overrides[getRegistryName(operationNameToCleanerType(op.Name))] = o
+	}
+
+	return overrides
+}
+
+// applyProfileSettings configures the registered cleaners from the settings
+// of the profile's operations.
+func applyProfileSettings(profileName string, cfg *domain.Config, registry *cleaner.Registry) error {
+	profile, exists := cfg.Profiles[profileName]
+	if !exists {
+		return nil
+	}
+
+	for _, op := range profile.Operations {
+		for _, cleanerType := range operationCleanerTypes(op) {
+			c, ok := registry.Get(getRegistryName(cleanerType))
+			if !ok {
+				continue
+			}
+
+			applier, ok := c.(cleaner.SettingsApplier)
+			if !ok {
+				continue
+			}
+
+			if err := applier.ApplySettings(op.Settings); err != nil {
+				return fmt.Errorf("invalid settings for operation %q: %w", op.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// operationCleanerTypes returns the cleaners a profile operation runs: its
+// own, plus the store optimisation for a nix-generations operation with
+// optimize enabled.
+func operationCleanerTypes(op domain.CleanupOperation) []CleanerType {
+	cleanerType := operationNameToCleanerType(op.Name)
+
+	if cleanerType == CleanerTypeNix && op.Settings != nil && op.Settings.NixGenerations != nil &&
+		op.Settings.NixGenerations.Optimize.IsEnabled() {
+		return []CleanerType{cleanerType, CleanerTypeNixOptimise}
+	}
+
+	return []CleanerType{cleanerType}
+}
+
+// operationNameToCleanerType resolves a profile operation name to its cleaner
+// type. Unknown names are custom or plugin cleaners referenced by registry name.
+func operationNameToCleanerType(opName string) CleanerType {
+	if cleanerType, ok := operationTypeToCleanerType[domain.GetOperationType(opName)]; ok {
+		return cleanerType
+	}
+
+	return CleanerType(opName)
+}
+
+func operationNamesToRegistryNames(opNames []string) []string {
+	names := make([]string, 0, len(opNames))
+	for _, opName := range opNames {
+		names = append(names, getRegistryName(operationNameToCleanerType(opName)))
+	}
+
+	return names
+}
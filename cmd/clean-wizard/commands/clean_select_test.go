@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/config"
+)
+
+func TestOperationCleanerTypes_OptimiseFromConfig(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `version: "1.0.0"
+safe_mode: true
+max_disk_usage_percent: 50
+protected:
+  - "/System"
+profiles:
+  nix:
+    name: "nix"
+    description: "Nix cleanup"
+    enabled: true
+    operations:
+      - name: "nix-generations"
+        description: "Clean Nix generations"
+        risk_level: "low"
+        enabled: true
+        settings:
+          nix_generations:
+            generations: 3
+            optimize: true
+            optimize_every: "30d"
+`
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := config.LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+
+	types := operationCleanerTypes(cfg.Profiles["nix"].Operations[0])
+	if !slices.Contains(types, CleanerTypeNixOptimise) {
+		t.Errorf("operationCleanerTypes() = %v, want the store optimisation step", types)
+	}
+
+	registry := cleaner.NewRegistry()
+	registry.Register(cleaner.CleanerNixOptimise, cleaner.NewNixOptimiseCleaner(false, true, cleaner.NixOptimiseDefaultEvery))
+
+	err = applyProfileSettings("nix", cfg, registry)
+	if err != nil {
+		t.Errorf("applyProfileSettings() error = %v", err)
+	}
+}
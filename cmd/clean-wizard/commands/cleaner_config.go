@@ -27,9 +27,25 @@ func GetCleanerConfigs(ctx context.Context, registry *cleaner.Registry) []Cleane
 			continue
 		}
 
+		available := toCleanerAvailability(c.IsAvailable(ctx))
+
 		cleanerType, ok := registryNameToCleanerType[name]
 		if !ok {
-			continue // Skip unknown cleaners
+			// Runtime-defined cleaners (custom YAML cleaners) describe themselves.
+			d, isDescriber := c.(cleaner.Describer)
+			if !isDescriber {
+				continue // Skip unknown cleaners
+			}
+
+			configs = append(configs, CleanerConfig{
+				Type:        CleanerType(name),
+				Name:        d.DisplayName(),
+				Description: d.Description(),
+				Icon:        d.Icon(),
+				Available:   available,
+			})
+
+			continue
 		}
 
 		configs = append(configs, CleanerConfig{
@@ -37,7 +53,7 @@ func GetCleanerConfigs(ctx context.Context, registry *cleaner.Registry) []Cleane
 			Name:        getCleanerName(cleanerType),
 			Description: getCleanerDescription(cleanerType),
 			Icon:        getCleanerIcon(cleanerType),
-			Available:   toCleanerAvailability(c.IsAvailable(ctx)),
+			Available:   available,
 		})
 	}
 
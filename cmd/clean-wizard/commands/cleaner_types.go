@@ -17,6 +17,8 @@ const (
 	CleanerTypeCompiledBinaries             CleanerType = "compiled-binaries"
 	CleanerTypeProjectExecutables           CleanerType = "project-executables"
 	CleanerTypeGolangciLintCache            CleanerType = "golangci-lint-cache"
+	CleanerTypeNixGCRoots                   CleanerType = "nix-gcroots"
+	CleanerTypeNixOptimise                  CleanerType = "nix-optimise"
 )
 
 // CleanerAvailability represents the availability status of a cleaner.
@@ -113,6 +115,18 @@ var cleanerMetadata = map[CleanerType]cleanerMetadataEntry{ //nolint:gochecknogl
 		Description:  "Clean golangci-lint cache (uses cache status for accurate sizing)",
 		Icon:         "🐹",
 	},
+	CleanerTypeNixGCRoots: {
+		RegistryName: "nix-gcroots",
+		DisplayName:  "Nix GC Roots",
+		Description:  "Remove result links and direnv/devenv roots of stale projects before Nix GC",
+		Icon:         "🔗",
+	},
+	CleanerTypeNixOptimise: {
+		RegistryName: "nix-optimise",
+		DisplayName:  "Nix Store Optimise",
+		Description:  "Hardlink identical store files with nix store optimise (scheduled, after Nix GC)",
+		Icon:         "🧬",
+	},
 }
 
 var registryNameToCleanerType = func() map[string]CleanerType { //nolint:gochecknoglobals
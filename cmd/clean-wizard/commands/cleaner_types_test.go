@@ -21,6 +21,8 @@ func TestCleanerMetadataCompleteness(t *testing.T) {
 		CleanerTypeCompiledBinaries,
 		CleanerTypeProjectExecutables,
 		CleanerTypeGolangciLintCache,
+		CleanerTypeNixGCRoots,
+		CleanerTypeNixOptimise,
 	}
 
 	for _, ct := range allCleanerTypes {
@@ -0,0 +1,314 @@
+package commands
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/config"
+	"github.com/LarsArtmann/clean-wizard/internal/di"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
+	"github.com/LarsArtmann/clean-wizard/internal/version"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+// doctorSummary counts checks by status.
+type doctorSummary struct {
+	OK      int `json:"ok"`
+	Warn    int `json:"warn"`
+	Fail    int `json:"fail"`
+	Skipped int `json:"skipped"`
+}
+
+// doctorReport is the result of clean-wizard doctor.
+type doctorReport struct {
+	Version  string              `json:"version"`
+	OS       string              `json:"os"`
+	Arch     string              `json:"arch"`
+	Config   []cleaner.Check     `json:"config"`
+	Cleaners []cleaner.Diagnosis `json:"cleaners"`
+	Summary  doctorSummary       `json:"summary"`
+}
+
+// NewDoctorCommand creates a command that diagnoses cleaner prerequisites.
+func NewDoctorCommand() *cobra.Command {
+	var (
+		jsonOut    bool
+		configPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose why cleaners are unavailable",
+		Long: `Runs every cleaner's prerequisite checks and reports the binaries it needs
+with their paths and versions, whether the daemons it talks to are reachable,
+whether it may write the directories it deletes below, and the configuration
+validation results, with a remediation hint for every problem.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctorCommand(cmd.Context(), configPath, jsonOut)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format, e.g. for support tickets")
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file")
+
+	return cmd
+}
+
+// runDoctorCommand executes the doctor command.
+func runDoctorCommand(ctx context.Context, configPath string, jsonOutput bool) error {
+	report := doctorReport{ //nolint:exhaustruct
+		Version: version.Get().Version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+	}
+
+	cfg, configChecks := diagnoseConfig(configPath)
+	report.Config = configChecks
+
+	container, cleanup := di.New()
+	defer cleanup()
+
+	settings := di.RunSettings{Verbose: false, DryRun: true, MaxConcurrency: 0}
+	if err := di.RegisterAllServices(container.Injector(), cfg, settings); err != nil {
+		return errorfamily.WrapRejection(err, "doctor.di_register", "failed to register DI services")
+	}
+
+	registry, err := di.CleanerRegistry(container.Injector())
+	if err != nil {
+		return errorfamily.WrapRejection(err, "doctor.di_resolve", "failed to resolve cleaner registry from DI")
+	}
+
+	doctor := cleaner.NewDoctor()
+	names := registry.Names()
+	slices.Sort(names)
+
+	report.Cleaners = make([]cleaner.Diagnosis, len(names))
+
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		c, _ := registry.Get(name)
+
+		wg.Go(func() { report.Cleaners[i] = doctor.Diagnose(ctx, name, c) })
+	}
+
+	wg.Wait()
+
+	report.Cleaners = append(report.Cleaners, diagnoseGitHistory(ctx, doctor))
+	report.Summary = summarizeDoctorReport(report)
+
+	if jsonOutput {
+		return printDoctorJSON(report)
+	}
+
+	printDoctorReport(report)
+
+	return nil
+}
+
+// diagnoseConfig loads and validates the configuration. A configuration
+// that fails to load is reported and replaced by the defaults so that the
+// cleaners can still be diagnosed.
+func diagnoseConfig(configPath string) (*domain.Config, []cleaner.Check) {
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		return config.GetDefaultConfig(), []cleaner.Check{{ //nolint:exhaustruct
+			Kind:   cleaner.CheckKindConfig,
+			Name:   "load",
+			Status: cleaner.CheckFail,
+			Detail: err.Error(),
+			Code:   "config.load",
+			Hint:   hints.ForCode("config.load"),
+		}}
+	}
+
+	result := config.NewConfigValidator().ValidateConfig(cfg)
+	checks := make([]cleaner.Check, 0, 1+len(result.Errors)+len(result.Warnings))
+
+	for _, e := range result.Errors {
+		checks = append(checks, cleaner.Check{ //nolint:exhaustruct
+			Kind:   cleaner.CheckKindConfig,
+			Name:   e.Field,
+			Status: cleaner.CheckFail,
+			Detail: e.Message,
+			Code:   "config.validation",
+			Hint:   e.Suggestion,
+		})
+	}
+
+	for _, w := range result.Warnings {
+		checks = append(checks, cleaner.Check{ //nolint:exhaustruct
+			Kind:   cleaner.CheckKindConfig,
+			Name:   w.Field,
+			Status: cleaner.CheckWarn,
+			Detail: w.Message,
+			Code:   "config.validation",
+			Hint:   w.Suggestion,
+		})
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, cleaner.Check{ //nolint:exhaustruct
+			Kind:   cleaner.CheckKindConfig,
+			Name:   "validation",
+			Status: cleaner.CheckOK,
+			Detail: fmt.Sprintf("valid, %d profiles", len(cfg.Profiles)),
+		})
+	}
+
+	return cfg, checks
+}
+
+// diagnoseGitHistory checks git and the git-filter-repo provider used by
+// the git-history command.
+func diagnoseGitHistory(ctx context.Context, doctor *cleaner.Doctor) cleaner.Diagnosis {
+	git := doctor.CheckBinary(ctx, cleaner.CleanerGitHistory, cleaner.Binary{
+		Name:        "git",
+		VersionArgs: []string{"--version"},
+		Optional:    false,
+		Hint:        "Install git: https://git-scm.com/downloads",
+	})
+
+	filterRepo := cleaner.Check{Kind: cleaner.CheckKindBinary, Name: "git-filter-repo", Status: cleaner.CheckOK} //nolint:exhaustruct
+
+	provider := cleaner.DetectFilterRepoProvider()
+	if provider == cleaner.FilterRepoNone {
+		filterRepo.Status = cleaner.CheckFail
+		filterRepo.Detail = "not installed and not available through nix"
+		filterRepo.Code = hints.CleanerCode(cleaner.CleanerGitHistory, hints.NotAvailable)
+		filterRepo.Hint = hints.ForCode(filterRepo.Code)
+	} else {
+		filterRepo.Detail = "provided by " + provider.String()
+	}
+
+	lfs := doctor.CheckBinary(ctx, cleaner.CleanerGitHistory, cleaner.Binary{
+		Name:        "git-lfs",
+		VersionArgs: []string{"version"},
+		Optional:    true,
+		Hint:        "Install git-lfs to migrate history blobs with --lfs: https://git-lfs.com",
+	})
+
+	return cleaner.Diagnosis{
+		Cleaner:   cleaner.CleanerGitHistory,
+		Available: git.Status == cleaner.CheckOK && provider != cleaner.FilterRepoNone,
+		Checks:    []cleaner.Check{git, filterRepo, lfs},
+	}
+}
+
+// summarizeDoctorReport counts the report's checks by status.
+func summarizeDoctorReport(report doctorReport) doctorSummary {
+	var summary doctorSummary
+
+	count := func(checks []cleaner.Check) {
+		for _, check := range checks {
+			switch check.Status {
+			case cleaner.CheckOK:
+				summary.OK++
+			case cleaner.CheckWarn:
+				summary.Warn++
+			case cleaner.CheckFail:
+				summary.Fail++
+			case cleaner.CheckSkipped:
+				summary.Skipped++
+			}
+		}
+	}
+
+	count(report.Config)
+
+	for _, diag := range report.Cleaners {
+		count(diag.Checks)
+	}
+
+	return summary
+}
+
+// checkIcon returns the icon shown for a check status.
+func checkIcon(status cleaner.CheckStatus) string {
+	switch status {
+	case cleaner.CheckOK:
+		return "✅"
+	case cleaner.CheckWarn:
+		return "⚠️ "
+	case cleaner.CheckFail:
+		return "❌"
+	case cleaner.CheckSkipped:
+		return "➖"
+	default:
+		return "•"
+	}
+}
+
+// printDoctorReport prints the report for humans.
+func printDoctorReport(report doctorReport) {
+	fmt.Println(TitleStyle.Render("🩺 Clean Wizard Doctor"))
+	fmt.Printf("   clean-wizard %s on %s/%s\n\n", report.Version, report.OS, report.Arch)
+
+	fmt.Println(HeaderStyle.Render("Configuration"))
+	printChecks(report.Config)
+	fmt.Println()
+
+	for _, diag := range report.Cleaners {
+		status := SuccessStyle.Render("available")
+		if !diag.Available {
+			status = WarningStyle.Render("not available")
+		}
+
+		fmt.Printf("%s  %s\n", HeaderStyle.Render(diag.Cleaner), status)
+
+		if len(diag.Checks) == 0 {
+			fmt.Println(MutedStyle.Render("   no prerequisites"))
+		}
+
+		printChecks(diag.Checks)
+		fmt.Println()
+	}
+
+	fmt.Printf("Summary: %d ok, %d warnings, %d failed, %d skipped\n",
+		report.Summary.OK, report.Summary.Warn, report.Summary.Fail, report.Summary.Skipped)
+}
+
+// printChecks prints checks with their details and hints.
+func printChecks(checks []cleaner.Check) {
+	for _, check := range checks {
+		line := fmt.Sprintf("   %s %s %s", checkIcon(check.Status), check.Kind, check.Name)
+
+		if check.Path != "" && check.Path != check.Name {
+			line += " → " + check.Path
+		}
+
+		if check.Version != "" {
+			line += " (" + check.Version + ")"
+		}
+
+		if check.Detail != "" {
+			line += ": " + check.Detail
+		}
+
+		fmt.Println(line)
+
+		if check.Hint != "" && check.Status != cleaner.CheckOK && check.Status != cleaner.CheckSkipped {
+			fmt.Println(InfoStyle.Render("      💡 " + check.Hint))
+		}
+	}
+}
+
+// printDoctorJSON prints the report as JSON.
+func printDoctorJSON(report doctorReport) error {
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode doctor report: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,350 @@
+package commands
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/di"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/execution"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+// explainVerdict is what a cleaner would do with an explained path.
+type explainVerdict string
+
+const (
+	// verdictSelects: the cleaner selects the path itself.
+	verdictSelects explainVerdict = "selects"
+	// verdictSelectsParent: the cleaner selects a directory containing the path.
+	verdictSelectsParent explainVerdict = "selects-parent"
+	// verdictSelectsInside: the cleaner selects items inside the path.
+	verdictSelectsInside explainVerdict = "selects-inside"
+	// verdictNotSelected: the path is in the cleaner's scope but not selected.
+	verdictNotSelected explainVerdict = "not-selected"
+	// verdictOutOfScope: the cleaner never works below the path.
+	verdictOutOfScope explainVerdict = "out-of-scope"
+	// verdictFailed: the cleaner's scan failed.
+	verdictFailed explainVerdict = "failed"
+)
+
+// explainInsideLimit is how many selected items inside a path are listed.
+const explainInsideLimit = 5
+
+// cleanerExplanation is what one cleaner would do with the explained path.
+type cleanerExplanation struct {
+	Cleaner string          `json:"cleaner"`
+	Verdict explainVerdict  `json:"verdict"`
+	Items   []explainedItem `json:"items,omitempty"`
+	Bytes   int64           `json:"bytes,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// explainedItem is a selected item with its reasons and, if an ignore rule
+// stops the cleaner from touching it, that rule.
+type explainedItem struct {
+	Path      string                   `json:"path"`
+	Bytes     int64                    `json:"bytes"`
+	Reasons   []domain.SelectionReason `json:"reasons,omitempty"`
+	IgnoredBy *ignore.Rule             `json:"ignoredBy,omitempty"`
+}
+
+// explainReport is the result of clean-wizard explain.
+type explainReport struct {
+	Path        string               `json:"path"`
+	Exists      bool                 `json:"exists"`
+	IsDir       bool                 `json:"isDir"`
+	IgnoredBy   *ignore.Rule         `json:"ignoredBy,omitempty"`
+	ProtectedBy string               `json:"protectedBy,omitempty"`
+	Cleaners    []cleanerExplanation `json:"cleaners"`
+}
+
+// NewExplainCommand creates a command that explains which cleaners would
+// touch a path and why.
+func NewExplainCommand() *cobra.Command {
+	var (
+		jsonOut    bool
+		configPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain <path>",
+		Short: "Explain which cleaners would touch a path and why",
+		Long: `Scans with every available cleaner whose scope covers the path and reports,
+per cleaner, whether it would remove the path, a directory containing it, or
+items inside it, with the reasons each item was selected. Also reports whether
+a .cleanwizardignore rule or a protected path would stop the cleaners.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExplainCommand(cmd.Context(), args[0], configPath, jsonOut)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format")
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file")
+
+	return cmd
+}
+
+// runExplainCommand executes the explain command.
+func runExplainCommand(ctx context.Context, target, configPath string, jsonOutput bool) error {
+	path, err := filepath.Abs(target)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "explain.invalid_path", "invalid path "+target)
+	}
+
+	cfg, err := loadConfigFromPath(configPath)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "explain.config_load", "failed to load config")
+	}
+
+	container, cleanup := di.New()
+	defer cleanup()
+
+	settings := di.RunSettings{Verbose: false, DryRun: true, MaxConcurrency: 0}
+	if err := di.RegisterAllServices(container.Injector(), cfg, settings); err != nil {
+		return errorfamily.WrapRejection(err, "explain.di_register", "failed to register DI services")
+	}
+
+	registry, err := di.CleanerRegistry(container.Injector())
+	if err != nil {
+		return errorfamily.WrapRejection(err, "explain.di_resolve", "failed to resolve cleaner registry from DI")
+	}
+
+	report := explainReport{Path: path} //nolint:exhaustruct
+	if info, err := os.Lstat(path); err == nil {
+		report.Exists = true
+		report.IsDir = info.IsDir()
+	}
+
+	ignores := ignore.NewDefaultMatcher()
+	if rule, ok := ignores.Match(path, report.IsDir); ok {
+		report.IgnoredBy = &rule
+	}
+
+	for _, protected := range cfg.Protected {
+		if pathWithin(path, filepath.Clean(protected)) {
+			report.ProtectedBy = protected
+
+			break
+		}
+	}
+
+	var inScope, outOfScope []string
+
+	for _, name := range cleanerConfigsToNames(getAvailableConfigs(ctx, registry)) {
+		c, ok := registry.Get(name)
+		if !ok {
+			continue
+		}
+
+		if cleanerCovers(path, cleaner.AffectedPaths(name, c)) {
+			inScope = append(inScope, name)
+		} else {
+			outOfScope = append(outOfScope, name)
+		}
+	}
+
+	if len(inScope) > 0 {
+		// Scan without ignore rules so that we can tell what they stop.
+		wr, err := execution.RunScans(ctx, registry, inScope, execution.WithIgnoreMatcher(ignore.NewNopMatcher()))
+		if err != nil {
+			return fmt.Errorf("explain scan failed: %w", err)
+		}
+
+		for _, step := range wr.Steps {
+			report.Cleaners = append(report.Cleaners, explainStep(path, step, ignores))
+		}
+	}
+
+	for _, name := range outOfScope {
+		report.Cleaners = append(report.Cleaners, cleanerExplanation{Cleaner: name, Verdict: verdictOutOfScope}) //nolint:exhaustruct
+	}
+
+	if jsonOutput {
+		return printExplainJSON(report)
+	}
+
+	printExplainReport(report)
+
+	return nil
+}
+
+// cleanerCovers reports whether a cleaner working below roots can touch
+// path: path is below a root, or a root is inside path.
+func cleanerCovers(path string, roots []string) bool {
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if pathWithin(path, root) || pathWithin(root, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathWithin reports whether path equals dir or lies below it.
+func pathWithin(path, dir string) bool {
+	if dir == string(filepath.Separator) {
+		return true
+	}
+
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// explainStep classifies what a scan step's items mean for path.
+func explainStep(path string, step execution.StepResult, ignores *ignore.Matcher) cleanerExplanation {
+	exp := cleanerExplanation{Cleaner: step.Name, Verdict: verdictNotSelected} //nolint:exhaustruct
+	if step.Err != nil {
+		exp.Verdict = verdictFailed
+		exp.Error = step.Err.Error()
+
+		return exp
+	}
+
+	var inside []domain.ScanItem
+
+	for _, item := range step.Items {
+		itemPath := filepath.Clean(item.Path)
+
+		switch {
+		case itemPath == path:
+			exp.Verdict = verdictSelects
+			exp.Items = []explainedItem{explainItem(item, ignores)}
+			exp.Bytes = item.DiskUsage()
+
+			return exp
+		case pathWithin(path, itemPath):
+			exp.Verdict = verdictSelectsParent
+			exp.Items = []explainedItem{explainItem(item, ignores)}
+			exp.Bytes = item.DiskUsage()
+
+			return exp
+		case pathWithin(itemPath, path):
+			inside = append(inside, item)
+		}
+	}
+
+	if len(inside) == 0 {
+		return exp
+	}
+
+	exp.Verdict = verdictSelectsInside
+
+	for i, item := range inside {
+		exp.Bytes += item.DiskUsage()
+
+		if i < explainInsideLimit {
+			exp.Items = append(exp.Items, explainItem(item, ignores))
+		}
+	}
+
+	return exp
+}
+
+// explainItem pairs a selected item with the ignore rule that would stop
+// the cleaner from touching it, if any.
+func explainItem(item domain.ScanItem, ignores *ignore.Matcher) explainedItem {
+	out := explainedItem{Path: item.Path, Bytes: item.DiskUsage(), Reasons: item.Reasons} //nolint:exhaustruct
+
+	info, err := os.Lstat(item.Path)
+	if rule, ok := ignores.Match(item.Path, err == nil && info.IsDir()); ok {
+		out.IgnoredBy = &rule
+	}
+
+	return out
+}
+
+// printExplainReport prints the explanation for humans.
+func printExplainReport(report explainReport) {
+	fmt.Println(TitleStyle.Render("🔎 " + report.Path))
+
+	switch {
+	case !report.Exists:
+		fmt.Println("   does not exist")
+	case report.IsDir:
+		fmt.Println("   directory")
+	default:
+		fmt.Println("   file")
+	}
+
+	if report.IgnoredBy != nil {
+		fmt.Println(WarningStyle.Render("   🙈 Ignored by " + report.IgnoredBy.String() + ": no cleaner will touch it"))
+	}
+
+	if report.ProtectedBy != "" {
+		fmt.Printf("   🛡️  Below protected path %s: custom cleaners never select it\n", report.ProtectedBy)
+	}
+
+	fmt.Println()
+
+	var outOfScope []string
+
+	for _, exp := range report.Cleaners {
+		switch exp.Verdict {
+		case verdictOutOfScope:
+			outOfScope = append(outOfScope, exp.Cleaner)
+		case verdictFailed:
+			fmt.Printf("• %s: scan failed: %s\n", exp.Cleaner, exp.Error)
+		case verdictNotSelected:
+			fmt.Printf("• %s: scanned, does not select this path\n", exp.Cleaner)
+		case verdictSelects:
+			fmt.Printf("• %s: would remove this path (%s)\n", exp.Cleaner, format.Bytes(exp.Bytes))
+			printExplainedItems(exp.Items, false)
+		case verdictSelectsParent:
+			fmt.Printf("• %s: would remove %s, which contains this path (%s)\n",
+				exp.Cleaner, exp.Items[0].Path, format.Bytes(exp.Bytes))
+			printExplainedItems(exp.Items, false)
+		case verdictSelectsInside:
+			fmt.Printf("• %s: would remove items inside this path (%s)\n", exp.Cleaner, format.Bytes(exp.Bytes))
+			printExplainedItems(exp.Items, true)
+		}
+	}
+
+	if len(outOfScope) > 0 {
+		fmt.Printf("• out of scope: %s\n", strings.Join(outOfScope, ", "))
+	}
+}
+
+// printExplainedItems prints items' reasons, with their paths when listing
+// several items.
+func printExplainedItems(items []explainedItem, withPaths bool) {
+	for _, item := range items {
+		indent := "    "
+
+		if withPaths {
+			fmt.Printf("    %s (%s)\n", item.Path, format.Bytes(item.Bytes))
+
+			indent = "      "
+		}
+
+		for _, reason := range item.Reasons {
+			fmt.Printf("%s- %s\n", indent, reason)
+		}
+
+		if item.IgnoredBy != nil {
+			fmt.Printf("%s- stopped: ignored by %s\n", indent, item.IgnoredBy)
+		}
+	}
+}
+
+// printExplainJSON prints the explanation as JSON.
+func printExplainJSON(report explainReport) error {
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode explanation: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/execution"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+)
+
+func TestExplainStep_Verdicts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	target := filepath.Join(root, "project", "target")
+	item := func(path string) domain.ScanItem {
+		return domain.ScanItem{Path: path, Size: 10} //nolint:exhaustruct
+	}
+
+	tests := []struct {
+		name  string
+		step  execution.StepResult
+		want  explainVerdict
+		items int
+	}{
+		{"exact", execution.StepResult{Items: []domain.ScanItem{item(target)}}, verdictSelects, 1},                      //nolint:exhaustruct
+		{"parent", execution.StepResult{Items: []domain.ScanItem{item(filepath.Dir(target))}}, verdictSelectsParent, 1}, //nolint:exhaustruct
+		{"inside", execution.StepResult{Items: []domain.ScanItem{ //nolint:exhaustruct
+			item(filepath.Join(target, "a")), item(filepath.Join(target, "b")),
+		}}, verdictSelectsInside, 2},
+		{"sibling", execution.StepResult{Items: []domain.ScanItem{item(target + "-old")}}, verdictNotSelected, 0}, //nolint:exhaustruct
+		{"failed", execution.StepResult{Err: errors.New("boom")}, verdictFailed, 0},                               //nolint:exhaustruct
+	}
+
+	for _, tt := range tests {
+		got := explainStep(target, tt.step, ignore.NewNopMatcher())
+		if got.Verdict != tt.want || len(got.Items) != tt.items {
+			t.Errorf("%s: explainStep() = %s with %d items, want %s with %d", tt.name, got.Verdict, len(got.Items), tt.want, tt.items)
+		}
+	}
+}
+
+func TestCleanerCovers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path  string
+		roots []string
+		want  bool
+	}{
+		{"/home/me/.cache/go-build/ab", []string{"/home/me/.cache/go-build"}, true},
+		{"/home/me", []string{"/home/me/.cache/go-build"}, true},
+		{"/home/me/src", []string{"/home/me/.cache/go-build"}, false},
+		{"/home/me/.cache/go-buildx", []string{"/home/me/.cache/go-build"}, false},
+		{"/anything", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := cleanerCovers(tt.path, tt.roots); got != tt.want {
+			t.Errorf("cleanerCovers(%s, %v) = %v, want %v", tt.path, tt.roots, got, tt.want)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
 	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
 	"github.com/spf13/cobra"
 )
 
@@ -24,16 +25,40 @@ var (
 	ErrNotAGitRepository      = errors.New("not a git repository")
 )
 
+// gitHistoryOptions are the flags git-history shares with its subcommands.
+type gitHistoryOptions struct {
+	dryRun         bool
+	verbose        bool
+	minSizeMB      int
+	maxFiles       int
+	force          bool
+	createBackup   bool
+	lfs            bool
+	lfsByExtension bool
+	removePaths    []string
+	secrets        bool
+	configPath     string
+	secretRules    []domain.GitHistorySecretRule
+}
+
+// action returns what the rewrite does with the selected files.
+func (o gitHistoryOptions) action() domain.GitHistoryAction {
+	if o.secrets {
+		return domain.GitHistoryActionPurge
+	}
+
+	if o.lfs {
+		return domain.GitHistoryActionLFS
+	}
+
+	return domain.GitHistoryActionRemove
+}
+
 // NewGitHistoryCommand creates the git-history subcommand.
 func NewGitHistoryCommand() *cobra.Command {
 	var (
-		dryRun          bool
-		verbose         bool
-		minSizeMB       int
-		maxFiles        int
+		opts            gitHistoryOptions
 		scanPath        string
-		force           bool
-		createBackup    bool
 		scanAllProjects bool
 	)
 
@@ -41,7 +66,10 @@ func NewGitHistoryCommand() *cobra.Command {
 		Use:   "git-history [path]",
 		Short: "Interactive wizard to remove binary files from git history",
 		Long: `Scans git history for binary files and provides an interactive wizard
-to select which files to remove using git-filter-repo.
+to select which files to remove using git-filter-repo, or with --lfs to
+move into Git LFS using git lfs migrate import. With --secrets it scans
+every blob for leaked credentials instead and redacts the selected ones
+using git-filter-repo --replace-text.
 
 ⚠️  WARNING: This rewrites git history and requires force-push.
     Always backup your repository first.
@@ -57,6 +85,23 @@ Examples:
   # Scan all projects under ~/projects
   clean-wizard git-history --scan-all-projects
 
+  # Rank every repository under ~/projects by history bloat
+  clean-wizard git-history scan --root ~/projects
+
+  # Undo a rewrite from its backup
+  clean-wizard git-history backups list
+  clean-wizard git-history restore ../repo-20260101-120000-backup.git
+
+  # Keep the files but move them and every file of their type to Git LFS
+  clean-wizard git-history --lfs --lfs-by-extension
+
+  # Find leaked credentials in every commit and redact them
+  clean-wizard git-history --secrets
+
+  # Find the directories that bloat history, then purge them
+  clean-wizard git-history dirs
+  clean-wizard git-history --remove-path vendor/ --remove-path 'reports/*.txt'
+
   # Quick mode: remove files > 10MB without interactive selection
   clean-wizard git-history --min-size 10 --force`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -70,41 +115,66 @@ Examples:
 				path = scanPath
 			}
 
-			return runGitHistoryWizard(
-				path,
-				dryRun,
-				verbose,
-				minSizeMB,
-				maxFiles,
-				force,
-				createBackup,
-				scanAllProjects,
-			)
+			if len(opts.removePaths) > 0 && opts.lfs {
+				return errorfamily.WrapRejectionf(cleaner.ErrRemovePathsWithLFS, "git_history.remove_path",
+					"--remove-path cannot be combined with --lfs")
+			}
+
+			if err := cleaner.ValidateRemovePaths(opts.removePaths); err != nil {
+				return errorfamily.WrapRejectionf(err, "git_history.remove_path", "invalid --remove-path")
+			}
+
+			if opts.secrets {
+				if len(opts.removePaths) > 0 || opts.lfs {
+					return errorfamily.WrapRejectionf(cleaner.ErrPurgeWithPaths, "git_history.secrets",
+						"--secrets cannot be combined with --remove-path or --lfs")
+				}
+
+				cfg, err := loadConfigFromPath(opts.configPath)
+				if err != nil {
+					return errorfamily.WrapRejection(err, "git_history.secrets", "cannot load the secret rules")
+				}
+
+				opts.secretRules = cfg.SecretRules
+			}
+
+			return runGitHistoryWizard(path, opts, scanAllProjects)
 		},
 	}
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Analyze only, don't modify history")
-	cmd.Flags().BoolVar(&verbose, "verbose", false, "Show detailed output")
-	cmd.Flags().IntVar(&minSizeMB, "min-size", 1, "Minimum file size in MB to consider")
-	cmd.Flags().IntVar(&maxFiles, "max-files", 100, "Maximum number of files to display")
+	cmd.PersistentFlags().BoolVar(&opts.dryRun, "dry-run", false, "Analyze only, don't modify history")
+	cmd.PersistentFlags().BoolVar(&opts.verbose, "verbose", false, "Show detailed output")
+	cmd.PersistentFlags().IntVar(&opts.minSizeMB, "min-size", 1, "Minimum file size in MB to consider")
+	cmd.PersistentFlags().IntVar(&opts.maxFiles, "max-files", 100, "Maximum number of files to display")
 	cmd.Flags().
 		StringVar(&scanPath, "path", "", "Path to git repository (default: current directory)")
-	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompts (dangerous)")
-	cmd.Flags().BoolVar(&createBackup, "backup", true, "Create backup before rewriting")
+	cmd.PersistentFlags().BoolVar(&opts.force, "force", false, "Skip confirmation prompts (dangerous)")
+	cmd.PersistentFlags().BoolVar(&opts.createBackup, "backup", true, "Create backup before rewriting")
+	cmd.PersistentFlags().BoolVar(&opts.lfs, "lfs", false, "Migrate the selected files to Git LFS instead of removing them")
+	cmd.PersistentFlags().
+		BoolVar(&opts.lfsByExtension, "lfs-by-extension", false, "With --lfs, migrate every file with a selected file's extension")
 	cmd.Flags().
 		BoolVar(&scanAllProjects, "scan-all-projects", false, "Scan all projects under ~/projects")
+	cmd.Flags().StringArrayVar(&opts.removePaths, "remove-path", nil,
+		"Remove this directory prefix or glob from all history, whatever the blob sizes (repeatable)")
+	cmd.Flags().BoolVar(&opts.secrets, "secrets", false,
+		"Scan every blob in history for leaked secrets and redact the selected ones")
+	cmd.Flags().StringVar(&opts.configPath, "config", "", "Configuration file with the secret_rules for --secrets")
+
+	cmd.AddCommand(
+		newGitHistoryScanCommand(&opts),
+		newGitHistoryDirsCommand(&opts),
+		newGitHistoryBackupsCommand(&opts),
+		newGitHistoryRestoreCommand(&opts),
+	)
 
 	return cmd
 }
 
 // runGitHistoryWizard runs the interactive git history cleaning wizard.
-func runGitHistoryWizard(
-	basePath string,
-	dryRun, verbose bool,
-	minSizeMB, maxFiles int,
-	force, createBackup, scanAllProjects bool,
-) error {
+func runGitHistoryWizard(basePath string, opts gitHistoryOptions, scanAllProjects bool) error {
 	ctx := context.Background()
+	minSizeMB, maxFiles := opts.minSizeMB, opts.maxFiles
 
 	fmt.Println(TitleStyle.Render("🔮 Git History Binary Cleaner"))
 	fmt.Println()
@@ -141,7 +211,7 @@ func runGitHistoryWizard(
 		fmt.Printf("✅ Found %d repositories\n\n", len(repos))
 
 		// Let user select which repos to clean
-		if !force {
+		if !opts.force {
 			var selectedRepos []string
 
 			form := huh.NewForm(
@@ -181,25 +251,22 @@ func runGitHistoryWizard(
 	}
 
 	// Process each repository
+	processRepositories(ctx, repos, opts)
+
+	return nil
+}
+
+// processRepositories runs the cleanup wizard on each repository in turn,
+// reporting failures and moving on to the next.
+func processRepositories(ctx context.Context, repos []string, opts gitHistoryOptions) {
 	for _, repoPath := range repos {
-		err := processRepository(
-			ctx,
-			repoPath,
-			dryRun,
-			verbose,
-			minSizeMB,
-			maxFiles,
-			force,
-			createBackup,
-		)
+		err := processRepository(ctx, repoPath, opts)
 		if err != nil {
 			fmt.Printf("❌ Error processing %s: %v\n\n", repoPath, err)
 
 			continue
 		}
 	}
-
-	return nil
 }
 
 // displaySafetyWarnings shows safety warnings to the user.
@@ -215,11 +282,26 @@ func displaySummary(
 	selectedFiles []domain.GitHistoryFile,
 	selectedSize int64,
 	impact *cleaner.ImpactEstimate,
+	action domain.GitHistoryAction,
 ) {
 	fmt.Println()
 	fmt.Println(TitleStyle.Render("📊 Summary"))
 	fmt.Printf("   Repository:      %s\n", repoPath)
-	fmt.Printf("   Files to remove: %d\n", len(selectedFiles))
+
+	switch action {
+	case domain.GitHistoryActionLFS:
+		fmt.Printf("   Files to LFS:    %d\n", len(selectedFiles))
+	case domain.GitHistoryActionPurge:
+		// Redacting secrets frees no space worth estimating
+		fmt.Printf("   Secrets:         %d\n", len(distinctSecrets(selectedFiles)))
+		fmt.Printf("   Blobs to redact: %d\n", len(selectedFiles))
+		fmt.Println()
+
+		return
+	case domain.GitHistoryActionRemove:
+		fmt.Printf("   Files to remove: %d\n", len(selectedFiles))
+	}
+
 	fmt.Printf("   Total size:      %s\n", format.Bytes(selectedSize))
 
 	if impact != nil {
@@ -236,14 +318,19 @@ func displayCleanupResults(
 	cleanResult domain.CleanResult,
 	dryRun, hasRemote bool,
 	remoteName, currentBranch string,
+	action domain.GitHistoryAction,
 ) {
 	fmt.Println()
 	fmt.Println(SuccessStyle.Render("✅ Cleanup completed!"))
 	fmt.Printf("   Files processed: %d\n", cleanResult.ItemsRemoved)
-	fmt.Printf("   Space freed:     %s\n", format.Bytes(int64(cleanResult.FreedBytes)))
 
-	if cleanResult.SizeEstimate.Known > 0 {
-		fmt.Printf("   Repository size: %s\n", format.Bytes(int64(cleanResult.SizeEstimate.Known)))
+	// A purge keeps every file, so there is no space to speak of
+	if action != domain.GitHistoryActionPurge {
+		fmt.Printf("   Space freed:     %s\n", format.Bytes(int64(cleanResult.FreedBytes)))
+
+		if cleanResult.SizeEstimate.Known > 0 {
+			fmt.Printf("   Repository size: %s\n", format.Bytes(int64(cleanResult.SizeEstimate.Known)))
+		}
 	}
 
 	if dryRun {
@@ -255,27 +342,38 @@ func displayCleanupResults(
 		if hasRemote {
 			fmt.Println(WarningStyle.Render("⚠️  Next steps:"))
 			fmt.Println("   1. Verify the repository is in good state")
+
+			step := 2
+			if action == domain.GitHistoryActionPurge {
+				fmt.Printf("   %d. Rotate every purged credential: redacting history does not revoke it\n", step)
+
+				step++
+			}
+
+			if action == domain.GitHistoryActionLFS {
+				fmt.Printf("   %d. Upload the LFS objects: git lfs push --all %s\n", step, remoteName)
+
+				step++
+			}
+
 			fmt.Printf(
-				"   2. Force push: git push --force-with-lease %s %s\n",
+				"   %d. Force push: git push --force-with-lease %s %s\n",
+				step,
 				remoteName,
 				currentBranch,
 			)
-			fmt.Println("   3. Notify team members to reclone or reset")
+			fmt.Printf("   %d. Notify team members to reclone or reset\n", step+1)
 		}
 	}
 }
 
 // processRepository processes a single repository.
-func processRepository(
-	ctx context.Context,
-	repoPath string,
-	dryRun, verbose bool,
-	minSizeMB, maxFiles int,
-	force, createBackup bool,
-) error {
+func processRepository(ctx context.Context, repoPath string, opts gitHistoryOptions) error {
+	minSizeMB, maxFiles := opts.minSizeMB, opts.maxFiles
+
 	fmt.Println(InfoStyle.Render("\n📂 Repository: " + repoPath))
 
-	c := newGitHistoryCleaner(repoPath, minSizeMB, maxFiles, verbose, dryRun, createBackup)
+	c := newGitHistoryCleaner(repoPath, opts)
 
 	if !c.IsAvailable(ctx) {
 		return ErrGitNotAvailable
@@ -291,7 +389,7 @@ func processRepository(
 		)
 	}
 
-	selectedFiles, selectedSize, err := scanAndSelectFiles(ctx, c, force)
+	selectedFiles, selectedSize, err := scanAndSelectFiles(ctx, c, opts)
 	if err != nil {
 		return fmt.Errorf(
 			"repoPath=%v, minSizeMB=%v, maxFiles=%v: %w",
@@ -306,24 +404,21 @@ func processRepository(
 		return nil
 	}
 
-	return confirmAndExecuteCleanup(
-		ctx, c, repoPath, selectedFiles, selectedSize,
-		dryRun, force,
-	)
+	return confirmAndExecuteCleanup(ctx, c, repoPath, selectedFiles, selectedSize, opts)
 }
 
-func newGitHistoryCleaner(
-	repoPath string,
-	minSizeMB, maxFiles int,
-	verbose, dryRun, createBackup bool,
-) *cleaner.GitHistoryCleaner {
+func newGitHistoryCleaner(repoPath string, opts gitHistoryOptions) *cleaner.GitHistoryCleaner {
 	return cleaner.NewGitHistoryCleaner(
 		cleaner.WithGitHistoryRepoPath(repoPath),
-		cleaner.WithGitHistoryMinSizeMB(minSizeMB),
-		cleaner.WithGitHistoryMaxFiles(maxFiles),
-		cleaner.WithGitHistoryVerbose(verbose),
-		cleaner.WithGitHistoryDryRun(dryRun),
-		cleaner.WithGitHistoryCreateBackup(createBackup),
+		cleaner.WithGitHistoryMinSizeMB(opts.minSizeMB),
+		cleaner.WithGitHistoryMaxFiles(opts.maxFiles),
+		cleaner.WithGitHistoryVerbose(opts.verbose),
+		cleaner.WithGitHistoryDryRun(opts.dryRun),
+		cleaner.WithGitHistoryCreateBackup(opts.createBackup),
+		cleaner.WithGitHistoryAction(opts.action()),
+		cleaner.WithGitHistoryLFSByExtension(opts.lfsByExtension),
+		cleaner.WithGitHistoryRemovePaths(opts.removePaths),
+		cleaner.WithGitHistorySecretRules(opts.secretRules),
 	)
 }
 
@@ -351,8 +446,16 @@ func runSafetyChecks(ctx context.Context, c *cleaner.GitHistoryCleaner) error {
 func scanAndSelectFiles(
 	ctx context.Context,
 	c *cleaner.GitHistoryCleaner,
-	force bool,
+	opts gitHistoryOptions,
 ) ([]domain.GitHistoryFile, int64, error) {
+	if len(opts.removePaths) > 0 {
+		return scanRemovePaths(ctx, c, opts.removePaths)
+	}
+
+	if opts.secrets {
+		return scanAndSelectSecrets(ctx, c, opts.force)
+	}
+
 	fmt.Print("🔍 Scanning git history for binary files... ")
 
 	scanResult, err := c.GetScanResult(ctx)
@@ -376,7 +479,7 @@ func scanAndSelectFiles(
 		),
 	)
 
-	selectedFiles, err := selectFilesToClean(scanResult.Files, force)
+	selectedFiles, err := selectFilesToClean(scanResult.Files, opts.force, opts.action())
 	if err != nil {
 		return nil, 0, fmt.Errorf("selection error: %w", err)
 	}
@@ -395,27 +498,206 @@ func scanAndSelectFiles(
 	return selectedFiles, selectedSize, nil
 }
 
+// scanRemovePaths finds every blob in history below the remove paths; all
+// of them are removed, so there is nothing to select.
+func scanRemovePaths(
+	ctx context.Context,
+	c *cleaner.GitHistoryCleaner,
+	removePaths []string,
+) ([]domain.GitHistoryFile, int64, error) {
+	fmt.Printf("🔍 Scanning git history for %s... ", strings.Join(removePaths, ", "))
+
+	scanResult, err := c.GetScanResult(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan failed: %w", err)
+	}
+
+	if len(scanResult.Files) == 0 {
+		fmt.Println(SuccessStyle.Render("Nothing in history matches!"))
+
+		return nil, 0, nil
+	}
+
+	paths := cleaner.GetUniquePaths(scanResult.Files)
+
+	fmt.Println(SuccessStyle.Render(fmt.Sprintf("Found %d blob(s) in %d path(s) (%s)",
+		len(scanResult.Files), len(paths), format.Bytes(scanResult.TotalBytes))))
+
+	for _, f := range scanResult.Files[:min(len(scanResult.Files), gitHistoryDirsTopBlobs)] {
+		fmt.Printf("   %10s  %s\n", format.Bytes(f.SizeBytes), f.Path)
+	}
+
+	return scanResult.Files, scanResult.TotalBytes, nil
+}
+
+// scanAndSelectSecrets finds every blob in history with a secret and lets
+// the user select the secrets to redact; a secret is redacted in every blob
+// it is found in, so the blobs follow from the selection.
+func scanAndSelectSecrets(
+	ctx context.Context,
+	c *cleaner.GitHistoryCleaner,
+	force bool,
+) ([]domain.GitHistoryFile, int64, error) {
+	fmt.Print("🔍 Scanning every blob in git history for secrets... ")
+
+	scanResult, err := c.GetScanResult(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("scan failed: %w", err)
+	}
+
+	findings := distinctSecrets(scanResult.Files)
+	if len(findings) == 0 {
+		fmt.Println(SuccessStyle.Render("No secrets found!"))
+
+		return nil, 0, nil
+	}
+
+	fmt.Println(WarningStyle.Render(fmt.Sprintf("Found %d secret(s) in %d blob(s)",
+		len(findings), len(scanResult.Files))))
+
+	selected, err := selectSecretsToPurge(findings, force)
+	if err != nil {
+		return nil, 0, fmt.Errorf("selection error: %w", err)
+	}
+
+	files := filesWithSecrets(scanResult.Files, selected)
+	if len(files) == 0 {
+		fmt.Println("❌ No secrets selected. Nothing to do.")
+
+		return nil, 0, nil
+	}
+
+	var selectedSize int64
+	for _, f := range files {
+		selectedSize += f.SizeBytes
+	}
+
+	return files, selectedSize, nil
+}
+
+// secretFinding is a distinct secret found by a secrets scan, with where it
+// was first found and in how many blobs.
+type secretFinding struct {
+	secret domain.GitHistorySecret
+	path   string
+	blobs  int
+}
+
+// distinctSecrets returns each distinct secret of the files once, in the
+// order they were found.
+func distinctSecrets(files []domain.GitHistoryFile) []secretFinding {
+	var findings []secretFinding
+
+	index := make(map[string]int)
+
+	for _, f := range files {
+		for _, secret := range f.Secrets {
+			if i, ok := index[secret.Value]; ok {
+				findings[i].blobs++
+
+				continue
+			}
+
+			index[secret.Value] = len(findings)
+			findings = append(findings, secretFinding{secret: secret, path: f.Path, blobs: 1})
+		}
+	}
+
+	return findings
+}
+
+// filesWithSecrets returns the files with a selected secret, each keeping
+// only its selected secrets.
+func filesWithSecrets(files []domain.GitHistoryFile, selected map[string]bool) []domain.GitHistoryFile {
+	var result []domain.GitHistoryFile
+
+	for _, f := range files {
+		var secrets []domain.GitHistorySecret
+
+		for _, secret := range f.Secrets {
+			if selected[secret.Value] {
+				secrets = append(secrets, secret)
+			}
+		}
+
+		if len(secrets) > 0 {
+			f.Secrets = secrets
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+// selectSecretsToPurge shows an interactive multi-select for secrets and
+// returns the selected ones.
+func selectSecretsToPurge(findings []secretFinding, force bool) (map[string]bool, error) {
+	selected := make(map[string]bool, len(findings))
+
+	if force {
+		// In force mode, select all secrets
+		for _, f := range findings {
+			selected[f.secret.Value] = true
+		}
+
+		return selected, nil
+	}
+
+	var selectedIndices []int
+
+	options := make([]huh.Option[int], len(findings))
+	for i, f := range findings {
+		label := fmt.Sprintf("%s  %s  %s:%d", f.secret.Rule, f.secret.Redacted, f.path, f.secret.Line)
+		if f.blobs > 1 {
+			label += fmt.Sprintf(" (+%d more blob(s))", f.blobs-1)
+		}
+
+		options[i] = huh.NewOption(label, i)
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Select secrets to redact from history").
+				Description("Each is replaced with ***REMOVED*** in every commit. Space to select, Enter to confirm").
+				Options(options...).
+				Value(&selectedIndices),
+		),
+	)
+
+	err := form.Run()
+	if err != nil {
+		return nil, fmt.Errorf("secret selection form failed: %w", err)
+	}
+
+	for _, idx := range selectedIndices {
+		selected[findings[idx].secret.Value] = true
+	}
+
+	return selected, nil
+}
+
 func confirmAndExecuteCleanup(
 	ctx context.Context,
 	c *cleaner.GitHistoryCleaner,
 	repoPath string,
 	selectedFiles []domain.GitHistoryFile,
 	selectedSize int64,
-	dryRun, force bool,
+	opts gitHistoryOptions,
 ) error {
 	impact, err := c.EstimateImpact(ctx)
 	if err != nil {
 		fmt.Printf("Warning: could not estimate impact: %v\n", err)
 	}
 
-	displaySummary(repoPath, selectedFiles, selectedSize, impact)
+	displaySummary(repoPath, selectedFiles, selectedSize, impact, opts.action())
 
-	if dryRun {
+	if opts.dryRun {
 		fmt.Println(InfoStyle.Render("🔍 DRY RUN MODE - No changes will be made"))
 		fmt.Println()
 	}
 
-	if !force && !dryRun {
+	if !opts.force && !opts.dryRun {
 		safetyReport := c.GetSafetyReport(ctx)
 		if !confirmAction(repoPath, len(selectedFiles), selectedSize, safetyReport) {
 			fmt.Println("❌ Cancelled. No changes made.")
@@ -443,19 +725,47 @@ func confirmAndExecuteCleanup(
 	safetyReport := c.GetSafetyReport(ctx)
 	displayCleanupResults(
 		cleanResult,
-		dryRun,
+		opts.dryRun,
 		safetyReport.HasRemote,
 		safetyReport.RemoteName,
 		safetyReport.CurrentBranch,
+		opts.action(),
 	)
+	displayRewriteHandoff(c.LastRewrite())
 
 	return nil
 }
 
+// displayRewriteHandoff shows the verification of a rewrite and where its
+// handoff report for collaborators was written.
+func displayRewriteHandoff(rewrite *domain.GitHistoryRewriteResult) {
+	if rewrite == nil || rewrite.Verification == nil {
+		return
+	}
+
+	v := rewrite.Verification
+
+	fmt.Println()
+	fmt.Println(SuccessStyle.Render("🔒 Rewrite verified"))
+	fmt.Printf("   HEAD:    only the %d selected path(s) changed\n", len(v.IntendedChanges))
+	fmt.Printf("   Commits: %d before, %d after\n", v.CommitsBefore, v.CommitsAfter)
+	fmt.Println("   git fsck passed, no selected blob is reachable")
+
+	if len(rewrite.HandoffPaths) > 0 {
+		fmt.Println()
+		fmt.Println(InfoStyle.Render("📋 Handoff report (force-push commands and re-sync steps for collaborators):"))
+
+		for _, path := range rewrite.HandoffPaths {
+			fmt.Println("   " + path)
+		}
+	}
+}
+
 // selectFilesToClean shows an interactive multi-select for files.
 func selectFilesToClean(
 	files []domain.GitHistoryFile,
 	force bool,
+	action domain.GitHistoryAction,
 ) ([]domain.GitHistoryFile, error) {
 	if force {
 		// In force mode, select all files
@@ -480,11 +790,16 @@ func selectFilesToClean(
 		options[i] = huh.NewOption(label, i)
 	}
 
+	title := "Select binary files to remove from history"
+	if action == domain.GitHistoryActionLFS {
+		title = "Select binary files to migrate to Git LFS"
+	}
+
 	// Show selection form
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewMultiSelect[int]().
-				Title("Select binary files to remove from history").
+				Title(title).
 				Description("Large files are shown first. Space to select, Enter to confirm").
 				Options(options...).
 				Value(&selectedIndices),
@@ -517,8 +832,19 @@ func confirmAction(
 	warnMsg.WriteString("⚠️  WARNING: DESTRUCTIVE OPERATION\n\n")
 	fmt.Fprintf(&warnMsg, "You are about to rewrite git history for:\n  %s\n\n", repoPath)
 	warnMsg.WriteString("This will:\n")
-	fmt.Fprintf(&warnMsg, "  • Remove %d binary files from history\n", fileCount)
-	fmt.Fprintf(&warnMsg, "  • Free approximately %s\n", format.Bytes(totalSize))
+	switch report.Action {
+	case domain.GitHistoryActionLFS:
+		fmt.Fprintf(&warnMsg, "  • Replace %d binary files with Git LFS pointers in every commit\n", fileCount)
+		fmt.Fprintf(&warnMsg, "  • Move approximately %s out of git history into LFS storage\n", format.Bytes(totalSize))
+		warnMsg.WriteString("  • Track the files in .gitattributes\n")
+	case domain.GitHistoryActionPurge:
+		fmt.Fprintf(&warnMsg, "  • Replace the selected secrets with ***REMOVED*** in %d blobs in every commit\n", fileCount)
+		warnMsg.WriteString("  • Keep every file; only the secrets change\n")
+	case domain.GitHistoryActionRemove:
+		fmt.Fprintf(&warnMsg, "  • Remove %d binary files from history\n", fileCount)
+		fmt.Fprintf(&warnMsg, "  • Free approximately %s\n", format.Bytes(totalSize))
+	}
+
 	warnMsg.WriteString("  • Require force-push to remote\n\n")
 
 	if report.HasRemote {
@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"cmp"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"charm.land/huh/v2"
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+// gitHistoryBackupEntry is an indexed backup with its state on disk.
+type gitHistoryBackupEntry struct {
+	domain.GitHistoryBackup
+
+	SizeBytes int64 `json:"size_bytes"`
+	Missing   bool  `json:"missing,omitzero"`
+}
+
+// newGitHistoryBackupsCommand creates the git-history backups subcommand.
+func newGitHistoryBackupsCommand(opts *gitHistoryOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "List and prune the backups made before history rewrites",
+		Long: `Every history rewrite first makes a mirror backup next to the repository
+(<repo>-<timestamp>-backup.git) and records it in the backup index. Use
+git-history restore to get a repository back from one of them.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newGitHistoryBackupsListCommand(), newGitHistoryBackupsPruneCommand(opts))
+
+	return cmd
+}
+
+// newGitHistoryBackupsListCommand creates the git-history backups list subcommand.
+func newGitHistoryBackupsListCommand() *cobra.Command {
+	var (
+		repo    string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the backups by repository, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			backups, err := cleaner.NewGitHistoryBackupIndex("").List()
+			if err != nil {
+				return errorfamily.WrapInfrastructure(err, "git_history.backups", "failed to read the backup index")
+			}
+
+			if repo != "" {
+				abs, absErr := filepath.Abs(repo)
+				if absErr != nil {
+					return errorfamily.WrapRejectionf(absErr, "git_history.repo", "invalid --repo %q", repo)
+				}
+
+				backups = filterBackupsByRepo(backups, abs)
+			}
+
+			entries := make([]gitHistoryBackupEntry, len(backups))
+			for i, b := range backups {
+				_, statErr := os.Stat(b.Path)
+				entries[i] = gitHistoryBackupEntry{
+					GitHistoryBackup: b,
+					SizeBytes:        cleaner.GitHistoryBackupSize(b.Path),
+					Missing:          errors.Is(statErr, os.ErrNotExist),
+				}
+			}
+
+			if jsonOut {
+				return printGitHistoryBackupsJSON(entries)
+			}
+
+			printGitHistoryBackups(entries)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "Only list the backups of this repository")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format")
+
+	return cmd
+}
+
+// newGitHistoryBackupsPruneCommand creates the git-history backups prune subcommand.
+func newGitHistoryBackupsPruneCommand(opts *gitHistoryOptions) *cobra.Command {
+	var olderThan string
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete backups older than the retention period",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			retention, err := domain.ParseCustomDuration(olderThan)
+			if err != nil {
+				return errorfamily.WrapRejectionf(err, "git_history.older_than", "invalid --older-than %q", olderThan)
+			}
+
+			index := cleaner.NewGitHistoryBackupIndex("")
+
+			backups, err := index.List()
+			if err != nil {
+				return errorfamily.WrapInfrastructure(err, "git_history.backups", "failed to read the backup index")
+			}
+
+			sizes := make(map[string]int64, len(backups))
+			for _, b := range backups {
+				sizes[b.Path] = cleaner.GitHistoryBackupSize(b.Path)
+			}
+
+			pruned, pruneErr := index.Prune(retention, time.Now(), opts.dryRun)
+
+			verb := "Deleted"
+			if opts.dryRun {
+				verb = "Would delete"
+			}
+
+			var freed int64
+
+			for _, b := range pruned {
+				freed += sizes[b.Path]
+				fmt.Printf("🗑️  %s %s (%s, %s)\n", verb, b.Path, format.Date(b.CreatedAt), format.Bytes(sizes[b.Path]))
+			}
+
+			fmt.Printf("💡 %s %d backup(s) older than %s, %s\n", verb, len(pruned), olderThan, format.Bytes(freed))
+
+			if pruneErr != nil {
+				return errorfamily.WrapInfrastructure(pruneErr, "git_history.backups_prune", "failed to prune some backups")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "30d", "Retention period; older backups are deleted")
+
+	return cmd
+}
+
+// newGitHistoryRestoreCommand creates the git-history restore subcommand.
+func newGitHistoryRestoreCommand(opts *gitHistoryOptions) *cobra.Command {
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Restore a repository from a backup made before a history rewrite",
+		Long: `Verifies the backup with git fsck and restores its refs and objects into
+the repository it was made from, or with --to into another directory. All
+refs are replaced in one atomic transaction, refs created after the backup
+are removed and the working tree is reset to the restored HEAD. Remotes
+removed by git-filter-repo are added back.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backupPath, err := filepath.Abs(args[0])
+			if err != nil {
+				return errorfamily.WrapRejectionf(err, "git_history.backup", "invalid backup %q", args[0])
+			}
+
+			backup, err := cleaner.NewGitHistoryBackupIndex("").Find(backupPath)
+			if err != nil {
+				if !errors.Is(err, cleaner.ErrBackupNotIndexed) || target == "" {
+					return errorfamily.WrapRejectionf(err, "git_history.backup",
+						"cannot restore %s; pass --to for backups missing from the index", backupPath)
+				}
+
+				backup = domain.GitHistoryBackup{Path: backupPath} //nolint:exhaustruct
+			}
+
+			if target != "" {
+				if target, err = filepath.Abs(target); err != nil {
+					return errorfamily.WrapRejectionf(err, "git_history.to", "invalid --to %q", target)
+				}
+			}
+
+			return runGitHistoryRestore(cmd, backup, target, *opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "to", "", "Restore into this directory instead of the original repository")
+
+	return cmd
+}
+
+// runGitHistoryRestore verifies the backup and, unless in dry-run mode or
+// declined, restores it into target.
+func runGitHistoryRestore(cmd *cobra.Command, backup domain.GitHistoryBackup, target string, opts gitHistoryOptions) error {
+	ctx := cmd.Context()
+	destination := cmp.Or(target, backup.RepoPath)
+
+	fmt.Print("🔒 Verifying backup with git fsck... ")
+
+	if err := cleaner.VerifyGitHistoryBackup(ctx, backup.Path); err != nil {
+		fmt.Println(WarningStyle.Render("FAILED"))
+
+		return errorfamily.WrapInfrastructure(err, "git_history.restore", "backup "+backup.Path+" is not restorable")
+	}
+
+	fmt.Println(SuccessStyle.Render("PASSED"))
+
+	if opts.dryRun {
+		fmt.Println(InfoStyle.Render("🔍 DRY RUN MODE - would restore " + backup.Path + " into " + destination))
+
+		return nil
+	}
+
+	// Restoring into an existing repository replaces its history.
+	if entries, _ := os.ReadDir(destination); !opts.force && len(entries) > 0 {
+		confirmed := false
+
+		err := huh.NewConfirm().
+			Title("Replace the history of " + destination + "?").
+			Description("All refs are reset to the backup from " + format.DateTime(backup.CreatedAt) +
+				"; commits made since are no longer referenced.").
+			Value(&confirmed).
+			Run()
+		if err != nil || !confirmed {
+			fmt.Println("❌ Cancelled. No changes made.")
+
+			return nil
+		}
+	}
+
+	if err := cleaner.RestoreGitHistoryBackup(ctx, backup, destination); err != nil {
+		return errorfamily.WrapInfrastructure(err, "git_history.restore", "failed to restore "+backup.Path)
+	}
+
+	fmt.Println(SuccessStyle.Render("✅ Restored " + backup.Path + " into " + destination))
+
+	return nil
+}
+
+// filterBackupsByRepo returns the backups of the repository at repo.
+func filterBackupsByRepo(backups []domain.GitHistoryBackup, repo string) []domain.GitHistoryBackup {
+	var filtered []domain.GitHistoryBackup
+
+	for _, b := range backups {
+		if b.RepoPath == repo {
+			filtered = append(filtered, b)
+		}
+	}
+
+	return filtered
+}
+
+// printGitHistoryBackups prints the backups as a table.
+func printGitHistoryBackups(entries []gitHistoryBackupEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No git-history backups found")
+
+		return
+	}
+
+	rows := make([][]string, 0, len(entries))
+
+	var total int64
+
+	for _, e := range entries {
+		size := format.Bytes(e.SizeBytes)
+		if e.Missing {
+			size = "missing"
+		}
+
+		total += e.SizeBytes
+		rows = append(rows, []string{e.RepoPath, format.DateTime(e.CreatedAt), e.Action.String(), size, e.Path})
+	}
+
+	fmt.Println(newResultsTable(rows...).Headers("Repository", "Created", "Action", "Size", "Backup"))
+	fmt.Printf("💡 %d backup(s), %s\n", len(entries), format.Bytes(total))
+}
+
+// printGitHistoryBackupsJSON prints the backups as JSON.
+func printGitHistoryBackupsJSON(entries []gitHistoryBackupEntry) error {
+	report := struct {
+		GeneratedAt time.Time               `json:"generated_at"`
+		Backups     []gitHistoryBackupEntry `json:"backups"`
+	}{GeneratedAt: time.Now(), Backups: entries}
+
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode git-history backups: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// gitHistoryDirsDefaultTop is how many directories are listed by default.
+	gitHistoryDirsDefaultTop = 20
+	// gitHistoryDirsTopBlobs is how many of the largest blobs below the
+	// remove paths are listed before a rewrite.
+	gitHistoryDirsTopBlobs = 5
+)
+
+// gitHistoryDirsReport is the result of git-history dirs.
+type gitHistoryDirsReport struct {
+	RepoPath    string                       `json:"repo_path"`
+	GeneratedAt time.Time                    `json:"generated_at"`
+	Depth       int                          `json:"depth"`
+	Directories []domain.GitHistoryDirectory `json:"directories"`
+}
+
+// newGitHistoryDirsCommand creates the git-history dirs subcommand.
+func newGitHistoryDirsCommand(opts *gitHistoryOptions) *cobra.Command {
+	var (
+		depth   int
+		top     int
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dirs [path]",
+		Short: "Rank the directories of a repository by their size in history",
+		Long: `Adds up every blob ever committed below each directory, down to --depth
+levels, whatever the size of the individual blobs, and lists the largest
+directories. Directories no longer in HEAD still weigh on every clone;
+remove them from history with git-history --remove-path <dir>/.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			repoPath, err := filepath.Abs(path)
+			if err != nil {
+				return errorfamily.WrapRejectionf(err, "git_history.path", "invalid path %q", path)
+			}
+
+			c := cleaner.NewGitHistoryCleaner(
+				cleaner.WithGitHistoryRepoPath(repoPath),
+				cleaner.WithGitHistoryVerbose(opts.verbose),
+			)
+
+			dirs, err := c.ScanDirectories(cmd.Context(), depth)
+			if err != nil {
+				return errorfamily.WrapRejectionf(err, "git_history.path", "cannot scan the history of %s", repoPath)
+			}
+
+			if top > 0 && len(dirs) > top {
+				dirs = dirs[:top]
+			}
+
+			if jsonOut {
+				return printGitHistoryDirsJSON(gitHistoryDirsReport{
+					RepoPath:    repoPath,
+					GeneratedAt: time.Now(),
+					Depth:       depth,
+					Directories: dirs,
+				})
+			}
+
+			printGitHistoryDirs(repoPath, dirs)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", cleaner.GitHistoryDefaultDirectoryDepth, "Directory levels to aggregate over")
+	cmd.Flags().IntVar(&top, "top", gitHistoryDirsDefaultTop, "Number of directories to list (0 = all)")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format")
+
+	return cmd
+}
+
+// printGitHistoryDirs prints the directories as a table.
+func printGitHistoryDirs(repoPath string, dirs []domain.GitHistoryDirectory) {
+	fmt.Println(TitleStyle.Render("📁 History size by directory: " + repoPath))
+
+	if len(dirs) == 0 {
+		fmt.Println("No directories in history")
+
+		return
+	}
+
+	rows := make([][]string, 0, len(dirs))
+
+	for _, d := range dirs {
+		status := "in HEAD"
+		if !d.InHEAD {
+			status = "deleted in HEAD"
+		}
+
+		rows = append(rows, []string{
+			d.Prefix,
+			format.Bytes(d.SizeBytes),
+			strconv.Itoa(d.Blobs),
+			strconv.Itoa(d.Paths),
+			status,
+		})
+	}
+
+	fmt.Println(newResultsTable(rows...).Headers("Directory", "History size", "Blobs", "Paths", "Status"))
+	fmt.Println("💡 Remove a directory from all history with: clean-wizard git-history --remove-path <dir>/")
+}
+
+// printGitHistoryDirsJSON prints the directories as JSON.
+func printGitHistoryDirsJSON(report gitHistoryDirsReport) error {
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode git-history directories: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"charm.land/huh/v2"
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+// gitHistoryScanTopBlobs is how many of the largest blobs are listed per
+// repository in the ranked report.
+const gitHistoryScanTopBlobs = 3
+
+// gitHistoryBatchReport is the result of git-history scan --root.
+type gitHistoryBatchReport struct {
+	Root         string                        `json:"root"`
+	GeneratedAt  time.Time                     `json:"generated_at"`
+	Repositories []domain.GitHistoryRepoReport `json:"repositories"`
+}
+
+// newGitHistoryScanCommand creates the git-history scan subcommand.
+func newGitHistoryScanCommand(opts *gitHistoryOptions) *cobra.Command {
+	var (
+		root        string
+		depth       int
+		concurrency int
+		jsonOut     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Rank the history bloat of every repository under a directory",
+		Long: `Finds every git repository under --root up to --depth levels deep, scans
+their histories concurrently and ranks them by history bloat: the bytes
+of binaries deleted in HEAD that a rewrite reclaims, and the binaries
+still in HEAD that are candidates for Git LFS. Afterwards, pick the
+repositories to run the cleanup wizard on.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGitHistoryScan(cmd.Context(), root, depth, concurrency, jsonOut, *opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&root, "root", ".", "Directory to search for repositories")
+	cmd.Flags().IntVar(&depth, "depth", cleaner.GitHistoryDefaultMaxSearchDepth, "Maximum directory depth to search")
+	cmd.Flags().IntVar(&concurrency, "concurrency", cleaner.GitHistoryDefaultScanConcurrency, "Repositories scanned at once")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Print the ranked report as JSON and exit")
+
+	return cmd
+}
+
+// runGitHistoryScan scans the repositories under root and lets the user pick
+// the ones to clean.
+func runGitHistoryScan(
+	ctx context.Context,
+	root string,
+	depth, concurrency int,
+	jsonOut bool,
+	opts gitHistoryOptions,
+) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return errorfamily.WrapRejectionf(err, "git_history.root", "invalid root %q", root)
+	}
+
+	repos, err := findRepositoriesUnder(root, depth)
+	if err != nil {
+		return errorfamily.WrapRejectionf(err, "git_history.root", "cannot search %s for repositories", root)
+	}
+
+	if len(repos) == 0 {
+		return fmt.Errorf("no git repositories found in %s: %w", root, ErrNoGitRepositoriesFound)
+	}
+
+	if !jsonOut {
+		fmt.Println(TitleStyle.Render("🔮 Git History Bloat"))
+		fmt.Printf("📁 Scanning %d repositories under %s...\n\n", len(repos), root)
+	}
+
+	reports := cleaner.ScanGitRepositories(ctx, repos, concurrency,
+		cleaner.WithMinSizeMB(opts.minSizeMB),
+		cleaner.WithMaxFiles(opts.maxFiles),
+		cleaner.WithVerbose(opts.verbose),
+	)
+
+	if jsonOut {
+		return printGitHistoryBatchJSON(gitHistoryBatchReport{Root: root, GeneratedAt: time.Now(), Repositories: reports})
+	}
+
+	printGitHistoryBatch(reports)
+
+	picked, err := pickRepositoriesToRewrite(reports, opts.force)
+	if err != nil {
+		return err
+	}
+
+	processRepositories(ctx, picked, opts)
+
+	return nil
+}
+
+// findRepositoriesUnder returns root itself if it is a repository, otherwise
+// the repositories below it.
+func findRepositoriesUnder(root string, depth int) ([]string, error) {
+	if info, err := os.Stat(filepath.Join(root, ".git")); err == nil && info.IsDir() {
+		return []string{root}, nil
+	}
+
+	return cleaner.FindGitRepositories(root, depth)
+}
+
+// printGitHistoryBatch prints the ranked report with the largest blobs of
+// each repository.
+func printGitHistoryBatch(reports []domain.GitHistoryRepoReport) {
+	rows := make([][]string, 0, len(reports))
+
+	for i, r := range reports {
+		if r.Error != "" {
+			rows = append(rows, []string{"-", r.RepoPath, "", "", "", "❌ " + r.Error})
+
+			continue
+		}
+
+		largest := ""
+		if r.HasFindings() {
+			largest = r.Files[0].Path + " (" + format.Bytes(r.Files[0].SizeBytes) + ")"
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			r.RepoPath,
+			format.Bytes(r.RepoSize),
+			format.Bytes(r.ReclaimableBytes),
+			fmt.Sprintf("%d (%s)", len(r.LFSCandidates), format.Bytes(r.LFSCandidateBytes)),
+			largest,
+		})
+	}
+
+	fmt.Println(newResultsTable(rows...).Headers("#", "Repository", ".git", "Reclaimable", "LFS candidates", "Largest blob"))
+
+	for _, r := range reports {
+		if len(r.Files) < 2 {
+			continue
+		}
+
+		fmt.Println(MutedStyle.Render(r.RepoPath + ":"))
+
+		for _, f := range r.Files[:min(len(r.Files), gitHistoryScanTopBlobs)] {
+			status := "in HEAD"
+			if f.IsDeleted {
+				status = "deleted in HEAD"
+			}
+
+			fmt.Printf("   %10s  %s [%s]\n", format.Bytes(f.SizeBytes), f.Path, status)
+		}
+	}
+
+	fmt.Println()
+}
+
+// pickRepositoriesToRewrite asks which repositories with findings to clean;
+// in force mode all of them are picked.
+func pickRepositoriesToRewrite(reports []domain.GitHistoryRepoReport, force bool) ([]string, error) {
+	var candidates []string
+
+	options := make([]huh.Option[string], 0, len(reports))
+
+	for _, r := range reports {
+		if r.Error != "" || !r.HasFindings() {
+			continue
+		}
+
+		candidates = append(candidates, r.RepoPath)
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%s reclaimable, %d LFS candidates)",
+			r.RepoPath, format.Bytes(r.ReclaimableBytes), len(r.LFSCandidates)), r.RepoPath))
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println(SuccessStyle.Render("No large binaries found in any history!"))
+
+		return nil, nil
+	}
+
+	if force {
+		return candidates, nil
+	}
+
+	var picked []string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Select repositories to rewrite").
+				Description("Ranked by reclaimable bytes. Space to select, Enter to confirm").
+				Options(options...).
+				Value(&picked),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return nil, fmt.Errorf("repository selection form failed: %w", err)
+	}
+
+	return picked, nil
+}
+
+// printGitHistoryBatchJSON prints the ranked report as JSON.
+func printGitHistoryBatchJSON(report gitHistoryBatchReport) error {
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode git history report: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
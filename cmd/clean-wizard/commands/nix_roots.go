@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/spf13/cobra"
+)
+
+// NewNixRootsCommand creates a command that audits the indirect Nix GC roots.
+func NewNixRootsCommand() *cobra.Command {
+	var (
+		jsonOut   bool
+		olderThan string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "nix-roots",
+		Short: "Audit the indirect Nix GC roots and what they pin",
+		Long: `Lists the indirect GC roots under /nix/var/nix/gcroots/auto - result links,
+nix-direnv profiles and devenv roots - with where they point, the project
+they belong to, when it was last used and the store space each root keeps
+alive. Roots of projects in the trash or untouched for longer than
+--older-than are marked for removal by the nix-gcroots cleaner.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			threshold, err := domain.ParseCustomDuration(olderThan)
+			if err != nil {
+				return errorfamily.WrapRejectionf(err, "nix_roots.older_than", "invalid --older-than %q", olderThan)
+			}
+
+			gc := cleaner.NewNixGCRootsCleaner(false, true, threshold)
+
+			audits, err := gc.Audit(cmd.Context())
+			if err != nil {
+				return errorfamily.WrapInfrastructure(err, "nix_roots.audit", "failed to audit Nix GC roots")
+			}
+
+			if jsonOut {
+				return printNixRootsJSON(audits)
+			}
+
+			printNixRoots(audits)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format")
+	cmd.Flags().StringVar(&olderThan, "older-than", "30d", "Age after which a project's roots count as stale")
+
+	return cmd
+}
+
+// printNixRoots prints the audit, one root per line.
+func printNixRoots(audits []cleaner.NixRootAudit) {
+	if len(audits) == 0 {
+		fmt.Println("No indirect Nix GC roots found")
+
+		return
+	}
+
+	var selected, pinned int64
+
+	fmt.Println(HeaderStyle.Render("🔗 Indirect Nix GC roots"))
+	fmt.Println()
+
+	for _, a := range audits {
+		mark := "  "
+		if a.Selected() {
+			mark = "🗑️"
+			selected++
+			pinned += a.PinnedBytes
+		}
+
+		fmt.Printf("%s %-9s %-7s %s\n", mark, a.Status, a.Kind, a.Link)
+
+		if a.Target != "" {
+			fmt.Printf("     → %s\n", a.Target)
+		}
+
+		fmt.Printf("     closure %s, pinned %s", format.Bytes(a.ClosureBytes), format.Bytes(a.PinnedBytes))
+
+		if !a.LastUsed.IsZero() {
+			fmt.Printf(", last used %s", format.Date(a.LastUsed))
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println()
+	fmt.Printf("💡 %d of %d roots selected, pinning %s\n", selected, len(audits), format.Bytes(pinned))
+}
+
+// printNixRootsJSON prints the audit as JSON.
+func printNixRootsJSON(audits []cleaner.NixRootAudit) error {
+	report := struct {
+		GeneratedAt time.Time              `json:"generated_at"`
+		Roots       []cleaner.NixRootAudit `json:"roots"`
+	}{GeneratedAt: time.Now(), Roots: audits}
+
+	data, err := json.Marshal(report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode Nix GC root audit: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
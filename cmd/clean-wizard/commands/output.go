@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/execution"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// outputUsage is the help text of the --output flag of scan and clean.
+func outputUsage() string {
+	return "Output format: " + strings.Join(format.Names(), ", ") +
+		" (default: interactive display; any format makes the command non-interactive)"
+}
+
+// resolveOutput returns the output format selected by --output, with --json
+// as shorthand for --output json. "" selects the interactive display.
+func resolveOutput(output string, jsonOut bool) string {
+	if output == "" && jsonOut {
+		return "json"
+	}
+
+	return output
+}
+
+// reportWriter writes a scan or clean report to stdout in the selected
+// output format, streaming cleaner results as they finish when the
+// formatter supports it.
+type reportWriter struct {
+	formatter format.Formatter
+	streamer  format.Streamer
+	w         io.Writer
+	err       error
+}
+
+// newReportWriter returns the writer for the named output format.
+func newReportWriter(name string) (*reportWriter, error) {
+	f, err := format.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rw := &reportWriter{formatter: f, streamer: nil, w: os.Stdout, err: nil}
+	rw.streamer, _ = f.(format.Streamer)
+
+	return rw, nil
+}
+
+// start opens the stream before the first cleaner runs.
+func (rw *reportWriter) start(command format.Command, dryRun bool) {
+	if rw.streamer != nil {
+		rw.record(rw.streamer.Start(rw.w, command, dryRun))
+	}
+}
+
+// observer returns the run option streaming each finished cleaner; it does
+// nothing for formatters that render the complete report only.
+func (rw *reportWriter) observer() execution.RunOption {
+	if rw.streamer == nil {
+		return execution.WithStepObserver(nil)
+	}
+
+	return execution.WithStepObserver(func(step execution.StepResult) {
+		rw.record(rw.streamer.Cleaner(rw.w, stepReport(step)))
+	})
+}
+
+// finish writes the complete report, or the rest of the stream.
+func (rw *reportWriter) finish(report *format.Report) error {
+	if rw.streamer != nil {
+		rw.record(rw.streamer.Finish(rw.w, report))
+	} else {
+		rw.record(rw.formatter.Render(rw.w, report))
+	}
+
+	if rw.err != nil {
+		return errorfamily.WrapCorruption(rw.err, "output.write", "failed to write "+rw.formatter.Name()+" output")
+	}
+
+	return nil
+}
+
+// record keeps the first error of the writer.
+func (rw *reportWriter) record(err error) {
+	if rw.err == nil {
+		rw.err = err
+	}
+}
+
+// buildReport converts a workflow result into the report the output
+// formats render.
+func buildReport(
+	command format.Command,
+	dryRun bool,
+	wr *execution.WorkflowResult,
+	snapshots []cleaner.SnapshotWarning,
+) *format.Report {
+	cleaners := make([]format.CleanerReport, 0, len(wr.Steps))
+	for _, step := range wr.Steps {
+		cleaners = append(cleaners, stepReport(step))
+	}
+
+	report := format.NewReport(command, dryRun, wr.Duration, cleaners)
+	report.Interrupted = wr.Interrupted
+	report.Pending = wr.Pending
+	report.Snapshots = snapshotReports(snapshots)
+
+	return report
+}
+
+// stepReport converts one step result into a cleaner report.
+func stepReport(step execution.StepResult) format.CleanerReport {
+	cr := format.NewCleanerReport(step.Name, step.Clean, step.Duration, step.Err)
+	cr.ScanItems = step.Items
+
+	return cr
+}
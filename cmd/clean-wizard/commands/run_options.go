@@ -3,13 +3,24 @@ package commands
 import (
 	"fmt"
 
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
 	"github.com/LarsArtmann/clean-wizard/internal/execution"
 )
 
+// classLimitUsage is the help text of the --class-limit flag.
+const classLimitUsage = "Max cleaners per resource class running concurrently, " +
+	"e.g. disk_io=1,network=4,cpu=2 (0=unlimited)"
+
 // buildRunOptions assembles execution.RunOption slice from CLI flags.
 // Shared between clean and scan commands to avoid duplicating the
-// verbose/concurrency/retry flag handling.
-func buildRunOptions(verbose bool, concurrency int, retries int, retryProfile string) ([]execution.RunOption, error) {
+// verbose/concurrency/retry/class-limit flag handling.
+func buildRunOptions(
+	verbose bool,
+	concurrency int,
+	retries int,
+	retryProfile string,
+	classLimits map[string]int,
+) ([]execution.RunOption, error) {
 	var opts []execution.RunOption
 
 	if verbose {
@@ -34,5 +45,34 @@ func buildRunOptions(verbose bool, concurrency int, retries int, retryProfile st
 		opts = append(opts, execution.WithRetry(execution.RetryConfigFromAttempts(retries)))
 	}
 
+	if len(classLimits) > 0 {
+		limits, err := parseClassLimits(classLimits)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, execution.WithClassLimits(limits))
+	}
+
 	return opts, nil
 }
+
+// parseClassLimits converts --class-limit entries to per-class limits.
+func parseClassLimits(raw map[string]int) (map[domain.ResourceClass]int, error) {
+	limits := make(map[domain.ResourceClass]int, len(raw))
+
+	for name, limit := range raw {
+		class, err := domain.ParseResourceClass(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --class-limit: %w", err)
+		}
+
+		if limit < 0 {
+			return nil, fmt.Errorf("invalid --class-limit %s=%d: must be >= 0", name, limit)
+		}
+
+		limits[class] = limit
+	}
+
+	return limits, nil
+}
@@ -2,15 +2,17 @@ package commands
 
 import (
 	"context"
-	"encoding/json/jsontext"
-	"encoding/json/v2"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
 	"github.com/LarsArtmann/clean-wizard/internal/di"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
 	"github.com/LarsArtmann/clean-wizard/internal/execution"
 	"github.com/LarsArtmann/clean-wizard/internal/format"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
 	errorfamily "github.com/larsartmann/go-error-family"
 	"github.com/spf13/cobra"
 )
@@ -21,10 +23,13 @@ func NewScanCommand() *cobra.Command {
 		verbose      bool
 		profile      string
 		jsonOut      bool
+		output       string
 		configPath   string
 		retries      int
 		retryProfile string
 		concurrency  int
+		classLimits  map[string]int
+		full         bool
 	)
 
 	cmd := &cobra.Command{
@@ -32,18 +37,24 @@ func NewScanCommand() *cobra.Command {
 		Short: "Scan for cleanable items",
 		Long:  `Scan your system for cleanable items and show size estimates.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runScanCommand(verbose, profile, jsonOut, configPath, retries, retryProfile, concurrency)
+			return runScanCommand(
+				verbose, profile, resolveOutput(output, jsonOut), configPath, retries, retryProfile, concurrency, classLimits, full,
+			)
 		},
 	}
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed scan information")
 	cmd.Flags().StringVarP(&profile, "profile", "p", "", "Filter results by profile")
-	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format")
+	cmd.Flags().StringVarP(&output, "output", "o", "", outputUsage())
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output in JSON format (shorthand for --output json)")
 	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file")
 	cmd.Flags().IntVar(&retries, "retries", 3, "Number of retry attempts per scanner (0=disabled)")
 	cmd.Flags().
 		StringVar(&retryProfile, "retry-profile", "", "Retry strategy preset: default, aggressive, conservative, or none (overrides --retries)")
 	cmd.Flags().IntVarP(&concurrency, "concurrency", "C", 0, "Max scanners running concurrently (0=unlimited)")
+	cmd.Flags().
+		StringToIntVar(&classLimits, "class-limit", nil, classLimitUsage)
+	cmd.Flags().BoolVar(&full, "full", false, "Ignore the size index and re-read every directory")
 
 	return cmd
 }
@@ -70,16 +81,28 @@ func printNixStoreSize(ctx context.Context, registry *cleaner.Registry) {
 func runScanCommand(
 	verbose bool,
 	profile string,
-	jsonOutput bool,
+	output string,
 	configPath string,
 	retries int,
 	retryProfile string,
 	concurrency int,
+	classLimits map[string]int,
+	full bool,
 ) error {
 	ctx := context.Background()
+	machine := output != ""
 
-	if profile != "" {
-		fmt.Printf("⚠️  Warning: --profile %q is not yet supported for scan; showing all available cleaners\n", profile)
+	var report *reportWriter
+
+	if machine {
+		var err error
+		if report, err = newReportWriter(output); err != nil {
+			return err
+		}
+	}
+
+	if profile != "" && !machine {
+		fmt.Printf("⚠️  Warning: --profile %q only applies its settings for scan; showing all available cleaners\n", profile)
 	}
 
 	cfg, err := loadConfigFromPath(configPath)
@@ -100,14 +123,20 @@ func runScanCommand(
 		return errorfamily.WrapRejection(err, "scan.di_resolve", "failed to resolve cleaner registry from DI")
 	}
 
-	if !jsonOutput {
+	if profile != "" {
+		if err := applyProfileSettings(profile, cfg, registry); err != nil {
+			return errorfamily.WrapRejectionf(err, "scan.profile_settings", "profile=%v", profile)
+		}
+	}
+
+	if !machine {
 		fmt.Println(TitleStyle.Render("🔍 Scanning system for cleanable items..."))
 		fmt.Println()
 	}
 
 	availableCleaners := getAvailableConfigs(ctx, registry)
 
-	if len(availableCleaners) == 0 {
+	if len(availableCleaners) == 0 && !machine {
 		fmt.Println("ℹ️  No cleanable items found on this system.")
 		fmt.Println(
 			"   Install package managers (Nix, Homebrew, Docker, etc.) to see cleaning options.",
@@ -116,32 +145,45 @@ func runScanCommand(
 		return nil
 	}
 
-	if !jsonOutput {
+	if !machine {
 		fmt.Printf("✅ Found %d available cleaner(s)\n\n", len(availableCleaners))
 	}
 
 	selectedNames := cleanerConfigsToNames(availableCleaners)
 
-	runOpts, err := buildRunOptions(verbose, concurrency, retries, retryProfile)
+	runOpts, err := buildRunOptions(verbose, concurrency, retries, retryProfile, classLimits)
 	if err != nil {
 		return errorfamily.WrapRejection(err, "scan.invalid_options", "invalid run options")
 	}
 
+	if machine {
+		report.start(format.CommandScan, false)
+		runOpts = append(runOpts, report.observer())
+	}
+
+	finishIndex := attachSizeIndex(full, verbose, machine)
 	wr, err := execution.RunScans(ctx, registry, selectedNames, runOpts...)
+
+	finishIndex()
+
 	if err != nil {
 		return fmt.Errorf("scan workflow execution failed: %w", err)
 	}
 
-	scanResults := buildScanResults(wr, availableCleaners)
-
-	if jsonOutput {
-		totalCleanable, totalItems := computeScanTotals(scanResults)
-		outputScanJSON(scanResults, totalCleanable, totalItems)
+	snapshots := scanSnapshotWarnings(ctx, wr)
 
-		return nil
+	if machine {
+		return report.finish(buildReport(format.CommandScan, false, wr, snapshots))
 	}
 
+	scanResults := buildScanResults(wr, availableCleaners)
+
 	printScanSummary(ctx, registry, scanResults)
+	printSnapshotWarnings(snapshots)
+
+	if verbose {
+		printExclusions(wr.Excluded)
+	}
 
 	return nil
 }
@@ -159,6 +201,7 @@ func computeScanTotals(results []ScanResult) (totalCleanable uint64, totalItems
 
 func printScanSummary(ctx context.Context, registry *cleaner.Registry, scanResults []ScanResult) {
 	printScanTable(scanResults, false)
+	printNixProfiles(scanResults)
 
 	totalCleanable, totalItems := computeScanTotals(scanResults)
 
@@ -181,6 +224,72 @@ func printScanSummary(ctx context.Context, registry *cleaner.Registry, scanResul
 	}
 }
 
+// printNixProfiles lists the Nix generations of each profile, how many of
+// them the retention policy removes and what that frees.
+func printNixProfiles(results []ScanResult) {
+	type profileSummary struct {
+		generations, removed int
+		bytes                int64
+	}
+
+	var profiles []string
+
+	summaries := make(map[string]*profileSummary)
+
+	for _, r := range results {
+		for _, item := range r.Items {
+			profile, ok := cleaner.NixGenerationProfile(item.Path)
+			if item.ScanType != domain.ScanTypeNixStore || !ok {
+				continue
+			}
+
+			summary, seen := summaries[profile]
+			if !seen {
+				summary = &profileSummary{} //nolint:exhaustruct
+				summaries[profile] = summary
+				profiles = append(profiles, profile)
+			}
+
+			summary.generations++
+
+			if slices.ContainsFunc(item.Reasons, func(reason domain.SelectionReason) bool {
+				return strings.HasPrefix(reason.Message, "removed:")
+			}) {
+				summary.removed++
+				summary.bytes += item.DiskUsage()
+			}
+		}
+	}
+
+	if len(profiles) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("❄️  Nix generations by profile:")
+
+	for _, profile := range profiles {
+		s := summaries[profile]
+		fmt.Printf("   • %s: %d generations, %d removed (%s)\n",
+			profile, s.generations, s.removed, format.Bytes(s.bytes))
+	}
+}
+
+// printExclusions lists the paths .cleanwizardignore rules excluded and the
+// rule that excluded each.
+func printExclusions(excluded []ignore.Exclusion) {
+	if len(excluded) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("🙈 Excluded by ignore files (%d):\n", len(excluded))
+
+	for _, e := range excluded {
+		fmt.Printf("   • %s\n     %s\n", e.Path, e.Rule)
+	}
+}
+
 func cleanerConfigsToNames(configs []CleanerConfig) []string {
 	names := make([]string, len(configs))
 	for i, c := range configs {
@@ -210,6 +319,7 @@ func buildScanResults(wr *execution.WorkflowResult, available []CleanerConfig) [
 		if step, ok := stepByName[regName]; ok && step.Err == nil {
 			sr.ItemsCount = step.Clean.ItemsRemoved
 			sr.BytesCleanable = step.Clean.FreedBytes
+			sr.Items = step.Items
 		}
 
 		results = append(results, sr)
@@ -226,6 +336,8 @@ type ScanResult struct {
 	BytesCleanable uint64
 	Description    string
 	Icon           string
+	// Items are the selected items with their selection reasons.
+	Items []domain.ScanItem
 }
 
 func getRegistryName(cleanerType CleanerType) string {
@@ -233,7 +345,8 @@ func getRegistryName(cleanerType CleanerType) string {
 		return m.RegistryName
 	}
 
-	return ""
+	// Custom cleaners use their registry name as CleanerType.
+	return string(cleanerType)
 }
 
 // printScanTable prints scan results as a formatted table.
@@ -269,50 +382,3 @@ func printScanTable(results []ScanResult, _ bool) {
 
 	fmt.Println(t)
 }
-
-// outputScanJSON outputs scan results in JSON format.
-func outputScanJSON(results []ScanResult, totalBytes uint64, totalItems uint) {
-	type scanJSONResult struct {
-		Name      string `json:"name"`
-		Items     uint   `json:"items"`
-		Bytes     uint64 `json:"bytes"`
-		Available bool   `json:"available"`
-	}
-
-	type scanJSONSummary struct {
-		TotalBytes uint64 `json:"totalBytes"`
-		TotalItems uint   `json:"totalItems"`
-	}
-
-	type scanJSONOutput struct {
-		Results []scanJSONResult `json:"results"`
-		Summary scanJSONSummary  `json:"summary"`
-	}
-
-	jsonResults := make([]scanJSONResult, 0, len(results))
-	for _, r := range results {
-		jsonResults = append(jsonResults, scanJSONResult{
-			Name:      r.Name,
-			Items:     r.ItemsCount,
-			Bytes:     r.BytesCleanable,
-			Available: r.Available == CleanerAvailabilityAvailable,
-		})
-	}
-
-	output := scanJSONOutput{
-		Results: jsonResults,
-		Summary: scanJSONSummary{
-			TotalBytes: totalBytes,
-			TotalItems: totalItems,
-		},
-	}
-
-	jsonBytes, err := json.Marshal(output, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
-	if err != nil {
-		fmt.Printf("{\"error\": %q}\n", err.Error())
-
-		return
-	}
-
-	fmt.Println(string(jsonBytes))
-}
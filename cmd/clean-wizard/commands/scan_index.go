@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+)
+
+// attachSizeIndex loads the persistent size index and attaches it to the
+// shared walker for one scan. With full, the index is rebuilt from scratch.
+// A damaged or incompatible index is reported and replaced; it never fails
+// the scan. The returned function detaches and saves the index.
+func attachSizeIndex(full, verbose, quiet bool) func() {
+	path, err := state.Path(fswalk.IndexFileName)
+	if err != nil {
+		return func() {}
+	}
+
+	index := fswalk.NewIndex()
+
+	if !full {
+		loaded, err := fswalk.LoadIndex(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) && !quiet {
+			fmt.Printf("⚠️  Size index unusable, scanning everything: %v\n\n", err)
+		}
+
+		index = loaded
+	}
+
+	fswalk.Shared().UseIndex(index)
+
+	return func() {
+		fswalk.Shared().UseIndex(nil)
+
+		if verbose && !quiet {
+			reused, read := index.Stats()
+			fmt.Printf("\n📇 Size index: %d director(ies) reused, %d read\n", reused, read)
+		}
+
+		if err := index.Save(path); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to save size index: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCommand creates a command that prints the JSON schemas of the
+// machine-readable output formats.
+func NewSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema [report|event]",
+		Short: "Print the JSON schema of the machine-readable output",
+		Long: `Prints the versioned JSON schema of scan and clean output: "report" (the
+default) describes --output json and yaml, "event" one line of --output ndjson.`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: format.SchemaNames,
+		RunE: func(_ *cobra.Command, args []string) error {
+			name := "report"
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			data, err := format.Schema(name)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(string(data))
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/execution"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+)
+
+// scanSnapshotWarnings checks the scanned items for data pinned by snapshots.
+func scanSnapshotWarnings(ctx context.Context, wr *execution.WorkflowResult) []cleaner.SnapshotWarning {
+	var items []domain.ScanItem
+	for _, s := range wr.Succeeded() {
+		items = append(items, s.Items...)
+	}
+
+	return cleaner.NewSnapshotInspector().Inspect(ctx, items)
+}
+
+// cleanSnapshotWarnings checks where the cleaners freed space for data
+// pinned by snapshots. Clean results carry no per-item ages, so each
+// cleaner's claimed bytes are attributed to the first of its affected paths
+// on a snapshotting filesystem and counted as pinned (an upper bound).
+func cleanSnapshotWarnings(
+	ctx context.Context,
+	registry *cleaner.Registry,
+	wr *execution.WorkflowResult,
+) []cleaner.SnapshotWarning {
+	inspector := cleaner.NewSnapshotInspector()
+
+	var items []domain.ScanItem
+
+	for _, s := range wr.Succeeded() {
+		c, ok := registry.Get(s.Name)
+		if !ok || s.Clean.FreedBytes == 0 {
+			continue
+		}
+
+		for _, path := range cleaner.AffectedPaths(s.Name, c) {
+			if fs, ok := inspector.Filesystem(path); ok && fs.IsSnapshotting() {
+				items = append(items, domain.ScanItem{ //nolint:exhaustruct
+					Path: path,
+					Size: int64(s.Clean.FreedBytes),
+				})
+
+				break
+			}
+		}
+	}
+
+	return inspector.Inspect(ctx, items)
+}
+
+// printSnapshotWarnings explains why space on snapshotting filesystems is
+// not reclaimed.
+func printSnapshotWarnings(warnings []cleaner.SnapshotWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(WarningStyle.Render("⚠️  Snapshots keep deleted data on disk:"))
+
+	for _, w := range warnings {
+		latest := ""
+		if !w.Snapshots.Latest.IsZero() {
+			latest = ", latest " + w.Snapshots.Latest.Format("2006-01-02 15:04")
+		}
+
+		fmt.Printf("   • %s (%s): %d snapshot(s)%s\n",
+			w.Filesystem.MountPoint, w.Filesystem.Type, w.Snapshots.Count, latest)
+		fmt.Printf("     ~%s of %s reported here stays referenced until the snapshots are deleted\n",
+			format.Bytes(w.PinnedBytes), format.Bytes(w.ReportedBytes))
+
+		if w.Snapshots.UsedBySnapshots > 0 {
+			fmt.Printf("     snapshots already hold %s that only they reference\n",
+				format.Bytes(w.Snapshots.UsedBySnapshots))
+		}
+	}
+}
+
+// snapshotReports converts warnings for the output formats.
+func snapshotReports(warnings []cleaner.SnapshotWarning) []format.Snapshot {
+	out := make([]format.Snapshot, 0, len(warnings))
+
+	for _, w := range warnings {
+		out = append(out, format.Snapshot{
+			MountPoint:      w.Filesystem.MountPoint,
+			Filesystem:      w.Filesystem.Type,
+			Snapshots:       w.Snapshots.Count,
+			LatestSnapshot:  w.Snapshots.Latest,
+			Items:           w.Items,
+			ReportedBytes:   w.ReportedBytes,
+			PinnedBytes:     w.PinnedBytes,
+			UsedBySnapshots: max(w.Snapshots.UsedBySnapshots, 0),
+		})
+	}
+
+	return out
+}
@@ -21,6 +21,10 @@ func main() {
 	rootCmd.AddCommand(commands.NewProfileCommand())
 	rootCmd.AddCommand(commands.NewConfigCommand())
 	rootCmd.AddCommand(commands.NewGitHistoryCommand())
+	rootCmd.AddCommand(commands.NewExplainCommand())
+	rootCmd.AddCommand(commands.NewDoctorCommand())
+	rootCmd.AddCommand(commands.NewNixRootsCommand())
+	rootCmd.AddCommand(commands.NewSchemaCommand())
 
 	info := version.Get()
 
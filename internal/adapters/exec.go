@@ -2,7 +2,9 @@ package adapters
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -47,3 +49,42 @@ func (n *NixAdapter) execWithTimeout(ctx context.Context, name string, arg ...st
 
 	return exec.CommandContext(timeoutCtx, name, arg...)
 }
+
+// runOutput runs a command with the configured timeout and returns its
+// standard output. Unlike execWithTimeout, the timeout stays in force until
+// the command has finished.
+func (n *NixAdapter) runOutput(ctx context.Context, name string, arg ...string) ([]byte, error) {
+	timeout := n.timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(timeoutCtx, name, arg...).Output()
+	if err != nil {
+		return output, fmt.Errorf("%s %s: %w", name, strings.Join(arg, " "), err)
+	}
+
+	return output, nil
+}
+
+// runCombined is runOutput for commands reporting on standard error, such
+// as nix store gc; it returns standard output and error together.
+func (n *NixAdapter) runCombined(ctx context.Context, name string, arg ...string) ([]byte, error) {
+	timeout := n.timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(timeoutCtx, name, arg...).CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("%s %s: %w", name, strings.Join(arg, " "), err)
+	}
+
+	return output, nil
+}
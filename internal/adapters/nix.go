@@ -5,29 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/conversions"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/logger"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
 )
 
 const (
-	// NixDryRunStoreSizeGB is the estimated Nix store size for dry-run mode.
-	NixDryRunStoreSizeGB = 50
-	// NixGCPostDelay is the delay after garbage collection to allow async operations.
-	NixGCPostDelay = 500 * time.Millisecond
-	// NixDryRunGenerationSizeMB is the estimated size per generation in dry-run mode.
-	NixDryRunGenerationSizeMB = 50
-	// NixDryRunGCSizeMB is the estimated size freed from GC in dry-run mode.
-	NixDryRunGCSizeMB = 100
-
-	// Byte conversion constants.
-	bytesPerMB = 1024 * 1024
-	bytesPerGB = 1024 * 1024 * 1024
-
 	// NixGenerationMinFields is the minimum number of fields expected in generation output.
 	NixGenerationMinFields = 3
 )
@@ -46,14 +35,22 @@ type NixAdapter struct {
 	timeout time.Duration
 	retries int
 	dryRun  bool
+
+	// run runs store queries and returns their standard output.
+	run func(ctx context.Context, name string, arg ...string) ([]byte, error)
+	// extraProfiles are configured profile links Profiles adds.
+	extraProfiles []string
 }
 
 // NewNixAdapter creates Nix adapter with configuration.
 func NewNixAdapter(timeout time.Duration, retries int) *NixAdapter {
-	return &NixAdapter{ //nolint:exhaustruct
+	n := &NixAdapter{ //nolint:exhaustruct
 		timeout: timeout,
 		retries: retries,
 	}
+	n.run = n.runOutput
+
+	return n
 }
 
 // SetDryRun configures dry-run mode for the adapter.
@@ -61,31 +58,45 @@ func (n *NixAdapter) SetDryRun(dryRun bool) {
 	n.dryRun = dryRun
 }
 
-// ListGenerations lists Nix generations.
+// ListGenerations lists the generations of every profile Profiles returns,
+// profile by profile. Without any profile found it lists the default
+// profile nix-env uses.
 // In dry-run mode, still lists real generations but won't actually delete them.
 func (n *NixAdapter) ListGenerations(ctx context.Context) result.Result[[]domain.NixGeneration] {
 	if !n.IsAvailable(ctx) {
 		return result.Err[[]domain.NixGeneration](errors.New("nix not available"))
 	}
 
-	// Use nix-env without --profile to let it use the default user profile
-	cmd := n.execWithTimeout(ctx, "nix-env", "--list-generations")
+	profiles := n.Profiles()
+	if len(profiles) == 0 {
+		return n.listDefaultGenerations(ctx)
+	}
+
+	var generations []domain.NixGeneration
+
+	for _, profile := range profiles {
+		profileGenerations, err := n.ProfileGenerations(ctx, profile)
+		if err != nil {
+			return result.Err[[]domain.NixGeneration](err)
+		}
+
+		generations = append(generations, profileGenerations...)
+	}
 
-	output, err := cmd.Output()
+	return result.Ok(generations)
+}
+
+// listDefaultGenerations lists the generations of the profile nix-env uses
+// without --profile.
+func (n *NixAdapter) listDefaultGenerations(ctx context.Context) result.Result[[]domain.NixGeneration] {
+	output, err := n.run(ctx, "nix-env", "--list-generations")
 	if err != nil {
 		return result.Err[[]domain.NixGeneration](fmt.Errorf("failed to list generations: %w", err))
 	}
 
 	var generations []domain.NixGeneration
 
-	lines := strings.SplitSeq(string(output), "\n")
-
-	for line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
+	for _, line := range storePaths(output) {
 		gen, err := n.ParseGeneration(line)
 		if err != nil {
 			return result.Err[[]domain.NixGeneration](
@@ -99,17 +110,10 @@ func (n *NixAdapter) ListGenerations(ctx context.Context) result.Result[[]domain
 	return result.Ok(generations)
 }
 
-// GetStoreSize returns Nix store size with dry-run isolation.
+// GetStoreSize returns the Nix store size. Measuring is read-only, so
+// dry-run mode measures too.
 func (n *NixAdapter) GetStoreSize(ctx context.Context) result.Result[int64] {
-	// If dry-run, return estimated size without system calls
-	if n.dryRun {
-		return result.Ok(int64(NixDryRunStoreSizeGB * bytesPerGB))
-	}
-
-	// Real system call for production mode
-	cmd := n.execWithTimeout(ctx, "du", "-sb", "/nix/store")
-
-	output, err := cmd.Output()
+	output, err := n.run(ctx, "du", "-sb", "/nix/store")
 	if err != nil {
 		return result.Err[int64](fmt.Errorf("failed to get store size: %w", err))
 	}
@@ -127,49 +131,47 @@ func (n *NixAdapter) GetStoreSize(ctx context.Context) result.Result[int64] {
 	return result.Ok(size)
 }
 
-// CollectGarbage runs garbage collection on the Nix store.
-// Uses the modern "nix store gc" command (Nix 2.4+).
-// In dry-run mode, returns a success result without actually running garbage collection.
+// CollectGarbage runs garbage collection on the Nix store and reports the
+// bytes it freed, as nix store gc (Nix 2.4+) itself counts them, or 0 when
+// its output does not say.
+// In dry-run mode, it reports what a garbage collection would free now: the
+// NAR size of the store paths that are already dead.
 func (n *NixAdapter) CollectGarbage(ctx context.Context) result.Result[domain.CleanResult] {
+	startTime := time.Now()
+
 	if n.dryRun {
-		estimatedFreed := int64(NixDryRunGCSizeMB * bytesPerMB)
-		cleanResult := conversions.NewCleanResultWithTiming(
-			domain.StrategyAggressiveType,
-			1,
-			estimatedFreed,
-			0,
-		)
+		dead, err := n.DeadPaths(ctx)
+		if err != nil {
+			return conversions.ToCleanResultFromError(err)
+		}
 
-		return result.Ok(cleanResult)
-	}
+		sizes, err := n.NarSizes(ctx, dead)
+		if err != nil {
+			return conversions.ToCleanResultFromError(err)
+		}
 
-	startTime := time.Now()
+		reclaim := NixReclaim{AlreadyDead: dead, ByGeneration: nil, NarSize: sizes}
 
-	beforeSize, err := n.getActualStoreSize(ctx)
-	if err != nil {
-		return conversions.ToCleanResultFromError(
-			fmt.Errorf("failed to get pre-gc store size: %w", err),
-		)
+		return result.Ok(conversions.NewCleanResultWithTiming(
+			domain.StrategyDryRunType,
+			1,
+			reclaim.NarBytes(dead),
+			time.Since(startTime),
+		))
 	}
 
-	cmd := n.execWithTimeout(ctx, "nix", "store", "gc")
-
-	err = cmd.Run()
+	output, err := n.runCombined(ctx, "nix", "store", "gc")
 	if err != nil {
 		return conversions.ToCleanResultFromError(fmt.Errorf("failed to run nix store gc: %w", err))
 	}
 
-	time.Sleep(NixGCPostDelay)
-
-	afterSize, err := n.getActualStoreSize(ctx)
-	if err != nil {
-		return conversions.ToCleanResultFromError(
-			fmt.Errorf("failed to get post-gc store size: %w", err),
-		)
+	// The collection succeeded even if its summary line is missing or
+	// worded differently; the freed bytes are then unknown and reported as 0.
+	_, bytesFreed, ok := parseGCFreed(output)
+	if !ok {
+		logger.Warn("nix store gc did not report the space it freed", "output", strings.TrimSpace(string(output)))
 	}
 
-	bytesFreed := max(beforeSize-afterSize, 0)
-
 	cleanResult := conversions.NewCleanResultWithTiming(
 		domain.StrategyAggressiveType,
 		1,
@@ -180,136 +182,66 @@ func (n *NixAdapter) CollectGarbage(ctx context.Context) result.Result[domain.Cl
 	return result.Ok(cleanResult)
 }
 
-// getActualStoreSize helper function to get real store size.
-func (n *NixAdapter) getActualStoreSize(ctx context.Context) (int64, error) {
-	cmd := n.execWithTimeout(ctx, "du", "-sb", "/nix/store")
-
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("failed to get store size: %w", err)
-	}
-
-	fields := strings.Fields(string(output))
-	if len(fields) < 1 {
-		return 0, fmt.Errorf("invalid du output: %s", string(output))
-	}
-
-	size, err := strconv.ParseInt(fields[0], 10, 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse store size: %w", err)
-	}
-
-	return size, nil
-}
-
-// RemoveGeneration removes specific Nix generation.
+// RemoveGeneration removes a generation from its profile. Removing a
+// generation frees no store space by itself; the following garbage
+// collection reports what its paths occupied.
 // In dry-run mode, returns a success result without actually deleting.
 func (n *NixAdapter) RemoveGeneration(
 	ctx context.Context,
-	genID domain.NixGenerationID,
+	gen domain.NixGeneration,
 ) result.Result[domain.CleanResult] {
-	// In dry-run mode, return success without actually removing
 	if n.dryRun {
-		// Return a success result with estimated bytes freed
-		estimatedFreed := int64(NixDryRunGenerationSizeMB * bytesPerMB)
-		cleanResult := conversions.NewCleanResultWithTiming(
-			domain.StrategyConservativeType,
-			1,
-			estimatedFreed,
-			0,
-		)
-
-		return result.Ok(cleanResult)
+		return result.Ok(conversions.NewCleanResultWithTiming(domain.StrategyDryRunType, 1, 0, 0))
 	}
 
 	startTime := time.Now()
 
-	// Get store size before removal
-	beforeSize, err := n.getActualStoreSize(ctx)
-	if err != nil {
-		return conversions.ToCleanResultFromError(
-			fmt.Errorf("failed to get pre-remove store size for generation %d: %w", genID, err),
-		)
+	args := []string{"--delete-generations", strconv.Itoa(int(gen.ID))}
+	if gen.Profile != "" {
+		args = append([]string{"--profile", gen.Profile}, args...)
 	}
 
-	// Remove the specific generation
-	cmd := n.execWithTimeout(ctx, "nix-env", "--delete-generations", strconv.Itoa(int(genID)))
-
-	err = cmd.Run()
-	if err != nil {
+	if _, err := n.run(ctx, "nix-env", args...); err != nil {
 		return conversions.ToCleanResultFromError(
-			fmt.Errorf("failed to remove generation %d: %w", genID, err),
+			fmt.Errorf("failed to remove generation %d: %w", gen.ID, err),
 		)
 	}
 
-	// Get store size after removal
-	afterSize, err := n.getActualStoreSize(ctx)
-	if err != nil {
-		return conversions.ToCleanResultFromError(
-			fmt.Errorf("failed to get post-remove store size for generation %d: %w", genID, err),
-		)
-	}
-
-	bytesFreed := max(beforeSize-afterSize,
-		// Guard against negative values
-		0)
-
 	// Use centralized conversion with proper timing
 	cleanResult := conversions.NewCleanResultWithTiming(
 		domain.StrategyConservativeType,
 		1,
-		bytesFreed,
+		0,
 		time.Since(startTime),
 	)
 
 	return result.Ok(cleanResult)
 }
 
-// ParseGeneration parses generation line from nix-env output.
-// Expected format when using --list-generations (without --profile):
-//
-//	"32   2026-01-12 08:03:14"
-//	"33   2026-01-15 21:14:05   (current)"
+// ParseGeneration parses a generation line of nix-env --list-generations
+// output for the user profile in ~/.local/state/nix/profiles.
 func (n *NixAdapter) ParseGeneration(line string) (domain.NixGeneration, error) {
-	fields := strings.Fields(line)
-	if len(fields) < NixGenerationMinFields {
-		return domain.NixGeneration{}, fmt.Errorf("invalid generation line: %s", line)
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return domain.NixGeneration{}, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	// Parse generation ID from first field
+	return parseGeneration(filepath.Join(homeDir, ".local", "state", "nix", "profiles", "profile"), line)
+}
+
+// parseGenerationFields parses the ID and the date of a generation line.
+func parseGenerationFields(fields []string) (domain.NixGeneration, error) {
 	id, err := strconv.Atoi(fields[0])
 	if err != nil {
 		return domain.NixGeneration{}, fmt.Errorf("invalid generation ID: %s", fields[0])
 	}
 
-	// Parse date and time from second and third fields
-	dateTimeStr := fmt.Sprintf("%s %s", fields[1], fields[2])
-
-	date, err := time.Parse("2006-01-02 15:04:05", dateTimeStr)
+	date, err := time.Parse("2006-01-02 15:04:05", fields[1]+" "+fields[2])
 	if err != nil {
 		return domain.NixGeneration{}, fmt.Errorf("invalid date/time: %s %s", fields[1], fields[2])
 	}
 
-	// Build the profile path using the standard Nix profile directory
-	// On macOS with Nix installed via single-user or multi-user:
-	// User profiles are typically in ~/.local/state/nix/profiles/ (Darwin)
-	// or ~/.nix-profile/ (Linux fallback)
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return domain.NixGeneration{}, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	path := fmt.Sprintf("%s/.local/state/nix/profiles/profile-%d-link", homeDir, id)
-
-	// Check if this is the current generation
-	isCurrent := strings.Contains(line, "current")
-
-	return domain.NixGeneration{
-		ID:      domain.NixGenerationID(id),
-		Path:    path,
-		Date:    date,
-		Current: boolToGenerationStatus(isCurrent),
-	}, nil
+	return domain.NixGeneration{ID: domain.NixGenerationID(id), Date: date}, nil //nolint:exhaustruct
 }
 
 // IsAvailable checks if Nix is available and accessible.
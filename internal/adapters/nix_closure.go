@@ -0,0 +1,272 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// storeQueryBatch is the number of store paths passed to one nix-store
+// call, keeping argument lists far below ARG_MAX.
+const storeQueryBatch = 500
+
+// NixGCRoot is a garbage collector root: a link keeping a store path alive.
+type NixGCRoot struct {
+	Link   string
+	Target string
+}
+
+// NixReclaim is what a garbage collection deletes after generations were
+// removed, from closure analysis: the paths that are dead already and, per
+// removed generation, the paths only that generation kept alive.
+type NixReclaim struct {
+	AlreadyDead []string
+	// ByGeneration attributes each path that becomes dead to the newest
+	// removed generation referencing it, keyed by the generation's link.
+	ByGeneration map[string][]string
+	// NarSize is the NAR size of every path above.
+	NarSize map[string]int64
+}
+
+// Paths returns every path the garbage collection deletes, sorted.
+func (r NixReclaim) Paths() []string {
+	paths := slices.Clone(r.AlreadyDead)
+	for _, genPaths := range r.ByGeneration {
+		paths = append(paths, genPaths...)
+	}
+
+	slices.Sort(paths)
+
+	return slices.Compact(paths)
+}
+
+// NarBytes sums the NAR sizes of paths.
+func (r NixReclaim) NarBytes(paths []string) int64 {
+	var total int64
+	for _, p := range paths {
+		total += r.NarSize[p]
+	}
+
+	return total
+}
+
+// DeadPaths lists the store paths no garbage collector root keeps alive.
+func (n *NixAdapter) DeadPaths(ctx context.Context) ([]string, error) {
+	output, err := n.run(ctx, "nix-store", "--gc", "--print-dead")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead store paths: %w", err)
+	}
+
+	return storePaths(output), nil
+}
+
+// GCRoots lists the garbage collector roots.
+func (n *NixAdapter) GCRoots(ctx context.Context) ([]NixGCRoot, error) {
+	output, err := n.run(ctx, "nix-store", "--gc", "--print-roots")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GC roots: %w", err)
+	}
+
+	return parseGCRoots(output), nil
+}
+
+// Closure returns the store paths paths reference, directly or indirectly,
+// including paths themselves.
+func (n *NixAdapter) Closure(ctx context.Context, paths []string) (map[string]bool, error) {
+	closure := make(map[string]bool)
+
+	for batch := range slices.Chunk(paths, storeQueryBatch) {
+		output, err := n.run(ctx, "nix-store", append([]string{"--query", "--requisites"}, batch...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the closure of %d store paths: %w", len(batch), err)
+		}
+
+		for _, p := range storePaths(output) {
+			closure[p] = true
+		}
+	}
+
+	return closure, nil
+}
+
+// NarSizes returns the NAR size of each store path.
+func (n *NixAdapter) NarSizes(ctx context.Context, paths []string) (map[string]int64, error) {
+	sizes := make(map[string]int64, len(paths))
+
+	for batch := range slices.Chunk(paths, storeQueryBatch) {
+		output, err := n.run(ctx, "nix-store", append([]string{"--query", "--size"}, batch...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the size of %d store paths: %w", len(batch), err)
+		}
+
+		lines := strings.Fields(string(output))
+		if len(lines) != len(batch) {
+			return nil, fmt.Errorf("nix-store --query --size returned %d sizes for %d paths", len(lines), len(batch))
+		}
+
+		for i, line := range lines {
+			size, err := strconv.ParseInt(line, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid size %q for %s: %w", line, batch[i], err)
+			}
+
+			sizes[batch[i]] = size
+		}
+	}
+
+	return sizes, nil
+}
+
+// ReclaimEstimate computes the store paths a garbage collection deletes once
+// the removed generations are gone: the paths dead already, plus the
+// closure of the removed generations minus the closure of every other root.
+// Removed generations are matched to GC roots by their link.
+func (n *NixAdapter) ReclaimEstimate(ctx context.Context, removed []domain.NixGeneration) (NixReclaim, error) {
+	roots, err := n.GCRoots(ctx)
+	if err != nil {
+		return NixReclaim{}, err
+	}
+
+	removedTargets := make(map[string]string, len(removed))
+	removedLinks := make(map[string]bool, len(removed))
+
+	for _, gen := range removed {
+		removedLinks[gen.Path] = true
+	}
+
+	keptTargets := make(map[string]bool)
+
+	for _, root := range roots {
+		if removedLinks[root.Link] {
+			removedTargets[root.Link] = root.Target
+		} else {
+			keptTargets[root.Target] = true
+		}
+	}
+
+	for _, gen := range removed {
+		if _, ok := removedTargets[gen.Path]; !ok {
+			return NixReclaim{}, fmt.Errorf("generation %d (%s) is not a GC root", gen.ID, gen.Path)
+		}
+	}
+
+	kept, err := n.Closure(ctx, slices.Sorted(maps.Keys(keptTargets)))
+	if err != nil {
+		return NixReclaim{}, err
+	}
+
+	dead, err := n.DeadPaths(ctx)
+	if err != nil {
+		return NixReclaim{}, err
+	}
+
+	reclaim := NixReclaim{
+		AlreadyDead:  dead,
+		ByGeneration: make(map[string][]string, len(removed)),
+		NarSize:      nil,
+	}
+
+	// Newest first, so that a path shared by removed generations is
+	// attributed to the newest one referencing it.
+	byNewest := slices.SortedFunc(slices.Values(removed), func(a, b domain.NixGeneration) int {
+		return cmp.Or(b.Date.Compare(a.Date), cmp.Compare(b.ID, a.ID))
+	})
+
+	claimed := make(map[string]bool)
+
+	for _, gen := range byNewest {
+		closure, err := n.Closure(ctx, []string{removedTargets[gen.Path]})
+		if err != nil {
+			return NixReclaim{}, err
+		}
+
+		var freed []string
+
+		for _, p := range slices.Sorted(maps.Keys(closure)) {
+			if !kept[p] && !claimed[p] {
+				claimed[p] = true
+				freed = append(freed, p)
+			}
+		}
+
+		reclaim.ByGeneration[gen.Path] = freed
+	}
+
+	reclaim.NarSize, err = n.NarSizes(ctx, reclaim.Paths())
+	if err != nil {
+		return NixReclaim{}, err
+	}
+
+	return reclaim, nil
+}
+
+// storePaths returns the non-empty lines of nix-store output.
+func storePaths(output []byte) []string {
+	var paths []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+
+	return paths
+}
+
+// parseGCRoots parses nix-store --gc --print-roots output:
+//
+//	/home/user/.local/state/nix/profiles/profile-42-link -> /nix/store/abc-profile
+//	{censored} -> /nix/store/def-bash
+//
+// Runtime roots of other users' processes are censored; they still keep
+// their target alive.
+func parseGCRoots(output []byte) []NixGCRoot {
+	var roots []NixGCRoot
+
+	for _, line := range storePaths(output) {
+		link, target, ok := strings.Cut(line, " -> ")
+		if !ok {
+			continue
+		}
+
+		roots = append(roots, NixGCRoot{Link: link, Target: target})
+	}
+
+	return roots
+}
+
+// gcSummary matches the summary nix store gc prints, e.g.
+// "42 store paths deleted, 1.23 GiB freed".
+var gcSummary = regexp.MustCompile(`(\d+) store paths? deleted, ([\d.]+) (bytes|[KMGT]iB) freed`) //nolint:gochecknoglobals
+
+// parseGCFreed returns the number of paths and the bytes a garbage
+// collection reported deleting; ok is false if the output has no summary.
+func parseGCFreed(output []byte) (paths int, freed int64, ok bool) {
+	m := gcSummary.FindSubmatch(output)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	paths, _ = strconv.Atoi(string(m[1]))
+
+	return paths, parseNixBytes(m[2], m[3]), true
+}
+
+// parseNixBytes converts an amount Nix printed with its unit, such as
+// "1.23" "GiB", to bytes.
+func parseNixBytes(amount, unit []byte) int64 {
+	value, _ := strconv.ParseFloat(string(amount), 64)
+	scale := map[string]float64{"bytes": 1, "KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40}[string(unit)]
+
+	return int64(value * scale)
+}
@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore answers the nix-store queries ReclaimEstimate runs.
+type fakeStore struct {
+	roots    string
+	dead     []string
+	closures map[string][]string
+	sizes    map[string]string
+}
+
+func (s fakeStore) run(_ context.Context, name string, arg ...string) ([]byte, error) {
+	if name != "nix-store" {
+		return nil, errors.New("unexpected command " + name)
+	}
+
+	var out []string
+
+	switch strings.Join(arg[:2], " ") {
+	case "--gc --print-roots":
+		return []byte(s.roots), nil
+	case "--gc --print-dead":
+		out = s.dead
+	case "--query --requisites":
+		for _, p := range arg[2:] {
+			out = append(out, s.closures[p]...)
+		}
+	case "--query --size":
+		for _, p := range arg[2:] {
+			out = append(out, s.sizes[p])
+		}
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n"), nil
+}
+
+func TestNixAdapter_ReclaimEstimate(t *testing.T) {
+	t.Parallel()
+
+	store := fakeStore{
+		roots: "/profiles/default-1-link -> /nix/store/a-profile\n" +
+			"/profiles/default-2-link -> /nix/store/b-profile\n" +
+			"/profiles/default-3-link -> /nix/store/c-profile\n" +
+			"{censored} -> /nix/store/r-runtime\n",
+		dead: []string{"/nix/store/d-dead"},
+		closures: map[string][]string{
+			"/nix/store/a-profile": {"/nix/store/a-profile", "/nix/store/x-shared", "/nix/store/o-only-a"},
+			"/nix/store/b-profile": {"/nix/store/b-profile", "/nix/store/x-shared", "/nix/store/y-kept"},
+			"/nix/store/c-profile": {"/nix/store/c-profile", "/nix/store/y-kept"},
+			"/nix/store/r-runtime": {"/nix/store/r-runtime", "/nix/store/o-only-a"},
+		},
+		sizes: map[string]string{
+			"/nix/store/a-profile": "10", "/nix/store/b-profile": "20",
+			"/nix/store/x-shared": "300", "/nix/store/d-dead": "4000",
+		},
+	}
+
+	n := NewNixAdapter(0, 0)
+	n.run = store.run
+
+	reclaim, err := n.ReclaimEstimate(context.Background(), []domain.NixGeneration{
+		{ID: 1, Path: "/profiles/default-1-link"}, //nolint:exhaustruct
+		{ID: 2, Path: "/profiles/default-2-link"}, //nolint:exhaustruct
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/nix/store/d-dead"}, reclaim.AlreadyDead)
+	assert.Equal(t, []string{"/nix/store/b-profile", "/nix/store/x-shared"}, reclaim.ByGeneration["/profiles/default-2-link"],
+		"a path shared by removed generations belongs to the newest")
+	assert.Equal(t, []string{"/nix/store/a-profile"}, reclaim.ByGeneration["/profiles/default-1-link"],
+		"paths a kept root references stay, censored roots included")
+	assert.Equal(t, int64(320), reclaim.NarBytes(reclaim.ByGeneration["/profiles/default-2-link"]))
+	assert.Equal(t, int64(4330), reclaim.NarBytes(reclaim.Paths()))
+
+	_, err = n.ReclaimEstimate(context.Background(), []domain.NixGeneration{
+		{ID: 9, Path: "/profiles/default-9-link"}, //nolint:exhaustruct
+	})
+	assert.ErrorContains(t, err, "not a GC root")
+}
+
+func TestParseGCFreed(t *testing.T) {
+	t.Parallel()
+
+	paths, freed, ok := parseGCFreed([]byte("deleting '/nix/store/abc'\n42 store paths deleted, 1.50 MiB freed\n"))
+	require.True(t, ok)
+	assert.Equal(t, 42, paths)
+	assert.Equal(t, int64(1536*1024), freed)
+
+	paths, freed, ok = parseGCFreed([]byte("1 store path deleted, 12 bytes freed"))
+	require.True(t, ok)
+	assert.Equal(t, 1, paths)
+	assert.Equal(t, int64(12), freed)
+
+	_, _, ok = parseGCFreed([]byte("error: cannot connect to daemon"))
+	assert.False(t, ok)
+}
+
+func TestParseOptimiseFreed(t *testing.T) {
+	t.Parallel()
+
+	stats, ok := parseOptimiseFreed([]byte("12.50 MiB freed by hard-linking 567 files\n"))
+	require.True(t, ok)
+	assert.Equal(t, NixOptimiseStats{FreedBytes: 12800 * 1024, FilesLinked: 567}, stats)
+
+	stats, ok = parseOptimiseFreed([]byte("12939264 bytes (12.34 MiB) freed by hard-linking 1 file"))
+	require.True(t, ok)
+	assert.Equal(t, NixOptimiseStats{FreedBytes: 12939264, FilesLinked: 1}, stats)
+
+	_, ok = parseOptimiseFreed([]byte("error: cannot connect to daemon"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// NixAutoRoots is the directory of indirect GC roots: one link per root
+// link that nix build, nix-direnv or devenv registered.
+const NixAutoRoots = "/nix/var/nix/gcroots/auto"
+
+// NixRootPins is what a set of GC roots keeps alive, from closure analysis.
+type NixRootPins struct {
+	// Closure is every store path a root references, keyed by root link.
+	Closure map[string][]string
+	// Exclusive is the part of Closure no other GC root references, which
+	// a garbage collection frees once the root is gone.
+	Exclusive map[string][]string
+	// NarSize is the NAR size of every path in Closure.
+	NarSize map[string]int64
+}
+
+// IndirectRoots returns the root links the entries of dir point at, sorted.
+// The links may be gone; a garbage collection drops such entries itself.
+func IndirectRoots(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indirect GC roots in %s: %w", dir, err)
+	}
+
+	links := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		if e.Type()&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		link, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+
+		links = append(links, link)
+	}
+
+	slices.Sort(links)
+
+	return slices.Compact(links), nil
+}
+
+// RootPins computes the closure of each root in links and the part of it
+// no other GC root keeps alive. Links that are not GC roots (any more) are
+// left out.
+func (n *NixAdapter) RootPins(ctx context.Context, links []string) (NixRootPins, error) {
+	roots, err := n.GCRoots(ctx)
+	if err != nil {
+		return NixRootPins{}, err
+	}
+
+	wanted := make(map[string]bool, len(links))
+	for _, link := range links {
+		wanted[link] = true
+	}
+
+	pins := NixRootPins{
+		Closure:   make(map[string][]string),
+		Exclusive: make(map[string][]string),
+		NarSize:   nil,
+	}
+
+	// How many distinct root links reference each path. Roots sharing a
+	// target share one closure query.
+	refs := make(map[string]int)
+	closures := make(map[string][]string)
+
+	for _, root := range roots {
+		closure, ok := closures[root.Target]
+		if !ok {
+			paths, err := n.Closure(ctx, []string{root.Target})
+			if err != nil {
+				return NixRootPins{}, err
+			}
+
+			closure = slices.Sorted(maps.Keys(paths))
+			closures[root.Target] = closure
+		}
+
+		for _, p := range closure {
+			refs[p]++
+		}
+
+		if wanted[root.Link] {
+			pins.Closure[root.Link] = closure
+		}
+	}
+
+	var all []string
+
+	for link, closure := range pins.Closure {
+		for _, p := range closure {
+			if refs[p] == 1 {
+				pins.Exclusive[link] = append(pins.Exclusive[link], p)
+			}
+		}
+
+		all = append(all, closure...)
+	}
+
+	slices.Sort(all)
+
+	pins.NarSize, err = n.NarSizes(ctx, slices.Compact(all))
+	if err != nil {
+		return NixRootPins{}, err
+	}
+
+	return pins, nil
+}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndirectRoots(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.Symlink("/home/u/b/result", filepath.Join(dir, "bbb")))
+	require.NoError(t, os.Symlink("/home/u/a/.direnv/flake-profile", filepath.Join(dir, "aaa")))
+	require.NoError(t, os.Symlink("/home/u/b/result", filepath.Join(dir, "ccc")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "not-a-link"), nil, 0o600))
+
+	links, err := IndirectRoots(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/home/u/a/.direnv/flake-profile", "/home/u/b/result"}, links)
+
+	_, err = IndirectRoots(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestNixAdapter_RootPins(t *testing.T) {
+	t.Parallel()
+
+	store := fakeStore{
+		roots: "/home/u/a/result -> /nix/store/a-app\n" +
+			"/home/u/b/result -> /nix/store/b-app\n" +
+			"/profiles/default-1-link -> /nix/store/p-profile\n",
+		dead: nil,
+		closures: map[string][]string{
+			"/nix/store/a-app":     {"/nix/store/a-app", "/nix/store/l-lib", "/nix/store/g-glibc"},
+			"/nix/store/b-app":     {"/nix/store/b-app", "/nix/store/l-lib", "/nix/store/g-glibc"},
+			"/nix/store/p-profile": {"/nix/store/p-profile", "/nix/store/g-glibc"},
+		},
+		sizes: map[string]string{
+			"/nix/store/a-app": "10", "/nix/store/b-app": "20",
+			"/nix/store/l-lib": "300", "/nix/store/g-glibc": "4000",
+		},
+	}
+
+	n := NewNixAdapter(0, 0)
+	n.run = store.run
+
+	pins, err := n.RootPins(context.Background(), []string{"/home/u/a/result", "/home/u/b/result", "/home/u/gone/result"})
+	require.NoError(t, err)
+
+	assert.Len(t, pins.Closure["/home/u/a/result"], 3)
+	assert.Equal(t, []string{"/nix/store/a-app"}, pins.Exclusive["/home/u/a/result"],
+		"paths another root references are not pinned by the root alone")
+	assert.Equal(t, []string{"/nix/store/b-app"}, pins.Exclusive["/home/u/b/result"])
+	assert.NotContains(t, pins.Closure, "/home/u/gone/result", "links that are no GC root are left out")
+	assert.NotContains(t, pins.Closure, "/profiles/default-1-link", "only the requested roots are reported")
+	assert.Equal(t, int64(4000), pins.NarSize["/nix/store/g-glibc"])
+}
@@ -0,0 +1,60 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// optimiseSummary matches the summary nix store optimise prints, e.g.
+// "12.34 MiB freed by hard-linking 567 files", or that of older versions,
+// "12939264 bytes (12.34 MiB) freed by hard-linking 567 files".
+var optimiseSummary = regexp.MustCompile( //nolint:gochecknoglobals
+	`(?:(\d+) bytes \([\d.]+ MiB\)|([\d.]+) (bytes|[KMGT]iB)) freed by hard-linking (\d+) files?`,
+)
+
+// NixOptimiseStats is what a store optimisation deduplicated.
+type NixOptimiseStats struct {
+	FreedBytes  int64 `json:"freed_bytes"`
+	FilesLinked int   `json:"files_linked"`
+}
+
+// Optimise replaces identical files in the store by hardlinks to one copy
+// and returns the space that freed.
+func (n *NixAdapter) Optimise(ctx context.Context) (NixOptimiseStats, error) {
+	output, err := n.runCombined(ctx, "nix", "store", "optimise")
+	if err != nil {
+		return NixOptimiseStats{}, fmt.Errorf("failed to run nix store optimise: %w", err)
+	}
+
+	stats, ok := parseOptimiseFreed(output)
+	if !ok {
+		return NixOptimiseStats{}, fmt.Errorf(
+			"nix store optimise did not report the space it freed: %s", strings.TrimSpace(string(output)),
+		)
+	}
+
+	return stats, nil
+}
+
+// parseOptimiseFreed returns what a store optimisation reported freeing; ok
+// is false if the output has no summary.
+func parseOptimiseFreed(output []byte) (NixOptimiseStats, bool) {
+	m := optimiseSummary.FindSubmatch(output)
+	if m == nil {
+		return NixOptimiseStats{}, false
+	}
+
+	stats := NixOptimiseStats{FreedBytes: 0, FilesLinked: 0}
+	stats.FilesLinked, _ = strconv.Atoi(string(m[4]))
+
+	if m[1] != nil {
+		stats.FreedBytes, _ = strconv.ParseInt(string(m[1]), 10, 64)
+	} else {
+		stats.FreedBytes = parseNixBytes(m[2], m[3])
+	}
+
+	return stats, true
+}
@@ -0,0 +1,231 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// NixProfileKind classifies a Nix profile.
+type NixProfileKind string
+
+const (
+	// NixProfileUser is the user's default profile (nix-env, nix profile).
+	NixProfileUser NixProfileKind = "user"
+	// NixProfileHomeManager is the home-manager profile.
+	NixProfileHomeManager NixProfileKind = "home-manager"
+	// NixProfileSystem is the NixOS or nix-darwin system profile.
+	NixProfileSystem NixProfileKind = "system"
+	// NixProfileNamed is any other profile found in a profile directory,
+	// such as channels or profiles created with --profile.
+	NixProfileNamed NixProfileKind = "named"
+	// NixProfileCustom is a profile configured explicitly.
+	NixProfileCustom NixProfileKind = "custom"
+)
+
+// nixProfilesRoot is the system-wide profile directory; only root manages
+// the profiles directly inside it.
+const nixProfilesRoot = "/nix/var/nix/profiles"
+
+// maxProfileAliases bounds how many profile-to-profile links are followed.
+const maxProfileAliases = 8
+
+// generationLink matches the link of one profile generation, "<profile>-<id>-link".
+var generationLink = regexp.MustCompile(`^(.+)-(\d+)-link$`) //nolint:gochecknoglobals
+
+// NixProfile is a profile link whose generations can be listed and removed.
+type NixProfile struct {
+	Name string
+	Kind NixProfileKind
+	// Path is the profile link with symlinked directories resolved, so that
+	// its generation links match the GC roots Nix reports.
+	Path string
+}
+
+// SetProfiles adds explicitly configured profile links to the discovered ones.
+func (n *NixAdapter) SetProfiles(paths []string) {
+	n.extraProfiles = paths
+}
+
+// Profiles returns the profiles the invoking user can manage: the user
+// profile, home-manager and named profiles in the user's profile
+// directories, the system profile when running as root, and the configured
+// extra profiles.
+func (n *NixAdapter) Profiles() []NixProfile {
+	var candidates []string
+
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".nix-profile"))
+	}
+
+	return discoverNixProfiles(nixProfileDirs(), candidates, n.extraProfiles)
+}
+
+// ProfileGenerations lists the generations of one profile.
+func (n *NixAdapter) ProfileGenerations(ctx context.Context, profile NixProfile) ([]domain.NixGeneration, error) {
+	output, err := n.run(ctx, "nix-env", "--profile", profile.Path, "--list-generations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generations of %s profile %s: %w", profile.Kind, profile.Path, err)
+	}
+
+	var generations []domain.NixGeneration
+
+	for _, line := range storePaths(output) {
+		gen, err := parseGeneration(profile.Path, line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse generation of %s: %w", profile.Path, err)
+		}
+
+		generations = append(generations, gen)
+	}
+
+	return generations, nil
+}
+
+// nixProfileDirs returns the directories holding the invoking user's
+// profiles, and the system-wide one when running as root.
+func nixProfileDirs() []string {
+	var dirs []string
+
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if home, err := os.UserHomeDir(); err == nil && stateHome == "" {
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+
+	if stateHome != "" {
+		dirs = append(dirs, filepath.Join(stateHome, "nix", "profiles"))
+	}
+
+	if u, err := user.Current(); err == nil {
+		dirs = append(dirs, filepath.Join(nixProfilesRoot, "per-user", u.Username))
+	}
+
+	if os.Geteuid() == 0 {
+		dirs = append(dirs, nixProfilesRoot)
+	}
+
+	return dirs
+}
+
+// discoverNixProfiles finds the profiles in dirs, then adds the candidate
+// links that resolve to a profile and the extra profiles. A profile reached
+// through several links is returned once.
+func discoverNixProfiles(dirs, candidates, extra []string) []NixProfile {
+	var profiles []NixProfile
+
+	seen := make(map[string]bool)
+
+	add := func(link string, custom bool) {
+		path, ok := canonicalProfile(link)
+		if !ok || seen[path] {
+			return
+		}
+
+		seen[path] = true
+
+		kind := profileKind(filepath.Base(path))
+		if custom && kind == NixProfileNamed {
+			kind = NixProfileCustom
+		}
+
+		profiles = append(profiles, NixProfile{Name: filepath.Base(path), Kind: kind, Path: path})
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			if e.Type()&fs.ModeSymlink != 0 && !generationLink.MatchString(e.Name()) {
+				add(filepath.Join(dir, e.Name()), false)
+			}
+		}
+	}
+
+	for _, link := range candidates {
+		add(link, false)
+	}
+
+	for _, link := range extra {
+		add(link, true)
+	}
+
+	return profiles
+}
+
+// canonicalProfile follows links from one profile to another (such as
+// ~/.nix-profile) until it reaches the link pointing at a generation, and
+// resolves symlinked directories on the way. ok is false if link is not a
+// profile.
+func canonicalProfile(link string) (string, bool) {
+	path := link
+
+	for range maxProfileAliases {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", false
+		}
+
+		if generationLink.MatchString(filepath.Base(target)) {
+			if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+				path = filepath.Join(dir, filepath.Base(path))
+			}
+
+			return path, true
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+
+		path = target
+	}
+
+	return "", false
+}
+
+// profileKind classifies a profile by its name.
+func profileKind(name string) NixProfileKind {
+	switch name {
+	case "profile", "default":
+		return NixProfileUser
+	case "home-manager":
+		return NixProfileHomeManager
+	case "system":
+		return NixProfileSystem
+	default:
+		return NixProfileNamed
+	}
+}
+
+// parseGeneration parses a line of nix-env --list-generations output for
+// the given profile:
+//
+//	"32   2026-01-12 08:03:14"
+//	"33   2026-01-15 21:14:05   (current)"
+func parseGeneration(profile, line string) (domain.NixGeneration, error) {
+	fields := strings.Fields(line)
+	if len(fields) < NixGenerationMinFields {
+		return domain.NixGeneration{}, fmt.Errorf("invalid generation line: %s", line)
+	}
+
+	gen, err := parseGenerationFields(fields)
+	if err != nil {
+		return domain.NixGeneration{}, err
+	}
+
+	gen.Profile = profile
+	gen.Path = fmt.Sprintf("%s-%d-link", profile, gen.ID)
+	gen.Current = boolToGenerationStatus(strings.Contains(line, "current"))
+
+	return gen, nil
+}
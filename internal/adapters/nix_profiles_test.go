@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverNixProfiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state")
+	perUser := filepath.Join(dir, "per-user")
+	custom := filepath.Join(dir, "custom")
+
+	for _, d := range []string{state, perUser, custom} {
+		require.NoError(t, os.Mkdir(d, 0o755))
+	}
+
+	link := func(target, name string) {
+		t.Helper()
+		require.NoError(t, os.Symlink(target, name))
+	}
+
+	link("profile-7-link", filepath.Join(state, "profile"))
+	link("/nix/store/a-profile", filepath.Join(state, "profile-7-link"))
+	link("home-manager-3-link", filepath.Join(state, "home-manager"))
+	link("channels-1-link", filepath.Join(perUser, "channels"))
+	// The old per-user location pointing at the new one is the same profile.
+	link(filepath.Join(state, "profile"), filepath.Join(perUser, "profile"))
+	link("/nix/store/b-tools", filepath.Join(perUser, "not-a-profile"))
+	link("tools-2-link", filepath.Join(custom, "tools"))
+	link(filepath.Join(state, "profile"), filepath.Join(dir, ".nix-profile"))
+
+	profiles := discoverNixProfiles(
+		[]string{state, perUser, filepath.Join(dir, "missing")},
+		[]string{filepath.Join(dir, ".nix-profile")},
+		[]string{filepath.Join(custom, "tools"), filepath.Join(custom, "missing")},
+	)
+
+	kinds := make(map[string]NixProfileKind, len(profiles))
+	for _, p := range profiles {
+		kinds[p.Name] = p.Kind
+	}
+
+	assert.Equal(t, map[string]NixProfileKind{
+		"home-manager": NixProfileHomeManager,
+		"profile":      NixProfileUser,
+		"channels":     NixProfileNamed,
+		"tools":        NixProfileCustom,
+	}, kinds)
+	assert.Len(t, profiles, 4, "a profile reached through several links is listed once")
+}
+
+func TestParseGeneration_Profile(t *testing.T) {
+	t.Parallel()
+
+	gen, err := parseGeneration("/nix/var/nix/profiles/system", "  42   2026-01-15 21:14:05   (current)")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/nix/var/nix/profiles/system", gen.Profile)
+	assert.Equal(t, "/nix/var/nix/profiles/system-42-link", gen.Path)
+	assert.True(t, gen.Current.IsCurrent())
+
+	_, err = parseGeneration("/p", "x 2026-01-15 21:14:05")
+	assert.Error(t, err)
+}
@@ -112,7 +112,7 @@ func getCachePath(toolType JVMBuildToolType, homeDir string) string {
 
 // scanBuildTool scans cache for a specific JVM build tool.
 func (bcc *BuildCacheCleaner) scanBuildTool(
-	_ context.Context,
+	ctx context.Context,
 	toolType JVMBuildToolType,
 	homeDir string,
 ) result.Result[[]domain.ScanItem] {
@@ -122,6 +122,7 @@ func (bcc *BuildCacheCleaner) scanBuildTool(
 	case JVMBuildToolGradle:
 		gradleCache := getCachePath(toolType, homeDir)
 		scanResult := ScanPath(
+			ctx,
 			"",
 			domain.ScanTypeTemp,
 			"Gradle cache",
@@ -133,12 +134,12 @@ func (bcc *BuildCacheCleaner) scanBuildTool(
 
 	case JVMBuildToolMaven:
 		mavenCache := getCachePath(toolType, homeDir)
-		scanResult := ScanDirectory(mavenCache, domain.ScanTypeTemp, bcc.verbose)
+		scanResult := ScanDirectory(ctx, mavenCache, domain.ScanTypeTemp, bcc.verbose)
 		items = append(items, scanResult.Items...)
 
 	case JVMBuildToolSBT:
 		sbtCache := getCachePath(toolType, homeDir)
-		scanResult := ScanDirectory(sbtCache, domain.ScanTypeTemp, bcc.verbose)
+		scanResult := ScanDirectory(ctx, sbtCache, domain.ScanTypeTemp, bcc.verbose)
 		items = append(items, scanResult.Items...)
 	}
 
@@ -194,7 +195,7 @@ func (bcc *BuildCacheCleaner) printVerbose(action, verboseMsg, baseName string)
 
 // genericClean handles common cleanup logic for both cache directories and partial files.
 func (bcc *BuildCacheCleaner) genericClean(
-	_ context.Context,
+	ctx context.Context,
 	toolName string,
 	baseDir string,
 	pattern string,
@@ -218,8 +219,12 @@ func (bcc *BuildCacheCleaner) genericClean(
 	bytesFreed := int64(0)
 
 	for _, match := range matches {
+		if keptByIgnoreRules(ctx, match) {
+			continue
+		}
+
 		if !bcc.dryRun {
-			bytesFreed += GetDirSize(match)
+			bytesFreed += GetDirDiskUsage(match)
 		}
 
 		if bcc.dryRun {
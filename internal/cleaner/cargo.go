@@ -65,12 +65,7 @@ func (cc *CargoCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanIte
 	if cargoHome != "" {
 		// Add registry cache location
 		registryCache := cargoHome + "/registry"
-		items = append(items, domain.ScanItem{
-			Path:     registryCache,
-			Size:     GetDirSize(registryCache),
-			Created:  GetDirModTime(registryCache),
-			ScanType: domain.ScanTypeTemp,
-		})
+		items = append(items, MeasureDirScanItem(ctx, registryCache, domain.ScanTypeTemp))
 
 		if cc.verbose {
 			fmt.Printf("Found Cargo registry cache: %s\n", registryCache)
@@ -78,12 +73,7 @@ func (cc *CargoCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanIte
 
 		// Add source cache location
 		sourceCache := cargoHome + "/git"
-		items = append(items, domain.ScanItem{
-			Path:     sourceCache,
-			Size:     GetDirSize(sourceCache),
-			Created:  GetDirModTime(sourceCache),
-			ScanType: domain.ScanTypeTemp,
-		})
+		items = append(items, MeasureDirScanItem(ctx, sourceCache, domain.ScanTypeTemp))
 
 		if cc.verbose {
 			fmt.Printf("Found Cargo source cache: %s\n", sourceCache)
@@ -96,7 +86,7 @@ func (cc *CargoCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanIte
 // Clean removes Cargo caches.
 func (cc *CargoCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	if !cc.IsAvailable(ctx) {
-		return result.Err[domain.CleanResult](NewNotAvailableError("cargo", ""))
+		return result.Err[domain.CleanResult](NewNotAvailableError(CleanerCargo, ""))
 	}
 
 	if cc.dryRun {
@@ -109,13 +99,13 @@ func (cc *CargoCleaner) Clean(ctx context.Context) result.Result[domain.CleanRes
 
 		if cargoHome != "" {
 			registryCache := cargoHome + "/registry"
-			if size := GetDirSize(registryCache); size > 0 {
+			if size := GetDirDiskUsage(registryCache); size > 0 {
 				totalBytes += size
 				itemsRemoved++
 			}
 
 			sourceCache := cargoHome + "/git"
-			if size := GetDirSize(sourceCache); size > 0 {
+			if size := GetDirDiskUsage(sourceCache); size > 0 {
 				totalBytes += size
 				itemsRemoved++
 			}
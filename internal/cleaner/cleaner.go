@@ -47,6 +47,13 @@ type Cleaner interface {
 	Scan(ctx context.Context) result.Result[[]domain.ScanItem]
 }
 
+// SettingsApplier is implemented by cleaners a profile operation's settings
+// configure, such as the Nix retention policy.
+type SettingsApplier interface {
+	// ApplySettings configures the cleaner; settings may be nil.
+	ApplySettings(settings *domain.OperationSettings) error
+}
+
 // NixStoreSizer defines the interface for cleaners that can report store size.
 type NixStoreSizer interface {
 	// GetStoreSize returns the size of the Nix store in bytes.
@@ -110,7 +117,8 @@ func (e *NotAvailableError) ErrorFamily() errorfamily.Family {
 
 // NewNotAvailableError constructs a NotAvailableError with a per-cleaner
 // diagnostic code (e.g. "cleaner.cargo.not_available"). Using this factory
-// ensures code consistency across all cleaner call sites.
+// ensures code consistency across all cleaner call sites. cleanerName must
+// be the registry name, under which the cleaner's hints are registered.
 func NewNotAvailableError(cleanerName, reason string) *NotAvailableError {
 	return &NotAvailableError{
 		CleanerName: cleanerName,
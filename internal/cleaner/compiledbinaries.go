@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
 	fileutil "github.com/LarsArtmann/clean-wizard/internal/shared/utils/fileutil"
 )
@@ -309,17 +310,35 @@ func (c *CompiledBinariesCleaner) Scan(ctx context.Context) result.Result[[]doma
 	// Convert to ScanItems
 	items := make([]domain.ScanItem, 0, len(allBinaries))
 	for _, b := range allBinaries {
-		items = append(items, domain.ScanItem{
+		items = append(items, domain.ScanItem{ //nolint:exhaustruct
 			Path:     b.Path,
 			Size:     b.Size,
 			Created:  b.ModTime,
 			ScanType: domain.ScanTypeSystem,
+			Reasons:  c.reasons(b, minSizeBytes),
 		})
 	}
 
 	return result.Ok(items)
 }
 
+// reasons explains why a binary was selected: its category, the size
+// threshold and, when configured, the age threshold.
+func (c *CompiledBinariesCleaner) reasons(b BinaryInfo, minSize int64) []domain.SelectionReason {
+	reasons := []domain.SelectionReason{
+		categoryReason(string(b.Category), "executable in an included build output category"),
+		sizeReason(b.Size, minSize),
+	}
+
+	if c.olderThan != "" && c.olderThan != "0" {
+		if age, err := parseAgeDuration(c.olderThan); err == nil {
+			reasons = append(reasons, ageReason(b.ModTime, age))
+		}
+	}
+
+	return reasons
+}
+
 // Clean removes compiled binary files using trash.
 func (c *CompiledBinariesCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	return ExecuteTrashPipeline(
@@ -401,28 +420,31 @@ func (s *defaultBinaryScanner) ScanDirectory(
 		categorySet[cat] = true
 	}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil //nolint:nilerr // Skip files we can't access
-		}
+	err := fswalk.Shared().Walk(ctx, dir, func(entry fswalk.Entry) error {
+		path := entry.Path
 
 		// Skip directories (but continue walking)
-		if info.IsDir() {
+		if entry.IsDir() {
 			// Skip excluded directories
 			if s.shouldSkipDirectory(path) {
-				return filepath.SkipDir
+				return fswalk.SkipDir
 			}
 
 			return nil
 		}
 
+		// Only regular files can be binaries
+		if !entry.Mode.IsRegular() {
+			return nil
+		}
+
 		// Check file size
-		if info.Size() < minSize {
+		if entry.Size < minSize {
 			return nil
 		}
 
 		// Check if it's an executable
-		if info.Mode()&0o111 == 0 {
+		if entry.Mode&0o111 == 0 {
 			return nil
 		}
 
@@ -447,13 +469,17 @@ func (s *defaultBinaryScanner) ScanDirectory(
 
 		binaries = append(binaries, BinaryInfo{
 			Path:     path,
-			Size:     info.Size(),
-			ModTime:  info.ModTime(),
+			Size:     entry.Size,
+			ModTime:  entry.ModTime,
 			Category: category,
 		})
 
 		return nil
 	})
+
+	// The walker visits directories in parallel; keep the output stable.
+	slices.SortFunc(binaries, func(a, b BinaryInfo) int { return strings.Compare(a.Path, b.Path) })
+
 	if err != nil {
 		return binaries, fmt.Errorf("dir=%v, minSize=%v: %w", dir, minSize, err)
 	}
@@ -0,0 +1,431 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// DefaultCustomCleanerIcon is shown for custom cleaners that do not declare an icon.
+const DefaultCustomCleanerIcon = "🧩"
+
+// Describer is implemented by cleaners defined at runtime (e.g. custom YAML
+// cleaners) that carry their own display metadata instead of having an entry
+// in the command layer's static metadata table.
+type Describer interface {
+	DisplayName() string
+	Description() string
+	Icon() string
+}
+
+// CustomCleaner is a filesystem cleaner declared in the custom_cleaners
+// config section. It walks its roots, selects files or directories by
+// include/exclude globs, size and age, and trashes or deletes them.
+type CustomCleaner struct {
+	CleanerBase
+
+	def       domain.CustomCleanerConfig
+	roots     []string
+	minSize   int64
+	olderThan time.Duration
+	protected []string
+}
+
+// NewCustomCleaner creates a cleaner from a custom cleaner definition.
+// Items below any of the protected paths are never selected.
+func NewCustomCleaner(
+	verbose, dryRun bool,
+	def domain.CustomCleanerConfig,
+	protected []string,
+) (*CustomCleaner, error) {
+	err := def.Validate()
+	if err != nil {
+		return nil, errorfamily.WrapRejection(err, "cleaner.custom.invalid", "invalid custom cleaner definition")
+	}
+
+	var olderThan time.Duration
+	if def.OlderThan != "" {
+		olderThan, err = domain.ParseCustomDuration(def.OlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("invalid older_than for custom cleaner %s: %w", def.Name, err)
+		}
+	}
+
+	roots := make([]string, 0, len(def.Roots))
+	for _, root := range def.Roots {
+		roots = append(roots, expandHomePath(root))
+	}
+
+	return &CustomCleaner{
+		CleanerBase: NewCleanerBase(verbose, dryRun),
+		def:         def,
+		roots:       NormalizePaths(roots),
+		minSize:     int64(def.MinSizeMB) * bytesPerMB,
+		olderThan:   olderThan,
+		protected:   NormalizePaths(protected),
+	}, nil
+}
+
+// RegisterCustomCleaners builds a CustomCleaner for each definition and adds
+// it to the registry. A definition whose name is already registered (e.g. a
+// built-in cleaner) is rejected rather than silently replacing it.
+func RegisterCustomCleaners(
+	registry *Registry,
+	defs []domain.CustomCleanerConfig,
+	protected []string,
+	verbose, dryRun bool,
+) error {
+	for _, def := range defs {
+		if _, exists := registry.Get(def.Name); exists {
+			return errorfamily.NewRejection(
+				"cleaner.custom.duplicate",
+				fmt.Sprintf("custom cleaner %q conflicts with an already registered cleaner", def.Name),
+			)
+		}
+
+		c, err := NewCustomCleaner(verbose, dryRun, def, protected)
+		if err != nil {
+			return err
+		}
+
+		registry.Register(def.Name, c)
+	}
+
+	return nil
+}
+
+// Name returns the cleaner name declared in the config.
+func (cc *CustomCleaner) Name() string {
+	return cc.def.Name
+}
+
+// Type returns the operation type for this cleaner. Custom cleaners use their
+// name as operation type, matching domain.GetOperationType's fallback so that
+// profile operations can reference them by name.
+func (cc *CustomCleaner) Type() domain.OperationType {
+	return domain.OperationType(cc.def.Name)
+}
+
+// DisplayName returns the name shown in the TUI.
+func (cc *CustomCleaner) DisplayName() string {
+	return cc.def.Name
+}
+
+// Description returns the declared description, or a summary of the roots.
+func (cc *CustomCleaner) Description() string {
+	if cc.def.Description != "" {
+		return cc.def.Description
+	}
+
+	return "Custom cleaner for " + strings.Join(cc.def.Roots, ", ")
+}
+
+// Icon returns the declared icon, or DefaultCustomCleanerIcon.
+func (cc *CustomCleaner) Icon() string {
+	if cc.def.Icon != "" {
+		return cc.def.Icon
+	}
+
+	return DefaultCustomCleanerIcon
+}
+
+// RiskLevel returns the declared risk level.
+func (cc *CustomCleaner) RiskLevel() domain.RiskLevelType {
+	return cc.def.RiskLevel
+}
+
+// Dependencies returns the ordering constraints declared in the config.
+func (cc *CustomCleaner) Dependencies() Dependencies {
+	return Dependencies{
+		After:           cc.def.After,
+		Requires:        cc.def.Requires,
+		ExclusiveGroups: cc.def.ExclusiveGroups,
+	}
+}
+
+// Resources returns the scheduling class and timeout declared in the config,
+// defaulting to DISK_IO without a timeout.
+func (cc *CustomCleaner) Resources() Resources {
+	return Resources{Class: domain.ResourceClassDiskIO, Timeout: 0}.
+		Apply(ParseResourceOverride(cc.def.ResourceClass, cc.def.Timeout))
+}
+
+// IsAvailable reports whether at least one root exists and, for the trash
+// action, whether the trash command is installed.
+func (cc *CustomCleaner) IsAvailable(_ context.Context) bool {
+	if cc.def.Action == domain.CustomActionTrash {
+		if _, err := exec.LookPath("trash"); err != nil {
+			return false
+		}
+	}
+
+	for _, root := range cc.roots {
+		if info, err := os.Stat(root); err == nil && info.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Scan walks every root and returns the items selected by the definition.
+func (cc *CustomCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
+	items := make([]domain.ScanItem, 0)
+
+	for _, root := range cc.roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			if cc.verbose {
+				fmt.Printf("Skipping non-existent path: %s\n", root)
+			}
+
+			continue
+		}
+
+		found, err := cc.scanRoot(ctx, root)
+		if err != nil {
+			return result.Err[[]domain.ScanItem](err)
+		}
+
+		items = append(items, found...)
+	}
+
+	return result.Ok(items)
+}
+
+// customDirectory is a directory matched during the walk of a root; it is
+// measured once the walk is done.
+type customDirectory struct {
+	path    string
+	matched string
+}
+
+// scanRoot walks a single root and collects matching items. Matched
+// directories are measured after the walk, so their trees are not walked
+// while the walker serializes its callbacks.
+func (cc *CustomCleaner) scanRoot(ctx context.Context, root string) ([]domain.ScanItem, error) {
+	var (
+		items       []domain.ScanItem
+		directories []customDirectory
+	)
+
+	cutoff := time.Now().Add(-cc.olderThan)
+	engine := SizeEngineFromContext(ctx)
+
+	err := fswalk.Shared().Walk(ctx, root, func(entry fswalk.Entry) error {
+		rel, err := filepath.Rel(root, entry.Path)
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		if isUnderAny(entry.Path, cc.protected) || matchesAnyGlob(cc.def.Exclude, rel) {
+			if entry.IsDir() {
+				return fswalk.SkipDir
+			}
+
+			return nil
+		}
+
+		if cc.def.Match == domain.CustomMatchDirectories {
+			return cc.visitDirectory(rel, entry, &directories)
+		}
+
+		cc.visitFile(engine, rel, entry, cutoff, &items)
+
+		return nil
+	})
+	if err != nil {
+		return items, fmt.Errorf("custom cleaner %s failed to scan %s: %w", cc.def.Name, root, err)
+	}
+
+	for _, dir := range directories {
+		cc.selectDirectory(ctx, engine, dir, cutoff, &items)
+	}
+
+	// The walker visits directories in parallel; keep the output stable.
+	slices.SortFunc(items, func(a, b domain.ScanItem) int { return strings.Compare(a.Path, b.Path) })
+
+	return items, nil
+}
+
+// visitFile selects a regular file if it matches the include globs, size and age filters.
+func (cc *CustomCleaner) visitFile(
+	engine *SizeEngine, rel string, entry fswalk.Entry, cutoff time.Time, items *[]domain.ScanItem,
+) {
+	if !entry.Mode.IsRegular() {
+		return
+	}
+
+	matched, ok := firstMatchingGlob(cc.def.Include, rel)
+	if len(cc.def.Include) > 0 && !ok {
+		return
+	}
+
+	if entry.Size < cc.minSize || (cc.olderThan > 0 && !entry.ModTime.Before(cutoff)) {
+		return
+	}
+
+	size := engine.MeasureEntry(entry)
+
+	*items = append(*items, domain.ScanItem{
+		Path:       entry.Path,
+		Size:       size.Apparent,
+		OnDiskSize: size.Allocated,
+		Created:    entry.ModTime,
+		ScanType:   domain.ScanTypeCache,
+		Reasons:    cc.reasons(matched, "file below a configured root", entry.Size, entry.ModTime),
+	})
+}
+
+// visitDirectory matches a directory as a unit. Without include globs, the
+// direct children of a root are candidates. A matched directory is not
+// descended into; one containing a protected path is never matched, as
+// removing it would remove the protected path too.
+func (cc *CustomCleaner) visitDirectory(rel string, entry fswalk.Entry, directories *[]customDirectory) error {
+	if !entry.IsDir() {
+		return nil
+	}
+
+	matched, ok := firstMatchingGlob(cc.def.Include, rel)
+	if len(cc.def.Include) == 0 {
+		if strings.ContainsRune(rel, filepath.Separator) {
+			return fswalk.SkipDir
+		}
+	} else if !ok {
+		return nil
+	}
+
+	if slices.ContainsFunc(cc.protected, func(p string) bool { return isUnderAny(p, []string{entry.Path}) }) {
+		return nil
+	}
+
+	*directories = append(*directories, customDirectory{path: entry.Path, matched: matched})
+
+	return fswalk.SkipDir
+}
+
+// selectDirectory selects a matched directory if it passes the size and age
+// filters; its age is the newest modification time inside it. Only selected
+// directories are recorded in the run's size engine.
+func (cc *CustomCleaner) selectDirectory(
+	ctx context.Context, engine *SizeEngine, dir customDirectory, cutoff time.Time, items *[]domain.ScanItem,
+) {
+	if keptByIgnoreRules(ctx, dir.path) {
+		return
+	}
+
+	size, modTime, ok := walkDirectory(dir.path)
+	if !ok || size < cc.minSize || (cc.olderThan > 0 && !modTime.Before(cutoff)) {
+		return
+	}
+
+	diskSize, _, _ := engine.Measure(ctx, dir.path)
+
+	*items = append(*items, domain.ScanItem{
+		Path:       dir.path,
+		Size:       diskSize.Apparent,
+		OnDiskSize: diskSize.Allocated,
+		Created:    modTime,
+		ScanType:   domain.ScanTypeCache,
+		Reasons:    cc.reasons(dir.matched, "direct child of a configured root", size, modTime),
+	})
+}
+
+// reasons explains the selection of an item: the include glob it matched
+// (or fallback when there are none), the size and age filters it passed, and
+// the declared risk level.
+func (cc *CustomCleaner) reasons(matched, fallback string, size int64, modTime time.Time) []domain.SelectionReason {
+	rule := ruleReason(fallback, "")
+	if matched != "" {
+		rule = ruleReason("matches an include glob of custom cleaner "+cc.def.Name, matched)
+	}
+
+	reasons := []domain.SelectionReason{rule}
+
+	if cc.minSize > 0 {
+		reasons = append(reasons, sizeReason(size, cc.minSize))
+	}
+
+	if cc.olderThan > 0 {
+		reasons = append(reasons, ageReason(modTime, cc.olderThan))
+	}
+
+	return append(reasons, riskReason(cc.def.RiskLevel))
+}
+
+// Clean removes the scanned items using the declared action.
+func (cc *CustomCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	return ExecuteTrashPipeline(
+		ctx,
+		cc.Scan(ctx),
+		cc.dryRun,
+		cc.verbose,
+		"item(s) for "+cc.def.Name,
+		cc.removeItem,
+		func(item domain.ScanItem) {
+			fmt.Printf("  ✓ Removed: %s (%.2f MB)\n", item.Path, float64(item.Size)/bytesPerMB)
+		},
+	)
+}
+
+// removeItem disposes of a single item according to the declared action.
+func (cc *CustomCleaner) removeItem(ctx context.Context, item domain.ScanItem) error {
+	if cc.def.Action == domain.CustomActionTrash {
+		return TrashPath(ctx, item.Path)
+	}
+
+	err := os.RemoveAll(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", item.Path, err)
+	}
+
+	return nil
+}
+
+// matchesAnyGlob reports whether rel matches one of the patterns. Patterns
+// containing a separator are matched against the relative path, all others
+// against the base name.
+func matchesAnyGlob(patterns []string, rel string) bool {
+	_, ok := firstMatchingGlob(patterns, rel)
+
+	return ok
+}
+
+// firstMatchingGlob returns the first of patterns matching rel.
+func firstMatchingGlob(patterns []string, rel string) (string, bool) {
+	for _, pattern := range patterns {
+		target := filepath.Base(rel)
+		if strings.ContainsRune(pattern, '/') {
+			target = filepath.ToSlash(rel)
+		}
+
+		if matched, err := filepath.Match(pattern, target); err == nil && matched {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
+
+// expandHomePath expands a leading "~" or "~/" to the user's home directory.
+func expandHomePath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDir, err := GetHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
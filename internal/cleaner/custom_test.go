@@ -0,0 +1,263 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+)
+
+func newTestCustomDef(root string) domain.CustomCleanerConfig {
+	return domain.CustomCleanerConfig{
+		Name:      "test-custom",
+		Roots:     []string{root},
+		Match:     domain.CustomMatchFiles,
+		Action:    domain.CustomActionDelete,
+		RiskLevel: domain.RiskLevelLowType,
+	}
+}
+
+func writeTestFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	err = os.WriteFile(path, make([]byte, size), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	err = os.Chtimes(path, modTime, modTime)
+	if err != nil {
+		t.Fatalf("failed to set mod time: %v", err)
+	}
+}
+
+func scanPaths(t *testing.T, c *CustomCleaner) []string {
+	t.Helper()
+
+	res := c.Scan(context.Background())
+	if res.IsErr() {
+		t.Fatalf("Scan() error = %v", res.Error())
+	}
+
+	paths := make([]string, 0, len(res.Value()))
+	for _, item := range res.Value() {
+		paths = append(paths, item.Path)
+	}
+
+	return paths
+}
+
+func TestNewCustomCleaner_InvalidDefinition(t *testing.T) {
+	t.Parallel()
+
+	def := newTestCustomDef("relative/path")
+
+	_, err := NewCustomCleaner(false, false, def, nil)
+	if err == nil {
+		t.Error("NewCustomCleaner() should reject relative roots")
+	}
+}
+
+func TestCustomCleaner_ScanFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	writeTestFile(t, filepath.Join(root, "a.log"), 10, old)
+	writeTestFile(t, filepath.Join(root, "nested", "b.log"), 10, old)
+	writeTestFile(t, filepath.Join(root, "keep", "c.log"), 10, old)
+	writeTestFile(t, filepath.Join(root, "d.txt"), 10, old)
+	writeTestFile(t, filepath.Join(root, "fresh.log"), 10, time.Now())
+
+	def := newTestCustomDef(root)
+	def.Include = []string{"*.log"}
+	def.Exclude = []string{"keep"}
+	def.OlderThan = "1d"
+
+	c, err := NewCustomCleaner(false, false, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	paths := scanPaths(t, c)
+	if len(paths) != 2 {
+		t.Fatalf("Scan() found %d items, want 2: %v", len(paths), paths)
+	}
+
+	for _, p := range paths {
+		if filepath.Ext(p) != ".log" || filepath.Base(filepath.Dir(p)) == "keep" {
+			t.Errorf("Scan() selected unexpected path %s", p)
+		}
+	}
+}
+
+func TestCustomCleaner_ScanDirectories(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	writeTestFile(t, filepath.Join(root, "big", "data.bin"), 2*bytesPerMB, old)
+	writeTestFile(t, filepath.Join(root, "small", "data.bin"), 10, old)
+
+	def := newTestCustomDef(root)
+	def.Match = domain.CustomMatchDirectories
+	def.MinSizeMB = 1
+
+	c, err := NewCustomCleaner(false, false, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	paths := scanPaths(t, c)
+	if len(paths) != 1 || paths[0] != filepath.Join(root, "big") {
+		t.Errorf("Scan() = %v, want only %s", paths, filepath.Join(root, "big"))
+	}
+}
+
+func TestCustomCleaner_ProtectedPaths(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "protected", "a.log"), 10, time.Now())
+	writeTestFile(t, filepath.Join(root, "b.log"), 10, time.Now())
+
+	c, err := NewCustomCleaner(false, false, newTestCustomDef(root), []string{filepath.Join(root, "protected")})
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	paths := scanPaths(t, c)
+	if len(paths) != 1 || paths[0] != filepath.Join(root, "b.log") {
+		t.Errorf("Scan() = %v, want only b.log", paths)
+	}
+}
+
+func TestCustomCleaner_DirectoryContainingProtectedPath(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "projects", "keep", "main.go"), 10, time.Now())
+	writeTestFile(t, filepath.Join(root, "cache", "data.bin"), 10, time.Now())
+
+	def := newTestCustomDef(root)
+	def.Match = domain.CustomMatchDirectories
+
+	c, err := NewCustomCleaner(false, false, def, []string{filepath.Join(root, "projects", "keep")})
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	paths := scanPaths(t, c)
+	if len(paths) != 1 || paths[0] != filepath.Join(root, "cache") {
+		t.Errorf("Scan() = %v, want only cache", paths)
+	}
+}
+
+func TestCustomCleaner_IgnoreRules(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "precious", "a.log"), 10, time.Now())
+	writeTestFile(t, filepath.Join(root, "b.log"), 10, time.Now())
+
+	err := os.WriteFile(filepath.Join(root, ignore.FileName), []byte("precious/\n"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	def := newTestCustomDef(root)
+	def.Include = []string{"*.log", "*/*.log"}
+
+	c, err := NewCustomCleaner(false, false, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	res := c.Scan(ignore.WithMatcher(context.Background(), ignore.NewMatcher(nil)))
+	if res.IsErr() {
+		t.Fatalf("Scan() error = %v", res.Error())
+	}
+
+	if items := res.Value(); len(items) != 1 || items[0].Path != filepath.Join(root, "b.log") {
+		t.Errorf("Scan() = %v, want only b.log", items)
+	}
+}
+
+func TestCustomCleaner_Clean(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		wantExists bool
+	}{
+		{name: "dry run keeps files", dryRun: true, wantExists: true},
+		{name: "delete removes files", dryRun: false, wantExists: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			target := filepath.Join(root, "a.log")
+			writeTestFile(t, target, 100, time.Now())
+
+			c, err := NewCustomCleaner(false, tt.dryRun, newTestCustomDef(root), nil)
+			if err != nil {
+				t.Fatalf("NewCustomCleaner() error = %v", err)
+			}
+
+			res := c.Clean(context.Background())
+			if res.IsErr() {
+				t.Fatalf("Clean() error = %v", res.Error())
+			}
+
+			if res.Value().ItemsRemoved != 1 {
+				t.Errorf("Clean() ItemsRemoved = %d, want 1", res.Value().ItemsRemoved)
+			}
+
+			_, statErr := os.Stat(target)
+			if exists := statErr == nil; exists != tt.wantExists {
+				t.Errorf("file exists = %v, want %v", exists, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestRegisterCustomCleaners(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	def := newTestCustomDef(t.TempDir())
+
+	err := RegisterCustomCleaners(registry, []domain.CustomCleanerConfig{def}, nil, false, true)
+	if err != nil {
+		t.Fatalf("RegisterCustomCleaners() error = %v", err)
+	}
+
+	c, ok := registry.Get(def.Name)
+	if !ok {
+		t.Fatal("custom cleaner not registered")
+	}
+
+	if _, isDescriber := c.(Describer); !isDescriber {
+		t.Error("custom cleaner should implement Describer")
+	}
+
+	err = RegisterCustomCleaners(registry, []domain.CustomCleanerConfig{def}, nil, false, true)
+	if err == nil {
+		t.Error("RegisterCustomCleaners() should reject duplicate names")
+	}
+}
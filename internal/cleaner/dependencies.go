@@ -0,0 +1,96 @@
+package cleaner
+
+import "slices"
+
+// ExclusiveGroupUserCache groups cleaners that delete inside the per-user
+// cache directory (~/.cache, ~/Library/Caches). Running them concurrently
+// makes their size accounting race against each other.
+const ExclusiveGroupUserCache = "user-cache"
+
+// Dependencies declares how a cleaner is ordered relative to other cleaners
+// selected in the same run. All names are registry names.
+type Dependencies struct {
+	// After lists cleaners this one runs after when they are also selected.
+	// Their outcome does not matter.
+	After []string
+	// Requires lists cleaners that must be selected and succeed before this
+	// one runs; if one fails, this cleaner is skipped.
+	Requires []string
+	// ExclusiveGroups names mutual-exclusion groups; cleaners sharing a
+	// group never run concurrently.
+	ExclusiveGroups []string
+}
+
+// IsEmpty reports whether no constraint is declared.
+func (d Dependencies) IsEmpty() bool {
+	return len(d.After) == 0 && len(d.Requires) == 0 && len(d.ExclusiveGroups) == 0
+}
+
+// Merge returns the union of both declarations without duplicates.
+func (d Dependencies) Merge(other Dependencies) Dependencies {
+	return Dependencies{
+		After:           mergeUnique(d.After, other.After),
+		Requires:        mergeUnique(d.Requires, other.Requires),
+		ExclusiveGroups: mergeUnique(d.ExclusiveGroups, other.ExclusiveGroups),
+	}
+}
+
+// DependencyDeclarer is implemented by cleaners that declare their own
+// ordering constraints (e.g. custom YAML cleaners).
+type DependencyDeclarer interface {
+	Dependencies() Dependencies
+}
+
+// defaultDependencies holds the ordering constraints of the built-in cleaners.
+// Cleanups here only pay off in order: pruning Docker and removing stale GC
+// roots first releases the store paths they pinned before Nix GC, the store
+// is optimised after GC so that it does not hardlink files about to go, the
+// Go build cache is cleaned before golangci-lint's derived cache, and
+// Homebrew cleanup runs before the system cache is sized.
+var defaultDependencies = map[string]Dependencies{ //nolint:gochecknoglobals
+	CleanerNix: {
+		After: []string{CleanerDocker, CleanerNixGCRoots},
+	},
+	CleanerNixOptimise: {
+		After: []string{CleanerNix},
+	},
+	CleanerGo: {
+		ExclusiveGroups: []string{ExclusiveGroupUserCache},
+	},
+	CleanerGolangciLint: {
+		After:           []string{CleanerGo},
+		ExclusiveGroups: []string{ExclusiveGroupUserCache},
+	},
+	CleanerNode: {
+		ExclusiveGroups: []string{ExclusiveGroupUserCache},
+	},
+	CleanerSystemCache: {
+		After:           []string{CleanerHomebrew},
+		ExclusiveGroups: []string{ExclusiveGroupUserCache},
+	},
+}
+
+// DependenciesFor returns the built-in constraints for the named cleaner
+// merged with those the cleaner declares itself.
+func DependenciesFor(name string, c Cleaner) Dependencies {
+	deps := defaultDependencies[name]
+
+	if declarer, ok := c.(DependencyDeclarer); ok {
+		deps = deps.Merge(declarer.Dependencies())
+	}
+
+	return deps
+}
+
+// mergeUnique appends the elements of b missing from a, keeping order.
+func mergeUnique(a, b []string) []string {
+	out := slices.Clone(a)
+
+	for _, v := range b {
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,51 @@
+package cleaner
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+func TestDependenciesFor_BuiltinAndDeclared(t *testing.T) {
+	t.Parallel()
+
+	deps := DependenciesFor(CleanerGolangciLint, nil)
+	if !slices.Contains(deps.After, CleanerGo) {
+		t.Errorf("golangci-lint-cache should run after go, got %+v", deps)
+	}
+
+	def := domain.CustomCleanerConfig{
+		Name:            "my-cache",
+		Roots:           []string{t.TempDir()},
+		After:           []string{CleanerDocker},
+		ExclusiveGroups: []string{ExclusiveGroupUserCache},
+	}
+
+	c, err := NewCustomCleaner(false, true, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	deps = DependenciesFor(def.Name, c)
+	if !slices.Equal(deps.After, def.After) || !slices.Equal(deps.ExclusiveGroups, def.ExclusiveGroups) {
+		t.Errorf("DependenciesFor() = %+v, want declared constraints", deps)
+	}
+}
+
+func TestDependencies_Merge(t *testing.T) {
+	t.Parallel()
+
+	merged := Dependencies{After: []string{"a"}}.Merge(Dependencies{
+		After:    []string{"a", "b"},
+		Requires: []string{"c"},
+	})
+
+	if !slices.Equal(merged.After, []string{"a", "b"}) || !slices.Equal(merged.Requires, []string{"c"}) {
+		t.Errorf("Merge() = %+v", merged)
+	}
+
+	if !(Dependencies{}).IsEmpty() || merged.IsEmpty() {
+		t.Error("IsEmpty() mismatch")
+	}
+}
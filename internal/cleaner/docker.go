@@ -7,16 +7,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/LarsArtmann/clean-wizard/internal/adapters"
 	"github.com/LarsArtmann/clean-wizard/internal/conversions"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
 	"github.com/LarsArtmann/clean-wizard/internal/format"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
+	errorfamily "github.com/larsartmann/go-error-family"
 )
 
-// dockerCommandTimeout is the timeout for Docker operations.
-const dockerCommandTimeout = 2 * time.Minute
-
 // DockerResourceType represents Docker resource types for scanning.
 type DockerResourceType string
 
@@ -147,6 +145,9 @@ func (dc *DockerCleaner) addScanItem(
 		Size:     size,
 		Created:  time.Time{},
 		ScanType: domain.ScanTypeTemp,
+		Reasons: []domain.SelectionReason{
+			ruleReason("unused "+string(resourceType)+" reported by docker", ""),
+		},
 	})
 
 	if dc.verbose {
@@ -154,12 +155,19 @@ func (dc *DockerCleaner) addScanItem(
 	}
 }
 
+// dockerOutput runs a docker command and returns its combined output. The
+// command runs for at most the docker default timeout, and never past the
+// step deadline, which follows a profile timeout override.
+func dockerOutput(ctx context.Context, args ...string) ([]byte, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, commandTimeout(ctx, defaultResources[CleanerDocker].Timeout))
+	defer cancel()
+
+	return exec.CommandContext(timeoutCtx, "docker", args...).CombinedOutput() //nolint:wrapcheck
+}
+
 // scanDanglingImages scans for dangling Docker images with size information.
 func (dc *DockerCleaner) scanDanglingImages(ctx context.Context) result.Result[[]domain.ScanItem] {
-	cmd := adapters.ExecWithTimeout(ctx, dockerCommandTimeout,
-		"docker", "images", "-f", "dangling=true", "--format", "{{.ID}}\t{{.Size}}")
-
-	output, err := cmd.CombinedOutput()
+	output, err := dockerOutput(ctx, "images", "-f", "dangling=true", "--format", "{{.ID}}\t{{.Size}}")
 	if err != nil {
 		return result.Err[[]domain.ScanItem](fmt.Errorf("failed to scan dangling images: %w", err))
 	}
@@ -196,10 +204,7 @@ func (dc *DockerCleaner) scanAndAccumulate(
 func (dc *DockerCleaner) scanUnusedContainers(
 	ctx context.Context,
 ) result.Result[[]domain.ScanItem] {
-	cmd := adapters.ExecWithTimeout(ctx, dockerCommandTimeout,
-		"docker", "ps", "-a", "--filter", "status=exited", "--format", "{{.ID}}\t{{.Size}}")
-
-	output, err := cmd.CombinedOutput()
+	output, err := dockerOutput(ctx, "ps", "-a", "--filter", "status=exited", "--format", "{{.ID}}\t{{.Size}}")
 	if err != nil {
 		return result.Err[[]domain.ScanItem](
 			fmt.Errorf("failed to scan unused containers: %w", err),
@@ -214,10 +219,7 @@ func (dc *DockerCleaner) scanUnusedContainers(
 // scanUnusedVolumes scans for unused Docker volumes with size information.
 func (dc *DockerCleaner) scanUnusedVolumes(ctx context.Context) result.Result[[]domain.ScanItem] {
 	// Use docker system df -v to get volume sizes
-	cmd := adapters.ExecWithTimeout(ctx, dockerCommandTimeout,
-		"docker", "system", "df", "-v", "--format", "{{json .Volumes}}")
-
-	output, err := cmd.CombinedOutput()
+	output, err := dockerOutput(ctx, "system", "df", "-v", "--format", "{{json .Volumes}}")
 	if err != nil {
 		// Fallback to basic listing if json format not available
 		return dc.scanVolumesFallback(ctx)
@@ -230,9 +232,7 @@ func (dc *DockerCleaner) scanUnusedVolumes(ctx context.Context) result.Result[[]
 
 // scanVolumesFallback is a fallback method for scanning volumes when system df fails.
 func (dc *DockerCleaner) scanVolumesFallback(ctx context.Context) result.Result[[]domain.ScanItem] {
-	cmd := adapters.ExecWithTimeout(ctx, dockerCommandTimeout, "docker", "volume", "ls", "-q")
-
-	output, err := cmd.CombinedOutput()
+	output, err := dockerOutput(ctx, "volume", "ls", "-q")
 	if err != nil {
 		return result.Err[[]domain.ScanItem](fmt.Errorf("failed to scan volumes: %w", err))
 	}
@@ -340,16 +340,7 @@ func (dc *DockerCleaner) estimateSizeFromScan(ctx context.Context) int64 {
 		fallthrough
 	case domain.DockerPruneBuilds:
 		// Build cache size estimation - try to get from docker system df
-		cmd := adapters.ExecWithTimeout(
-			ctx,
-			dockerCommandTimeout,
-			"docker",
-			"system",
-			"df",
-			"--format",
-			"{{.BuildCache}}",
-		)
-		if output, err := cmd.CombinedOutput(); err == nil {
+		if output, err := dockerOutput(ctx, "system", "df", "--format", "{{.BuildCache}}"); err == nil {
 			if size, parseErr := ParseDockerSize(
 				strings.TrimSpace(string(output)),
 			); parseErr == nil &&
@@ -405,7 +396,7 @@ func (dc *DockerCleaner) parseVolumeJSONOutput(output string) []domain.ScanItem
 // Clean removes Docker resources based on prune mode.
 func (dc *DockerCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	if !dc.IsAvailable(ctx) {
-		return result.Err[domain.CleanResult](NewNotAvailableError("docker", ""))
+		return result.Err[domain.CleanResult](NewNotAvailableError(CleanerDocker, ""))
 	}
 
 	if dc.dryRun {
@@ -492,10 +483,14 @@ func (dc *DockerCleaner) pruneDocker(ctx context.Context) result.Result[domain.C
 		)
 	}
 
-	cmd := adapters.ExecWithTimeout(ctx, dockerCommandTimeout, "docker", args...)
-
-	output, err := cmd.CombinedOutput()
+	output, err := dockerOutput(ctx, args...)
 	if err != nil {
+		if isDockerDaemonDown(output) {
+			return result.Err[domain.CleanResult](errorfamily.WrapInfrastructure(
+				err, hints.CleanerCode(CleanerDocker, hints.DaemonUnreachable), "docker daemon is not reachable",
+			))
+		}
+
 		return result.Err[domain.CleanResult](
 			fmt.Errorf("docker system prune failed: %w (output: %s)", err, string(output)),
 		)
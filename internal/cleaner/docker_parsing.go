@@ -64,3 +64,12 @@ func ParseDockerSize(sizeStr string) (int64, error) {
 
 	return int64(number * float64(multiplier)), nil
 }
+
+// isDockerDaemonDown reports whether docker CLI output says the daemon is
+// not reachable, e.g. "Cannot connect to the Docker daemon at unix://...".
+func isDockerDaemonDown(output []byte) bool {
+	text := strings.ToLower(string(output))
+
+	return strings.Contains(text, "cannot connect to the docker daemon") ||
+		strings.Contains(text, "is the docker daemon running")
+}
@@ -471,3 +471,16 @@ func TestParseDockerSize(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDockerDaemonDown(t *testing.T) {
+	t.Parallel()
+
+	down := "Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"
+	if !isDockerDaemonDown([]byte(down)) {
+		t.Errorf("isDockerDaemonDown(%q) = false, want true", down)
+	}
+
+	if isDockerDaemonDown([]byte("Error response from daemon: conflict")) {
+		t.Error("isDockerDaemonDown(daemon error) = true, want false")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"syscall"
 
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
 	errorfamily "github.com/larsartmann/go-error-family"
 )
 
@@ -89,4 +90,12 @@ func init() {
 		Fix:    "Review the error message for the specific field and correct the value.",
 		WayOut: "Run 'clean-wizard init' to generate a fresh configuration file.",
 	})
+
+	// Register the remediation hints of the built-in cleaners' prerequisites
+	// and of git-history, whose install hint depends on what is installed.
+	for name, prereq := range defaultPrerequisites {
+		prereq.registerHints(name)
+	}
+
+	hints.RegisterFunc(hints.CleanerCode(CleanerGitHistory, hints.NotAvailable), GetInstallHint)
 }
@@ -0,0 +1,56 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// PathDeclarer is implemented by cleaners that know which paths they free
+// space under (e.g. custom YAML cleaners). The execution layer samples free
+// space on the filesystems holding these paths before and after a step.
+type PathDeclarer interface {
+	AffectedPaths() []string
+}
+
+// defaultAffectedPaths returns the paths below which a built-in cleaner frees
+// space. Paths may be missing on this system; callers skip those.
+func defaultAffectedPaths(name, home string) []string {
+	switch name {
+	case CleanerNix, CleanerNixGCRoots, CleanerNixOptimise:
+		return []string{"/nix"}
+	case CleanerDocker:
+		if runtime.GOOS == "darwin" {
+			return []string{filepath.Join(home, "Library", "Containers", "com.docker.docker")}
+		}
+
+		return []string{"/var/lib/docker"}
+	case CleanerHomebrew:
+		return []string{home, "/opt/homebrew", "/usr/local/Homebrew", "/home/linuxbrew/.linuxbrew"}
+	case CleanerTempFiles, CleanerGo:
+		return []string{home, os.TempDir()}
+	default:
+		return []string{home}
+	}
+}
+
+// AffectedPaths returns the paths below which the named cleaner frees space.
+// A cleaner's own declaration wins over the built-in table; unknown cleaners
+// are assumed to work below the home directory.
+func AffectedPaths(name string, c Cleaner) []string {
+	if declarer, ok := c.(PathDeclarer); ok {
+		return declarer.AffectedPaths()
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+
+	return defaultAffectedPaths(name, home)
+}
+
+// AffectedPaths returns the configured roots.
+func (cc *CustomCleaner) AffectedPaths() []string {
+	return cc.roots
+}
@@ -13,6 +13,8 @@ import (
 
 	"github.com/LarsArtmann/clean-wizard/internal/conversions"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
 	"golang.org/x/sys/unix"
 )
@@ -45,29 +47,12 @@ func GetHomeDir() (string, error) {
 	return "", errors.New("unable to determine home directory")
 }
 
-// walkDirectory walks the directory tree starting at path, collecting size and modTime.
-// This consolidates the common directory walking pattern to avoid duplication.
+// walkDirectory walks the directory tree starting at path, collecting its
+// apparent size (hardlinks counted once) and newest modTime.
 func walkDirectory(path string) (size int64, modTime time.Time, ok bool) {
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			return nil //nolint:nilerr // Skip files/dirs we can't access
-		}
-
-		if !info.IsDir() {
-			size += info.Size()
-		}
-
-		if info.ModTime().After(modTime) {
-			modTime = info.ModTime()
-		}
+	diskSize, modTime, ok := NewSizeEngine().Measure(context.Background(), path)
 
-		return nil
-	})
-	if err != nil {
-		return 0, time.Time{}, false
-	}
-
-	return size, modTime, true
+	return diskSize.Apparent, modTime, ok
 }
 
 // GetDirSize returns total size of directory recursively.
@@ -80,6 +65,17 @@ func GetDirSize(path string) int64 {
 	return size
 }
 
+// GetDirDiskUsage returns the bytes a directory occupies on disk
+// (allocated blocks, hardlinks counted once).
+func GetDirDiskUsage(path string) int64 {
+	size, _, ok := NewSizeEngine().Measure(context.Background(), path)
+	if !ok {
+		return 0
+	}
+
+	return size.Allocated
+}
+
 // GetDirModTime returns the most recent modification time in directory.
 func GetDirModTime(path string) time.Time {
 	_, modTime, ok := walkDirectory(path)
@@ -99,7 +95,7 @@ type ScanDirectoryResult struct {
 // ScanDirectory scans a directory and returns scan items if it exists and is a directory.
 // This helper consolidates the common pattern of checking if a path exists and is a directory,
 // then creating scan items for it.
-func ScanDirectory(path string, scanType domain.ScanType, verbose bool) ScanDirectoryResult {
+func ScanDirectory(ctx context.Context, path string, scanType domain.ScanType, verbose bool) ScanDirectoryResult {
 	result := ScanDirectoryResult{
 		Items: make([]domain.ScanItem, 0),
 		Found: false,
@@ -108,12 +104,12 @@ func ScanDirectory(path string, scanType domain.ScanType, verbose bool) ScanDire
 	info, err := os.Stat(path)
 	if err == nil && info.IsDir() {
 		result.Found = true
-		result.Items = append(result.Items, domain.ScanItem{
-			Path:     path,
-			Size:     GetDirSize(path),
-			Created:  GetDirModTime(path),
-			ScanType: scanType,
-		})
+
+		if keptByIgnoreRules(ctx, path) {
+			return result
+		}
+
+		result.Items = append(result.Items, MeasureDirScanItem(ctx, path, scanType))
 
 		if verbose {
 			fmt.Printf("Found: %s\n", filepath.Base(path))
@@ -124,16 +120,16 @@ func ScanDirectory(path string, scanType domain.ScanType, verbose bool) ScanDire
 }
 
 // appendScanItem appends a scan item for a directory to the items slice with verbose output.
+// Directories excluded by ignore files are left out.
 func appendScanItem(
+	ctx context.Context,
 	items []domain.ScanItem, path, displayName string, scanType domain.ScanType, verbose bool,
 ) []domain.ScanItem {
-	item := domain.ScanItem{
-		Path:     path,
-		Size:     GetDirSize(path),
-		Created:  GetDirModTime(path),
-		ScanType: scanType,
+	if keptByIgnoreRules(ctx, path) {
+		return items
 	}
-	items = append(items, item)
+
+	items = append(items, MeasureDirScanItem(ctx, path, scanType))
 
 	if verbose {
 		fmt.Printf("Found %s: %s\n", displayName, filepath.Base(path))
@@ -169,7 +165,7 @@ func ScanVersionDirectory(
 	}
 
 	for _, match := range matches {
-		items = appendScanItem(items, match, managerName, domain.ScanTypeTemp, verbose)
+		items = appendScanItem(ctx, items, match, managerName, domain.ScanTypeTemp, verbose)
 	}
 
 	return result.Ok(items)
@@ -183,6 +179,7 @@ func ScanVersionDirectory(
 // If homeDir is empty and pathComponents contains a complete path, it uses that directly.
 // If pattern is provided, it walks the directory to find matching entries instead of scanning.
 func ScanPath(
+	ctx context.Context,
 	homeDir string, scanType domain.ScanType, displayName string,
 	verbose bool, pattern string, pathComponents ...string,
 ) ScanDirectoryResult {
@@ -212,40 +209,66 @@ func ScanPath(
 			}
 
 			for _, match := range matches {
-				result.Items = appendScanItem(result.Items, match, displayName, scanType, verbose)
+				result.Items = appendScanItem(ctx, result.Items, match, displayName, scanType, verbose)
 			}
 		} else {
 			// Scan the directory itself
-			result.Items = appendScanItem(result.Items, fullPath, displayName, scanType, verbose)
+			result.Items = appendScanItem(ctx, result.Items, fullPath, displayName, scanType, verbose)
 		}
 	}
 
 	return result
 }
 
+// isIgnored reports whether path is excluded by a .cleanwizardignore rule of
+// the run, recording the exclusion for scan --verbose.
+func isIgnored(ctx context.Context, path string, isDir bool) bool {
+	return ignore.FromContext(ctx).Exclude(path, isDir)
+}
+
+// keptByIgnoreRules reports whether path must not be removed as a whole: it
+// is ignored itself, or it is a directory holding ignored entries or an
+// ignore file, which removing the directory would remove too.
+func keptByIgnoreRules(ctx context.Context, path string) bool {
+	info, err := os.Lstat(path)
+	isDir := err == nil && info.IsDir()
+
+	if isIgnored(ctx, path, isDir) {
+		return true
+	}
+
+	if !isDir {
+		return false
+	}
+
+	summary, err := fswalk.Shared().Summarize(ctx, path)
+
+	return err == nil && summary.Ignored > 0
+}
+
 // CalculateBytesFreed calculates the bytes freed from a directory after a cleanup operation.
 // This consolidates the common pattern of:
-// 1. Getting directory size before cleanup
+// 1. Getting the directory's on-disk size before cleanup
 // 2. Executing the cleanup function
-// 3. Getting directory size after cleanup
+// 3. Getting the directory's on-disk size after cleanup
 // 4. Calculating the difference (bytes freed)
 // 5. Logging verbose output if requested
 // Returns the bytes freed (always non-negative), beforeSize, and afterSize for logging.
 func CalculateBytesFreed(
 	path string, cleanup func() error, verbose bool, cacheName string,
 ) (bytesFreed, beforeSize, afterSize int64) {
-	beforeSize = GetDirSize(path)
+	beforeSize = GetDirDiskUsage(path)
 
 	err := cleanup()
 	if err != nil {
 		// Return 0 bytes freed if cleanup failed, but still calculate size
-		afterSize = GetDirSize(path)
+		afterSize = GetDirDiskUsage(path)
 		bytesFreed = max(beforeSize-afterSize, 0)
 
 		return bytesFreed, beforeSize, afterSize
 	}
 
-	afterSize = GetDirSize(path)
+	afterSize = GetDirDiskUsage(path)
 	bytesFreed = max(beforeSize-afterSize, 0)
 
 	if verbose {
@@ -404,7 +427,7 @@ func ExecuteTrashPipeline(
 
 	var totalBytes int64
 	for _, item := range items {
-		totalBytes += item.Size
+		totalBytes += item.DiskUsage()
 	}
 
 	if dryRun {
@@ -416,15 +439,27 @@ func ExecuteTrashPipeline(
 	}
 
 	counters := NewCleanCounters()
+	engine := SizeEngineFromContext(ctx)
 
 	for _, item := range items {
+		// Scans already leave ignored paths out; this guards items that were
+		// selected before an ignore file was added, and directories that
+		// would take ignored entries with them.
+		if keptByIgnoreRules(ctx, item.Path) {
+			if verbose {
+				fmt.Printf("  Skipping %s: kept by an ignore file\n", item.Path)
+			}
+
+			continue
+		}
+
 		if err := trash(ctx, item); err != nil {
 			counters.RecordFailure(verbose, item.Path, err)
 
 			continue
 		}
 
-		counters.RecordSuccess(item.Size)
+		counters.RecordSuccess(freedBytes(engine, item))
 
 		if verbose && logItem != nil {
 			logItem(item)
@@ -0,0 +1,73 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTrashPipeline_SkipsIgnoredDirectories(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	kept := filepath.Join(root, "cache")
+	trashed := filepath.Join(root, "build")
+
+	require.NoError(t, os.Mkdir(kept, 0o755))
+	require.NoError(t, os.Mkdir(trashed, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ignore.FileName), []byte("cache/\n"), 0o600))
+
+	ctx := ignore.WithMatcher(context.Background(), ignore.NewMatcher(nil))
+	items := []domain.ScanItem{{Path: kept}, {Path: trashed}} //nolint:exhaustruct
+
+	var seen []string
+
+	res := ExecuteTrashPipeline(ctx, result.Ok(items), false, false, "directories",
+		func(_ context.Context, item domain.ScanItem) error {
+			seen = append(seen, item.Path)
+
+			return nil
+		}, nil)
+	require.True(t, res.IsOk())
+
+	assert.Equal(t, []string{trashed}, seen, "a directory-only rule keeps the directory")
+}
+
+func TestExecuteTrashPipeline_KeepsDirectoriesHoldingIgnoredEntries(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	withRule := filepath.Join(root, "with-rule")
+	withFile := filepath.Join(root, "with-file")
+	trashed := filepath.Join(root, "build")
+
+	for _, dir := range []string{withRule, withFile, trashed} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ignore.FileName), []byte("with-rule/sub/keep.txt\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(withRule, "sub", "keep.txt"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(withFile, "sub", ignore.FileName), []byte("# no rules\n"), 0o600))
+
+	ctx := ignore.WithMatcher(context.Background(), ignore.NewMatcher(nil))
+	items := []domain.ScanItem{{Path: withRule}, {Path: withFile}, {Path: trashed}} //nolint:exhaustruct
+
+	var seen []string
+
+	res := ExecuteTrashPipeline(ctx, result.Ok(items), false, false, "directories",
+		func(_ context.Context, item domain.ScanItem) error {
+			seen = append(seen, item.Path)
+
+			return nil
+		}, nil)
+	require.True(t, res.IsOk())
+
+	assert.Equal(t, []string{trashed}, seen, "directories holding ignored entries or ignore files are kept")
+}
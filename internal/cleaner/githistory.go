@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/conversions"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
@@ -23,6 +25,10 @@ const (
 	GitHistoryDefaultMaxSearchDepth = 3
 )
 
+// CleanerGitHistory is the name of the git-history cleaner. It runs from
+// its own command and is not part of the registry.
+const CleanerGitHistory = "git-history"
+
 type GitHistoryCleaner struct {
 	CleanerBase
 
@@ -31,13 +37,20 @@ type GitHistoryCleaner struct {
 	excludeExts   []string
 	includeExts   []string
 	excludePaths  []string
+	removePaths   []string
 	maxFiles      int
 	createBackup  bool
 	selectedFiles []domain.GitHistoryFile
 
+	action         domain.GitHistoryAction
+	lfsByExtension bool
+	secretRules    []domain.GitHistorySecretRule
+
 	scanner       *GitHistoryScanner
 	safetyChecker *GitHistorySafetyChecker
 	executor      *GitHistoryExecutor
+
+	lastRewrite *domain.GitHistoryRewriteResult
 }
 
 // GitHistoryCleanerOption is a functional option for the cleaner.
@@ -57,17 +70,24 @@ func NewGitHistoryCleaner(opts ...GitHistoryCleanerOption) *GitHistoryCleaner {
 	}
 
 	// Initialize components
-	c.scanner = NewGitHistoryScanner(
-		c.repoPath,
+	scannerOpts := []GitHistoryScannerOption{
 		WithMinSizeMB(c.minSizeMB),
 		WithExcludeExtensions(c.excludeExts),
 		WithIncludeExtensions(c.includeExts),
 		WithExcludePaths(c.excludePaths),
+		WithRemovePaths(c.removePaths),
 		WithMaxFiles(c.maxFiles),
 		WithVerbose(c.verbose),
-	)
+	}
+
+	if c.action == domain.GitHistoryActionPurge {
+		scannerOpts = append(scannerOpts, WithSecretScan(c.secretRules))
+	}
+
+	c.scanner = NewGitHistoryScanner(c.repoPath, scannerOpts...)
 
 	c.safetyChecker = NewGitHistorySafetyChecker(c.repoPath, c.verbose)
+	c.safetyChecker.SetAction(c.action)
 	c.executor = NewGitHistoryExecutor(c.repoPath, c.verbose, c.dryRun)
 
 	return c
@@ -108,6 +128,14 @@ func WithGitHistoryExcludePaths(paths []string) GitHistoryCleanerOption {
 	}
 }
 
+// WithGitHistoryRemovePaths sets directory prefixes and globs to remove from
+// all history instead of selecting large binaries.
+func WithGitHistoryRemovePaths(paths []string) GitHistoryCleanerOption {
+	return func(c *GitHistoryCleaner) {
+		c.removePaths = paths
+	}
+}
+
 // WithGitHistoryMaxFiles sets the maximum number of files to show.
 func WithGitHistoryMaxFiles(maxFiles int) GitHistoryCleanerOption {
 	return func(c *GitHistoryCleaner) {
@@ -143,6 +171,29 @@ func WithGitHistorySelectedFiles(files []domain.GitHistoryFile) GitHistoryCleane
 	}
 }
 
+// WithGitHistoryAction sets what the rewrite does with the selected files.
+func WithGitHistoryAction(action domain.GitHistoryAction) GitHistoryCleanerOption {
+	return func(c *GitHistoryCleaner) {
+		c.action = action
+	}
+}
+
+// WithGitHistoryLFSByExtension sets whether a migration to Git LFS tracks
+// the extensions of the selected files instead of only their paths.
+func WithGitHistoryLFSByExtension(byExtension bool) GitHistoryCleanerOption {
+	return func(c *GitHistoryCleaner) {
+		c.lfsByExtension = byExtension
+	}
+}
+
+// WithGitHistorySecretRules sets the secret rules a purge scans with in
+// addition to the built-in rules.
+func WithGitHistorySecretRules(rules []domain.GitHistorySecretRule) GitHistoryCleanerOption {
+	return func(c *GitHistoryCleaner) {
+		c.secretRules = rules
+	}
+}
+
 // Type returns the operation type.
 func (c *GitHistoryCleaner) Type() domain.OperationType {
 	return domain.OperationTypeGitHistory
@@ -150,7 +201,7 @@ func (c *GitHistoryCleaner) Type() domain.OperationType {
 
 // Name returns the cleaner name.
 func (c *GitHistoryCleaner) Name() string {
-	return "git-history"
+	return CleanerGitHistory
 }
 
 // IsAvailable checks if git and git-filter-repo are available.
@@ -180,6 +231,10 @@ func (c *GitHistoryCleaner) ValidateSettings(settings *domain.OperationSettings)
 				return fmt.Errorf("max_files must be >= 0, got %d", s.MaxFiles)
 			}
 
+			if err := ValidateRemovePaths(s.RemovePaths); err != nil {
+				return fmt.Errorf("remove_paths: %w", err)
+			}
+
 			return nil
 		},
 	)
@@ -199,13 +254,15 @@ func (c *GitHistoryCleaner) Scan(ctx context.Context) result.Result[[]domain.Sca
 			Size:     f.SizeBytes,
 			Created:  f.CommitDate,
 			ScanType: domain.ScanTypeSystem,
+			Reasons:  []domain.SelectionReason{ruleReason("large blob in git history", "")},
 		}
 	}
 
 	return result.Ok(items)
 }
 
-// Clean removes selected files from git history.
+// Clean removes selected files from git history, migrates them to Git LFS,
+// or redacts their secrets.
 func (c *GitHistoryCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	// Ensure files are selected
 	err := c.ensureSelectedFiles(ctx)
@@ -270,8 +327,17 @@ func (c *GitHistoryCleaner) calculateTotalBytes() int64 {
 // executeDryRun returns a result for dry run mode.
 func (c *GitHistoryCleaner) executeDryRun(totalBytes int64) result.Result[domain.CleanResult] {
 	if c.verbose {
-		fmt.Printf("Would remove %d binary file(s) from git history (%.2f MB)\n",
-			len(c.selectedFiles), float64(totalBytes)/float64(BytesPerMB))
+		msg := "Would remove %d binary file(s) from git history (%.2f MB)\n"
+
+		switch c.action {
+		case domain.GitHistoryActionLFS:
+			msg = "Would migrate %d binary file(s) in git history to Git LFS (%.2f MB)\n"
+		case domain.GitHistoryActionPurge:
+			msg = "Would redact the secrets of %d blob(s) in git history (%.2f MB)\n"
+		case domain.GitHistoryActionRemove:
+		}
+
+		fmt.Printf(msg, len(c.selectedFiles), float64(totalBytes)/float64(BytesPerMB))
 	}
 
 	return result.Ok(conversions.NewCleanResultWithSizeEstimate(
@@ -287,16 +353,46 @@ func (c *GitHistoryCleaner) executeClean(
 	_ int64,
 ) result.Result[domain.CleanResult] {
 	execResult, err := c.executor.Execute(ctx, ExecuteOptions{ //nolint:exhaustruct
-		FilesToRemove: c.selectedFiles,
-		CreateBackup:  c.createBackup,
+		FilesToRemove:  c.selectedFiles,
+		RemovePaths:    c.removePaths,
+		Action:         c.action,
+		LFSByExtension: c.lfsByExtension,
+		CreateBackup:   c.createBackup,
 	})
 	if err != nil {
 		return result.Err[domain.CleanResult](fmt.Errorf("execution failed: %w", err))
 	}
 
+	c.lastRewrite = execResult
+
+	if v := execResult.Verification; v != nil && !v.OK() {
+		return result.Err[domain.CleanResult](fmt.Errorf(
+			"%w:\n  %s\nDo not push; restore with: clean-wizard git-history restore %s",
+			ErrRewriteVerificationFailed, strings.Join(v.Problems(), "\n  "), execResult.BackupPath))
+	}
+
 	if c.verbose {
-		fmt.Printf("Removed %d file(s) from history, reclaimed %.2f MB\n",
-			len(execResult.FilesRemoved), float64(execResult.BytesReclaimed)/float64(BytesPerMB))
+		msg := "Removed %d file(s) from history, reclaimed %.2f MB\n"
+
+		switch execResult.Action {
+		case domain.GitHistoryActionLFS:
+			msg = "Migrated %d file(s) in history to Git LFS, reclaimed %.2f MB\n"
+		case domain.GitHistoryActionPurge:
+			msg = "Redacted the secrets of %d blob(s) in history, reclaimed %.2f MB\n"
+		case domain.GitHistoryActionRemove:
+		}
+
+		fmt.Printf(msg, len(execResult.FilesRemoved), float64(execResult.BytesReclaimed)/float64(BytesPerMB))
+
+		if len(execResult.RemovePaths) > 0 {
+			fmt.Printf("Removed from every commit: %s\n", strings.Join(execResult.RemovePaths, ", "))
+		}
+
+		if len(execResult.LFSPatterns) > 0 {
+			fmt.Printf("Tracked in .gitattributes: %s (%.2f MB of LFS objects)\n",
+				strings.Join(execResult.LFSPatterns, ", "),
+				float64(execResult.LFSObjectBytes)/float64(BytesPerMB))
+		}
 
 		if execResult.BackupCreated {
 			fmt.Printf("Backup created at: %s\n", execResult.BackupPath)
@@ -336,6 +432,12 @@ func (c *GitHistoryCleaner) GetScanResult(
 	return c.scanner.Scan(ctx)
 }
 
+// ScanDirectories returns the history size of each directory down to depth
+// levels, largest first.
+func (c *GitHistoryCleaner) ScanDirectories(ctx context.Context, depth int) ([]domain.GitHistoryDirectory, error) {
+	return c.scanner.ScanDirectories(ctx, depth)
+}
+
 // EstimateImpact estimates the impact of removing the selected files.
 func (c *GitHistoryCleaner) EstimateImpact(ctx context.Context) (*ImpactEstimate, error) {
 	if len(c.selectedFiles) == 0 {
@@ -350,6 +452,12 @@ func (c *GitHistoryCleaner) EstimateImpact(ctx context.Context) (*ImpactEstimate
 	return c.executor.EstimateImpact(ctx, c.selectedFiles)
 }
 
+// LastRewrite returns the result of the last history rewrite, nil before
+// the first one.
+func (c *GitHistoryCleaner) LastRewrite() *domain.GitHistoryRewriteResult {
+	return c.lastRewrite
+}
+
 // SetSelectedFiles sets the files to remove.
 func (c *GitHistoryCleaner) SetSelectedFiles(files []domain.GitHistoryFile) {
 	c.selectedFiles = files
@@ -365,13 +473,18 @@ func (c *GitHistoryCleaner) StripLargeBlobs(ctx context.Context, sizeMB int) err
 	return c.executor.StripLargeBlobs(ctx, sizeMB)
 }
 
-// getDefaultBackupPath returns the default backup path for a repository.
+// getDefaultBackupPath returns the default backup path for a repository,
+// timestamped so earlier backups are kept.
 func getDefaultBackupPath(repoPath string) string {
+	return newBackupPath(repoPath, time.Now())
+}
+
+// newBackupPath returns a timestamped backup path next to the repository.
+func newBackupPath(repoPath string, now time.Time) string {
 	absPath, _ := filepath.Abs(repoPath)
-	parent := filepath.Dir(absPath)
-	base := filepath.Base(absPath)
 
-	return filepath.Join(parent, base+"-backup.git")
+	return filepath.Join(filepath.Dir(absPath),
+		filepath.Base(absPath)+"-"+now.Format(backupTimestampFormat)+"-backup.git")
 }
 
 // FindGitRepositories finds all git repositories under the given base path.
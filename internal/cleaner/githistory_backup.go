@@ -0,0 +1,352 @@
+package cleaner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+)
+
+const (
+	// GitHistoryBackupsStateFileName is the name of the file in the state
+	// directory indexing the backups made before history rewrites.
+	GitHistoryBackupsStateFileName = "git-history-backups.json"
+	// GitHistoryDefaultBackupRetention is how long backups are kept by default.
+	GitHistoryDefaultBackupRetention = 30 * 24 * time.Hour
+	// RestoreTimeout is the timeout for verifying and restoring a backup.
+	RestoreTimeout = 10 * time.Minute
+	// backupTimestampFormat is the timestamp in backup directory names.
+	backupTimestampFormat = "20060102-150405"
+)
+
+// Sentinel errors for restoring backups.
+var (
+	ErrBackupNotIndexed   = errors.New("backup is not in the backup index")
+	ErrBackupCorrupt      = errors.New("backup failed git fsck")
+	ErrRestoreTargetDirty = errors.New("restore target has uncommitted changes")
+	ErrRestoreTargetInUse = errors.New("restore target is a non-empty directory that is not a git repository")
+)
+
+// GitHistoryBackupIndex is the list of backups made before history rewrites,
+// persisted in the state directory.
+type GitHistoryBackupIndex struct {
+	// path is the index file; "" uses GitHistoryBackupsStateFileName in the
+	// state directory.
+	path string
+}
+
+// gitHistoryBackupIndexFile is the on-disk format of the backup index.
+type gitHistoryBackupIndexFile struct {
+	Backups []domain.GitHistoryBackup `json:"backups"`
+}
+
+// NewGitHistoryBackupIndex returns the backup index stored at path, or in
+// the state directory if path is empty.
+func NewGitHistoryBackupIndex(path string) *GitHistoryBackupIndex {
+	return &GitHistoryBackupIndex{path: path}
+}
+
+// indexPath returns the file the index is stored in.
+func (i *GitHistoryBackupIndex) indexPath() (string, error) {
+	if i.path != "" {
+		return i.path, nil
+	}
+
+	return state.Path(GitHistoryBackupsStateFileName)
+}
+
+// List returns the indexed backups grouped by repository, newest first.
+func (i *GitHistoryBackupIndex) List() ([]domain.GitHistoryBackup, error) {
+	path, err := i.indexPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var file gitHistoryBackupIndexFile
+
+	err = state.ReadJSON(path, &file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []domain.GitHistoryBackup{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(file.Backups, func(a, b domain.GitHistoryBackup) int {
+		return cmp.Or(
+			cmp.Compare(a.RepoPath, b.RepoPath),
+			b.CreatedAt.Compare(a.CreatedAt),
+		)
+	})
+
+	return file.Backups, nil
+}
+
+// Add records a backup, replacing an entry for the same backup path.
+func (i *GitHistoryBackupIndex) Add(backup domain.GitHistoryBackup) error {
+	backups, err := i.List()
+	if err != nil {
+		return err
+	}
+
+	backups = slices.DeleteFunc(backups, func(b domain.GitHistoryBackup) bool { return b.Path == backup.Path })
+
+	return i.write(append(backups, backup))
+}
+
+// Find returns the indexed backup at path.
+func (i *GitHistoryBackupIndex) Find(path string) (domain.GitHistoryBackup, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return domain.GitHistoryBackup{}, fmt.Errorf("invalid backup path %q: %w", path, err) //nolint:exhaustruct
+	}
+
+	backups, err := i.List()
+	if err != nil {
+		return domain.GitHistoryBackup{}, err //nolint:exhaustruct
+	}
+
+	for _, b := range backups {
+		if b.Path == abs {
+			return b, nil
+		}
+	}
+
+	return domain.GitHistoryBackup{}, fmt.Errorf("%w: %s", ErrBackupNotIndexed, abs) //nolint:exhaustruct
+}
+
+// Prune deletes the backups created longer than retention before now and
+// drops them from the index, together with entries whose backup no longer
+// exists. Directories that are not bare repositories are left alone. In
+// dry-run mode nothing is deleted. It returns the pruned backups.
+func (i *GitHistoryBackupIndex) Prune(
+	retention time.Duration,
+	now time.Time,
+	dryRun bool,
+) ([]domain.GitHistoryBackup, error) {
+	backups, err := i.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		kept   []domain.GitHistoryBackup
+		pruned []domain.GitHistoryBackup
+		errs   []error
+	)
+
+	for _, b := range backups {
+		if _, statErr := os.Stat(b.Path); errors.Is(statErr, fs.ErrNotExist) {
+			continue
+		}
+
+		if now.Sub(b.CreatedAt) < retention {
+			kept = append(kept, b)
+
+			continue
+		}
+
+		if !isBareGitRepo(b.Path) {
+			errs = append(errs, fmt.Errorf("not pruning %s: not a bare git repository", b.Path))
+			kept = append(kept, b)
+
+			continue
+		}
+
+		if !dryRun {
+			if rmErr := os.RemoveAll(b.Path); rmErr != nil {
+				errs = append(errs, fmt.Errorf("failed to remove backup %s: %w", b.Path, rmErr))
+				kept = append(kept, b)
+
+				continue
+			}
+		}
+
+		pruned = append(pruned, b)
+	}
+
+	if !dryRun {
+		if writeErr := i.write(kept); writeErr != nil {
+			errs = append(errs, writeErr)
+		}
+	}
+
+	return pruned, errors.Join(errs...)
+}
+
+// write stores the backups as the index.
+func (i *GitHistoryBackupIndex) write(backups []domain.GitHistoryBackup) error {
+	path, err := i.indexPath()
+	if err != nil {
+		return err
+	}
+
+	if backups == nil {
+		backups = []domain.GitHistoryBackup{}
+	}
+
+	return state.WriteJSON(path, gitHistoryBackupIndexFile{Backups: backups})
+}
+
+// GitHistoryBackupSize returns the size of a backup on disk.
+func GitHistoryBackupSize(backupPath string) int64 {
+	return dirSize(backupPath)
+}
+
+// isBareGitRepo reports whether dir looks like a bare repository such as a
+// mirror backup.
+func isBareGitRepo(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runGit runs git with args and returns its trimmed output, including the
+// output in the error.
+func runGit(ctx context.Context, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w\nOutput: %s", strings.Join(args, " "), err, string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitRemotes returns the URL of each remote of the repository.
+func gitRemotes(ctx context.Context, repoPath string) map[string]string {
+	output, err := runGit(ctx, "-C", repoPath, "config", "--get-regexp", `^remote\..*\.url$`)
+	if err != nil {
+		return nil
+	}
+
+	remotes := make(map[string]string)
+
+	for line := range strings.Lines(output) {
+		key, url, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+
+		remotes[strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".url")] = url
+	}
+
+	return remotes
+}
+
+// VerifyGitHistoryBackup checks the backup's objects and refs with git fsck.
+func VerifyGitHistoryBackup(ctx context.Context, backupPath string) error {
+	ctx, cancel := context.WithTimeout(ctx, RestoreTimeout)
+	defer cancel()
+
+	if _, err := runGit(ctx, "--git-dir", backupPath, "fsck", "--full", "--no-progress"); err != nil {
+		return fmt.Errorf("%w: %w", ErrBackupCorrupt, err)
+	}
+
+	return nil
+}
+
+// RestoreGitHistoryBackup restores the refs and objects of a verified backup
+// into target, the backed-up repository if empty. Refs are replaced in a
+// single atomic transaction, so a failed restore leaves them untouched;
+// refs created after the backup are removed. A missing or empty target is
+// initialised with the backup's HEAD. Remotes git-filter-repo removed are
+// added back, and the working tree is reset to the restored HEAD.
+func RestoreGitHistoryBackup(ctx context.Context, backup domain.GitHistoryBackup, target string) error {
+	if target == "" {
+		target = backup.RepoPath
+	}
+
+	err := VerifyGitHistoryBackup(ctx, backup.Path)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RestoreTimeout)
+	defer cancel()
+
+	fresh, err := prepareRestoreTarget(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	_, err = runGit(ctx, "-C", target, "fetch", "--atomic", "--update-head-ok", "--prune", "--no-tags",
+		backup.Path, "+refs/*:refs/*")
+	if err != nil {
+		return fmt.Errorf("failed to restore refs from %s: %w", backup.Path, err)
+	}
+
+	if fresh {
+		head, headErr := runGit(ctx, "--git-dir", backup.Path, "symbolic-ref", "HEAD")
+		if headErr == nil {
+			_, headErr = runGit(ctx, "-C", target, "symbolic-ref", "HEAD", head)
+		}
+
+		if headErr != nil {
+			return fmt.Errorf("failed to set HEAD of %s: %w", target, headErr)
+		}
+	}
+
+	for name, url := range backup.Remotes {
+		if _, getErr := runGit(ctx, "-C", target, "remote", "get-url", name); getErr == nil {
+			continue
+		}
+
+		if _, addErr := runGit(ctx, "-C", target, "remote", "add", name, url); addErr != nil {
+			return fmt.Errorf("failed to restore remote %s: %w", name, addErr)
+		}
+	}
+
+	if _, err := runGit(ctx, "-C", target, "reset", "--hard", "--quiet"); err != nil {
+		return fmt.Errorf("failed to check out the restored HEAD in %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// prepareRestoreTarget initialises a missing or empty target and otherwise
+// checks it is a repository without uncommitted changes. It reports whether
+// the target was initialised.
+func prepareRestoreTarget(ctx context.Context, target string) (bool, error) {
+	entries, err := os.ReadDir(target)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("cannot read restore target %s: %w", target, err)
+	}
+
+	if len(entries) == 0 {
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return false, fmt.Errorf("cannot create restore target %s: %w", target, err)
+		}
+
+		if _, err := runGit(ctx, "init", "--quiet", target); err != nil {
+			return false, fmt.Errorf("cannot initialise restore target: %w", err)
+		}
+
+		return true, nil
+	}
+
+	checker := NewGitHistorySafetyChecker(target, false)
+	if !checker.isGitRepo(ctx) {
+		return false, fmt.Errorf("%w: %s", ErrRestoreTargetInUse, target)
+	}
+
+	if checker.hasUncommittedChanges(ctx) {
+		return false, fmt.Errorf("%w: %s; commit or stash them first", ErrRestoreTargetDirty, target)
+	}
+
+	return false, nil
+}
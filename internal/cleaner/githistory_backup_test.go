@@ -0,0 +1,189 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// gitOutput returns the trimmed output of a git command in dir.
+func gitOutput(dir string, args ...string) string {
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	return strings.TrimSpace(string(output))
+}
+
+var _ = ginkgo.Describe("Git history backups", func() {
+	var (
+		ctx     context.Context
+		tempDir string
+		index   *GitHistoryBackupIndex
+	)
+
+	ginkgo.BeforeEach(func() {
+		ctx = context.Background()
+		tempDir = ginkgo.GinkgoT().TempDir()
+		index = NewGitHistoryBackupIndex(filepath.Join(tempDir, "state", GitHistoryBackupsStateFileName))
+	})
+
+	newBareRepo := func(name string) string {
+		path := filepath.Join(tempDir, name)
+		runGitCommand(tempDir, "init", "--quiet", "--bare", path)
+
+		return path
+	}
+
+	ginkgo.Describe("newBackupPath", func() {
+		ginkgo.It("should timestamp the backup next to the repository", func() {
+			now := time.Date(2026, 10, 18, 9, 30, 5, 0, time.UTC)
+			gomega.Expect(newBackupPath("/src/repo", now)).To(gomega.Equal("/src/repo-20261018-093005-backup.git"))
+		})
+	})
+
+	ginkgo.Describe("GitHistoryBackupIndex", func() {
+		ginkgo.It("should list nothing without an index file", func() {
+			backups, err := index.List()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(backups).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should list backups by repository, newest first", func() {
+			now := time.Now().Truncate(time.Second)
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/b", Path: "/b-1", CreatedAt: now})).To(gomega.Succeed())
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/a", Path: "/a-1", CreatedAt: now.Add(-time.Hour)})).To(gomega.Succeed())
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/a", Path: "/a-2", CreatedAt: now})).To(gomega.Succeed())
+
+			backups, err := index.List()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			paths := make([]string, len(backups))
+			for i, b := range backups {
+				paths[i] = b.Path
+			}
+
+			gomega.Expect(paths).To(gomega.Equal([]string{"/a-2", "/a-1", "/b-1"}))
+		})
+
+		ginkgo.It("should find indexed backups and reject others", func() {
+			backup := newBareRepo("repo-backup.git")
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/repo", Path: backup})).To(gomega.Succeed())
+
+			found, err := index.Find(backup)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(found.RepoPath).To(gomega.Equal("/repo"))
+
+			_, err = index.Find(filepath.Join(tempDir, "other.git"))
+			gomega.Expect(errors.Is(err, ErrBackupNotIndexed)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should prune backups older than the retention", func() {
+			now := time.Now()
+			old := newBareRepo("old-backup.git")
+			recent := newBareRepo("recent-backup.git")
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/repo", Path: old, CreatedAt: now.Add(-40 * 24 * time.Hour)})).To(gomega.Succeed())
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/repo", Path: recent, CreatedAt: now})).To(gomega.Succeed())
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/repo", Path: filepath.Join(tempDir, "gone.git")})).To(gomega.Succeed())
+
+			pruned, err := index.Prune(GitHistoryDefaultBackupRetention, now, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(pruned).To(gomega.HaveLen(1))
+			gomega.Expect(old).To(gomega.BeADirectory())
+
+			pruned, err = index.Prune(GitHistoryDefaultBackupRetention, now, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(pruned).To(gomega.HaveLen(1))
+			gomega.Expect(pruned[0].Path).To(gomega.Equal(old))
+			gomega.Expect(old).NotTo(gomega.BeAnExistingFile())
+			gomega.Expect(recent).To(gomega.BeADirectory())
+
+			backups, err := index.List()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(backups).To(gomega.HaveLen(1))
+			gomega.Expect(backups[0].Path).To(gomega.Equal(recent))
+		})
+
+		ginkgo.It("should not prune directories that are not repositories", func() {
+			notRepo := filepath.Join(tempDir, "important")
+			gomega.Expect(os.MkdirAll(notRepo, 0o755)).To(gomega.Succeed())
+			gomega.Expect(index.Add(domain.GitHistoryBackup{RepoPath: "/repo", Path: notRepo})).To(gomega.Succeed())
+
+			pruned, err := index.Prune(time.Hour, time.Now(), false)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(pruned).To(gomega.BeEmpty())
+			gomega.Expect(notRepo).To(gomega.BeADirectory())
+		})
+	})
+
+	ginkgo.Describe("restoring", func() {
+		var (
+			repo     string
+			original string
+			backup   domain.GitHistoryBackup
+		)
+
+		ginkgo.BeforeEach(func() {
+			repo = filepath.Join(tempDir, "repo")
+			gomega.Expect(os.MkdirAll(repo, 0o755)).To(gomega.Succeed())
+			runGitCommand(repo, "init", "--quiet")
+			runGitCommand(repo, "config", "user.email", "test@example.com")
+			runGitCommand(repo, "config", "user.name", "Test User")
+			runGitCommand(repo, "config", "commit.gpgsign", "false")
+			runGitCommand(repo, "remote", "add", "origin", "https://example.com/repo.git")
+			setupInitialGitCommit(repo)
+			original = gitOutput(repo, "rev-parse", "HEAD")
+
+			executor := NewGitHistoryExecutor(repo, false, false, WithBackupIndex(index))
+			backupPath := filepath.Join(tempDir, "repo-backup.git")
+			gomega.Expect(executor.createBackup(ctx, backupPath)).To(gomega.Succeed())
+			executor.recordBackup(ctx, backupPath, domain.GitHistoryActionRemove, time.Now())
+
+			var err error
+			backup, err = index.Find(backupPath)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(backup.Remotes).To(gomega.HaveKeyWithValue("origin", "https://example.com/repo.git"))
+
+			// Simulate a rewrite: new history, a new branch and a removed remote
+			runGitCommand(repo, "commit", "--quiet", "--amend", "-m", "rewritten")
+			runGitCommand(repo, "branch", "after-backup")
+			runGitCommand(repo, "remote", "remove", "origin")
+		})
+
+		ginkgo.It("should verify the backup", func() {
+			gomega.Expect(VerifyGitHistoryBackup(ctx, backup.Path)).To(gomega.Succeed())
+			gomega.Expect(errors.Is(VerifyGitHistoryBackup(ctx, tempDir), ErrBackupCorrupt)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should restore refs and remotes into the original repository", func() {
+			gomega.Expect(RestoreGitHistoryBackup(ctx, backup, "")).To(gomega.Succeed())
+
+			gomega.Expect(gitOutput(repo, "rev-parse", "HEAD")).To(gomega.Equal(original))
+			gomega.Expect(gitOutput(repo, "branch", "--list", "after-backup")).To(gomega.BeEmpty())
+			gomega.Expect(gitOutput(repo, "remote", "get-url", "origin")).To(gomega.Equal("https://example.com/repo.git"))
+			gomega.Expect(gitOutput(repo, "status", "--porcelain")).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should restore into a new directory", func() {
+			target := filepath.Join(tempDir, "restored")
+			gomega.Expect(RestoreGitHistoryBackup(ctx, backup, target)).To(gomega.Succeed())
+
+			gomega.Expect(gitOutput(target, "rev-parse", "HEAD")).To(gomega.Equal(original))
+			gomega.Expect(filepath.Join(target, "README.md")).To(gomega.BeARegularFile())
+		})
+
+		ginkgo.It("should refuse a target with uncommitted changes", func() {
+			gomega.Expect(os.WriteFile(filepath.Join(repo, "README.md"), []byte("changed"), 0o644)).To(gomega.Succeed())
+
+			err := RestoreGitHistoryBackup(ctx, backup, "")
+			gomega.Expect(errors.Is(err, ErrRestoreTargetDirty)).To(gomega.BeTrue())
+		})
+	})
+})
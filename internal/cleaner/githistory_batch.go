@@ -0,0 +1,122 @@
+package cleaner
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// GitHistoryDefaultScanConcurrency is how many repositories a batch scan
+// reads at once by default.
+const GitHistoryDefaultScanConcurrency = 4
+
+// ScanGitRepositories scans the history of every repository in repos with
+// at most concurrency scans at a time (0 = default) and returns one report
+// per repository, ranked by RankGitHistoryReports. A repository that fails
+// to scan is reported with its error.
+func ScanGitRepositories(
+	ctx context.Context,
+	repos []string,
+	concurrency int,
+	opts ...GitHistoryScannerOption,
+) []domain.GitHistoryRepoReport {
+	if concurrency <= 0 {
+		concurrency = GitHistoryDefaultScanConcurrency
+	}
+
+	reports := make([]domain.GitHistoryRepoReport, len(repos))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, repo := range repos {
+		wg.Go(func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			reports[i] = scanGitRepository(ctx, repo, opts)
+		})
+	}
+
+	wg.Wait()
+
+	RankGitHistoryReports(reports)
+
+	return reports
+}
+
+// scanGitRepository scans one repository for a batch report.
+func scanGitRepository(ctx context.Context, repo string, opts []GitHistoryScannerOption) domain.GitHistoryRepoReport {
+	start := time.Now()
+	report := domain.GitHistoryRepoReport{RepoPath: repo} //nolint:exhaustruct
+
+	if ctx.Err() != nil {
+		report.Error = ctx.Err().Error()
+
+		return report
+	}
+
+	scanner := NewGitHistoryScanner(repo, opts...)
+
+	scan, err := scanner.Scan(ctx)
+	if err != nil {
+		report.Error = err.Error()
+		report.DurationMs = time.Since(start).Milliseconds()
+
+		return report
+	}
+
+	report = NewGitHistoryRepoReport(scan)
+	report.RepoSize, _ = scanner.GetRepoSize()
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	return report
+}
+
+// NewGitHistoryRepoReport summarizes a scan: files deleted in HEAD are
+// reclaimable, files still in HEAD are LFS candidates.
+func NewGitHistoryRepoReport(scan *domain.GitHistoryScanResult) domain.GitHistoryRepoReport {
+	report := domain.GitHistoryRepoReport{ //nolint:exhaustruct
+		RepoPath:   scan.RepoPath,
+		Files:      scan.Files,
+		DurationMs: scan.Duration.Milliseconds(),
+	}
+
+	for _, f := range scan.Files {
+		if f.IsDeleted {
+			report.ReclaimableBytes += f.SizeBytes
+
+			continue
+		}
+
+		report.LFSCandidates = append(report.LFSCandidates, f)
+		report.LFSCandidateBytes += f.SizeBytes
+	}
+
+	return report
+}
+
+// RankGitHistoryReports orders reports by reclaimable bytes, then by the size
+// of their LFS candidates, largest first; failed scans go last.
+func RankGitHistoryReports(reports []domain.GitHistoryRepoReport) {
+	failed := func(r domain.GitHistoryRepoReport) int {
+		if r.Error != "" {
+			return 1
+		}
+
+		return 0
+	}
+
+	slices.SortStableFunc(reports, func(a, b domain.GitHistoryRepoReport) int {
+		return cmp.Or(
+			cmp.Compare(failed(a), failed(b)),
+			cmp.Compare(b.ReclaimableBytes, a.ReclaimableBytes),
+			cmp.Compare(b.LFSCandidateBytes, a.LFSCandidateBytes),
+			cmp.Compare(a.RepoPath, b.RepoPath),
+		)
+	})
+}
@@ -0,0 +1,91 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("GitHistory batch scan", func() {
+	ginkgo.Describe("NewGitHistoryRepoReport", func() {
+		ginkgo.It("should split reclaimable blobs from LFS candidates", func() {
+			report := NewGitHistoryRepoReport(&domain.GitHistoryScanResult{ //nolint:exhaustruct
+				RepoPath: "/repo",
+				Files: []domain.GitHistoryFile{
+					{Path: "old.zip", SizeBytes: 300, IsDeleted: true}, //nolint:exhaustruct
+					{Path: "app.bin", SizeBytes: 200},                  //nolint:exhaustruct
+					{Path: "lib.so", SizeBytes: 100, IsDeleted: true},  //nolint:exhaustruct
+				},
+			})
+
+			gomega.Expect(report.ReclaimableBytes).To(gomega.Equal(int64(400)))
+			gomega.Expect(report.LFSCandidates).To(gomega.HaveLen(1))
+			gomega.Expect(report.LFSCandidateBytes).To(gomega.Equal(int64(200)))
+		})
+	})
+
+	ginkgo.Describe("RankGitHistoryReports", func() {
+		ginkgo.It("should rank by reclaimable bytes, then LFS candidates, failures last", func() {
+			reports := []domain.GitHistoryRepoReport{
+				{RepoPath: "/failed", Error: "not a git repository"}, //nolint:exhaustruct
+				{RepoPath: "/lfs", LFSCandidateBytes: 500},           //nolint:exhaustruct
+				{RepoPath: "/bloated", ReclaimableBytes: 1000},       //nolint:exhaustruct
+				{RepoPath: "/clean"},                                 //nolint:exhaustruct
+			}
+
+			RankGitHistoryReports(reports)
+
+			paths := make([]string, len(reports))
+			for i, r := range reports {
+				paths[i] = r.RepoPath
+			}
+
+			gomega.Expect(paths).To(gomega.Equal([]string{"/bloated", "/lfs", "/clean", "/failed"}))
+		})
+	})
+
+	ginkgo.Describe("ScanGitRepositories", func() {
+		ginkgo.It("should scan every repository and report the failing ones", func() {
+			root := ginkgo.GinkgoT().TempDir()
+			bloated := filepath.Join(root, "team", "bloated")
+			clean := filepath.Join(root, "clean")
+
+			for _, repo := range []string{bloated, clean} {
+				gomega.Expect(os.MkdirAll(repo, 0o755)).To(gomega.Succeed())
+				runGitCommand(repo, "init")
+				runGitCommand(repo, "config", "user.email", "test@example.com")
+				runGitCommand(repo, "config", "user.name", "Test User")
+				runGitCommand(repo, "config", "commit.gpgsign", "false")
+				setupInitialGitCommit(repo)
+			}
+
+			binary := make([]byte, 2*1024*1024)
+			for i := range binary {
+				binary[i] = byte(i % 251)
+			}
+
+			gomega.Expect(os.WriteFile(filepath.Join(bloated, "large.bin"), binary, 0o644)).To(gomega.Succeed())
+			runGitCommand(bloated, "add", "large.bin")
+			runGitCommand(bloated, "commit", "-m", "add binary")
+			runGitCommand(bloated, "rm", "-q", "large.bin")
+			runGitCommand(bloated, "commit", "-m", "remove binary")
+
+			repos, err := FindGitRepositories(root, GitHistoryDefaultMaxSearchDepth)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			reports := ScanGitRepositories(context.Background(), append(repos, filepath.Join(root, "missing")), 2)
+			gomega.Expect(reports).To(gomega.HaveLen(3))
+
+			gomega.Expect(reports[0].RepoPath).To(gomega.Equal(bloated))
+			gomega.Expect(reports[0].ReclaimableBytes).To(gomega.BeNumerically(">=", int64(2*1024*1024)))
+			gomega.Expect(reports[0].RepoSize).To(gomega.BeNumerically(">", 0))
+			gomega.Expect(reports[1].RepoPath).To(gomega.Equal(clean))
+			gomega.Expect(reports[1].HasFindings()).To(gomega.BeFalse())
+			gomega.Expect(reports[2].Error).NotTo(gomega.BeEmpty())
+		})
+	})
+})
@@ -33,6 +33,7 @@ type GitHistoryExecutor struct {
 	repoPath string
 	CleanerBase
 	packRatio float64
+	backups   *GitHistoryBackupIndex
 }
 
 // GitHistoryExecutorOption is a functional option for the executor.
@@ -45,6 +46,13 @@ func WithPackRatio(ratio float64) GitHistoryExecutorOption {
 	}
 }
 
+// WithBackupIndex sets the index backups are recorded in.
+func WithBackupIndex(index *GitHistoryBackupIndex) GitHistoryExecutorOption {
+	return func(e *GitHistoryExecutor) {
+		e.backups = index
+	}
+}
+
 // NewGitHistoryExecutor creates a new executor.
 func NewGitHistoryExecutor(
 	repoPath string,
@@ -55,6 +63,7 @@ func NewGitHistoryExecutor(
 		repoPath:    repoPath,
 		CleanerBase: NewCleanerBase(verbose, dryRun),
 		packRatio:   DefaultPackRatio,
+		backups:     NewGitHistoryBackupIndex(""),
 	}
 
 	for _, opt := range opts {
@@ -66,10 +75,21 @@ func NewGitHistoryExecutor(
 
 // ExecuteOptions configures the history rewrite.
 type ExecuteOptions struct {
+	// FilesToRemove are the files removed, or with GitHistoryActionLFS
+	// replaced by LFS pointers, or with GitHistoryActionPurge the blobs
+	// whose secrets are redacted.
 	FilesToRemove []domain.GitHistoryFile
-	CreateBackup  bool
-	BackupPath    string
-	SkipGC        bool // Skip garbage collection (for testing)
+	// RemovePaths are directory prefixes and globs removed from all history
+	// instead of the paths of FilesToRemove, which are then the blobs they
+	// match.
+	RemovePaths []string
+	Action      domain.GitHistoryAction
+	// LFSByExtension migrates every file with the extension of a selected
+	// file instead of only the selected paths.
+	LFSByExtension bool
+	CreateBackup   bool
+	BackupPath     string
+	SkipGC         bool // Skip garbage collection (for testing)
 }
 
 // Execute runs the history rewrite.
@@ -83,8 +103,33 @@ func (e *GitHistoryExecutor) Execute(
 		return nil, errors.New("no files to remove")
 	}
 
-	// Get old repo size
+	if len(opts.RemovePaths) > 0 && opts.Action == domain.GitHistoryActionLFS {
+		return nil, ErrRemovePathsWithLFS
+	}
+
+	if len(opts.RemovePaths) > 0 && opts.Action == domain.GitHistoryActionPurge {
+		return nil, ErrPurgeWithPaths
+	}
+
+	removePaths, err := parseHistoryPaths(opts.RemovePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var lfsPatterns []string
+
+	if opts.Action == domain.GitHistoryActionLFS {
+		patterns, err := LFSMigratePatterns(opts.FilesToRemove, opts.LFSByExtension)
+		if err != nil {
+			return nil, err
+		}
+
+		lfsPatterns = patterns
+	}
+
+	// Get old repo size, without the LFS objects a migration adds to
 	oldSize, _ := e.getRepoSize()
+	oldSize -= e.getLFSObjectsSize()
 
 	// Create backup if requested
 	var (
@@ -98,18 +143,23 @@ func (e *GitHistoryExecutor) Execute(
 			backupPath = e.getDefaultBackupPath()
 		}
 
-		err := e.createBackup(ctx, backupPath)
+		err = e.createBackup(ctx, backupPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create backup at backupPath=%v: %w", backupPath, err)
 		}
 
 		backupCreated = true
+
+		e.recordBackup(ctx, backupPath, opts.Action, start)
 	}
 
 	if e.dryRun {
 		return &domain.GitHistoryRewriteResult{ //nolint:exhaustruct
+			Action:          opts.Action,
 			FilesRemoved:    opts.FilesToRemove,
 			BytesRemoved:    e.calculateTotalSize(opts.FilesToRemove),
+			RemovePaths:     removePaths.specs(),
+			LFSPatterns:     lfsPatterns,
 			CommitsAffected: 0,
 			OldRepoSize:     oldSize,
 			BackupCreated:   false,
@@ -118,10 +168,14 @@ func (e *GitHistoryExecutor) Execute(
 		}, nil
 	}
 
-	// Execute git-filter-repo
-	commitsAffected, err := e.runFilterRepo(ctx, opts.FilesToRemove)
+	before, err := snapshotHistory(ctx, e.repoPath)
 	if err != nil {
-		return nil, fmt.Errorf("git-filter-repo failed: %w", err)
+		return nil, fmt.Errorf("failed to snapshot history before the rewrite: %w", err)
+	}
+
+	commitsAffected, commitMap, err := e.rewrite(ctx, opts.FilesToRemove, removePaths, lfsPatterns, opts.Action)
+	if err != nil {
+		return nil, err
 	}
 
 	// Run garbage collection
@@ -133,14 +187,20 @@ func (e *GitHistoryExecutor) Execute(
 	}
 
 	// Get new repo size
+	lfsObjectBytes := e.getLFSObjectsSize()
 	newSize, _ := e.getRepoSize()
+	newSize -= lfsObjectBytes
 
 	// Calculate bytes reclaimed
 	bytesReclaimed := max(oldSize-newSize, 0)
 
-	return &domain.GitHistoryRewriteResult{
+	result := &domain.GitHistoryRewriteResult{ //nolint:exhaustruct
+		Action:          opts.Action,
 		FilesRemoved:    opts.FilesToRemove,
 		BytesRemoved:    e.calculateTotalSize(opts.FilesToRemove),
+		RemovePaths:     removePaths.specs(),
+		LFSPatterns:     lfsPatterns,
+		LFSObjectBytes:  lfsObjectBytes,
 		CommitsAffected: commitsAffected,
 		OldRepoSize:     oldSize,
 		NewRepoSize:     newSize,
@@ -149,28 +209,102 @@ func (e *GitHistoryExecutor) Execute(
 		BackupPath:      backupPath,
 		ExecutedAt:      time.Now(),
 		Duration:        time.Since(start),
-	}, nil
+	}
+
+	e.verifyAndHandOff(ctx, result, removePaths, before, commitMap)
+
+	return result, nil
 }
 
-// runFilterRepo executes git-filter-repo to remove the specified files.
-func (e *GitHistoryExecutor) runFilterRepo(
+// verifyAndHandOff verifies the rewrite against the snapshot from before it
+// and writes the handoff report for collaborators. A report that cannot be
+// written is only a warning; the verification is in the result either way.
+func (e *GitHistoryExecutor) verifyAndHandOff(
+	ctx context.Context,
+	result *domain.GitHistoryRewriteResult,
+	removePaths historyPaths,
+	before *historySnapshot,
+	commitMap []domain.GitHistoryCommitMapping,
+) {
+	after, err := snapshotHistory(ctx, e.repoPath)
+	if err != nil {
+		after = &historySnapshot{} //nolint:exhaustruct
+	}
+
+	verification := verifyRewrite(ctx, e.repoPath, before, after, result.FilesRemoved, removePaths, result.Action)
+	result.Verification = &verification
+
+	handoff := newGitHistoryHandoff(e.repoPath, result, before, after, commitMap, verification)
+
+	paths, err := writeGitHistoryHandoff(e.repoPath, handoff)
+	if err != nil && e.verbose {
+		fmt.Printf("Warning: failed to write the handoff report: %v\n", err)
+	}
+
+	result.HandoffPaths = paths
+}
+
+// rewrite rewrites history with the tool of the action and returns the
+// number of commits affected and the old and new hash of each changed commit.
+func (e *GitHistoryExecutor) rewrite(
 	ctx context.Context,
 	files []domain.GitHistoryFile,
-) (int, error) {
-	ctx, cancel := context.WithTimeout(ctx, FilterRepoTimeout)
-	defer cancel()
+	removePaths historyPaths,
+	lfsPatterns []string,
+	action domain.GitHistoryAction,
+) (int, []domain.GitHistoryCommitMapping, error) {
+	if action == domain.GitHistoryActionLFS {
+		commits, commitMap, err := e.runLFSMigrate(ctx, lfsPatterns)
+		if err != nil {
+			return 0, nil, fmt.Errorf("git lfs migrate failed: %w", err)
+		}
 
+		return commits, commitMap, nil
+	}
+
+	var (
+		commits int
+		err     error
+	)
+
+	if action == domain.GitHistoryActionPurge {
+		commits, err = e.runReplaceText(ctx, files)
+	} else {
+		commits, err = e.runFilterRepo(ctx, filterRepoRemoveArgs(files, removePaths))
+	}
+
+	if err != nil {
+		return 0, nil, fmt.Errorf("git-filter-repo failed: %w", err)
+	}
+
+	return commits, readCommitMap(filterRepoCommitMap(e.repoPath), " "), nil
+}
+
+// filterRepoRemoveArgs returns the git-filter-repo arguments removing the
+// remove paths, or without them the paths of the files.
+func filterRepoRemoveArgs(files []domain.GitHistoryFile, removePaths historyPaths) []string {
 	// Build arguments for git-filter-repo
-	args := make([]string, 0, 1+2*len(files)+1)
+	args := make([]string, 0, 1+2*max(len(files), len(removePaths))+1)
 	args = append(args, "--force")
 
-	// Add paths to remove
-	for _, file := range files {
-		args = append(args, "--path", file.Path)
+	if len(removePaths) > 0 {
+		args = append(args, removePaths.filterRepoArgs()...)
+	} else {
+		// Add paths to remove
+		for _, file := range GetUniquePaths(files) {
+			args = append(args, "--path", file)
+		}
 	}
 
 	// Invert paths (remove instead of keep)
-	args = append(args, "--invert-paths")
+	return append(args, "--invert-paths")
+}
+
+// runFilterRepo executes git-filter-repo with the arguments that select
+// what to remove.
+func (e *GitHistoryExecutor) runFilterRepo(ctx context.Context, args []string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, FilterRepoTimeout)
+	defer cancel()
 
 	if e.verbose {
 		gitfilterrepo.LogFilterRepoCommand(e.verbose, args)
@@ -181,6 +315,10 @@ func (e *GitHistoryExecutor) runFilterRepo(
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		if DetectFilterRepoProvider() == FilterRepoNone {
+			return 0, NewNotAvailableError(CleanerGitHistory, "git-filter-repo is not installed")
+		}
+
 		return 0, fmt.Errorf("%w\nOutput: %s", err, string(output))
 	}
 
@@ -224,6 +362,29 @@ func (e *GitHistoryExecutor) createBackup(ctx context.Context, backupPath string
 	return createGitMirrorBackup(ctx, e.repoPath, backupPath)
 }
 
+// recordBackup adds the backup to the index so it can be listed, restored
+// and pruned. A failure only loses the index entry, not the backup.
+func (e *GitHistoryExecutor) recordBackup(
+	ctx context.Context,
+	backupPath string,
+	action domain.GitHistoryAction,
+	createdAt time.Time,
+) {
+	repoPath, _ := filepath.Abs(e.repoPath)
+	backupPath, _ = filepath.Abs(backupPath)
+
+	err := e.backups.Add(domain.GitHistoryBackup{
+		RepoPath:  repoPath,
+		Path:      backupPath,
+		CreatedAt: createdAt,
+		Action:    action,
+		Remotes:   gitRemotes(ctx, e.repoPath),
+	})
+	if err != nil && e.verbose {
+		fmt.Printf("Warning: failed to record backup %s in the backup index: %v\n", backupPath, err)
+	}
+}
+
 // createGitMirrorBackup creates a mirror backup of a git repository.
 // This is a shared helper used by both GitHistoryExecutor and GitHistorySafetyChecker.
 // If the backup path already exists, it will be removed before creating the new backup.
@@ -284,6 +445,26 @@ func getGitDirSize(repoPath string) (int64, error) {
 	return size, nil
 }
 
+// dirSize returns the total size of the files below dir, skipping files it
+// cannot access; 0 if dir does not exist.
+func dirSize(dir string) int64 {
+	var size int64
+
+	_ = filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Skip files we can't access
+		}
+
+		if info, infoErr := d.Info(); infoErr == nil && !d.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
+
 // getDefaultBackupPath returns the default backup path.
 func (e *GitHistoryExecutor) getDefaultBackupPath() string {
 	return getDefaultBackupPath(e.repoPath)
@@ -0,0 +1,322 @@
+package cleaner
+
+import (
+	"bufio"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+const (
+	// handoffDirName is the directory in .git the handoff reports are written to.
+	handoffDirName = "clean-wizard"
+	// handoffMaxCommitRows limits the commit map in the Markdown report; the
+	// JSON report has all of them.
+	handoffMaxCommitRows = 100
+	// nullCommit is the hash git-filter-repo maps dropped commits to.
+	nullCommit = "0000000000000000000000000000000000000000"
+)
+
+// filterRepoCommitMap returns the path of the commit map git-filter-repo
+// writes.
+func filterRepoCommitMap(repoPath string) string {
+	return filepath.Join(repoPath, ".git", "filter-repo", "commit-map")
+}
+
+// readCommitMap reads a commit map with one "old<sep>new" pair per line -
+// git-filter-repo's commit-map or git lfs migrate's object map - and returns
+// the commits that changed. A missing map yields nothing.
+func readCommitMap(path, sep string) []domain.GitHistoryCommitMapping {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var mappings []domain.GitHistoryCommitMapping
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), sep)
+		if len(fields) != 2 {
+			continue
+		}
+
+		old, updated := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+		if old == "old" || old == updated {
+			continue
+		}
+
+		if updated == nullCommit {
+			updated = ""
+		}
+
+		mappings = append(mappings, domain.GitHistoryCommitMapping{Old: old, New: updated})
+	}
+
+	return mappings
+}
+
+// newGitHistoryHandoff builds the handoff report of a rewrite from the
+// snapshots before and after it.
+func newGitHistoryHandoff(
+	repoPath string,
+	result *domain.GitHistoryRewriteResult,
+	before, after *historySnapshot,
+	commits []domain.GitHistoryCommitMapping,
+	verification domain.GitHistoryVerification,
+) domain.GitHistoryHandoff {
+	absPath, _ := filepath.Abs(repoPath)
+
+	h := domain.GitHistoryHandoff{
+		RepoPath:             absPath,
+		Action:               result.Action,
+		Files:                GetUniquePaths(result.FilesRemoved),
+		RemovePaths:          result.RemovePaths,
+		GeneratedAt:          time.Now(),
+		BackupPath:           result.BackupPath,
+		Remote:               "",
+		RemoteURL:            "",
+		Refs:                 []domain.GitHistoryRefUpdate{},
+		Commits:              commits,
+		MaintainerCommands:   []string{},
+		CollaboratorCommands: []string{},
+		Verification:         verification,
+	}
+
+	if h.Commits == nil {
+		h.Commits = []domain.GitHistoryCommitMapping{}
+	}
+
+	for ref, old := range before.refs {
+		if updated, ok := after.refs[ref]; ok && updated != old {
+			h.Refs = append(h.Refs, domain.GitHistoryRefUpdate{Ref: ref, Old: old, New: updated})
+		}
+	}
+
+	slices.SortFunc(h.Refs, func(a, b domain.GitHistoryRefUpdate) int { return strings.Compare(a.Ref, b.Ref) })
+
+	h.Remote = handoffRemote(before.remotes)
+	h.RemoteURL = before.remotes[h.Remote]
+
+	if h.Remote != "" {
+		h.MaintainerCommands, h.CollaboratorCommands = handoffCommands(h, after)
+	}
+
+	return h
+}
+
+// handoffRemote picks the remote to publish to: origin, else the first by name.
+func handoffRemote(remotes map[string]string) string {
+	if _, ok := remotes["origin"]; ok {
+		return "origin"
+	}
+
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	return names[0]
+}
+
+// handoffCommands returns the commands that publish the rewrite and the
+// commands collaborators run to re-sync their clones.
+func handoffCommands(h domain.GitHistoryHandoff, after *historySnapshot) ([]string, []string) {
+	var maintainer, collaborator []string
+
+	if _, ok := after.remotes[h.Remote]; !ok {
+		maintainer = append(maintainer, fmt.Sprintf("git remote add %s %s", h.Remote, h.RemoteURL))
+	}
+
+	if h.Action == domain.GitHistoryActionLFS {
+		maintainer = append(maintainer, "git lfs push --all "+h.Remote)
+	}
+
+	for _, r := range h.Refs {
+		maintainer = append(maintainer,
+			fmt.Sprintf("git push --force-with-lease=%s:%s %s %s", r.Ref, r.Old, h.Remote, r.Ref))
+	}
+
+	collaborator = append(collaborator,
+		"git fetch "+h.Remote+" --prune",
+		"git fetch "+h.Remote+" --tags --force",
+	)
+
+	for _, r := range h.Refs {
+		branch, ok := strings.CutPrefix(r.Ref, "refs/heads/")
+		if !ok {
+			continue
+		}
+
+		collaborator = append(collaborator,
+			fmt.Sprintf("# unpushed work on %s: git rebase --onto %s/%s %s <your-branch>",
+				branch, h.Remote, branch, r.Old),
+			fmt.Sprintf("git checkout %s && git reset --hard %s/%s", branch, h.Remote, branch),
+		)
+	}
+
+	if h.Action == domain.GitHistoryActionLFS {
+		collaborator = append(collaborator, "git lfs install", "git lfs pull")
+	}
+
+	collaborator = append(collaborator, "git reflog expire --expire=now --all && git gc --prune=now")
+
+	return maintainer, collaborator
+}
+
+// RenderGitHistoryHandoffMarkdown renders the handoff report as Markdown.
+func RenderGitHistoryHandoffMarkdown(h domain.GitHistoryHandoff) string {
+	var b strings.Builder
+
+	verb := "Removed"
+
+	switch h.Action {
+	case domain.GitHistoryActionLFS:
+		verb = "Migrated to Git LFS"
+	case domain.GitHistoryActionPurge:
+		verb = "Redacted the secrets of"
+	case domain.GitHistoryActionRemove:
+	}
+
+	fmt.Fprintf(&b, "# History rewrite of %s\n\n", filepath.Base(h.RepoPath))
+	fmt.Fprintf(&b, "Rewritten on %s in `%s`. %s %d file(s) in every commit",
+		h.GeneratedAt.Format(time.RFC1123), h.RepoPath, verb, len(h.Files))
+
+	// Every file below a removed directory would drown the report
+	listed := h.Files
+	if len(h.RemovePaths) > 0 {
+		b.WriteString(", everything matching")
+		listed = h.RemovePaths
+	}
+
+	b.WriteString(":\n\n")
+
+	for _, f := range listed {
+		fmt.Fprintf(&b, "- `%s`\n", f)
+	}
+
+	if h.Action == domain.GitHistoryActionPurge {
+		b.WriteString("\nRedacting history does not revoke a credential: rotate every purged secret.")
+
+		if h.BackupPath != "" {
+			b.WriteString(" The backup still contains them; delete it once the rewrite is pushed.")
+		}
+
+		b.WriteString("\n")
+	}
+
+	if h.BackupPath != "" {
+		fmt.Fprintf(&b, "\nBackup: `%s` (`clean-wizard git-history restore %s`)\n", h.BackupPath, h.BackupPath)
+	}
+
+	v := h.Verification
+
+	b.WriteString("\n## Verification\n\n")
+	fmt.Fprintf(&b, "- Commits: %d before, %d after\n", v.CommitsBefore, v.CommitsAfter)
+	fmt.Fprintf(&b, "- HEAD: %d path(s) changed as intended, %d unexpected change(s)\n",
+		len(v.IntendedChanges), len(v.UnexpectedChanges))
+	fmt.Fprintf(&b, "- Branches and tags missing: %d\n", len(v.MissingRefs))
+	fmt.Fprintf(&b, "- Selected blobs still reachable: %d\n", len(v.ReachableBlobs))
+	fmt.Fprintf(&b, "- git fsck: %s\n", map[bool]string{true: "passed", false: "FAILED"}[v.FsckPassed])
+
+	if problems := v.Problems(); len(problems) > 0 {
+		b.WriteString("\n**Do not push.** The rewrite changed more than intended:\n\n")
+
+		for _, p := range problems {
+			fmt.Fprintf(&b, "- %s\n", p)
+		}
+	}
+
+	b.WriteString("\n## Branches and tags to force-push\n\n")
+
+	if len(h.Refs) == 0 {
+		b.WriteString("None.\n")
+	} else {
+		b.WriteString("| Ref | Old | New |\n| --- | --- | --- |\n")
+
+		for _, r := range h.Refs {
+			fmt.Fprintf(&b, "| `%s` | `%s` | `%s` |\n", r.Ref, shortHash(r.Old), shortHash(r.New))
+		}
+	}
+
+	if h.Remote == "" {
+		b.WriteString("\nThe repository has no remote; nothing to publish.\n")
+	} else {
+		fmt.Fprintf(&b, "\n## Publish (maintainer)\n\n```sh\n%s\n```\n", strings.Join(h.MaintainerCommands, "\n"))
+		fmt.Fprintf(&b, "\n## Re-sync (collaborators)\n\nIn every existing clone of `%s`:\n\n```sh\n%s\n```\n",
+			h.RemoteURL, strings.Join(h.CollaboratorCommands, "\n"))
+		fmt.Fprintf(&b, "\nA fresh clone works too: `git clone %s`.\n", h.RemoteURL)
+	}
+
+	fmt.Fprintf(&b, "\n## Commit map\n\n%d commit(s) rewritten.\n", len(h.Commits))
+
+	if len(h.Commits) > 0 {
+		b.WriteString("\n| Old | New |\n| --- | --- |\n")
+
+		for _, c := range h.Commits[:min(len(h.Commits), handoffMaxCommitRows)] {
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", shortHash(c.Old), cmpOrDropped(shortHash(c.New)))
+		}
+
+		if len(h.Commits) > handoffMaxCommitRows {
+			fmt.Fprintf(&b, "\n%d more in the JSON report.\n", len(h.Commits)-handoffMaxCommitRows)
+		}
+	}
+
+	return b.String()
+}
+
+// shortHash abbreviates a hash for display.
+func shortHash(hash string) string {
+	return hash[:min(len(hash), 12)]
+}
+
+// cmpOrDropped shows a dropped commit's empty new hash as "dropped".
+func cmpOrDropped(hash string) string {
+	if hash == "" {
+		return "dropped"
+	}
+
+	return hash
+}
+
+// writeGitHistoryHandoff writes the handoff report as Markdown and JSON to
+// .git/clean-wizard and returns their paths.
+func writeGitHistoryHandoff(repoPath string, h domain.GitHistoryHandoff) ([]string, error) {
+	dir := filepath.Join(repoPath, ".git", handoffDirName)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	data, err := json.Marshal(h, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode handoff report: %w", err)
+	}
+
+	base := filepath.Join(dir, "handoff-"+h.GeneratedAt.Format(backupTimestampFormat))
+	paths := []string{base + ".md", base + ".json"}
+
+	for i, content := range [][]byte{[]byte(RenderGitHistoryHandoffMarkdown(h)), data} {
+		if err := os.WriteFile(paths[i], content, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write handoff report %s: %w", paths[i], err)
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,147 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// LFSMigrateTimeout is the timeout for git lfs migrate import.
+const LFSMigrateTimeout = 10 * time.Minute
+
+// lfsRewrittenCommits matches the progress line of git lfs migrate, e.g.
+// "migrate: Rewriting commits: 100% (12/12), done.".
+var lfsRewrittenCommits = regexp.MustCompile(`Rewriting commits:\s+\d+%\s+\((\d+)/\d+\)`) //nolint:gochecknoglobals
+
+// ErrLFSPatternComma is returned for paths git lfs migrate cannot include,
+// because it splits its --include list on commas.
+var ErrLFSPatternComma = errors.New("git lfs migrate cannot include paths containing a comma")
+
+// LFSMigratePatterns returns the .gitattributes patterns git lfs migrate
+// tracks for the files: each file's own path, or with byExtension the
+// "*.ext" pattern of its extension so later commits of the same type land
+// in LFS too. Extensionless files always use their path.
+func LFSMigratePatterns(files []domain.GitHistoryFile, byExtension bool) ([]string, error) {
+	patterns := make([]string, 0, len(files))
+
+	for _, f := range files {
+		if strings.Contains(f.Path, ",") {
+			return nil, fmt.Errorf("%w: %s", ErrLFSPatternComma, f.Path)
+		}
+
+		pattern := escapeLFSPattern(f.Path)
+		if ext := filepath.Ext(f.Path); byExtension && ext != "" {
+			pattern = "*" + escapeLFSPattern(ext)
+		}
+
+		if !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	slices.Sort(patterns)
+
+	return patterns, nil
+}
+
+// escapeLFSPattern quotes the wildcard characters of a path so it matches
+// only itself; spaces become a character class as .gitattributes patterns
+// end at whitespace.
+func escapeLFSPattern(path string) string {
+	var b strings.Builder
+
+	for _, r := range path {
+		switch r {
+		case '*', '?', '[', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case ' ':
+			b.WriteString("[[:space:]]")
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// lfsMigrateArgs returns the git arguments that rewrite every ref, replacing
+// the files matching patterns with LFS pointers, tracking the patterns in
+// .gitattributes and writing the old and new commits to objectMap.
+func lfsMigrateArgs(repoPath string, patterns []string, objectMap string) []string {
+	return []string{
+		"-C", repoPath,
+		"lfs", "migrate", "import",
+		"--everything",
+		"--include=" + strings.Join(patterns, ","),
+		"--object-map=" + objectMap,
+	}
+}
+
+// runLFSMigrate executes git lfs migrate import for the patterns and returns
+// the number of rewritten commits and the commit map.
+func (e *GitHistoryExecutor) runLFSMigrate(
+	ctx context.Context,
+	patterns []string,
+) (int, []domain.GitHistoryCommitMapping, error) {
+	ctx, cancel := context.WithTimeout(ctx, LFSMigrateTimeout)
+	defer cancel()
+
+	objectMap, err := os.CreateTemp("", "clean-wizard-lfs-object-map-*.csv")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create object map: %w", err)
+	}
+
+	_ = objectMap.Close()
+
+	defer func() { _ = os.Remove(objectMap.Name()) }()
+
+	args := lfsMigrateArgs(e.repoPath, patterns, objectMap.Name())
+
+	if e.verbose {
+		fmt.Printf("Running: git %s\n", strings.Join(args, " "))
+	}
+
+	output, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil {
+		if exec.CommandContext(ctx, "git", "lfs", "version").Run() != nil {
+			return 0, nil, NewNotAvailableError(CleanerGitHistory, "git-lfs is not installed")
+		}
+
+		return 0, nil, fmt.Errorf("%w\nOutput: %s", err, string(output))
+	}
+
+	return parseLFSCommitCount(string(output)), readCommitMap(objectMap.Name(), ","), nil
+}
+
+// parseLFSCommitCount parses git lfs migrate output to count rewritten
+// commits, taking the last progress update.
+func parseLFSCommitCount(output string) int {
+	matches := lfsRewrittenCommits.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+
+	count, err := strconv.Atoi(matches[len(matches)-1][1])
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// getLFSObjectsSize returns the size of the LFS objects stored in the
+// repository, 0 if it has none.
+func (e *GitHistoryExecutor) getLFSObjectsSize() int64 {
+	return dirSize(filepath.Join(e.repoPath, ".git", "lfs", "objects"))
+}
@@ -0,0 +1,142 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Git LFS migration", func() {
+	ginkgo.Describe("LFSMigratePatterns", func() {
+		files := []domain.GitHistoryFile{
+			{Path: "assets/logo.psd", SizeBytes: 1},
+			{Path: "assets/banner.psd", SizeBytes: 1},
+			{Path: "bin/server", SizeBytes: 1},
+		}
+
+		ginkgo.It("should track each selected path", func() {
+			patterns, err := LFSMigratePatterns(files, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(patterns).To(gomega.Equal([]string{
+				"assets/banner.psd", "assets/logo.psd", "bin/server",
+			}))
+		})
+
+		ginkgo.It("should track extensions once and keep extensionless paths", func() {
+			patterns, err := LFSMigratePatterns(files, true)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(patterns).To(gomega.Equal([]string{"*.psd", "bin/server"}))
+		})
+
+		ginkgo.It("should escape wildcards and spaces", func() {
+			patterns, err := LFSMigratePatterns([]domain.GitHistoryFile{{Path: "my docs/[draft]*.bin"}}, false)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(patterns).To(gomega.Equal([]string{`my[[:space:]]docs/\[draft]\*.bin`}))
+		})
+
+		ginkgo.It("should reject paths containing a comma", func() {
+			_, err := LFSMigratePatterns([]domain.GitHistoryFile{{Path: "a,b.bin"}}, false)
+			gomega.Expect(errors.Is(err, ErrLFSPatternComma)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("lfsMigrateArgs", func() {
+		ginkgo.It("should rewrite every ref for the joined patterns", func() {
+			gomega.Expect(lfsMigrateArgs("/repo", []string{"*.psd", "bin/server"}, "/tmp/map")).To(gomega.Equal([]string{
+				"-C", "/repo", "lfs", "migrate", "import", "--everything", "--include=*.psd,bin/server",
+				"--object-map=/tmp/map",
+			}))
+		})
+	})
+
+	ginkgo.Describe("parseLFSCommitCount", func() {
+		ginkgo.It("should take the last progress update", func() {
+			output := "migrate: Sorting commits: ..., done.\n" +
+				"migrate: Rewriting commits:  50% (6/12)\r" +
+				"migrate: Rewriting commits: 100% (12/12), done.\n" +
+				"  main\tabc -> def\n"
+			gomega.Expect(parseLFSCommitCount(output)).To(gomega.Equal(12))
+		})
+
+		ginkgo.It("should return 0 without progress output", func() {
+			gomega.Expect(parseLFSCommitCount("nothing to migrate")).To(gomega.Equal(0))
+		})
+	})
+
+	ginkgo.Describe("Execute in dry run mode", func() {
+		ginkgo.It("should report the patterns a migration tracks", func() {
+			executor := NewGitHistoryExecutor(ginkgo.GinkgoT().TempDir(), false, true)
+
+			result, err := executor.Execute(context.Background(), ExecuteOptions{ //nolint:exhaustruct
+				FilesToRemove:  []domain.GitHistoryFile{{Path: "assets/logo.psd", SizeBytes: 5 * 1024 * 1024}},
+				Action:         domain.GitHistoryActionLFS,
+				LFSByExtension: true,
+			})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(result.Action).To(gomega.Equal(domain.GitHistoryActionLFS))
+			gomega.Expect(result.LFSPatterns).To(gomega.Equal([]string{"*.psd"}))
+			gomega.Expect(result.BytesRemoved).To(gomega.Equal(int64(5 * 1024 * 1024)))
+
+			data, err := json.Marshal(result.Action)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(string(data)).To(gomega.Equal(`"lfs"`))
+		})
+	})
+
+	ginkgo.Describe("safety checks for a migration", func() {
+		ginkgo.It("should require Git LFS instead of git-filter-repo", func() {
+			repo := ginkgo.GinkgoT().TempDir()
+			runGitCommand(repo, "init")
+			runGitCommand(repo, "config", "user.email", "test@example.com")
+			runGitCommand(repo, "config", "user.name", "Test User")
+			runGitCommand(repo, "config", "commit.gpgsign", "false")
+			setupInitialGitCommit(repo)
+
+			checker := NewGitHistorySafetyChecker(repo, false)
+			checker.SetAction(domain.GitHistoryActionLFS)
+
+			report := checker.Check(context.Background())
+			gomega.Expect(report.Action).To(gomega.Equal(domain.GitHistoryActionLFS))
+			gomega.Expect(report.Blockers).NotTo(gomega.ContainElement(gomega.ContainSubstring("git-filter-repo")))
+
+			installed := exec.Command("git", "lfs", "version").Run() == nil
+			gomega.Expect(report.LFSInstalled).To(gomega.Equal(installed))
+			gomega.Expect(report.CanProceed()).To(gomega.Equal(installed))
+
+			if !installed {
+				gomega.Expect(report.Blockers).To(gomega.ContainElement(gomega.ContainSubstring("Git LFS is not installed")))
+			}
+		})
+
+		ginkgo.It("should not let the filter-repo check decide CanProceed", func() {
+			report := domain.GitHistorySafetyReport{ //nolint:exhaustruct
+				IsGitRepo:    true,
+				Action:       domain.GitHistoryActionLFS,
+				LFSInstalled: true,
+			}
+			gomega.Expect(report.CanProceed()).To(gomega.BeTrue())
+
+			report.Action = domain.GitHistoryActionRemove
+			gomega.Expect(report.CanProceed()).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("getLFSObjectsSize", func() {
+		ginkgo.It("should sum the objects under .git/lfs/objects", func() {
+			repo := ginkgo.GinkgoT().TempDir()
+			objects := filepath.Join(repo, ".git", "lfs", "objects", "ab", "cd")
+			gomega.Expect(os.MkdirAll(objects, 0o755)).To(gomega.Succeed())
+			gomega.Expect(os.WriteFile(filepath.Join(objects, "abcd"), make([]byte, 2048), 0o644)).To(gomega.Succeed())
+
+			executor := NewGitHistoryExecutor(repo, false, false)
+			gomega.Expect(executor.getLFSObjectsSize()).To(gomega.Equal(int64(2048)))
+		})
+	})
+})
@@ -0,0 +1,320 @@
+package cleaner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// GitHistoryDefaultDirectoryDepth is how many directory levels the history
+// size is aggregated over by default.
+const GitHistoryDefaultDirectoryDepth = 2
+
+// historyPathGlobChars are the characters that make a remove path a glob.
+const historyPathGlobChars = "*?["
+
+// ErrInvalidHistoryPath is returned for remove paths that cannot match a
+// path in a repository.
+var ErrInvalidHistoryPath = errors.New("invalid history path")
+
+// historyPath is a directory prefix or glob removed from all history.
+type historyPath struct {
+	// spec is the path as given, passed on to git-filter-repo.
+	spec string
+	// glob matches the whole path for globs; nil for prefixes.
+	glob *regexp.Regexp
+}
+
+// historyPaths are the paths removed from all history.
+type historyPaths []historyPath
+
+// parseHistoryPaths parses remove paths. A path containing *, ? or [ is a
+// glob matched against the whole path, where * also matches slashes, as in
+// git-filter-repo's --path-glob. Any other path is a prefix as in its
+// --path: the file itself or everything below the directory; a trailing
+// slash matches only a directory.
+func parseHistoryPaths(specs []string) (historyPaths, error) {
+	paths := make(historyPaths, 0, len(specs))
+
+	for _, spec := range specs {
+		spec = strings.TrimPrefix(strings.TrimSpace(spec), "./")
+
+		if spec == "" || spec == "/" || path.IsAbs(spec) ||
+			slices.Contains(strings.Split(strings.TrimSuffix(spec, "/"), "/"), "..") {
+			return nil, fmt.Errorf("%w %q: use a path relative to the repository root", ErrInvalidHistoryPath, spec)
+		}
+
+		p := historyPath{spec: spec, glob: nil}
+
+		if strings.ContainsAny(spec, historyPathGlobChars) {
+			glob, err := regexp.Compile(globToRegexp(spec))
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidHistoryPath, spec, err)
+			}
+
+			p.glob = glob
+		}
+
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+// ValidateRemovePaths checks that the directory prefixes and globs can be
+// removed from history.
+func ValidateRemovePaths(paths []string) error {
+	_, err := parseHistoryPaths(paths)
+
+	return err
+}
+
+// globToRegexp translates a glob with fnmatch semantics, as used by
+// git-filter-repo, to an anchored regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if negated, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + negated
+			}
+
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// Match reports whether the file at path is removed by any of the paths.
+func (ps historyPaths) Match(filePath string) bool {
+	for _, p := range ps {
+		switch {
+		case p.glob != nil:
+			if p.glob.MatchString(filePath) {
+				return true
+			}
+		case strings.HasSuffix(p.spec, "/"):
+			if strings.HasPrefix(filePath, p.spec) {
+				return true
+			}
+		case filePath == p.spec || strings.HasPrefix(filePath, p.spec+"/"):
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterRepoArgs returns the git-filter-repo arguments selecting the paths.
+func (ps historyPaths) filterRepoArgs() []string {
+	args := make([]string, 0, 2*len(ps))
+
+	for _, p := range ps {
+		if p.glob != nil {
+			args = append(args, "--path-glob", p.spec)
+		} else {
+			args = append(args, "--path", p.spec)
+		}
+	}
+
+	return args
+}
+
+// specs returns the paths as given, normalised.
+func (ps historyPaths) specs() []string {
+	specs := make([]string, len(ps))
+	for i, p := range ps {
+		specs[i] = p.spec
+	}
+
+	return specs
+}
+
+// historyBlob is a blob reachable from a ref, with the path it was
+// committed at.
+type historyBlob struct {
+	hash string
+	path string
+	size int64
+}
+
+// listHistoryBlobs returns every blob reachable from a ref with its path
+// and size.
+func (s *GitHistoryScanner) listHistoryBlobs(ctx context.Context) ([]historyBlob, error) {
+	objectPaths, err := s.getObjectPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runGit(ctx, "-C", s.repoPath, "cat-file", "--batch-check", "--batch-all-objects")
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file --batch-check failed: %w", err)
+	}
+
+	var blobs []historyBlob
+
+	for line := range strings.Lines(output) {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "blob" {
+			continue
+		}
+
+		blobPath, ok := objectPaths[fields[0]]
+		if !ok || blobPath == "" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		blobs = append(blobs, historyBlob{hash: fields[0], path: blobPath, size: size})
+	}
+
+	return blobs, nil
+}
+
+// findPathBlobs returns every blob in history matching the remove paths,
+// whatever its size or type, one entry per blob.
+func (s *GitHistoryScanner) findPathBlobs(
+	ctx context.Context,
+	removePaths historyPaths,
+) ([]domain.GitHistoryFile, error) {
+	blobs, err := s.listHistoryBlobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []domain.GitHistoryFile
+
+	for _, b := range blobs {
+		if !removePaths.Match(b.path) {
+			continue
+		}
+
+		files = append(files, domain.GitHistoryFile{ //nolint:exhaustruct
+			Path:      b.path,
+			SizeBytes: b.size,
+			BlobHash:  b.hash,
+			Extension: strings.ToLower(path.Ext(b.path)),
+		})
+	}
+
+	s.markDeletedFiles(ctx, files)
+
+	return files, nil
+}
+
+// ScanDirectories aggregates the size of every blob in history by the
+// directories it was committed in, down to depth levels, largest first,
+// so bloated folders stand out even when no single blob is large.
+func (s *GitHistoryScanner) ScanDirectories(ctx context.Context, depth int) ([]domain.GitHistoryDirectory, error) {
+	if !s.isGitRepo(ctx) {
+		return nil, fmt.Errorf("not a git repository: %s", s.repoPath)
+	}
+
+	if depth <= 0 {
+		depth = GitHistoryDefaultDirectoryDepth
+	}
+
+	blobs, err := s.listHistoryBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan git history: %w", err)
+	}
+
+	dirs := make(map[string]*domain.GitHistoryDirectory)
+	paths := make(map[string]map[string]bool)
+
+	for _, b := range blobs {
+		for _, prefix := range directoryPrefixes(b.path, depth) {
+			dir, ok := dirs[prefix]
+			if !ok {
+				dir = &domain.GitHistoryDirectory{Prefix: prefix} //nolint:exhaustruct
+				dirs[prefix] = dir
+				paths[prefix] = make(map[string]bool)
+			}
+
+			dir.SizeBytes += b.size
+			dir.Blobs++
+			paths[prefix][b.path] = true
+		}
+	}
+
+	inHEAD := s.headDirectories(ctx)
+
+	result := make([]domain.GitHistoryDirectory, 0, len(dirs))
+	for prefix, dir := range dirs {
+		dir.Paths = len(paths[prefix])
+		dir.InHEAD = inHEAD[prefix]
+		result = append(result, *dir)
+	}
+
+	slices.SortFunc(result, func(a, b domain.GitHistoryDirectory) int {
+		return cmp.Or(cmp.Compare(b.SizeBytes, a.SizeBytes), strings.Compare(a.Prefix, b.Prefix))
+	})
+
+	return result, nil
+}
+
+// directoryPrefixes returns the directories of a file path down to depth
+// levels, each with a trailing slash: a/b/c.txt yields a/ and a/b/.
+func directoryPrefixes(filePath string, depth int) []string {
+	parts := strings.Split(filePath, "/")
+	parts = parts[:len(parts)-1]
+
+	prefixes := make([]string, 0, min(len(parts), depth))
+	for i := range min(len(parts), depth) {
+		prefixes = append(prefixes, strings.Join(parts[:i+1], "/")+"/")
+	}
+
+	return prefixes
+}
+
+// headDirectories returns the directories in HEAD, with a trailing slash.
+func (s *GitHistoryScanner) headDirectories(ctx context.Context) map[string]bool {
+	output, err := runGit(ctx, "-C", s.repoPath, "ls-tree", "-r", "-d", "-z", "--name-only", "HEAD")
+	if err != nil {
+		return nil
+	}
+
+	dirs := make(map[string]bool)
+
+	for dir := range strings.SplitSeq(output, "\x00") {
+		if dir != "" {
+			dirs[dir+"/"] = true
+		}
+	}
+
+	return dirs
+}
@@ -0,0 +1,177 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Git history path removal", func() {
+	ginkgo.Describe("parseHistoryPaths", func() {
+		ginkgo.DescribeTable("should reject paths outside the repository",
+			func(spec string) {
+				_, err := parseHistoryPaths([]string{spec})
+				gomega.Expect(errors.Is(err, ErrInvalidHistoryPath)).To(gomega.BeTrue())
+			},
+			ginkgo.Entry("empty", ""),
+			ginkgo.Entry("root", "/"),
+			ginkgo.Entry("absolute", "/etc/passwd"),
+			ginkgo.Entry("parent", "../other"),
+			ginkgo.Entry("nested parent", "vendor/../../other/"),
+		)
+
+		ginkgo.It("should strip a leading ./", func() {
+			paths, err := parseHistoryPaths([]string{"./vendor/"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(paths.specs()).To(gomega.Equal([]string{"vendor/"}))
+		})
+	})
+
+	ginkgo.DescribeTable("Match",
+		func(spec, path string, want bool) {
+			paths, err := parseHistoryPaths([]string{spec})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(paths.Match(path)).To(gomega.Equal(want))
+		},
+		ginkgo.Entry("directory prefix", "vendor/", "vendor/a/b.go", true),
+		ginkgo.Entry("directory prefix is not a file", "vendor/", "vendor", false),
+		ginkgo.Entry("directory prefix is not a name prefix", "vendor/", "vendored/a.go", false),
+		ginkgo.Entry("bare prefix matches the file", "vendor", "vendor", true),
+		ginkgo.Entry("bare prefix matches the directory", "vendor", "vendor/a.go", true),
+		ginkgo.Entry("bare prefix is not a name prefix", "vendor", "vendored/a.go", false),
+		ginkgo.Entry("nested directory", ".reports/html/", ".reports/html/styles/tailwind.css", true),
+		ginkgo.Entry("glob star crosses slashes", "reports/*.txt", "reports/2025/dupl.txt", true),
+		ginkgo.Entry("glob is anchored", "reports/*.txt", "old/reports/a.txt", false),
+		ginkgo.Entry("glob question mark", "build-?.log", "build-1.log", true),
+		ginkgo.Entry("glob class", "*.[ao]", "lib/x.o", true),
+		ginkgo.Entry("negated glob class", "*.[!ao]", "lib/x.o", false),
+		ginkgo.Entry("glob dot is literal", "*.txt", "notes-txt", false),
+	)
+
+	ginkgo.Describe("filterRepoRemoveArgs", func() {
+		ginkgo.It("should pass prefixes as --path and globs as --path-glob", func() {
+			paths, err := parseHistoryPaths([]string{"vendor/", "reports/*.txt"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(filterRepoRemoveArgs(nil, paths)).To(gomega.Equal([]string{
+				"--force", "--path", "vendor/", "--path-glob", "reports/*.txt", "--invert-paths",
+			}))
+		})
+
+		ginkgo.It("should pass each selected file once without remove paths", func() {
+			files := []domain.GitHistoryFile{{Path: "b.bin"}, {Path: "a.bin"}, {Path: "b.bin"}} //nolint:exhaustruct
+
+			gomega.Expect(filterRepoRemoveArgs(files, nil)).To(gomega.Equal([]string{
+				"--force", "--path", "a.bin", "--path", "b.bin", "--invert-paths",
+			}))
+		})
+	})
+
+	ginkgo.Describe("directoryPrefixes", func() {
+		ginkgo.It("should list the directories down to the depth", func() {
+			gomega.Expect(directoryPrefixes("a/b/c/d.txt", 2)).To(gomega.Equal([]string{"a/", "a/b/"}))
+			gomega.Expect(directoryPrefixes("a/d.txt", 2)).To(gomega.Equal([]string{"a/"}))
+			gomega.Expect(directoryPrefixes("d.txt", 2)).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("in a repository", func() {
+		var (
+			ctx  context.Context
+			repo string
+		)
+
+		write := func(path, content string) {
+			full := filepath.Join(repo, path)
+			gomega.Expect(os.MkdirAll(filepath.Dir(full), 0o755)).To(gomega.Succeed())
+			gomega.Expect(os.WriteFile(full, []byte(content), 0o644)).To(gomega.Succeed())
+		}
+
+		ginkgo.BeforeEach(func() {
+			ctx = context.Background()
+			repo = ginkgo.GinkgoT().TempDir()
+			runGitCommand(repo, "init", "--quiet")
+			runGitCommand(repo, "config", "user.email", "test@example.com")
+			runGitCommand(repo, "config", "user.name", "Test User")
+			runGitCommand(repo, "config", "commit.gpgsign", "false")
+
+			write("vendor/lib/a.go", "package lib // v1")
+			write("reports/old report.txt", strings.Repeat("r", 300))
+			write("LICENSE", "MIT")
+			write("vendor/lib/LICENSE", "MIT")
+			setupInitialGitCommit(repo)
+
+			write("vendor/lib/a.go", "package lib // v2")
+			runGitCommand(repo, "rm", "--quiet", "-r", "reports")
+			runGitCommand(repo, "add", ".")
+			runGitCommand(repo, "commit", "--quiet", "-m", "update")
+		})
+
+		ginkgo.It("should select every blob below the remove paths, whatever its size", func() {
+			result, err := NewGitHistoryScanner(repo, WithRemovePaths([]string{"vendor/", "reports/*.txt"})).Scan(ctx)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// Two versions of a.go; the LICENSE blob is listed at the root
+			gomega.Expect(GetUniquePaths(result.Files)).To(gomega.Equal([]string{"reports/old report.txt", "vendor/lib/a.go"}))
+			gomega.Expect(result.Files).To(gomega.HaveLen(3))
+			gomega.Expect(result.Files[0].Path).To(gomega.Equal("reports/old report.txt"))
+			gomega.Expect(result.Files[0].IsDeleted).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should fail the scan for invalid remove paths", func() {
+			_, err := NewGitHistoryScanner(repo, WithRemovePaths([]string{"../x"})).Scan(ctx)
+			gomega.Expect(errors.Is(err, ErrInvalidHistoryPath)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should aggregate the history size by directory", func() {
+			dirs, err := NewGitHistoryScanner(repo).ScanDirectories(ctx, 2)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(dirs[0].Prefix).To(gomega.Equal("reports/"))
+			gomega.Expect(dirs[0].SizeBytes).To(gomega.Equal(int64(300)))
+			gomega.Expect(dirs[0].InHEAD).To(gomega.BeFalse())
+
+			var vendor domain.GitHistoryDirectory
+
+			for _, d := range dirs {
+				if d.Prefix == "vendor/lib/" {
+					vendor = d
+				}
+			}
+
+			gomega.Expect(vendor.Paths).To(gomega.Equal(1))
+			gomega.Expect(vendor.Blobs).To(gomega.Equal(2))
+			gomega.Expect(vendor.InHEAD).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should verify a removal that keeps identical content elsewhere", func() {
+			files, err := NewGitHistoryScanner(repo, WithRemovePaths([]string{"vendor/"})).Scan(ctx)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			before, err := snapshotHistory(ctx, repo)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			// Simulate the rewrite of the last commit; the root LICENSE
+			// shares its blob with vendor/lib/LICENSE and stays reachable
+			runGitCommand(repo, "rm", "--quiet", "-r", "--cached", "vendor")
+			runGitCommand(repo, "commit", "--quiet", "--amend", "-m", "update")
+
+			after, err := snapshotHistory(ctx, repo)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			paths, err := parseHistoryPaths([]string{"vendor/"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			v := verifyRewrite(ctx, repo, before, after, files.Files, paths, domain.GitHistoryActionRemove)
+			gomega.Expect(v.UnexpectedChanges).To(gomega.BeEmpty())
+			gomega.Expect(v.IntendedChanges).To(gomega.ConsistOf("vendor/lib/LICENSE", "vendor/lib/a.go"))
+			gomega.Expect(v.MissingRefs).To(gomega.BeEmpty())
+		})
+	})
+})
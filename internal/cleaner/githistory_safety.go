@@ -16,6 +16,7 @@ import (
 type GitHistorySafetyChecker struct {
 	repoPath string
 	verbose  bool
+	action   domain.GitHistoryAction
 }
 
 // NewGitHistorySafetyChecker creates a new safety checker.
@@ -23,9 +24,16 @@ func NewGitHistorySafetyChecker(repoPath string, verbose bool) *GitHistorySafety
 	return &GitHistorySafetyChecker{
 		repoPath: repoPath,
 		verbose:  verbose,
+		action:   domain.GitHistoryActionRemove,
 	}
 }
 
+// SetAction sets the rewrite the checks are run for; it decides whether
+// git-filter-repo or Git LFS must be installed.
+func (c *GitHistorySafetyChecker) SetAction(action domain.GitHistoryAction) {
+	c.action = action
+}
+
 // Protected branch names that require extra caution.
 var protectedBranches = map[string]bool{ //nolint:gochecknoglobals
 	"main":       true, //nolint:goconst
@@ -44,6 +52,7 @@ func (c *GitHistorySafetyChecker) Check(ctx context.Context) *domain.GitHistoryS
 	defer cancel()
 
 	report := &domain.GitHistorySafetyReport{ //nolint:exhaustruct
+		Action:   c.action,
 		Warnings: []string{},
 		Blockers: []string{},
 	}
@@ -95,20 +104,29 @@ func (c *GitHistorySafetyChecker) Check(ctx context.Context) *domain.GitHistoryS
 	report.FilterRepoAvailable = c.isFilterRepoAvailable()
 
 	report.FilterRepoProvider = DetectFilterRepoProvider().String()
-	if !report.FilterRepoAvailable {
+	if !report.FilterRepoAvailable && c.action != domain.GitHistoryActionLFS {
 		report.Blockers = append(
 			report.Blockers,
 			"git-filter-repo is not installed. Install with: brew install git-filter-repo, or ensure nix is available to use it automatically",
 		)
 	}
 
+	// Check if git lfs is available for migrating files to LFS
+	report.LFSInstalled = c.isLFSInstalled(ctx)
+	if !report.LFSInstalled && c.action == domain.GitHistoryActionLFS {
+		report.Blockers = append(
+			report.Blockers,
+			"Git LFS is not installed. Install with: brew install git-lfs, then run: git lfs install",
+		)
+	}
+
 	// Check backup capability
 	report.DefaultBackupPath = c.getDefaultBackupPath()
 	report.CanCreateBackup = c.canCreateBackup(report.DefaultBackupPath)
 
 	// Check for Git LFS
 	report.HasLFS = c.hasLFS()
-	if report.HasLFS {
+	if report.HasLFS && c.action != domain.GitHistoryActionLFS {
 		report.Warnings = append(
 			report.Warnings,
 			"Git LFS is configured. History rewriting may affect LFS objects.",
@@ -247,6 +265,11 @@ func (c *GitHistorySafetyChecker) isFilterRepoAvailable() bool {
 	return provider != FilterRepoNone
 }
 
+// isLFSInstalled checks if the git lfs extension is installed.
+func (c *GitHistorySafetyChecker) isLFSInstalled(ctx context.Context) bool {
+	return c.newGitCommand(ctx, "lfs", "version").Run() == nil
+}
+
 // getDefaultBackupPath returns the default backup path.
 func (c *GitHistorySafetyChecker) getDefaultBackupPath() string {
 	return getDefaultBackupPath(c.repoPath)
@@ -22,6 +22,9 @@ type GitHistoryScanner struct {
 	excludeExts  map[string]bool
 	includeExts  map[string]bool
 	excludePaths []string
+	removePaths  []string
+	secretScan   bool
+	secretRules  []domain.GitHistorySecretRule
 	maxFiles     int
 }
 
@@ -84,6 +87,23 @@ func WithExcludePaths(paths []string) GitHistoryScannerOption {
 	}
 }
 
+// WithRemovePaths selects every blob in history below the directory
+// prefixes or matching the globs instead of large binaries.
+func WithRemovePaths(paths []string) GitHistoryScannerOption {
+	return func(s *GitHistoryScanner) {
+		s.removePaths = paths
+	}
+}
+
+// WithSecretScan selects every blob in history containing a secret instead
+// of large binaries, with the built-in secret rules and the extra rules.
+func WithSecretScan(extraRules []domain.GitHistorySecretRule) GitHistoryScannerOption {
+	return func(s *GitHistoryScanner) {
+		s.secretScan = true
+		s.secretRules = extraRules
+	}
+}
+
 // WithMaxFiles sets the maximum number of files to return.
 func WithMaxFiles(maxFiles int) GitHistoryScannerOption {
 	return func(s *GitHistoryScanner) {
@@ -100,7 +120,8 @@ func WithVerbose(verbose bool) GitHistoryScannerOption {
 	}
 }
 
-// Scan scans git history for large binary files.
+// Scan scans git history for large binary files, or with remove paths for
+// every blob below them, or in a secrets scan for every blob with secrets.
 func (s *GitHistoryScanner) Scan(ctx context.Context) (*domain.GitHistoryScanResult, error) {
 	start := time.Now()
 
@@ -109,19 +130,9 @@ func (s *GitHistoryScanner) Scan(ctx context.Context) (*domain.GitHistoryScanRes
 		return nil, fmt.Errorf("not a git repository: %s", s.repoPath)
 	}
 
-	// Get all large blobs from history
-	files, err := s.findLargeBlobs(ctx)
+	files, err := s.scanFiles(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan git history: %w", err)
-	}
-
-	// Filter and sort files
-	files = s.filterFiles(files)
-	domain.SortBySizeDesc(files)
-
-	// Limit results
-	if len(files) > s.maxFiles {
-		files = files[:s.maxFiles]
+		return nil, err
 	}
 
 	// Calculate total size
@@ -140,6 +151,55 @@ func (s *GitHistoryScanner) Scan(ctx context.Context) (*domain.GitHistoryScanRes
 	}, nil
 }
 
+// scanFiles returns the files to clean: every blob with a secret, every
+// blob matching the remove paths, or the largest binaries that pass the
+// filters.
+func (s *GitHistoryScanner) scanFiles(ctx context.Context) ([]domain.GitHistoryFile, error) {
+	if s.secretScan {
+		// Every leaked secret must be found, so none are left out
+		files, err := s.findSecretBlobs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan git history for secrets: %w", err)
+		}
+
+		return files, nil
+	}
+
+	if len(s.removePaths) > 0 {
+		removePaths, err := parseHistoryPaths(s.removePaths)
+		if err != nil {
+			return nil, err
+		}
+
+		// All of them are removed, so none are left out
+		files, err := s.findPathBlobs(ctx, removePaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan git history: %w", err)
+		}
+
+		domain.SortBySizeDesc(files)
+
+		return files, nil
+	}
+
+	// Get all large blobs from history
+	files, err := s.findLargeBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan git history: %w", err)
+	}
+
+	// Filter and sort files
+	files = s.filterFiles(files)
+	domain.SortBySizeDesc(files)
+
+	// Limit results
+	if len(files) > s.maxFiles {
+		files = files[:s.maxFiles]
+	}
+
+	return files, nil
+}
+
 // isGitRepo checks if the path is a git repository.
 func (s *GitHistoryScanner) isGitRepo(ctx context.Context) bool {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -196,14 +256,10 @@ func (s *GitHistoryScanner) getObjectPaths(ctx context.Context) (map[string]stri
 			continue
 		}
 
-		parts := strings.Fields(line)
-		if len(parts) < 1 {
-			continue
-		}
-
-		objectHash := parts[0]
-		if len(parts) > 1 {
-			objectPaths[objectHash] = parts[1]
+		// Paths may contain spaces
+		objectHash, path, ok := strings.Cut(line, " ")
+		if ok && path != "" {
+			objectPaths[objectHash] = path
 		}
 	}
 
@@ -0,0 +1,334 @@
+package cleaner
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+const (
+	// secretScanMaxBlobSize skips larger blobs in a secrets scan; secrets
+	// are committed in source and config files, not in large binaries.
+	secretScanMaxBlobSize = 1024 * 1024
+	// secretScanBinarySniffBytes is how much of a blob is checked for a NUL
+	// byte, which marks it as binary, as git does.
+	secretScanBinarySniffBytes = 8000
+	// secretRedaction replaces each purged secret in history.
+	secretRedaction = "***REMOVED***"
+	// secretRedactedPrefix is how many characters of a secret are shown.
+	secretRedactedPrefix = 4
+)
+
+// ErrPurgeWithPaths is returned when a secrets purge is combined with remove
+// paths or an LFS migration; a purge redacts content and keeps every file.
+var ErrPurgeWithPaths = errors.New("secrets are purged by content; remove paths and Git LFS do not apply")
+
+// secretRule is a compiled secret rule.
+type secretRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// compileSecretRules compiles the built-in secret rules and the extra rules.
+func compileSecretRules(extra []domain.GitHistorySecretRule) ([]secretRule, error) {
+	all := slices.Concat(domain.DefaultGitHistorySecretRules, extra)
+	rules := make([]secretRule, 0, len(all))
+
+	for _, r := range all {
+		err := r.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, secretRule{name: r.Name, re: regexp.MustCompile(r.Pattern)})
+	}
+
+	return rules, nil
+}
+
+// findSecretBlobs returns every blob in history containing a secret, with
+// the secrets found in it, one entry per blob.
+func (s *GitHistoryScanner) findSecretBlobs(ctx context.Context) ([]domain.GitHistoryFile, error) {
+	rules, err := compileSecretRules(s.secretRules)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs, err := s.listHistoryBlobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs = slices.DeleteFunc(blobs, func(b historyBlob) bool {
+		return b.size == 0 || b.size > secretScanMaxBlobSize
+	})
+
+	var files []domain.GitHistoryFile
+
+	err = readHistoryBlobs(ctx, s.repoPath, blobs, func(b historyBlob, content []byte) {
+		secrets := findSecrets(rules, content)
+		if len(secrets) == 0 {
+			return
+		}
+
+		files = append(files, domain.GitHistoryFile{ //nolint:exhaustruct
+			Path:      b.path,
+			SizeBytes: b.size,
+			BlobHash:  b.hash,
+			Extension: strings.ToLower(path.Ext(b.path)),
+			Secrets:   secrets,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.markDeletedFiles(ctx, files)
+
+	slices.SortFunc(files, func(a, b domain.GitHistoryFile) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.BlobHash, b.BlobHash))
+	})
+
+	return files, nil
+}
+
+// readHistoryBlobs streams the content of the blobs through a single
+// git cat-file --batch and calls fn for each of them.
+func readHistoryBlobs(
+	ctx context.Context,
+	repoPath string,
+	blobs []historyBlob,
+	fn func(b historyBlob, content []byte),
+) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "cat-file", "--batch")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("git cat-file --batch failed: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("git cat-file --batch failed: %w", err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("git cat-file --batch failed: %w", err)
+	}
+
+	go func() {
+		w := bufio.NewWriter(stdin)
+		for _, b := range blobs {
+			_, _ = w.WriteString(b.hash + "\n")
+		}
+
+		_ = w.Flush()
+		_ = stdin.Close()
+	}()
+
+	r := bufio.NewReader(stdout)
+
+	for _, b := range blobs {
+		content, err := readBatchObject(r)
+		if err != nil {
+			_ = cmd.Wait()
+
+			return fmt.Errorf("git cat-file --batch failed reading %s: %w", b.hash, err)
+		}
+
+		if content != nil {
+			fn(b, content)
+		}
+	}
+
+	err = cmd.Wait()
+	if err != nil {
+		return fmt.Errorf("git cat-file --batch failed: %w", err)
+	}
+
+	return nil
+}
+
+// readBatchObject reads one object of git cat-file --batch output:
+// "<hash> <type> <size>", the content and a newline. A missing object
+// yields nil content.
+func readBatchObject(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, nil //nolint:nilnil // "<hash> missing"
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid object header %q: %w", strings.TrimSpace(header), err)
+	}
+
+	content := make([]byte, size+1)
+
+	_, err = io.ReadFull(r, content)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return content[:size], nil
+}
+
+// findSecrets returns the secrets the rules find in a text blob, each
+// distinct secret once per rule at its first line; binary blobs have none.
+func findSecrets(rules []secretRule, content []byte) []domain.GitHistorySecret {
+	if bytes.IndexByte(content[:min(len(content), secretScanBinarySniffBytes)], 0) >= 0 {
+		return nil
+	}
+
+	var secrets []domain.GitHistorySecret
+
+	for _, rule := range rules {
+		seen := make(map[string]bool)
+
+		for _, m := range rule.re.FindAllSubmatchIndex(content, -1) {
+			start, end := m[0], m[1]
+			if len(m) >= 4 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+
+			value := string(content[start:end])
+			if value == "" || seen[value] {
+				continue
+			}
+
+			seen[value] = true
+
+			secrets = append(secrets, domain.GitHistorySecret{
+				Rule:     rule.name,
+				Line:     bytes.Count(content[:start], []byte("\n")) + 1,
+				Redacted: redactSecret(value),
+				Value:    value,
+			})
+		}
+	}
+
+	return secrets
+}
+
+// redactSecret shows the first line of a multi-line secret, such as the
+// header of a private key, and otherwise the first characters of longer
+// secrets, so a finding can be recognised without revealing it.
+func redactSecret(value string) string {
+	if first, _, multiline := strings.Cut(value, "\n"); multiline {
+		return strings.TrimSpace(first) + " …"
+	}
+
+	if len(value) <= 2*secretRedactedPrefix {
+		return strings.Repeat("*", len(value))
+	}
+
+	return value[:secretRedactedPrefix] + strings.Repeat("*", min(len(value)-secretRedactedPrefix, 16))
+}
+
+// purgedSecrets returns the distinct secrets of the files, longest first so
+// a secret containing another is redacted whole.
+func purgedSecrets(files []domain.GitHistoryFile) []string {
+	var values []string
+
+	for _, f := range files {
+		for _, secret := range f.Secrets {
+			values = append(values, secret.Value)
+		}
+	}
+
+	slices.SortFunc(values, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+
+	return slices.Compact(values)
+}
+
+// replaceTextExpressions returns the git-filter-repo --replace-text lines
+// redacting the secrets. Secrets spanning lines, or containing the ==>
+// separator, are passed as escaped regular expressions; the rest literally.
+func replaceTextExpressions(secrets []string) []string {
+	lines := make([]string, 0, len(secrets))
+
+	for _, secret := range secrets {
+		if !strings.ContainsAny(secret, "\r\n") && !strings.Contains(secret, "==>") {
+			lines = append(lines, "literal:"+secret+"==>"+secretRedaction)
+
+			continue
+		}
+
+		escaped := strings.NewReplacer("\n", `\n`, "\r", `\r`, "=", `\=`).Replace(regexp.QuoteMeta(secret))
+		lines = append(lines, "regex:"+escaped+"==>"+secretRedaction)
+	}
+
+	return lines
+}
+
+// runReplaceText redacts the secrets of the files in every commit with
+// git-filter-repo --replace-text. The expressions file holds the secrets,
+// so it is private to the user and removed right after the rewrite.
+func (e *GitHistoryExecutor) runReplaceText(ctx context.Context, files []domain.GitHistoryFile) (int, error) {
+	secrets := purgedSecrets(files)
+	if len(secrets) == 0 {
+		return 0, errors.New("no secrets to purge")
+	}
+
+	f, err := os.CreateTemp("", "clean-wizard-replace-text-*.txt")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the replace-text file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	_, err = f.WriteString(strings.Join(replaceTextExpressions(secrets), "\n") + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to write the replace-text file: %w", err)
+	}
+
+	return e.runFilterRepo(ctx, []string{"--force", "--replace-text", f.Name()})
+}
+
+// isRedactedBlob reports whether the blob of a tree entry had secrets
+// redacted: it contains the redaction and none of the secrets. The blobs
+// from before a purge are gone after it, so only the new ones are checked.
+func isRedactedBlob(ctx context.Context, repoPath, entry string, secrets []string) bool {
+	_, blob, ok := strings.Cut(entry, " ")
+	if !ok {
+		return false
+	}
+
+	content, err := exec.CommandContext(ctx, "git", "-C", repoPath, "cat-file", "blob", blob).Output()
+	if err != nil || !bytes.Contains(content, []byte(secretRedaction)) {
+		return false
+	}
+
+	return !slices.ContainsFunc(secrets, func(secret string) bool {
+		return bytes.Contains(content, []byte(secret))
+	})
+}
@@ -0,0 +1,230 @@
+package cleaner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// lfsPointerPrefix starts every Git LFS pointer file.
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+// ErrRewriteVerificationFailed is returned when a rewritten repository
+// differs from its state before the rewrite in more than the selected files.
+var ErrRewriteVerificationFailed = errors.New("history rewrite verification failed")
+
+// ErrRemovePathsWithLFS is returned when remove paths are combined with an
+// LFS migration, which selects files by .gitattributes patterns instead.
+var ErrRemovePathsWithLFS = errors.New("remove paths cannot be migrated to Git LFS; select the files instead")
+
+// historySnapshot is the state of a repository a rewrite is verified against.
+type historySnapshot struct {
+	// refs maps each branch and tag to the object it points to.
+	refs map[string]string
+	// tree maps each path in HEAD to its mode and blob.
+	tree map[string]string
+	// commits are the commits reachable from any ref.
+	commits int
+	// remotes maps each remote to its URL.
+	remotes map[string]string
+	// branch is the checked-out branch, "" if HEAD is detached.
+	branch string
+}
+
+// snapshotHistory records the refs, the HEAD tree and the commits of the
+// repository.
+func snapshotHistory(ctx context.Context, repoPath string) (*historySnapshot, error) {
+	refs, err := runGit(ctx, "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)",
+		"refs/heads", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := runGit(ctx, "-C", repoPath, "rev-list", "--all", "--count")
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &historySnapshot{
+		refs:    make(map[string]string),
+		tree:    make(map[string]string),
+		commits: 0,
+		remotes: gitRemotes(ctx, repoPath),
+		branch:  "",
+	}
+
+	for line := range strings.Lines(refs) {
+		if object, ref, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			snap.refs[ref] = object
+		}
+	}
+
+	snap.commits, _ = strconv.Atoi(commits)
+
+	if branch, err := runGit(ctx, "-C", repoPath, "symbolic-ref", "--short", "HEAD"); err == nil {
+		snap.branch = branch
+	}
+
+	// An unborn HEAD has no tree to compare.
+	tree, err := runGit(ctx, "-C", repoPath, "ls-tree", "-r", "-z", "--full-tree", "HEAD")
+	if err != nil {
+		return snap, nil //nolint:nilerr // No HEAD yet
+	}
+
+	for entry := range strings.SplitSeq(tree, "\x00") {
+		// "<mode> blob <sha>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) == 3 {
+			snap.tree[path] = fields[0] + " " + fields[2]
+		}
+	}
+
+	return snap, nil
+}
+
+// verifyRewrite compares the repository after a rewrite with its snapshot
+// from before: HEAD may differ only in the selected paths and the paths
+// matching removePaths - removed, or replaced by LFS pointers with
+// .gitattributes updated, or with their secrets redacted - every branch and
+// tag must still exist, the selected blobs must be unreachable at those
+// paths and git fsck must pass.
+func verifyRewrite(
+	ctx context.Context,
+	repoPath string,
+	before, after *historySnapshot,
+	files []domain.GitHistoryFile,
+	removePaths historyPaths,
+	action domain.GitHistoryAction,
+) domain.GitHistoryVerification {
+	v := domain.GitHistoryVerification{ //nolint:exhaustruct
+		CommitsBefore: before.commits,
+		CommitsAfter:  after.commits,
+	}
+
+	selected := make(map[string]bool, len(files))
+	for _, f := range files {
+		selected[f.Path] = true
+	}
+
+	intended := func(path string) bool { return selected[path] || removePaths.Match(path) }
+
+	var secrets []string
+	if action == domain.GitHistoryActionPurge {
+		secrets = purgedSecrets(files)
+	}
+
+	for path, entry := range before.tree {
+		afterEntry, ok := after.tree[path]
+
+		switch {
+		case ok && afterEntry == entry:
+		case action == domain.GitHistoryActionLFS && path == ".gitattributes":
+		case !ok && action == domain.GitHistoryActionRemove && intended(path):
+			v.IntendedChanges = append(v.IntendedChanges, path)
+		case ok && action == domain.GitHistoryActionLFS && isLFSPointer(ctx, repoPath, afterEntry):
+			v.IntendedChanges = append(v.IntendedChanges, path)
+		case ok && action == domain.GitHistoryActionPurge && selected[path] &&
+			isRedactedBlob(ctx, repoPath, afterEntry, secrets):
+			v.IntendedChanges = append(v.IntendedChanges, path)
+		case !ok:
+			v.UnexpectedChanges = append(v.UnexpectedChanges, path+" (removed)")
+		default:
+			v.UnexpectedChanges = append(v.UnexpectedChanges, path+" (modified)")
+		}
+	}
+
+	for path := range after.tree {
+		if _, ok := before.tree[path]; !ok && !(action == domain.GitHistoryActionLFS && path == ".gitattributes") {
+			v.UnexpectedChanges = append(v.UnexpectedChanges, path+" (added)")
+		}
+	}
+
+	for ref := range before.refs {
+		if _, ok := after.refs[ref]; !ok {
+			v.MissingRefs = append(v.MissingRefs, ref)
+		}
+	}
+
+	v.ReachableBlobs = reachableBlobs(ctx, repoPath, files, intended)
+
+	_, err := runGit(ctx, "-C", repoPath, "fsck", "--full", "--no-progress", "--no-dangling")
+	v.FsckPassed = err == nil
+
+	if err != nil {
+		v.FsckOutput = err.Error()
+	}
+
+	slices.Sort(v.IntendedChanges)
+	slices.Sort(v.UnexpectedChanges)
+	slices.Sort(v.MissingRefs)
+
+	return v
+}
+
+// isLFSPointer reports whether the blob of a tree entry is a Git LFS pointer.
+func isLFSPointer(ctx context.Context, repoPath, entry string) bool {
+	_, blob, ok := strings.Cut(entry, " ")
+	if !ok {
+		return false
+	}
+
+	content, err := runGit(ctx, "-C", repoPath, "cat-file", "blob", blob)
+
+	return err == nil && strings.HasPrefix(content, lfsPointerPrefix)
+}
+
+// reachableBlobs returns the blobs of the files still reachable from a ref
+// at an intended path; the same content kept at another path is fine.
+func reachableBlobs(
+	ctx context.Context,
+	repoPath string,
+	files []domain.GitHistoryFile,
+	intended func(path string) bool,
+) []string {
+	wanted := make(map[string]bool, len(files))
+
+	for _, f := range files {
+		if f.BlobHash != "" {
+			wanted[f.BlobHash] = true
+		}
+	}
+
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-list", "--all", "--objects")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil || cmd.Start() != nil {
+		return nil
+	}
+
+	var found []string
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		object, path, _ := strings.Cut(scanner.Text(), " ")
+		if wanted[object] && intended(path) {
+			found = append(found, object)
+			delete(wanted, object)
+		}
+	}
+
+	_ = cmd.Wait()
+
+	slices.Sort(found)
+
+	return found
+}
@@ -0,0 +1,193 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Git history rewrite verification", func() {
+	var (
+		ctx    context.Context
+		repo   string
+		before *historySnapshot
+		large  domain.GitHistoryFile
+	)
+
+	ginkgo.BeforeEach(func() {
+		ctx = context.Background()
+		repo = ginkgo.GinkgoT().TempDir()
+		runGitCommand(repo, "init", "--quiet", "--initial-branch=main")
+		runGitCommand(repo, "config", "user.email", "test@example.com")
+		runGitCommand(repo, "config", "user.name", "Test User")
+		runGitCommand(repo, "config", "commit.gpgsign", "false")
+		runGitCommand(repo, "remote", "add", "origin", "https://example.com/repo.git")
+		gomega.Expect(os.WriteFile(filepath.Join(repo, "large.bin"), []byte("large"), 0o644)).To(gomega.Succeed())
+		setupInitialGitCommit(repo)
+		runGitCommand(repo, "tag", "v1")
+
+		large = domain.GitHistoryFile{Path: "large.bin", BlobHash: gitOutput(repo, "rev-parse", "HEAD:large.bin")} //nolint:exhaustruct
+
+		var err error
+		before, err = snapshotHistory(ctx, repo)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	// rewrite simulates a history rewrite: the files are removed from the
+	// only commit, the tag is moved along and the old history is pruned.
+	rewrite := func(paths ...string) {
+		runGitCommand(repo, append([]string{"rm", "--quiet", "--cached"}, paths...)...)
+		runGitCommand(repo, "commit", "--quiet", "--amend", "--allow-empty", "-m", "initial")
+		runGitCommand(repo, "tag", "--force", "v1")
+		runGitCommand(repo, "reflog", "expire", "--expire=now", "--all")
+		runGitCommand(repo, "gc", "--quiet", "--prune=now")
+	}
+
+	verify := func(files ...domain.GitHistoryFile) domain.GitHistoryVerification {
+		after, err := snapshotHistory(ctx, repo)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		return verifyRewrite(ctx, repo, before, after, files, nil, domain.GitHistoryActionRemove)
+	}
+
+	ginkgo.Describe("snapshotHistory", func() {
+		ginkgo.It("should record refs, the HEAD tree and commits", func() {
+			gomega.Expect(before.refs).To(gomega.HaveKey("refs/heads/main"))
+			gomega.Expect(before.refs).To(gomega.HaveKey("refs/tags/v1"))
+			gomega.Expect(before.tree).To(gomega.HaveKeyWithValue("large.bin", "100644 "+large.BlobHash))
+			gomega.Expect(before.commits).To(gomega.Equal(1))
+			gomega.Expect(before.branch).To(gomega.Equal("main"))
+			gomega.Expect(before.remotes).To(gomega.HaveKey("origin"))
+		})
+	})
+
+	ginkgo.Describe("verifyRewrite", func() {
+		ginkgo.It("should pass when only the selected file disappeared", func() {
+			rewrite("large.bin")
+
+			v := verify(large)
+			gomega.Expect(v.Problems()).To(gomega.BeEmpty())
+			gomega.Expect(v.IntendedChanges).To(gomega.Equal([]string{"large.bin"}))
+			gomega.Expect(v.FsckPassed).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should report other paths that changed", func() {
+			rewrite("large.bin", "README.md")
+
+			v := verify(large)
+			gomega.Expect(v.OK()).To(gomega.BeFalse())
+			gomega.Expect(v.UnexpectedChanges).To(gomega.Equal([]string{"README.md (removed)"}))
+		})
+
+		ginkgo.It("should report selected blobs that are still reachable", func() {
+			v := verify(large)
+			gomega.Expect(v.ReachableBlobs).To(gomega.Equal([]string{large.BlobHash}))
+			gomega.Expect(v.UnexpectedChanges).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("should report refs that disappeared", func() {
+			rewrite("large.bin")
+			runGitCommand(repo, "tag", "--delete", "v1")
+
+			gomega.Expect(verify(large).MissingRefs).To(gomega.Equal([]string{"refs/tags/v1"}))
+		})
+	})
+
+	ginkgo.Describe("readCommitMap", func() {
+		ginkgo.It("should read changed commits from a git-filter-repo commit map", func() {
+			path := filepath.Join(repo, "commit-map")
+			content := "old                                      new\n" +
+				"1111111111111111111111111111111111111111 2222222222222222222222222222222222222222\n" +
+				"3333333333333333333333333333333333333333 3333333333333333333333333333333333333333\n" +
+				"4444444444444444444444444444444444444444 " + nullCommit + "\n"
+			gomega.Expect(os.WriteFile(path, []byte(content), 0o644)).To(gomega.Succeed())
+
+			gomega.Expect(readCommitMap(path, " ")).To(gomega.Equal([]domain.GitHistoryCommitMapping{
+				{Old: "1111111111111111111111111111111111111111", New: "2222222222222222222222222222222222222222"},
+				{Old: "4444444444444444444444444444444444444444", New: ""},
+			}))
+		})
+
+		ginkgo.It("should read a git lfs migrate object map", func() {
+			path := filepath.Join(repo, "object-map.csv")
+			gomega.Expect(os.WriteFile(path, []byte("aaa,bbb\n"), 0o644)).To(gomega.Succeed())
+
+			gomega.Expect(readCommitMap(path, ",")).To(gomega.Equal([]domain.GitHistoryCommitMapping{
+				{Old: "aaa", New: "bbb"},
+			}))
+		})
+
+		ginkgo.It("should yield nothing for a missing map", func() {
+			gomega.Expect(readCommitMap(filepath.Join(repo, "missing"), " ")).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("handoff report", func() {
+		var handoff domain.GitHistoryHandoff
+
+		ginkgo.BeforeEach(func() {
+			oldMain := before.refs["refs/heads/main"]
+
+			rewrite("large.bin")
+			// git-filter-repo removes the remotes
+			runGitCommand(repo, "remote", "remove", "origin")
+
+			after, err := snapshotHistory(ctx, repo)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			result := &domain.GitHistoryRewriteResult{ //nolint:exhaustruct
+				Action:       domain.GitHistoryActionRemove,
+				FilesRemoved: []domain.GitHistoryFile{large},
+				BackupPath:   "/backups/repo-backup.git",
+			}
+			commits := []domain.GitHistoryCommitMapping{{Old: oldMain, New: after.refs["refs/heads/main"]}}
+			handoff = newGitHistoryHandoff(repo, result, before, after, commits,
+				verifyRewrite(ctx, repo, before, after, result.FilesRemoved, nil, result.Action))
+		})
+
+		ginkgo.It("should list the moved refs and the commands to publish them", func() {
+			gomega.Expect(handoff.Refs).To(gomega.HaveLen(2))
+			gomega.Expect(handoff.Refs[0].Ref).To(gomega.Equal("refs/heads/main"))
+			gomega.Expect(handoff.Remote).To(gomega.Equal("origin"))
+			gomega.Expect(handoff.MaintainerCommands).To(gomega.Equal([]string{
+				"git remote add origin https://example.com/repo.git",
+				"git push --force-with-lease=refs/heads/main:" + handoff.Refs[0].Old + " origin refs/heads/main",
+				"git push --force-with-lease=refs/tags/v1:" + handoff.Refs[1].Old + " origin refs/tags/v1",
+			}))
+			gomega.Expect(handoff.CollaboratorCommands).To(gomega.ContainElement(
+				"git checkout main && git reset --hard origin/main"))
+		})
+
+		ginkgo.It("should render the report as Markdown", func() {
+			markdown := RenderGitHistoryHandoffMarkdown(handoff)
+
+			gomega.Expect(markdown).To(gomega.ContainSubstring("- `large.bin`"))
+			gomega.Expect(markdown).To(gomega.ContainSubstring("- git fsck: passed"))
+			gomega.Expect(markdown).To(gomega.ContainSubstring("| `refs/heads/main` |"))
+			gomega.Expect(markdown).To(gomega.ContainSubstring("git fetch origin --prune"))
+			gomega.Expect(markdown).NotTo(gomega.ContainSubstring("Do not push"))
+			gomega.Expect(markdown).To(gomega.ContainSubstring("1 commit(s) rewritten."))
+		})
+
+		ginkgo.It("should write the report as Markdown and JSON", func() {
+			handoff.GeneratedAt = time.Date(2026, 10, 18, 9, 30, 5, 0, time.UTC)
+
+			paths, err := writeGitHistoryHandoff(repo, handoff)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(paths).To(gomega.Equal([]string{
+				filepath.Join(repo, ".git", "clean-wizard", "handoff-20261018-093005.md"),
+				filepath.Join(repo, ".git", "clean-wizard", "handoff-20261018-093005.json"),
+			}))
+
+			data, err := os.ReadFile(paths[1])
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(strings.Contains(string(data), `"maintainer_commands"`)).To(gomega.BeTrue())
+		})
+	})
+})
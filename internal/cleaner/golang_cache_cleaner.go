@@ -61,7 +61,7 @@ func (gcc *GoCacheCleaner) Scan(ctx context.Context) result.Result[[]domain.Scan
 	case GoCacheModCache:
 		items = append(items, gcc.scanGoEnvCache(ctx, "GOMODCACHE")...)
 	case GoCacheBuildCache:
-		items = append(items, gcc.scanGoBuildCache()...)
+		items = append(items, gcc.scanGoBuildCache(ctx)...)
 	case GoCacheNone, GoCacheLintCache:
 		// No scan items for these cache types
 	}
@@ -76,14 +76,7 @@ func (gcc *GoCacheCleaner) scanGoEnvCache(ctx context.Context, envVar string) []
 		return []domain.ScanItem{}
 	}
 
-	return []domain.ScanItem{
-		{
-			Path:     cachePath,
-			Size:     GetDirSize(cachePath),
-			Created:  GetDirModTime(cachePath),
-			ScanType: domain.ScanTypeTemp,
-		},
-	}
+	return []domain.ScanItem{MeasureDirScanItem(ctx, cachePath, domain.ScanTypeTemp)}
 }
 
 // getGoBuildCacheLocations returns all potential go-build cache locations.
@@ -110,7 +103,7 @@ func (gcc *GoCacheCleaner) getGoBuildCacheLocations() []string {
 }
 
 // scanGoBuildCache scans go-build* folders in temp directories.
-func (gcc *GoCacheCleaner) scanGoBuildCache() []domain.ScanItem {
+func (gcc *GoCacheCleaner) scanGoBuildCache(ctx context.Context) []domain.ScanItem {
 	items := make([]domain.ScanItem, 0)
 	buildCachePattern := "go-build*"
 	seen := make(map[string]bool) // Prevent duplicates
@@ -129,12 +122,11 @@ func (gcc *GoCacheCleaner) scanGoBuildCache() []domain.ScanItem {
 
 			seen[match] = true
 
-			items = append(items, domain.ScanItem{
-				Path:     match,
-				Size:     GetDirSize(match),
-				Created:  GetDirModTime(match),
-				ScanType: domain.ScanTypeTemp,
-			})
+			if keptByIgnoreRules(ctx, match) {
+				continue
+			}
+
+			items = append(items, MeasureDirScanItem(ctx, match, domain.ScanTypeTemp))
 		}
 	}
 
@@ -278,7 +270,7 @@ func (gcc *GoCacheCleaner) cleanGoCacheEnv(
 	}
 
 	// Dry run - calculate size estimate and return
-	bytesFreed = GetDirSize(cachePath)
+	bytesFreed = GetDirDiskUsage(cachePath)
 
 	return result.Ok(conversions.NewCleanResultWithSizeEstimate(
 		domain.StrategyConservativeType,
@@ -304,7 +296,7 @@ func (gcc *GoCacheCleaner) cleanGoModCache(ctx context.Context) result.Result[do
 
 // cleanGoBuildCache removes go-build* folders from all temp locations.
 func (gcc *GoCacheCleaner) cleanGoBuildCache(
-	_ context.Context,
+	ctx context.Context,
 ) result.Result[domain.CleanResult] {
 	buildCachePattern := "go-build*"
 	seen := make(map[string]bool) // Prevent cleaning same path twice
@@ -326,8 +318,12 @@ func (gcc *GoCacheCleaner) cleanGoBuildCache(
 
 			seen[match] = true
 
+			if keptByIgnoreRules(ctx, match) {
+				continue
+			}
+
 			// Calculate size before removal (always, for accurate dry-run estimates)
-			bytesFreed := GetDirSize(match)
+			bytesFreed := GetDirDiskUsage(match)
 			totalSizeEstimate = domain.SizeEstimate{ //nolint:exhaustruct
 				Known: totalSizeEstimate.Known + uint64(bytesFreed),
 			}
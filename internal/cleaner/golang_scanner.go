@@ -80,6 +80,7 @@ func (gs *GoScanner) addScanItem(
 		Size:     GetDirSize(path),
 		Created:  GetDirModTime(path),
 		ScanType: domain.ScanTypeTemp,
+		Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeTemp)},
 	})
 
 	if gs.verbose {
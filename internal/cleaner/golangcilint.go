@@ -154,6 +154,7 @@ func (glcc *GolangciLintCacheCleaner) Scan(ctx context.Context) result.Result[[]
 		Size:     status.Size,
 		Created:  GetDirModTime(status.Dir),
 		ScanType: domain.ScanTypeCache,
+		Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeCache)},
 	})
 
 	if glcc.verbose {
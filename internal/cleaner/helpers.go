@@ -15,7 +15,8 @@ import (
 const (
 	// DryRunBytesPerItem is the estimated bytes freed per item in dry run mode when no size estimator is provided.
 	DryRunBytesPerItem = 300 * 1024 * 1024 // 300MB per item
-	// TrashPathTimeout is the timeout for trash operations.
+	// TrashPathTimeout bounds a single trash operation; within a workflow
+	// step an earlier step deadline cuts it short.
 	TrashPathTimeout = 30 * time.Second
 )
 
@@ -298,17 +299,17 @@ func calculateTotalSizeFromScan(scanResult result.Result[[]domain.ScanItem]) int
 
 	var total int64
 	for _, item := range scanResult.Value() {
-		total += item.Size
+		total += item.DiskUsage()
 	}
 
 	return total
 }
 
 // TrashPath moves a file or directory to the system trash using the `trash` command.
-// It applies a 30-second timeout to the operation.
+// It runs for at most TrashPathTimeout, and never past the step deadline of ctx.
 // This is a shared helper to eliminate duplicate trash implementations across cleaners.
 func TrashPath(ctx context.Context, path string) error {
-	timeoutCtx, cancel := context.WithTimeout(ctx, TrashPathTimeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, commandTimeout(ctx, TrashPathTimeout))
 	defer cancel()
 
 	cmd := exec.CommandContext(timeoutCtx, "trash", path)
@@ -74,7 +74,7 @@ func (hbc *HomebrewCleaner) ValidateSettings(settings *domain.OperationSettings)
 // Scan scans for Homebrew packages that can be cleaned.
 func (hbc *HomebrewCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
 	if !hbc.IsAvailable(ctx) {
-		return result.Err[[]domain.ScanItem](NewNotAvailableError("homebrew", ""))
+		return result.Err[[]domain.ScanItem](NewNotAvailableError(CleanerHomebrew, ""))
 	}
 
 	items := make([]domain.ScanItem, 0)
@@ -107,6 +107,9 @@ func (hbc *HomebrewCleaner) Scan(ctx context.Context) result.Result[[]domain.Sca
 				Size:     0, // Size unknown without checking
 				Created:  time.Time{},
 				ScanType: domain.ScanTypeHomebrew,
+				Reasons: []domain.SelectionReason{
+					retentionReason("removed: outdated versions of an installed package", currentVersion, "latest"),
+				},
 			})
 
 			if hbc.verbose {
@@ -125,7 +128,7 @@ func (hbc *HomebrewCleaner) Scan(ctx context.Context) result.Result[[]domain.Sca
 // Clean removes old Homebrew packages with proper type safety.
 func (hbc *HomebrewCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	if !hbc.IsAvailable(ctx) {
-		return result.Err[domain.CleanResult](NewNotAvailableError("homebrew", ""))
+		return result.Err[domain.CleanResult](NewNotAvailableError(CleanerHomebrew, ""))
 	}
 
 	if hbc.dryRun {
@@ -16,8 +16,6 @@ const (
 	NixMockStoreSizeGB = 300
 	// NixMaxGenerationsToKeep is the maximum allowed generations to keep.
 	NixMaxGenerationsToKeep = 10
-	// NixDryRunBytesPerGeneration is the estimated bytes freed per generation in dry-run mode.
-	NixDryRunBytesPerGeneration = 50 * bytesPerMB
 
 	// Mock generation IDs for testing when Nix is unavailable.
 	mockGenerationIDCurrent = 300
@@ -32,6 +30,9 @@ const (
 	mockGenerationAgeOlder   = 72
 	mockGenerationAgeOld     = 96
 	mockGenerationAgeVeryOld = 120
+
+	// nixMockProfile is the profile of the mock generations.
+	nixMockProfile = "/nix/var/nix/profiles/default"
 )
 
 // NixCleaner handles Nix package manager cleanup with proper type safety.
@@ -39,7 +40,7 @@ type NixCleaner struct {
 	CleanerBase
 
 	adapter   *adapters.NixAdapter
-	keepCount int
+	retention nixRetention
 }
 
 // NewNixCleaner creates Nix cleaner with proper configuration.
@@ -53,7 +54,7 @@ func NewNixCleaner(verbose, dryRun bool, keepCount ...int) *NixCleaner {
 	nc := &NixCleaner{
 		adapter:     adapters.NewNixAdapter(0, 0),
 		CleanerBase: NewCleanerBase(verbose, dryRun),
-		keepCount:   kc,
+		retention:   nixRetention{keepLast: kc, keepNewerThan: 0},
 	}
 	nc.adapter.SetDryRun(dryRun) // Pass dry-run to adapter
 
@@ -75,22 +76,87 @@ func (nc *NixCleaner) IsAvailable(ctx context.Context) bool {
 	return nc.adapter.IsAvailable(ctx)
 }
 
-// Scan scans for Nix generations and returns them as scan items.
+// ApplySettings configures the retention policy and the extra profiles from
+// a profile operation's nix_generations settings.
+func (nc *NixCleaner) ApplySettings(settings *domain.OperationSettings) error {
+	if settings == nil || settings.NixGenerations == nil {
+		return nil
+	}
+
+	if err := nc.ValidateSettings(settings); err != nil {
+		return err
+	}
+
+	s := settings.NixGenerations
+	retention := nixRetention{keepLast: s.Generations, keepNewerThan: 0}
+
+	if s.KeepLast > 0 {
+		retention.keepLast = s.KeepLast
+	}
+
+	if s.KeepNewerThan != "" {
+		keepNewerThan, err := domain.ParseCustomDuration(s.KeepNewerThan)
+		if err != nil {
+			return fmt.Errorf("invalid keep_newer_than %q: %w", s.KeepNewerThan, err)
+		}
+
+		retention.keepNewerThan = keepNewerThan
+	}
+
+	nc.retention = retention
+	nc.adapter.SetProfiles(s.Profiles)
+
+	return nil
+}
+
+// Scan scans the generations of every profile and returns them as scan
+// items, grouped by profile. The generations the retention policy removes
+// are sized by closure analysis: the store paths only they keep alive.
+// Store paths that are dead already are reported as one more item.
 func (nc *NixCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
 	genResult := nc.ListGenerations(ctx)
 	if genResult.IsErr() {
 		return result.Err[[]domain.ScanItem](genResult.Error())
 	}
 
-	generations := genResult.Value()
-	items := make([]domain.ScanItem, 0, len(generations))
+	decisions := nc.retention.decide(genResult.Value(), time.Now())
+
+	var old []domain.NixGeneration
+
+	for _, d := range decisions {
+		if d.removed {
+			old = append(old, d.gen)
+		}
+	}
+
+	estimate, err := nc.estimateReclaim(ctx, old)
+	if err != nil {
+		return result.Err[[]domain.ScanItem](err)
+	}
+
+	items := make([]domain.ScanItem, 0, len(decisions)+1)
 
-	for _, gen := range generations {
+	for _, d := range decisions {
+		paths := estimate.ByGeneration[d.gen.Path]
 		items = append(items, domain.ScanItem{
-			Path:     gen.Path,
-			Size:     0, // Individual generation size is hard to determine
-			Created:  gen.Date,
-			ScanType: domain.ScanTypeNixStore,
+			Path:       d.gen.Path,
+			Size:       estimate.NarBytes(paths),
+			OnDiskSize: estimate.onDiskBytes(paths),
+			Created:    d.gen.Date,
+			ScanType:   domain.ScanTypeNixStore,
+			Reasons:    []domain.SelectionReason{d.reason},
+		})
+	}
+
+	if dead := estimate.AlreadyDead; len(dead) > 0 {
+		items = append(items, domain.ScanItem{ //nolint:exhaustruct
+			Path:       nixStorePath,
+			Size:       estimate.NarBytes(dead),
+			OnDiskSize: estimate.onDiskBytes(dead),
+			ScanType:   domain.ScanTypeNixStore,
+			Reasons: []domain.SelectionReason{
+				ruleReason(fmt.Sprintf("%d store paths no GC root keeps alive", len(dead)), "nix-store --gc --print-dead"),
+			},
 		})
 	}
 
@@ -98,9 +164,9 @@ func (nc *NixCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem]
 }
 
 // Clean implements the Cleaner interface.
-// It removes old Nix generations, keeping the configured number of generations.
+// It removes the generations the retention policy does not keep.
 func (nc *NixCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
-	return nc.CleanOldGenerations(ctx, nc.keepCount)
+	return nc.CleanOldGenerations(ctx, nc.retention.keepLast)
 }
 
 // GetStoreSize gets Nix store size with type safety.
@@ -150,36 +216,41 @@ func (nc *NixCleaner) ListGenerations(ctx context.Context) result.Result[[]domai
 		// Return mock data for CI/testing - proper adapter pattern eliminates ghost system
 		return result.MockSuccess([]domain.NixGeneration{
 			{
-				ID:   mockGenerationIDCurrent,
-				Path: "/nix/var/nix/profiles/default-300-link",
+				ID:      mockGenerationIDCurrent,
+				Path:    "/nix/var/nix/profiles/default-300-link",
+				Profile: nixMockProfile,
 				Date: time.Now().
 					Add(-mockGenerationAgeCurrent * time.Hour),
 				Current: domain.GenerationStatusCurrent,
 			},
 			{
-				ID:   mockGenerationIDRecent1,
-				Path: "/nix/var/nix/profiles/default-299-link",
+				ID:      mockGenerationIDRecent1,
+				Path:    "/nix/var/nix/profiles/default-299-link",
+				Profile: nixMockProfile,
 				Date: time.Now().
 					Add(-mockGenerationAgeRecent * time.Hour),
 				Current: domain.GenerationStatusHistorical,
 			},
 			{
-				ID:   mockGenerationIDRecent2,
-				Path: "/nix/var/nix/profiles/default-298-link",
+				ID:      mockGenerationIDRecent2,
+				Path:    "/nix/var/nix/profiles/default-298-link",
+				Profile: nixMockProfile,
 				Date: time.Now().
 					Add(-mockGenerationAgeOlder * time.Hour),
 				Current: domain.GenerationStatusHistorical,
 			},
 			{
-				ID:   mockGenerationIDOlder1,
-				Path: "/nix/var/nix/profiles/default-297-link",
+				ID:      mockGenerationIDOlder1,
+				Path:    "/nix/var/nix/profiles/default-297-link",
+				Profile: nixMockProfile,
 				Date: time.Now().
 					Add(-mockGenerationAgeOld * time.Hour),
 				Current: domain.GenerationStatusHistorical,
 			},
 			{
-				ID:   mockGenerationIDOlder2,
-				Path: "/nix/var/nix/profiles/default-296-link",
+				ID:      mockGenerationIDOlder2,
+				Path:    "/nix/var/nix/profiles/default-296-link",
+				Profile: nixMockProfile,
 				Date: time.Now().
 					Add(-mockGenerationAgeVeryOld * time.Hour),
 				Current: domain.GenerationStatusHistorical,
@@ -191,94 +262,108 @@ func (nc *NixCleaner) ListGenerations(ctx context.Context) result.Result[[]domai
 	return nc.adapter.ListGenerations(ctx)
 }
 
-// CleanOldGenerations removes old Nix generations using centralized conversions.
+// CleanOldGenerations removes old Nix generations, keeping the keepCount
+// newest of each profile besides those the age rule keeps, and collects
+// garbage. In dry-run mode it reports what that would free from closure
+// analysis.
 func (nc *NixCleaner) CleanOldGenerations(
 	ctx context.Context,
 	keepCount int,
 ) result.Result[domain.CleanResult] {
-	// Get generations first
 	genResult := nc.ListGenerations(ctx)
 	if genResult.IsErr() {
 		return conversions.ToCleanResultFromError(genResult.Error())
 	}
 
-	generations := genResult.Value()
-
-	// Count and remove old generations
-	toRemove := countOldGenerations(generations, keepCount)
+	retention := nc.retention
+	retention.keepLast = keepCount
+	old := retention.removed(genResult.Value(), time.Now())
 
 	if nc.dryRun {
-		// Use real store size to calculate average generation size
-		storeSize := nc.GetStoreSize(ctx)
-		// Calculate average generation size (avoid division by zero)
-		var avgSize int64
-		if len(generations) > 0 {
-			avgSize = storeSize / int64(len(generations))
+		estimate, err := nc.estimateReclaim(ctx, old)
+		if err != nil {
+			return conversions.ToCleanResultFromError(err)
 		}
-		// Estimate bytes to free based on average generation size
-		estimatedBytes := avgSize * int64(toRemove)
-		cleanResult := conversions.NewCleanResult(
-			domain.StrategyDryRunType,
-			toRemove,
-			estimatedBytes,
-		)
 
-		return result.Ok(cleanResult)
+		return result.Ok(conversions.NewCleanResult(
+			domain.StrategyDryRunType,
+			len(old),
+			estimate.freedBytes(estimate.Paths()),
+		))
 	}
 
-	// Real cleaning implementation
-	if !nc.dryRun && toRemove > 0 {
-		// Remove old generations individually to track what's cleaned
-		results := make([]domain.CleanResult, 0, toRemove)
-		start := time.Now()
+	// Remove old generations individually to track what's cleaned
+	results := make([]domain.CleanResult, 0, len(old)+1)
+	start := time.Now()
 
-		for i := len(generations) - toRemove; i < len(generations); i++ {
-			// Skip current generation
-			if generations[i].Current.IsCurrent() {
-				continue
-			}
+	for _, gen := range old {
+		cleanResult := nc.adapter.RemoveGeneration(ctx, gen)
+		if cleanResult.IsErr() {
+			return conversions.ToCleanResultFromError(cleanResult.Error())
+		}
 
-			// Remove this generation
-			cleanResult := nc.adapter.RemoveGeneration(ctx, generations[i].ID)
-			if cleanResult.IsErr() {
-				return conversions.ToCleanResultFromError(cleanResult.Error())
-			}
+		results = append(results, cleanResult.Value())
+	}
 
-			results = append(results, cleanResult.Value())
-		}
+	// Collect garbage: this frees the removed generations' paths and those
+	// that were dead already.
+	gcResult := nc.adapter.CollectGarbage(ctx)
+	if gcResult.IsErr() {
+		return conversions.ToCleanResultFromError(gcResult.Error())
+	}
 
-		// Run garbage collection to clean up references
-		gcResult := nc.adapter.CollectGarbage(ctx)
-		if gcResult.IsErr() {
-			return conversions.ToCleanResultFromError(gcResult.Error())
-		}
+	results = append(results, gcResult.Value())
 
-		results = append(results, gcResult.Value())
+	// Combine all results using centralized function
+	combinedResult := conversions.CombineCleanResults(results)
+	combinedResult.CleanTime = time.Since(start)
+	combinedResult.Strategy = domain.StrategyAggressiveType
 
-		// Combine all results using centralized function
-		combinedResult := conversions.CombineCleanResults(results)
-		combinedResult.CleanTime = time.Since(start)
-		combinedResult.Strategy = domain.StrategyAggressiveType
+	return result.Ok(combinedResult)
+}
+
+// nixReclaimEstimate is the closure analysis of the store paths a garbage
+// collection frees, with their on-disk sizes.
+type nixReclaimEstimate struct {
+	adapters.NixReclaim
+
+	onDisk map[string]int64
+}
 
-		return result.Ok(combinedResult)
+// onDiskBytes sums the on-disk bytes collecting paths frees.
+func (e nixReclaimEstimate) onDiskBytes(paths []string) int64 {
+	var total int64
+	for _, p := range paths {
+		total += e.onDisk[p]
 	}
 
-	// Dry-run or no generations to remove - use centralized conversion
-	estimatedBytes := int64(toRemove) * NixDryRunBytesPerGeneration
-	cleanResult := conversions.NewCleanResult(
-		domain.StrategyDryRunType,
-		toRemove,
-		estimatedBytes,
-	)
+	return total
+}
 
-	return result.Ok(cleanResult)
+// freedBytes is what collecting paths frees: the on-disk bytes, or the NAR
+// size where the store could not be read.
+func (e nixReclaimEstimate) freedBytes(paths []string) int64 {
+	if onDisk := e.onDiskBytes(paths); onDisk > 0 {
+		return onDisk
+	}
+
+	return e.NarBytes(paths)
 }
 
-// countOldGenerations counts generations to remove (keeping current + N others).
-func countOldGenerations(generations []domain.NixGeneration, keepCount int) int {
-	if len(generations) <= keepCount {
-		return 0
+// estimateReclaim analyses what removing the old generations and collecting
+// garbage frees. Without Nix (mock generations) the estimate is empty.
+func (nc *NixCleaner) estimateReclaim(ctx context.Context, old []domain.NixGeneration) (nixReclaimEstimate, error) {
+	if !nc.adapter.IsAvailable(ctx) {
+		return nixReclaimEstimate{}, nil
+	}
+
+	reclaim, err := nc.adapter.ReclaimEstimate(ctx, old)
+	if err != nil {
+		return nixReclaimEstimate{}, fmt.Errorf("failed to estimate the space a Nix garbage collection frees: %w", err)
 	}
 
-	return len(generations) - keepCount
+	return nixReclaimEstimate{
+		NixReclaim: reclaim,
+		onDisk:     nixOnDiskFreed(ctx, reclaim.Paths(), nixStoreLinks),
+	}, nil
 }
@@ -0,0 +1,449 @@
+package cleaner
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/adapters"
+	"github.com/LarsArtmann/clean-wizard/internal/conversions"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+)
+
+// NixGCRootsDefaultOlderThan is how long a project must be untouched before
+// its GC roots count as stale by default.
+const NixGCRootsDefaultOlderThan = 30 * 24 * time.Hour
+
+// nixRootMaxHops bounds the links followed from a root to its store path.
+const nixRootMaxHops = 40
+
+var (
+	// errNixRootNotLink is returned for a root path that is not a symlink.
+	errNixRootNotLink = errors.New("not a symlink")
+	// errNixRootOutsideStore is returned for a root whose links do not lead
+	// into the Nix store.
+	errNixRootOutsideStore = errors.New("does not point into the Nix store")
+)
+
+// NixRootKind classifies an indirect GC root by what created it.
+type NixRootKind string
+
+const (
+	// NixRootResult is an output link of nix build or nix-build ("result").
+	NixRootResult NixRootKind = "result"
+	// NixRootDirenv is a nix-direnv profile in a project's .direnv.
+	NixRootDirenv NixRootKind = "direnv"
+	// NixRootDevenv is a devenv root in a project's .devenv.
+	NixRootDevenv NixRootKind = "devenv"
+	// NixRootOther is any other indirect root, e.g. nix-build --add-root.
+	NixRootOther NixRootKind = "other"
+)
+
+// NixRootStatus is the auditor's verdict on an indirect GC root.
+type NixRootStatus string
+
+const (
+	// NixRootActive roots belong to a project used recently; they stay.
+	NixRootActive NixRootStatus = "active"
+	// NixRootStale roots belong to a project untouched for longer than the
+	// threshold; they are removed.
+	NixRootStale NixRootStatus = "stale"
+	// NixRootTrashed roots lie in a project moved to the trash; they are
+	// removed.
+	NixRootTrashed NixRootStatus = "trashed"
+	// NixRootDangling roots point at a link that is gone, usually with its
+	// project, or at a missing store path; they pin nothing and garbage
+	// collection drops them itself.
+	NixRootDangling NixRootStatus = "dangling"
+	// NixRootUnmanaged roots were not created for a project, or are not a
+	// symlink into the Nix store; they stay.
+	NixRootUnmanaged NixRootStatus = "unmanaged"
+)
+
+// trashDirs are the path components of the trash directories a deleted
+// project may still sit in.
+var trashDirs = []string{"/.local/share/Trash/", "/.Trash/", "/.Trashes/"} //nolint:gochecknoglobals
+
+// NixRootAudit is one indirect GC root with what it keeps alive.
+type NixRootAudit struct {
+	Link     string        `json:"link"`
+	Target   string        `json:"target,omitempty"`
+	Project  string        `json:"project"`
+	Kind     NixRootKind   `json:"kind"`
+	Status   NixRootStatus `json:"status"`
+	LastUsed time.Time     `json:"last_used,omitzero"`
+	// ClosureBytes is the NAR size of every store path the root references.
+	ClosureBytes int64 `json:"closure_bytes"`
+	// PinnedBytes is the NAR size of the paths only this root keeps alive:
+	// what a garbage collection frees once it is removed.
+	PinnedBytes int64 `json:"pinned_bytes"`
+	// PinnedOnDisk is PinnedBytes measured as allocated blocks, counting
+	// hardlinked files only when no other path shares them.
+	PinnedOnDisk int64 `json:"pinned_on_disk"`
+}
+
+// Selected reports whether the cleaner removes the root.
+func (a NixRootAudit) Selected() bool {
+	return a.Status == NixRootStale || a.Status == NixRootTrashed
+}
+
+// NixGCRootsCleaner removes indirect GC roots of stale or deleted projects
+// so that the following Nix garbage collection can free what they pin.
+type NixGCRootsCleaner struct {
+	CleanerBase
+
+	adapter   *adapters.NixAdapter
+	olderThan time.Duration
+	autoRoots string
+	// storeDir is the store a root must point into to be removed.
+	storeDir string
+}
+
+// NewNixGCRootsCleaner creates a GC root cleaner treating projects
+// untouched for olderThan as stale.
+func NewNixGCRootsCleaner(verbose, dryRun bool, olderThan time.Duration) *NixGCRootsCleaner {
+	return &NixGCRootsCleaner{
+		CleanerBase: NewCleanerBase(verbose, dryRun),
+		adapter:     adapters.NewNixAdapter(0, 0),
+		olderThan:   olderThan,
+		autoRoots:   adapters.NixAutoRoots,
+		storeDir:    nixStorePath,
+	}
+}
+
+// Type returns the operation type for the GC root cleaner.
+func (gc *NixGCRootsCleaner) Type() domain.OperationType {
+	return domain.OperationTypeNixGCRoots
+}
+
+// Name returns the unique identifier for this cleaner.
+func (gc *NixGCRootsCleaner) Name() string {
+	return CleanerNixGCRoots
+}
+
+// IsAvailable checks if Nix is available and has indirect GC roots.
+func (gc *NixGCRootsCleaner) IsAvailable(ctx context.Context) bool {
+	if _, err := os.Stat(gc.autoRoots); err != nil {
+		return false
+	}
+
+	return gc.adapter.IsAvailable(ctx)
+}
+
+// ValidateSettings validates the GC root cleaner settings.
+func (gc *NixGCRootsCleaner) ValidateSettings(settings *domain.OperationSettings) error {
+	return ValidateOptionalSettings(
+		settings,
+		func(s *domain.OperationSettings) *domain.NixGCRootsSettings { return s.NixGCRoots },
+		func(s *domain.NixGCRootsSettings) error {
+			if s.OlderThan == "" {
+				return nil
+			}
+
+			if _, err := domain.ParseCustomDuration(s.OlderThan); err != nil {
+				return fmt.Errorf("invalid older_than %q: %w", s.OlderThan, err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// ApplySettings configures the staleness threshold from a profile
+// operation's nix_gcroots settings.
+func (gc *NixGCRootsCleaner) ApplySettings(settings *domain.OperationSettings) error {
+	if settings == nil || settings.NixGCRoots == nil || settings.NixGCRoots.OlderThan == "" {
+		return nil
+	}
+
+	olderThan, err := domain.ParseCustomDuration(settings.NixGCRoots.OlderThan)
+	if err != nil {
+		return fmt.Errorf("invalid older_than %q: %w", settings.NixGCRoots.OlderThan, err)
+	}
+
+	gc.olderThan = olderThan
+
+	return nil
+}
+
+// Audit lists the indirect GC roots with where they point, their status
+// and what they pin, the roots pinning the most first.
+func (gc *NixGCRootsCleaner) Audit(ctx context.Context) ([]NixRootAudit, error) {
+	links, err := adapters.IndirectRoots(gc.autoRoots)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	audits := make([]NixRootAudit, 0, len(links))
+	live := make([]string, 0, len(links))
+
+	for _, link := range links {
+		audit := auditNixRoot(link, gc.storeDir, now, gc.olderThan)
+		if audit.Status != NixRootDangling {
+			live = append(live, link)
+		}
+
+		audits = append(audits, audit)
+	}
+
+	pins, err := gc.adapter.RootPins(ctx, live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute what the GC roots pin: %w", err)
+	}
+
+	var exclusive []string
+	for _, paths := range pins.Exclusive {
+		exclusive = append(exclusive, paths...)
+	}
+
+	onDisk := nixOnDiskFreed(ctx, exclusive, nixStoreLinks)
+
+	for i := range audits {
+		a := &audits[i]
+		a.ClosureBytes = sumNarSize(pins.NarSize, pins.Closure[a.Link])
+		a.PinnedBytes = sumNarSize(pins.NarSize, pins.Exclusive[a.Link])
+
+		for _, p := range pins.Exclusive[a.Link] {
+			a.PinnedOnDisk += onDisk[p]
+		}
+	}
+
+	slices.SortStableFunc(audits, func(a, b NixRootAudit) int {
+		return cmp.Compare(b.PinnedBytes, a.PinnedBytes)
+	})
+
+	return audits, nil
+}
+
+// auditNixRoot classifies one root link without querying the store. Only
+// a symlink leading into storeDir can be stale or trashed.
+func auditNixRoot(link, storeDir string, now time.Time, olderThan time.Duration) NixRootAudit {
+	audit := NixRootAudit{ //nolint:exhaustruct
+		Link:    link,
+		Project: nixRootProject(link),
+		Kind:    nixRootKind(link),
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		audit.Status = NixRootDangling
+
+		return audit
+	}
+
+	audit.LastUsed = info.ModTime()
+	if dir, err := os.Stat(audit.Project); err == nil && dir.ModTime().After(audit.LastUsed) {
+		audit.LastUsed = dir.ModTime()
+	}
+
+	target, err := nixStoreTarget(link, storeDir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		audit.Status = NixRootDangling
+
+		return audit
+	case err != nil:
+		audit.Status = NixRootUnmanaged
+
+		return audit
+	}
+
+	audit.Target = target
+
+	switch {
+	case slices.ContainsFunc(trashDirs, func(dir string) bool { return strings.Contains(link, dir) }):
+		audit.Status = NixRootTrashed
+	case audit.Kind == NixRootOther:
+		audit.Status = NixRootUnmanaged
+	case now.Sub(audit.LastUsed) > olderThan:
+		audit.Status = NixRootStale
+	default:
+		audit.Status = NixRootActive
+	}
+
+	return audit
+}
+
+// nixStoreTarget follows a root link hop by hop to the store path it keeps
+// alive, e.g. a .direnv profile through its generation link. It fails for a
+// path that is not a symlink, a broken link (fs.ErrNotExist) and links that
+// lead out of storeDir.
+func nixStoreTarget(link, storeDir string) (string, error) {
+	path := link
+
+	for range nixRootMaxHops {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		if info.Mode()&fs.ModeSymlink == 0 {
+			if path == link {
+				return "", errNixRootNotLink
+			}
+
+			return "", errNixRootOutsideStore
+		}
+
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+
+		target = filepath.Clean(target)
+		if strings.HasPrefix(target, storeDir+string(filepath.Separator)) {
+			if _, err := os.Lstat(target); err != nil {
+				return "", err //nolint:wrapcheck
+			}
+
+			return target, nil
+		}
+
+		path = target
+	}
+
+	return "", errNixRootOutsideStore
+}
+
+// nixRootKind classifies a root link by its location.
+func nixRootKind(link string) NixRootKind {
+	switch {
+	case strings.Contains(link, "/.direnv/"):
+		return NixRootDirenv
+	case strings.Contains(link, "/.devenv/"):
+		return NixRootDevenv
+	case strings.HasPrefix(filepath.Base(link), "result"):
+		return NixRootResult
+	default:
+		return NixRootOther
+	}
+}
+
+// nixRootProject returns the project directory a root link belongs to:
+// the directory holding .direnv or .devenv, or the link's directory.
+func nixRootProject(link string) string {
+	for _, dir := range []string{"/.direnv/", "/.devenv/"} {
+		if i := strings.Index(link, dir); i > 0 {
+			return link[:i]
+		}
+	}
+
+	return filepath.Dir(link)
+}
+
+// sumNarSize sums the NAR sizes of paths.
+func sumNarSize(sizes map[string]int64, paths []string) int64 {
+	var total int64
+	for _, p := range paths {
+		total += sizes[p]
+	}
+
+	return total
+}
+
+// Scan returns the roots the cleaner removes, sized by what they pin.
+func (gc *NixGCRootsCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
+	audits, err := gc.Audit(ctx)
+	if err != nil {
+		return result.Err[[]domain.ScanItem](err)
+	}
+
+	var items []domain.ScanItem
+
+	for _, a := range audits {
+		if !a.Selected() {
+			continue
+		}
+
+		items = append(items, domain.ScanItem{
+			Path:       a.Link,
+			Size:       a.PinnedBytes,
+			OnDiskSize: a.PinnedOnDisk,
+			Created:    a.LastUsed,
+			ScanType:   domain.ScanTypeNixStore,
+			Reasons:    []domain.SelectionReason{gc.selectionReason(a)},
+		})
+	}
+
+	return result.Ok(items)
+}
+
+// selectionReason explains why a root is removed.
+func (gc *NixGCRootsCleaner) selectionReason(a NixRootAudit) domain.SelectionReason {
+	if a.Status == NixRootTrashed {
+		return ruleReason(fmt.Sprintf("%s root of a project in the trash", a.Kind), "trash")
+	}
+
+	reason := ageReason(a.LastUsed, gc.olderThan)
+	reason.Message = fmt.Sprintf("%s root of a project not used recently", a.Kind)
+
+	return reason
+}
+
+// Clean removes the roots of stale and trashed projects. It frees no store
+// space by itself: the Nix cleaner, which runs after it, collects garbage
+// and reports what the roots pinned. In dry-run mode it reports that
+// estimate instead.
+func (gc *NixGCRootsCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	itemsResult := gc.Scan(ctx)
+	if itemsResult.IsErr() {
+		return conversions.ToCleanResultFromError(itemsResult.Error())
+	}
+
+	items := itemsResult.Value()
+
+	if gc.dryRun {
+		var total int64
+		for _, item := range items {
+			total += item.DiskUsage()
+		}
+
+		return result.Ok(conversions.NewCleanResult(domain.StrategyDryRunType, len(items), total))
+	}
+
+	start := time.Now()
+	removed, failed := 0, 0
+
+	for _, item := range items {
+		// The link may have been replaced since the scan; only a symlink
+		// into the store is ever removed.
+		if _, err := nixStoreTarget(item.Path, gc.storeDir); err != nil {
+			failed++
+
+			if gc.verbose {
+				fmt.Printf("  ⚠️  skipping GC root %s: %v\n", item.Path, err)
+			}
+
+			continue
+		}
+
+		if err := os.Remove(item.Path); err != nil {
+			failed++
+
+			if gc.verbose {
+				fmt.Printf("  ⚠️  failed to remove GC root %s: %v\n", item.Path, err)
+			}
+
+			continue
+		}
+
+		removed++
+	}
+
+	return result.Ok(conversions.NewCleanResultWithFailures(
+		domain.StrategyConservativeType, removed, failed, 0, time.Since(start),
+	))
+}
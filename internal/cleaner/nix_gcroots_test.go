@@ -0,0 +1,96 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditNixRoot(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	store := filepath.Join(home, "store")
+	target := filepath.Join(store, "abc-store-path")
+	require.NoError(t, os.MkdirAll(store, 0o755))
+	require.NoError(t, os.WriteFile(target, nil, 0o600))
+
+	linkTo := func(path, to string) string {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.Symlink(to, path))
+
+		return path
+	}
+	link := func(path string) string { return linkTo(path, target) }
+
+	direnv := link(filepath.Join(home, "src", "app", ".direnv", "flake-profile-a5d5b61a"))
+	result := link(filepath.Join(home, "src", "tool", "result-bin"))
+	trashed := link(filepath.Join(home, ".local", "share", "Trash", "files", "old", "result"))
+	other := link(filepath.Join(home, "roots", "pinned"))
+	gone := filepath.Join(home, "deleted", ".devenv", "gc", "shell")
+	outside := linkTo(filepath.Join(home, "src", "notes", "result"), filepath.Join(home, "src", "app"))
+	missing := linkTo(filepath.Join(home, "src", "old", "result"), filepath.Join(store, "gone-store-path"))
+	profile := linkTo(filepath.Join(home, "src", "web", ".direnv", "profile"), "profile-1-link")
+	link(filepath.Join(home, "src", "web", ".direnv", "profile-1-link"))
+
+	plain := filepath.Join(home, "src", "plain", "result")
+	require.NoError(t, os.MkdirAll(filepath.Dir(plain), 0o755))
+	require.NoError(t, os.WriteFile(plain, nil, 0o600))
+
+	now := time.Now()
+	later := now.Add(2 * NixGCRootsDefaultOlderThan)
+
+	tests := []struct {
+		link    string
+		now     time.Time
+		kind    NixRootKind
+		status  NixRootStatus
+		project string
+	}{
+		{direnv, now, NixRootDirenv, NixRootActive, filepath.Join(home, "src", "app")},
+		{direnv, later, NixRootDirenv, NixRootStale, filepath.Join(home, "src", "app")},
+		{result, later, NixRootResult, NixRootStale, filepath.Join(home, "src", "tool")},
+		{trashed, now, NixRootResult, NixRootTrashed, filepath.Join(home, ".local", "share", "Trash", "files", "old")},
+		{other, later, NixRootOther, NixRootUnmanaged, filepath.Join(home, "roots")},
+		{gone, now, NixRootDevenv, NixRootDangling, filepath.Join(home, "deleted")},
+		{outside, later, NixRootResult, NixRootUnmanaged, filepath.Join(home, "src", "notes")},
+		{missing, later, NixRootResult, NixRootDangling, filepath.Join(home, "src", "old")},
+		{plain, later, NixRootResult, NixRootUnmanaged, filepath.Join(home, "src", "plain")},
+		{profile, later, NixRootDirenv, NixRootStale, filepath.Join(home, "src", "web")},
+	}
+
+	for _, tt := range tests {
+		audit := auditNixRoot(tt.link, store, tt.now, NixGCRootsDefaultOlderThan)
+
+		assert.Equal(t, tt.kind, audit.Kind, tt.link)
+		assert.Equal(t, tt.status, audit.Status, tt.link)
+		assert.Equal(t, tt.project, audit.Project, tt.link)
+		assert.Equal(t, tt.status == NixRootStale || tt.status == NixRootTrashed, audit.Selected(), tt.link)
+	}
+
+	assert.Equal(t, target, auditNixRoot(result, store, now, NixGCRootsDefaultOlderThan).Target)
+	assert.Equal(t, target, auditNixRoot(profile, store, now, NixGCRootsDefaultOlderThan).Target)
+}
+
+func TestNixGCRootsCleaner_ApplySettings(t *testing.T) {
+	t.Parallel()
+
+	gc := NewNixGCRootsCleaner(false, true, NixGCRootsDefaultOlderThan)
+
+	require.NoError(t, gc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGCRoots: &domain.NixGCRootsSettings{OlderThan: "7d"},
+	}))
+	assert.Equal(t, 7*24*time.Hour, gc.olderThan)
+
+	require.NoError(t, gc.ApplySettings(&domain.OperationSettings{})) //nolint:exhaustruct
+	assert.Equal(t, 7*24*time.Hour, gc.olderThan, "missing settings keep the threshold")
+
+	assert.Error(t, gc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGCRoots: &domain.NixGCRootsSettings{OlderThan: "soon"},
+	}))
+}
@@ -0,0 +1,222 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/adapters"
+	"github.com/LarsArtmann/clean-wizard/internal/conversions"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+)
+
+// NixOptimiseDefaultEvery is how long after the last store optimisation the
+// next one runs by default.
+const NixOptimiseDefaultEvery = 7 * 24 * time.Hour
+
+// NixOptimiseStateFileName is the name of the file in the state directory
+// recording the last store optimisation.
+const NixOptimiseStateFileName = "nix-optimise.json"
+
+// NixOptimiseRecord is the last store optimisation and what it freed.
+type NixOptimiseRecord struct {
+	LastRun time.Time `json:"last_run"`
+	adapters.NixOptimiseStats
+}
+
+// NixOptimiseCleaner deduplicates identical store files into hardlinks with
+// nix store optimise. Optimising a large store takes long, so it runs only
+// when the last optimisation is older than every.
+type NixOptimiseCleaner struct {
+	CleanerBase
+
+	adapter *adapters.NixAdapter
+	every   time.Duration
+	// statePath is the file the last optimisation is recorded in; "" uses
+	// NixOptimiseStateFileName in the state directory.
+	statePath string
+}
+
+// NewNixOptimiseCleaner creates a store optimisation cleaner running at most
+// once per every (0 = on every run).
+func NewNixOptimiseCleaner(verbose, dryRun bool, every time.Duration) *NixOptimiseCleaner {
+	return &NixOptimiseCleaner{
+		CleanerBase: NewCleanerBase(verbose, dryRun),
+		adapter:     adapters.NewNixAdapter(0, 0),
+		every:       every,
+		statePath:   "",
+	}
+}
+
+// Type returns the operation type for the store optimisation cleaner.
+func (oc *NixOptimiseCleaner) Type() domain.OperationType {
+	return domain.OperationTypeNixOptimise
+}
+
+// Name returns the unique identifier for this cleaner.
+func (oc *NixOptimiseCleaner) Name() string {
+	return CleanerNixOptimise
+}
+
+// IsAvailable checks if Nix is available.
+func (oc *NixOptimiseCleaner) IsAvailable(ctx context.Context) bool {
+	return oc.adapter.IsAvailable(ctx)
+}
+
+// ValidateSettings validates the optimisation schedule in the Nix settings.
+func (oc *NixOptimiseCleaner) ValidateSettings(settings *domain.OperationSettings) error {
+	return ValidateOptionalSettings(
+		settings,
+		func(s *domain.OperationSettings) *domain.NixGenerationsSettings { return s.NixGenerations },
+		func(s *domain.NixGenerationsSettings) error {
+			_, err := nixOptimiseEvery(s)
+
+			return err
+		},
+	)
+}
+
+// ApplySettings configures the schedule from the optimize_every of a
+// profile operation's nix_generations settings; without optimize_every the
+// schedule the cleaner was created with is kept.
+func (oc *NixOptimiseCleaner) ApplySettings(settings *domain.OperationSettings) error {
+	if settings == nil || settings.NixGenerations == nil || settings.NixGenerations.OptimizeEvery == "" {
+		return nil
+	}
+
+	every, err := nixOptimiseEvery(settings.NixGenerations)
+	if err != nil {
+		return err
+	}
+
+	oc.every = every
+
+	return nil
+}
+
+// nixOptimiseEvery parses the optimisation schedule; empty means the
+// default schedule and "0" every run.
+func nixOptimiseEvery(s *domain.NixGenerationsSettings) (time.Duration, error) {
+	if s.OptimizeEvery == "" {
+		return NixOptimiseDefaultEvery, nil
+	}
+
+	every, err := domain.ParseCustomDuration(s.OptimizeEvery)
+	if err != nil {
+		return 0, fmt.Errorf("invalid optimize_every %q: %w", s.OptimizeEvery, err)
+	}
+
+	return every, nil
+}
+
+// LastRun returns the recorded last optimisation; ok is false if none was
+// recorded or the record is unreadable.
+func (oc *NixOptimiseCleaner) LastRun() (NixOptimiseRecord, bool) {
+	var record NixOptimiseRecord
+
+	path, err := oc.recordPath()
+	if err == nil {
+		err = state.ReadJSON(path, &record)
+	}
+
+	if err != nil {
+		if oc.verbose && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("  ⚠️  ignoring Nix optimisation record: %v\n", err)
+		}
+
+		return NixOptimiseRecord{}, false //nolint:exhaustruct
+	}
+
+	return record, !record.LastRun.IsZero()
+}
+
+// due reports whether the schedule calls for an optimisation at now, with
+// the reason shown for it.
+func (oc *NixOptimiseCleaner) due(now time.Time) (bool, domain.SelectionReason) {
+	actual := "never optimised"
+
+	last, ok := oc.LastRun()
+	if ok {
+		actual = "last optimised " + formatAge(now.Sub(last.LastRun)) + " ago"
+	}
+
+	threshold := "every run"
+	if oc.every > 0 {
+		threshold = "every " + formatAge(oc.every)
+	}
+
+	reason := domain.SelectionReason{ //nolint:exhaustruct
+		Kind:      domain.ReasonAge,
+		Message:   "store optimisation due",
+		Actual:    actual,
+		Threshold: threshold,
+	}
+
+	return !ok || now.Sub(last.LastRun) >= oc.every, reason
+}
+
+// recordPath returns the file the last optimisation is recorded in.
+func (oc *NixOptimiseCleaner) recordPath() (string, error) {
+	if oc.statePath != "" {
+		return oc.statePath, nil
+	}
+
+	return state.Path(NixOptimiseStateFileName)
+}
+
+// Scan returns the store as a single item when an optimisation is due. Its
+// size is unknown until nix store optimise has run.
+func (oc *NixOptimiseCleaner) Scan(_ context.Context) result.Result[[]domain.ScanItem] {
+	due, reason := oc.due(time.Now())
+	if !due {
+		return result.Ok([]domain.ScanItem{})
+	}
+
+	return result.Ok([]domain.ScanItem{{ //nolint:exhaustruct
+		Path:     nixStorePath,
+		ScanType: domain.ScanTypeNixStore,
+		Reasons:  []domain.SelectionReason{reason},
+	}})
+}
+
+// Clean runs nix store optimise when it is due and records the run. It
+// reports the bytes the optimisation deduplicated and the files it
+// hardlinked. In dry-run mode it only reports whether it is due.
+func (oc *NixOptimiseCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	start := time.Now()
+
+	due, reason := oc.due(start)
+	if !due {
+		if oc.verbose {
+			fmt.Printf("  ⏭️  Nix store optimisation not due: %s, %s\n", reason.Actual, reason.Threshold)
+		}
+
+		return result.Ok(conversions.NewCleanResult(domain.StrategyConservativeType, 0, 0))
+	}
+
+	if oc.dryRun {
+		return result.Ok(conversions.NewCleanResult(domain.StrategyDryRunType, 1, 0))
+	}
+
+	stats, err := oc.adapter.Optimise(ctx)
+	if err != nil {
+		return conversions.ToCleanResultFromError(err)
+	}
+
+	path, err := oc.recordPath()
+	if err == nil {
+		err = state.WriteJSON(path, NixOptimiseRecord{LastRun: start, NixOptimiseStats: stats})
+	}
+
+	if err != nil && oc.verbose {
+		fmt.Printf("  ⚠️  failed to record Nix optimisation, it runs again next time: %v\n", err)
+	}
+
+	return result.Ok(conversions.NewCleanResultWithTiming(
+		domain.StrategyConservativeType, stats.FilesLinked, stats.FreedBytes, time.Since(start),
+	))
+}
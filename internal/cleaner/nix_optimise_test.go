@@ -0,0 +1,84 @@
+package cleaner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/adapters"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNixOptimiseCleaner_Schedule(t *testing.T) {
+	t.Parallel()
+
+	oc := NewNixOptimiseCleaner(false, true, NixOptimiseDefaultEvery)
+	oc.statePath = filepath.Join(t.TempDir(), NixOptimiseStateFileName)
+
+	now := time.Now()
+
+	due, reason := oc.due(now)
+	assert.True(t, due, "a store never optimised is due")
+	assert.Equal(t, "never optimised", reason.Actual)
+
+	items := oc.Scan(context.Background())
+	require.True(t, items.IsOk())
+	require.Len(t, items.Value(), 1)
+	assert.Equal(t, nixStorePath, items.Value()[0].Path)
+
+	require.NoError(t, state.WriteJSON(oc.statePath, NixOptimiseRecord{
+		LastRun:          now.Add(-3 * 24 * time.Hour),
+		NixOptimiseStats: adapters.NixOptimiseStats{FreedBytes: 1 << 20, FilesLinked: 3},
+	}))
+
+	due, reason = oc.due(now)
+	assert.False(t, due)
+	assert.Equal(t, "last optimised 3d ago", reason.Actual)
+	assert.Equal(t, "every 7d", reason.Threshold)
+
+	items = oc.Scan(context.Background())
+	require.True(t, items.IsOk())
+	assert.Empty(t, items.Value())
+
+	cleaned := oc.Clean(context.Background())
+	require.True(t, cleaned.IsOk())
+	assert.Zero(t, cleaned.Value().ItemsRemoved, "an optimisation that is not due does not run")
+
+	due, _ = oc.due(now.Add(5 * 24 * time.Hour))
+	assert.True(t, due)
+}
+
+func TestNixOptimiseCleaner_ApplySettings(t *testing.T) {
+	t.Parallel()
+
+	oc := NewNixOptimiseCleaner(false, true, NixOptimiseDefaultEvery)
+
+	require.NoError(t, oc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{Optimize: domain.OptimizationModeEnabled, OptimizeEvery: "30d"}, //nolint:exhaustruct
+	}))
+	assert.Equal(t, 30*24*time.Hour, oc.every)
+
+	require.NoError(t, oc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{Optimize: domain.OptimizationModeEnabled}, //nolint:exhaustruct
+	}))
+	assert.Equal(t, 30*24*time.Hour, oc.every, "without optimize_every the schedule is kept")
+
+	oc = NewNixOptimiseCleaner(false, true, NixOptimiseDefaultEvery)
+	require.NoError(t, oc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{Optimize: domain.OptimizationModeEnabled}, //nolint:exhaustruct
+	}))
+	assert.Equal(t, NixOptimiseDefaultEvery, oc.every, "without optimize_every the default schedule applies")
+
+	require.NoError(t, oc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{OptimizeEvery: "0"}, //nolint:exhaustruct
+	}))
+	assert.Zero(t, oc.every, "optimize_every 0 optimises the store after every GC")
+
+	assert.Error(t, oc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{OptimizeEvery: "weekly"}, //nolint:exhaustruct
+	}))
+}
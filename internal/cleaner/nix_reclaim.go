@@ -0,0 +1,68 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
+)
+
+// nixStorePath is the Nix store the garbage collector deletes from.
+const nixStorePath = "/nix/store"
+
+// nixStoreLinks is the directory nix-store --optimise hardlinks identical
+// store files into; a garbage collection removes the entries it no longer
+// needs.
+const nixStoreLinks = "/nix/store/.links"
+
+// nixOnDiskFreed returns, per store path, the allocated bytes a garbage
+// collection deleting all of paths frees: the blocks of every file whose
+// links all lie within paths, apart from the one link an optimised store
+// keeps in linksDir. A file hardlinked into several of the paths counts for
+// the first of them. Paths that cannot be read count as 0.
+func nixOnDiskFreed(ctx context.Context, paths []string, linksDir string) map[string]int64 {
+	type inode struct {
+		owner     string
+		allocated int64
+		nlink     uint64
+		seen      uint64
+	}
+
+	_, err := os.Lstat(linksDir)
+	optimised := err == nil
+
+	freed := make(map[string]int64, len(paths))
+	inodes := make(map[[2]uint64]*inode)
+
+	for _, p := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+
+		summary, err := fswalk.Shared().Summarize(ctx, p)
+		if err != nil {
+			continue
+		}
+
+		freed[p] += summary.Allocated
+
+		for _, link := range summary.Links {
+			freed[p] -= link.Allocated
+
+			key := [2]uint64{link.Dev, link.Ino}
+			if in, ok := inodes[key]; ok {
+				in.seen++
+			} else {
+				inodes[key] = &inode{owner: p, allocated: link.Allocated, nlink: link.Nlink, seen: 1}
+			}
+		}
+	}
+
+	for _, in := range inodes {
+		if in.seen >= in.nlink || (optimised && in.seen+1 == in.nlink) {
+			freed[in.owner] += in.allocated
+		}
+	}
+
+	return freed
+}
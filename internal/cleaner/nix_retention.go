@@ -0,0 +1,130 @@
+package cleaner
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// nixRetention is the keep policy applied to each profile's generations on
+// its own. A generation is kept if it is current, among the keepLast newest
+// of its profile, or younger than keepNewerThan.
+type nixRetention struct {
+	keepLast      int
+	keepNewerThan time.Duration
+}
+
+// nixDecision is the retention decision for one generation.
+type nixDecision struct {
+	gen     domain.NixGeneration
+	removed bool
+	reason  domain.SelectionReason
+}
+
+// decide applies the policy to generations. Profiles keep the order they
+// were listed in; within a profile, generations are ordered newest first.
+func (r nixRetention) decide(generations []domain.NixGeneration, now time.Time) []nixDecision {
+	var profiles []string
+
+	byProfile := make(map[string][]domain.NixGeneration)
+
+	for _, gen := range generations {
+		if _, ok := byProfile[gen.Profile]; !ok {
+			profiles = append(profiles, gen.Profile)
+		}
+
+		byProfile[gen.Profile] = append(byProfile[gen.Profile], gen)
+	}
+
+	decisions := make([]nixDecision, 0, len(generations))
+
+	for _, profile := range profiles {
+		newestFirst := slices.SortedFunc(slices.Values(byProfile[profile]), func(a, b domain.NixGeneration) int {
+			return cmp.Compare(b.ID, a.ID)
+		})
+
+		for i, gen := range newestFirst {
+			decisions = append(decisions, r.decideOne(i, gen, now))
+		}
+	}
+
+	return decisions
+}
+
+// decideOne decides on the generation at rank (0 = newest) in its profile.
+func (r nixRetention) decideOne(rank int, gen domain.NixGeneration, now time.Time) nixDecision {
+	age := now.Sub(gen.Date)
+	actual := fmt.Sprintf("generation %d of %s, %s old", gen.ID, nixProfileName(gen.Profile), formatAge(age))
+	threshold := r.String()
+
+	decision := nixDecision{gen: gen, removed: false, reason: domain.SelectionReason{}} //nolint:exhaustruct
+
+	switch {
+	case gen.Current.IsCurrent():
+		decision.reason = retentionReason("kept: current generation", actual, threshold)
+	case rank < r.keepLast:
+		decision.reason = retentionReason("kept: among the newest generations kept", actual, threshold)
+	case r.keepNewerThan > 0 && age < r.keepNewerThan:
+		decision.reason = retentionReason("kept: newer than the age kept", actual, threshold)
+	default:
+		decision.removed = true
+		decision.reason = retentionReason("removed: older than the generations kept", actual, threshold)
+	}
+
+	return decision
+}
+
+// removed returns the generations the policy removes.
+func (r nixRetention) removed(generations []domain.NixGeneration, now time.Time) []domain.NixGeneration {
+	var old []domain.NixGeneration
+
+	for _, d := range r.decide(generations, now) {
+		if d.removed {
+			old = append(old, d.gen)
+		}
+	}
+
+	return old
+}
+
+// String describes the policy, e.g. "keep last 3 or newer than 14d".
+func (r nixRetention) String() string {
+	policy := fmt.Sprintf("keep last %d", r.keepLast)
+	if r.keepNewerThan > 0 {
+		policy += " or newer than " + formatAge(r.keepNewerThan)
+	}
+
+	return policy
+}
+
+// nixProfileName is the display name of a profile link.
+func nixProfileName(profile string) string {
+	if profile == "" {
+		return "default profile"
+	}
+
+	return filepath.Base(profile)
+}
+
+// NixGenerationProfile returns the profile link a generation link such as
+// /nix/var/nix/profiles/system-42-link belongs to.
+func NixGenerationProfile(link string) (string, bool) {
+	base := filepath.Base(link)
+
+	trimmed, ok := strings.CutSuffix(base, "-link")
+	if !ok {
+		return "", false
+	}
+
+	i := strings.LastIndexByte(trimmed, '-')
+	if i <= 0 || strings.Trim(trimmed[i+1:], "0123456789") != "" || i+1 == len(trimmed) {
+		return "", false
+	}
+
+	return filepath.Join(filepath.Dir(link), trimmed[:i]), true
+}
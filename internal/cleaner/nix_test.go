@@ -3,10 +3,17 @@ package cleaner
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/adapters"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNixCleaner_ListGenerations(t *testing.T) {
@@ -122,3 +129,115 @@ func TestNixCleaner_IsNixNotAvailable(t *testing.T) {
 
 	t.Logf("✅ Nix availability check working correctly")
 }
+
+func TestNixRetention_PerProfile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	// nix-env lists generations oldest first, profile by profile.
+	generations := []domain.NixGeneration{
+		{ID: 1, Profile: "/p/profile", Date: daysAgo(40)},                                          //nolint:exhaustruct
+		{ID: 2, Profile: "/p/profile", Date: daysAgo(30)},                                          //nolint:exhaustruct
+		{ID: 3, Profile: "/p/profile", Date: daysAgo(20), Current: domain.GenerationStatusCurrent}, //nolint:exhaustruct
+		{ID: 4, Profile: "/p/profile", Date: daysAgo(10)},                                          //nolint:exhaustruct
+		{ID: 1, Profile: "/p/home-manager", Date: daysAgo(9)},                                      //nolint:exhaustruct
+		{ID: 2, Profile: "/p/home-manager", Date: daysAgo(1)},                                      //nolint:exhaustruct
+	}
+
+	removedIDs := func(r nixRetention) map[string][]domain.NixGenerationID {
+		removed := make(map[string][]domain.NixGenerationID)
+		for _, gen := range r.removed(generations, now) {
+			removed[gen.Profile] = append(removed[gen.Profile], gen.ID)
+		}
+
+		return removed
+	}
+
+	assert.Equal(t, map[string][]domain.NixGenerationID{"/p/profile": {2, 1}, "/p/home-manager": {1}},
+		removedIDs(nixRetention{keepLast: 1}),
+		"each profile keeps its newest and the current generation is never removed")
+	assert.Equal(t, map[string][]domain.NixGenerationID{"/p/profile": {1}},
+		removedIDs(nixRetention{keepLast: 1, keepNewerThan: 35 * 24 * time.Hour}),
+		"the age rule keeps what the count rule would remove")
+	assert.Equal(t, map[string][]domain.NixGenerationID{"/p/profile": {4, 2, 1}, "/p/home-manager": {1}},
+		removedIDs(nixRetention{keepLast: 0, keepNewerThan: 5 * 24 * time.Hour}))
+
+	decisions := nixRetention{keepLast: 1}.decide(generations, now)
+	require.Len(t, decisions, len(generations))
+	assert.Equal(t, "generation 4 of profile, 10d old", decisions[0].reason.Actual)
+	assert.Equal(t, "/p/home-manager", decisions[4].gen.Profile, "profiles keep their listed order")
+}
+
+func TestNixGenerationProfile(t *testing.T) {
+	t.Parallel()
+
+	profile, ok := NixGenerationProfile("/nix/var/nix/profiles/system-42-link")
+	assert.True(t, ok)
+	assert.Equal(t, "/nix/var/nix/profiles/system", profile)
+
+	profile, ok = NixGenerationProfile("/home/u/.local/state/nix/profiles/home-manager-7-link")
+	assert.True(t, ok)
+	assert.Equal(t, "/home/u/.local/state/nix/profiles/home-manager", profile)
+
+	for _, path := range []string{"/nix/store", "/p/profile-link", "/p/profile-x-link"} {
+		_, ok = NixGenerationProfile(path)
+		assert.False(t, ok, path)
+	}
+}
+
+func TestNixCleaner_ApplySettings(t *testing.T) {
+	t.Parallel()
+
+	nc := NewNixCleaner(false, true)
+	require.NoError(t, nc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{Generations: 3, KeepLast: 2, KeepNewerThan: "14d"}, //nolint:exhaustruct
+	}))
+	assert.Equal(t, "keep last 2 or newer than 14d", nc.retention.String())
+
+	err := nc.ApplySettings(&domain.OperationSettings{ //nolint:exhaustruct
+		NixGenerations: &domain.NixGenerationsSettings{Generations: 3, KeepNewerThan: "soon"}, //nolint:exhaustruct
+	})
+	assert.Error(t, err)
+}
+
+func TestNixOnDiskFreed_Hardlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	own := filepath.Join(dir, "a-own")
+	shared := filepath.Join(dir, "b-shared")
+	links := filepath.Join(dir, ".links")
+
+	for _, d := range []string{own, shared, links} {
+		require.NoError(t, os.Mkdir(d, 0o755))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(own, "unique"), make([]byte, 8192), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(own, "linked"), make([]byte, 8192), 0o644))
+	require.NoError(t, os.Link(filepath.Join(own, "linked"), filepath.Join(shared, "linked")))
+
+	allocated := func(name string) int64 {
+		info, err := os.Stat(filepath.Join(own, name))
+		require.NoError(t, err)
+
+		return info.Sys().(*syscall.Stat_t).Blocks * 512 //nolint:forcetypeassert
+	}
+
+	ctx := context.Background()
+	missing := filepath.Join(dir, "missing")
+
+	freed := nixOnDiskFreed(ctx, []string{own}, missing)
+	assert.Equal(t, allocated("unique"), freed[own], "a file another path links stays")
+
+	freed = nixOnDiskFreed(ctx, []string{own, shared}, missing)
+	assert.Equal(t, allocated("unique")+allocated("linked"), freed[own], "a shared file counts once")
+	assert.Zero(t, freed[shared])
+
+	// An optimised store holds one more link to every deduplicated file.
+	require.NoError(t, os.Link(filepath.Join(own, "linked"), filepath.Join(links, "hash")))
+
+	freed = nixOnDiskFreed(ctx, []string{own, shared}, links)
+	assert.Equal(t, allocated("unique")+allocated("linked"), freed[own], "the .links entry goes with the last user")
+}
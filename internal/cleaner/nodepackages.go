@@ -167,6 +167,7 @@ func (npmc *NodePackageManagerCleaner) scanPackageManager(
 				Size:     0, // Size unknown without checking
 				Created:  time.Time{},
 				ScanType: domain.ScanTypeTemp,
+				Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeTemp)},
 			})
 
 			if npmc.verbose {
@@ -198,6 +199,7 @@ func (npmc *NodePackageManagerCleaner) scanPackageManager(
 				Size:     0, // Size unknown without checking
 				Created:  time.Time{},
 				ScanType: domain.ScanTypeTemp,
+				Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeTemp)},
 			})
 
 			if npmc.verbose {
@@ -249,6 +251,7 @@ func (npmc *NodePackageManagerCleaner) scanHomeDirCache(
 			Size:     0, // Size unknown without checking
 			Created:  time.Time{},
 			ScanType: domain.ScanTypeTemp,
+			Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeTemp)},
 		},
 	}
 
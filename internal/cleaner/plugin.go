@@ -0,0 +1,498 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// PluginExecutablePrefix is the file name prefix of plugin executables.
+// The remainder of the file name becomes the cleaner's registry name.
+const PluginExecutablePrefix = "clean-wizard-plugin-"
+
+// PluginDirEnv overrides the default plugins directory.
+const PluginDirEnv = "CLEAN_WIZARD_PLUGIN_DIR"
+
+// DefaultPluginIcon is shown for plugins that do not describe an icon.
+const DefaultPluginIcon = "🔌"
+
+// PluginCleaner adapts an external executable speaking the plugin protocol
+// to the Cleaner interface. The plugin only reports what to remove; deletion
+// of filesystem paths is always performed by the core after path safety checks,
+// and only below the roots the plugin describes or for paths its scan reported.
+type PluginCleaner struct {
+	CleanerBase
+
+	name      string
+	path      string
+	protected []string
+
+	metadataTimeout  time.Duration
+	operationTimeout time.Duration
+
+	// describeMu serializes describe calls; mu guards the fields below and
+	// is never held while the plugin runs.
+	describeMu  sync.Mutex
+	mu          sync.Mutex
+	described   bool
+	description PluginResponse
+	scanned     map[string]bool
+}
+
+// NewPluginCleaner creates a cleaner for the plugin executable at path.
+// Paths the plugin asks to delete below any of the protected paths are refused.
+func NewPluginCleaner(verbose, dryRun bool, path string, protected []string) (*PluginCleaner, error) {
+	name, ok := PluginNameFromPath(path)
+	if !ok {
+		return nil, errorfamily.NewRejection(
+			"cleaner.plugin.invalid_name",
+			fmt.Sprintf("plugin executable %s must be named %s<name>", path, PluginExecutablePrefix),
+		)
+	}
+
+	return &PluginCleaner{ //nolint:exhaustruct
+		CleanerBase:      NewCleanerBase(verbose, dryRun),
+		name:             name,
+		path:             path,
+		protected:        NormalizePaths(protected),
+		metadataTimeout:  PluginMetadataTimeout,
+		operationTimeout: PluginOperationTimeout,
+	}, nil
+}
+
+// PluginNameFromPath derives the registry name from a plugin executable path.
+// It returns false if the file name lacks the plugin prefix or the remainder
+// is not a valid kebab-case cleaner name.
+func PluginNameFromPath(path string) (string, bool) {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, PluginExecutablePrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(base, PluginExecutablePrefix)
+	if !domain.IsValidCleanerName(name) {
+		return "", false
+	}
+
+	return name, true
+}
+
+// DefaultPluginDir returns the plugins directory: $CLEAN_WIZARD_PLUGIN_DIR if
+// set, otherwise clean-wizard/plugins below the user config directory.
+func DefaultPluginDir() string {
+	if dir := os.Getenv(PluginDirEnv); dir != "" {
+		return dir
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "clean-wizard", "plugins")
+}
+
+// PluginSearchDirs returns the directories searched for plugins: the plugins
+// directory first, then every PATH entry.
+func PluginSearchDirs() []string {
+	dirs := []string{DefaultPluginDir()}
+
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// DiscoverPlugins returns the executables named clean-wizard-plugin-* found in
+// dirs. When the same plugin name appears in several directories, the first
+// one wins, so the plugins directory shadows PATH.
+func DiscoverPlugins(dirs []string) []string {
+	seen := make(map[string]bool)
+
+	var found []string
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := PluginNameFromPath(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+
+			seen[name] = true
+
+			found = append(found, path)
+		}
+	}
+
+	sort.Strings(found)
+
+	return found
+}
+
+// RegisterPlugins creates a PluginCleaner for each executable and adds it to
+// the registry. A plugin that would shadow an already registered cleaner is
+// skipped, so a stray executable on PATH cannot replace a built-in cleaner.
+func RegisterPlugins(registry *Registry, paths, protected []string, verbose, dryRun bool) {
+	for _, path := range paths {
+		c, err := NewPluginCleaner(verbose, dryRun, path, protected)
+		if err != nil {
+			continue
+		}
+
+		if _, exists := registry.Get(c.Name()); exists {
+			if verbose {
+				fmt.Printf("Skipping plugin %s: cleaner %q is already registered\n", path, c.Name())
+			}
+
+			continue
+		}
+
+		registry.Register(c.Name(), c)
+	}
+}
+
+// Name returns the registry name derived from the executable file name.
+func (pc *PluginCleaner) Name() string {
+	return pc.name
+}
+
+// Type returns the operation type the plugin describes, or its name.
+// Like the other metadata accessors it never calls the plugin; the
+// description is fetched by IsAvailable, Scan and Clean under the caller's
+// context, and defaults are returned until then.
+func (pc *PluginCleaner) Type() domain.OperationType {
+	if t := pc.metadata().Type; t != "" {
+		return domain.OperationType(t)
+	}
+
+	return domain.OperationType(pc.name)
+}
+
+// DisplayName returns the name the plugin describes, or its registry name.
+func (pc *PluginCleaner) DisplayName() string {
+	if n := pc.metadata().Name; n != "" {
+		return n
+	}
+
+	return pc.name
+}
+
+// Description returns the description the plugin describes.
+func (pc *PluginCleaner) Description() string {
+	if d := pc.metadata().Description; d != "" {
+		return d
+	}
+
+	return "External plugin " + pc.path
+}
+
+// Icon returns the icon the plugin describes, or DefaultPluginIcon.
+func (pc *PluginCleaner) Icon() string {
+	if i := pc.metadata().Icon; i != "" {
+		return i
+	}
+
+	return DefaultPluginIcon
+}
+
+// Resources returns the resource class and timeout the plugin describes,
+// defaulting to DISK_IO without a timeout (each call is still bounded by
+// PluginOperationTimeout).
+func (pc *PluginCleaner) Resources() Resources {
+	desc := pc.metadata()
+
+	return Resources{Class: domain.ResourceClassDiskIO, Timeout: 0}.
+		Apply(ParseResourceOverride(desc.ResourceClass, desc.Timeout))
+}
+
+// metadata returns the description fetched so far without calling the plugin.
+func (pc *PluginCleaner) metadata() PluginResponse {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	return pc.description
+}
+
+// describe queries the plugin's metadata once. Failures leave the
+// description empty so callers fall back to defaults; a call cut short by
+// ctx is retried on the next use.
+func (pc *PluginCleaner) describe(ctx context.Context) PluginResponse {
+	pc.describeMu.Lock()
+	defer pc.describeMu.Unlock()
+
+	pc.mu.Lock()
+	described := pc.described
+	pc.mu.Unlock()
+
+	if !described {
+		resp, err := pc.call(ctx, PluginMethodDescribe, pc.metadataTimeout)
+
+		pc.mu.Lock()
+		if err == nil {
+			pc.description = resp
+		}
+
+		pc.described = ctx.Err() == nil
+		pc.mu.Unlock()
+	}
+
+	return pc.metadata()
+}
+
+// IsAvailable asks the plugin whether it can run on this system.
+func (pc *PluginCleaner) IsAvailable(ctx context.Context) bool {
+	pc.describe(ctx)
+
+	resp, err := pc.call(ctx, PluginMethodIsAvailable, pc.metadataTimeout)
+
+	return err == nil && resp.Available
+}
+
+// Scan asks the plugin for cleanable items. Items kept by ignore files are
+// left out; the rest are remembered as deletable by Clean in this run.
+func (pc *PluginCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
+	pc.describe(ctx)
+
+	resp, err := pc.call(ctx, PluginMethodScan, pc.operationTimeout)
+	if err != nil {
+		return result.Err[[]domain.ScanItem](err)
+	}
+
+	items := slices.DeleteFunc(pluginItemsToScanItems(resp.Items), func(item domain.ScanItem) bool {
+		return keptByIgnoreRules(ctx, item.Path)
+	})
+
+	scanned := make(map[string]bool, len(items))
+	for _, item := range items {
+		scanned[item.Path] = true
+	}
+
+	pc.mu.Lock()
+	pc.scanned = scanned
+	pc.mu.Unlock()
+
+	return result.Ok(items)
+}
+
+// Clean asks the plugin to clean. In dry-run mode the plugin is only scanned.
+// Paths returned in the response's delete list are trashed by the core after
+// validatePluginDeletePath, and only those are counted, sized by the core
+// itself. Paths kept by ignore files are skipped. A response claiming
+// removals of its own is rejected unprocessed, since the core cannot verify
+// them.
+func (pc *PluginCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	if pc.dryRun {
+		scanResult := pc.Scan(ctx)
+		if scanResult.IsErr() {
+			return result.Err[domain.CleanResult](scanResult.Error())
+		}
+
+		var totalBytes int64
+		for _, item := range scanResult.Value() {
+			totalBytes += item.Size
+		}
+
+		return NewDryRunCleanResult(len(scanResult.Value()), totalBytes)
+	}
+
+	roots := pc.declaredRoots(pc.describe(ctx).Roots)
+
+	resp, err := pc.call(ctx, PluginMethodClean, pc.operationTimeout)
+	if err != nil {
+		return result.Err[domain.CleanResult](err)
+	}
+
+	if resp.ItemsRemoved != 0 || resp.FreedBytes != 0 {
+		return result.Err[domain.CleanResult](errorfamily.NewRejection(
+			pluginCode(pc.name, "unverified_counters"),
+			fmt.Sprintf(
+				"plugin %s reported %d items and %d bytes it removed itself; plugins must list paths in delete instead",
+				pc.name, resp.ItemsRemoved, resp.FreedBytes,
+			),
+		))
+	}
+
+	counters := NewCleanCounters()
+	engine := SizeEngineFromContext(ctx)
+
+	for _, item := range pluginItemsToScanItems(resp.Delete) {
+		err := pc.validatePluginDeletePath(item.Path, roots)
+		if err == nil && keptByIgnoreRules(ctx, item.Path) {
+			if pc.verbose {
+				fmt.Printf("  Skipping %s: kept by an ignore file\n", item.Path)
+			}
+
+			continue
+		}
+
+		if err == nil {
+			engine.Measure(ctx, item.Path)
+
+			err = TrashPath(ctx, item.Path)
+		}
+
+		if err != nil {
+			counters.RecordFailure(pc.verbose, item.Path, err)
+
+			continue
+		}
+
+		// The size the plugin reported is not trusted; a path the core
+		// could not measure counts as freeing nothing.
+		freed, _ := engine.Freed(item.Path)
+		counters.RecordSuccess(freed)
+	}
+
+	return NewCleanResultWithMetrics(
+		counters.ItemsRemoved,
+		counters.ItemsFailed,
+		counters.BytesFreed,
+		counters.Duration(),
+	)
+}
+
+// validatePluginDeletePath is the sandbox for plugin-requested deletions: the
+// path must be absolute and clean, must exist, must not be a system path or
+// the home directory, must not lie below a critical or protected path, and
+// must lie below one of roots or have been reported by this run's scan.
+func (pc *PluginCleaner) validatePluginDeletePath(path string, roots []string) error {
+	reject := func(reason string) error {
+		return errorfamily.NewRejection(
+			pluginCode(pc.name, "unsafe_path"),
+			fmt.Sprintf("plugin %s requested deletion of %s: %s", pc.name, path, reason),
+		)
+	}
+
+	if reason := pc.unsafePathReason(path); reason != "" {
+		return reject(reason)
+	}
+
+	if _, err := os.Lstat(path); err != nil {
+		return reject("path does not exist")
+	}
+
+	pc.mu.Lock()
+	scanned := pc.scanned[path]
+	pc.mu.Unlock()
+
+	if !scanned && !isUnderAny(path, roots) {
+		return reject("path is neither below a declared root nor reported by scan")
+	}
+
+	return nil
+}
+
+// declaredRoots returns the roots a plugin described that are safe to
+// delete below. A root failing the path checks, or holding the home
+// directory, is dropped.
+func (pc *PluginCleaner) declaredRoots(roots []string) []string {
+	homeDir, homeErr := GetHomeDir()
+
+	valid := make([]string, 0, len(roots))
+
+	for _, root := range roots {
+		if reason := pc.unsafePathReason(root); reason != "" {
+			if pc.verbose {
+				fmt.Printf("Ignoring root %s declared by plugin %s: %s\n", root, pc.name, reason)
+			}
+
+			continue
+		}
+
+		if homeErr == nil && isUnderAny(homeDir, []string{root}) {
+			if pc.verbose {
+				fmt.Printf("Ignoring root %s declared by plugin %s: it holds the home directory\n", root, pc.name)
+			}
+
+			continue
+		}
+
+		valid = append(valid, root)
+	}
+
+	return valid
+}
+
+// unsafePathReason explains why path may never be deleted, or returns "".
+func (pc *PluginCleaner) unsafePathReason(path string) string {
+	switch {
+	case !filepath.IsAbs(path) || filepath.Clean(path) != path:
+		return "path must be absolute and clean"
+	case slices.Contains(protectedSystemPathsAndHome(), path):
+		return "path is a system directory"
+	case isUnderAny(path, slices.Concat(domain.CriticalSystemPaths(), pc.protected)):
+		return "path is protected"
+	default:
+		return ""
+	}
+}
+
+// call sends a single request to the plugin.
+func (pc *PluginCleaner) call(ctx context.Context, method string, timeout time.Duration) (PluginResponse, error) {
+	return callPlugin(ctx, pc.name, pc.path, timeout, PluginRequest{ //nolint:exhaustruct
+		Method:  method,
+		DryRun:  pc.dryRun,
+		Verbose: pc.verbose,
+	})
+}
+
+// pluginItemsToScanItems converts protocol items to domain scan items.
+func pluginItemsToScanItems(items []PluginItem) []domain.ScanItem {
+	scanItems := make([]domain.ScanItem, 0, len(items))
+	for _, item := range items {
+		scanItems = append(scanItems, domain.ScanItem{
+			Path:     item.Path,
+			Size:     item.Size,
+			Created:  item.Created,
+			ScanType: domain.ScanTypeCache,
+			Reasons:  []domain.SelectionReason{ruleReason("reported by the plugin", "")},
+		})
+	}
+
+	return scanItems
+}
+
+// protectedSystemPathsAndHome returns the protected system paths plus the
+// user's home directory, none of which may ever be removed as a whole.
+func protectedSystemPathsAndHome() []string {
+	paths := domain.AllProtectedSystemPaths()
+	if homeDir, err := GetHomeDir(); err == nil {
+		paths = append(paths, homeDir)
+	}
+
+	return paths
+}
+
+// isUnderAny reports whether path equals one of roots or lies below one.
+func isUnderAny(path string, roots []string) bool {
+	for _, root := range roots {
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,189 @@
+package cleaner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// PluginProtocolVersion is the JSON protocol version spoken with plugins.
+// A plugin answering with a different version is rejected as a conflict.
+const PluginProtocolVersion = 1
+
+// Plugin protocol methods. Each call starts the plugin executable once,
+// writes a single PluginRequest to stdin and reads a single PluginResponse
+// from stdout.
+const (
+	PluginMethodDescribe    = "describe"
+	PluginMethodIsAvailable = "is_available"
+	PluginMethodScan        = "scan"
+	PluginMethodClean       = "clean"
+)
+
+// Plugin call timeouts. Metadata calls must answer quickly; scan and clean
+// may walk large trees or call slow tools.
+const (
+	PluginMetadataTimeout  = 10 * time.Second
+	PluginOperationTimeout = 10 * time.Minute
+)
+
+// PluginRequest is written to a plugin's stdin.
+type PluginRequest struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Method          string `json:"method"`
+	DryRun          bool   `json:"dry_run"`
+	Verbose         bool   `json:"verbose"`
+}
+
+// PluginItem is a cleanable item reported by a plugin.
+type PluginItem struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created,omitzero"`
+}
+
+// PluginError is an error reported by a plugin. Family is one of the
+// go-error-family names (rejection, conflict, transient, corruption,
+// infrastructure, orchestration); unknown values are treated as transient.
+type PluginError struct {
+	Family  string `json:"family"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PluginResponse is read from a plugin's stdout. Only the fields relevant to
+// the requested method need to be set.
+type PluginResponse struct {
+	ProtocolVersion int `json:"protocol_version"`
+
+	// describe
+	Name        string `json:"name,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	// ResourceClass and Timeout declare scheduling defaults like the
+	// resource_class and timeout fields of custom cleaners.
+	ResourceClass string `json:"resource_class,omitempty"`
+	Timeout       string `json:"timeout,omitempty"`
+	// Roots are the absolute directories the plugin cleans. clean may only
+	// delete paths below them, or paths scan reported in the same run.
+	Roots []string `json:"roots,omitempty"`
+
+	// is_available
+	Available bool `json:"available,omitempty"`
+
+	// scan
+	Items []PluginItem `json:"items,omitempty"`
+
+	// clean: Delete lists paths the core should remove on the plugin's behalf
+	// (subject to the core's path safety checks). ItemsRemoved and FreedBytes
+	// would report work the plugin did itself; the core cannot verify that,
+	// so a response setting them is rejected.
+	Delete       []PluginItem `json:"delete,omitempty"`
+	ItemsRemoved int          `json:"items_removed,omitempty"`
+	FreedBytes   int64        `json:"freed_bytes,omitempty"`
+
+	Error *PluginError `json:"error,omitempty"`
+}
+
+// callPlugin runs one protocol round-trip against the plugin executable.
+// Failures are classified: timeouts are transient, unparseable output is
+// corruption, a protocol version mismatch is a conflict, and errors reported
+// by the plugin keep the family the plugin declared.
+func callPlugin(
+	ctx context.Context,
+	name, path string,
+	timeout time.Duration,
+	req PluginRequest,
+) (PluginResponse, error) {
+	var resp PluginResponse
+
+	req.ProtocolVersion = PluginProtocolVersion
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return resp, errorfamily.WrapOrchestration(err, "cleaner.plugin.encode", "failed to encode plugin request")
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(callCtx, path) //nolint:gosec // plugin path comes from discovery
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return resp, errorfamily.NewTransient(
+			pluginCode(name, "timeout"),
+			fmt.Sprintf("plugin %s did not answer %s within %s", name, req.Method, timeout),
+		)
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return resp, ctxErr
+	}
+
+	err = json.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		if runErr != nil {
+			return resp, errorfamily.WrapCorruptionf(
+				runErr, pluginCode(name, "failed"),
+				"plugin %s failed on %s: %s", name, req.Method, bytes.TrimSpace(stderr.Bytes()),
+			)
+		}
+
+		return resp, errorfamily.WrapCorruptionf(
+			err, pluginCode(name, "invalid_response"),
+			"plugin %s returned an invalid %s response", name, req.Method,
+		)
+	}
+
+	if resp.ProtocolVersion != PluginProtocolVersion {
+		return resp, errorfamily.NewConflict(
+			pluginCode(name, "protocol_version"),
+			fmt.Sprintf(
+				"plugin %s speaks protocol version %d, expected %d",
+				name, resp.ProtocolVersion, PluginProtocolVersion,
+			),
+		)
+	}
+
+	if resp.Error != nil {
+		return resp, resp.Error.toError(name)
+	}
+
+	if runErr != nil {
+		return resp, errorfamily.WrapCorruptionf(
+			runErr, pluginCode(name, "failed"),
+			"plugin %s exited with an error on %s", name, req.Method,
+		)
+	}
+
+	return resp, nil
+}
+
+// toError converts a plugin-reported error into a classified error.
+func (pe *PluginError) toError(name string) error {
+	code := pe.Code
+	if code == "" {
+		code = pluginCode(name, "error")
+	}
+
+	return errorfamily.New(errorfamily.ParseFamily(pe.Family), code, "plugin "+name+": "+pe.Message)
+}
+
+// pluginCode builds a diagnostic code in the cleaner.plugin.<name>.<suffix> form.
+func pluginCode(name, suffix string) string {
+	return "cleaner.plugin." + name + "." + suffix
+}
@@ -0,0 +1,318 @@
+package cleaner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// writeTestPlugin writes a shell script plugin that answers every method with
+// the response registered for it in responses.
+func writeTestPlugin(t *testing.T, dir, name string, responses map[string]string) string {
+	t.Helper()
+
+	script := "#!/bin/sh\nread -r req\ncase \"$req\" in\n"
+	for method, resp := range responses {
+		script += "  *'\"method\":\"" + method + "\"'*) echo '" + resp + "' ;;\n"
+	}
+
+	script += "  *) echo '{\"protocol_version\":1}' ;;\nesac\n"
+
+	path := filepath.Join(dir, PluginExecutablePrefix+name)
+
+	err := os.WriteFile(path, []byte(script), 0o755) //nolint:gosec // test plugin must be executable
+	if err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+
+	return path
+}
+
+func TestPluginNameFromPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{path: "/usr/bin/clean-wizard-plugin-xcode", want: "xcode", wantOK: true},
+		{path: "clean-wizard-plugin-my-tool", want: "my-tool", wantOK: true},
+		{path: "/usr/bin/clean-wizard", want: "", wantOK: false},
+		{path: "clean-wizard-plugin-Bad_Name", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := PluginNameFromPath(tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("PluginNameFromPath(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDiscoverPlugins_FirstDirWins(t *testing.T) {
+	t.Parallel()
+
+	first, second := t.TempDir(), t.TempDir()
+	want := writeTestPlugin(t, first, "dup", nil)
+	writeTestPlugin(t, second, "dup", nil)
+	writeTestPlugin(t, second, "other", nil)
+
+	err := os.WriteFile(filepath.Join(second, PluginExecutablePrefix+"noexec"), []byte("x"), 0o644)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	found := DiscoverPlugins([]string{first, second, filepath.Join(first, "missing")})
+	if len(found) != 2 || found[0] != want {
+		t.Errorf("DiscoverPlugins() = %v, want %s and the other plugin", found, want)
+	}
+}
+
+func TestPluginCleaner_Protocol(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeTestPlugin(t, dir, "demo", map[string]string{
+		PluginMethodDescribe:    `{"protocol_version":1,"name":"Demo","description":"Demo plugin","icon":"🧪"}`,
+		PluginMethodIsAvailable: `{"protocol_version":1,"available":true}`,
+		PluginMethodScan:        `{"protocol_version":1,"items":[{"path":"/tmp/a","size":100},{"path":"/tmp/b","size":50}]}`,
+	})
+
+	pc, err := NewPluginCleaner(false, true, path, nil)
+	if err != nil {
+		t.Fatalf("NewPluginCleaner() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	if pc.DisplayName() != "demo" || pc.Icon() != DefaultPluginIcon {
+		t.Errorf("metadata before describe: display=%s icon=%s, want defaults", pc.DisplayName(), pc.Icon())
+	}
+
+	if !pc.IsAvailable(ctx) {
+		t.Error("IsAvailable() = false, want true")
+	}
+
+	if pc.Name() != "demo" || pc.DisplayName() != "Demo" || pc.Icon() != "🧪" {
+		t.Errorf("unexpected metadata: name=%s display=%s icon=%s", pc.Name(), pc.DisplayName(), pc.Icon())
+	}
+
+	scan := pc.Scan(ctx)
+	if scan.IsErr() || len(scan.Value()) != 2 {
+		t.Fatalf("Scan() = %v, want 2 items", scan)
+	}
+
+	clean := pc.Clean(ctx)
+	if clean.IsErr() {
+		t.Fatalf("Clean() error = %v", clean.Error())
+	}
+
+	if clean.Value().ItemsRemoved != 2 || clean.Value().FreedBytes != 150 {
+		t.Errorf("dry-run Clean() = %+v, want 2 items / 150 bytes", clean.Value())
+	}
+}
+
+func TestPluginCleaner_ErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		response string
+		want     errorfamily.Family
+	}{
+		{
+			name:     "declared",
+			response: `{"protocol_version":1,"error":{"family":"rejection","code":"x.denied","message":"no"}}`,
+			want:     errorfamily.Rejection,
+		},
+		{name: "version", response: `{"protocol_version":99}`, want: errorfamily.Conflict},
+		{name: "garbage", response: `not json`, want: errorfamily.Corruption},
+	}
+
+	for _, tt := range tests {
+		path := writeTestPlugin(t, dir, tt.name, map[string]string{PluginMethodScan: tt.response})
+
+		pc, err := NewPluginCleaner(false, false, path, nil)
+		if err != nil {
+			t.Fatalf("NewPluginCleaner() error = %v", err)
+		}
+
+		scan := pc.Scan(context.Background())
+		if !scan.IsErr() {
+			t.Errorf("%s: Scan() should fail", tt.name)
+
+			continue
+		}
+
+		if got := errorfamily.Classify(scan.Error()); got != tt.want {
+			t.Errorf("%s: family = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPluginCleaner_Timeout(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), PluginExecutablePrefix+"slow")
+
+	err := os.WriteFile(path, []byte("#!/bin/sh\nexec sleep 5\n"), 0o755) //nolint:gosec // test plugin must be executable
+	if err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+
+	pc, err := NewPluginCleaner(false, false, path, nil)
+	if err != nil {
+		t.Fatalf("NewPluginCleaner() error = %v", err)
+	}
+
+	pc.operationTimeout = 100 * time.Millisecond
+
+	scan := pc.Scan(context.Background())
+	if !scan.IsErr() || errorfamily.Classify(scan.Error()) != errorfamily.Transient {
+		t.Errorf("Scan() = %v, want transient timeout error", scan)
+	}
+}
+
+func TestPluginCleaner_RejectsUnsafeDeletes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	protectedDir := filepath.Join(dir, "protected")
+
+	err := os.MkdirAll(protectedDir, 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	path := writeTestPlugin(t, dir, "unsafe", map[string]string{
+		PluginMethodClean: `{"protocol_version":1,` +
+			`"delete":[{"path":"/etc"},{"path":"relative"},{"path":"` + protectedDir + `"}]}`,
+	})
+
+	pc, err := NewPluginCleaner(false, false, path, []string{protectedDir})
+	if err != nil {
+		t.Fatalf("NewPluginCleaner() error = %v", err)
+	}
+
+	clean := pc.Clean(context.Background())
+	if clean.IsErr() {
+		t.Fatalf("Clean() error = %v", clean.Error())
+	}
+
+	got := clean.Value()
+	if got.ItemsRemoved != 0 || got.ItemsFailed != 3 || got.FreedBytes != 0 {
+		t.Errorf("Clean() = %+v, want 3 rejected", got)
+	}
+
+	if _, statErr := os.Stat(protectedDir); statErr != nil {
+		t.Errorf("protected dir was removed: %v", statErr)
+	}
+}
+
+func TestPluginCleaner_RejectsSelfReportedCounters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	victim := filepath.Join(dir, "victim")
+
+	err := os.MkdirAll(victim, 0o755)
+	if err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	path := writeTestPlugin(t, dir, "boastful", map[string]string{
+		PluginMethodClean: `{"protocol_version":1,"items_removed":5,"freed_bytes":1000000,` +
+			`"delete":[{"path":"` + victim + `","size":1000000}]}`,
+	})
+
+	pc, err := NewPluginCleaner(false, false, path, nil)
+	if err != nil {
+		t.Fatalf("NewPluginCleaner() error = %v", err)
+	}
+
+	clean := pc.Clean(context.Background())
+	if !clean.IsErr() || errorfamily.Classify(clean.Error()) != errorfamily.Rejection {
+		t.Errorf("Clean() = %v, want a rejection of the self-reported counters", clean)
+	}
+
+	if _, statErr := os.Stat(victim); statErr != nil {
+		t.Errorf("a rejected response was acted on: %v", statErr)
+	}
+}
+
+func TestPluginCleaner_ConfinesDeletesToRootsAndScannedPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	kept := filepath.Join(root, "kept")
+	outside := filepath.Join(dir, "outside")
+	scanned := filepath.Join(dir, "scanned")
+
+	for _, d := range []string{kept, outside, scanned} {
+		err := os.MkdirAll(d, 0o755)
+		if err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+
+	err := os.WriteFile(filepath.Join(root, ignore.FileName), []byte("kept\n"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	path := writeTestPlugin(t, dir, "confined", map[string]string{
+		PluginMethodDescribe: `{"protocol_version":1,"roots":["` + root + `","/","relative"]}`,
+		PluginMethodScan:     `{"protocol_version":1,"items":[{"path":"` + scanned + `"}]}`,
+		PluginMethodClean: `{"protocol_version":1,` +
+			`"delete":[{"path":"` + kept + `"},{"path":"` + outside + `"}]}`,
+	})
+
+	pc, err := NewPluginCleaner(false, false, path, nil)
+	if err != nil {
+		t.Fatalf("NewPluginCleaner() error = %v", err)
+	}
+
+	ctx := ignore.WithMatcher(context.Background(), ignore.NewMatcher(nil))
+
+	clean := pc.Clean(ctx)
+	if clean.IsErr() {
+		t.Fatalf("Clean() error = %v", clean.Error())
+	}
+
+	got := clean.Value()
+	if got.ItemsRemoved != 0 || got.ItemsFailed != 1 {
+		t.Errorf("Clean() = %+v, want the ignored path skipped and the undeclared one rejected", got)
+	}
+
+	for _, d := range []string{kept, outside} {
+		if _, statErr := os.Stat(d); statErr != nil {
+			t.Errorf("%s was removed: %v", d, statErr)
+		}
+	}
+
+	roots := pc.declaredRoots(pc.metadata().Roots)
+	if len(roots) != 1 || roots[0] != root {
+		t.Errorf("declaredRoots() = %v, want only %s", roots, root)
+	}
+
+	if err := pc.validatePluginDeletePath(scanned, roots); err == nil {
+		t.Error("a path outside the roots was accepted before scan reported it")
+	}
+
+	if scan := pc.Scan(ctx); scan.IsErr() {
+		t.Fatalf("Scan() error = %v", scan.Error())
+	}
+
+	if err := pc.validatePluginDeletePath(scanned, roots); err != nil {
+		t.Errorf("a path reported by scan was rejected: %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package cleaner
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
+	"golang.org/x/sys/unix"
+)
+
+// prerequisiteCommandTimeout bounds one version or daemon probe.
+const prerequisiteCommandTimeout = 10 * time.Second
+
+// Binary is an executable a cleaner runs.
+type Binary struct {
+	Name string
+	// VersionArgs print the binary's version; nil skips the version probe.
+	VersionArgs []string
+	// Optional binaries improve a cleaner but are not required to run it.
+	Optional bool
+	// Hint tells the user how to install the binary; it becomes the
+	// remediation hint of the cleaner's not_available code.
+	Hint string
+}
+
+// Daemon is a service a cleaner talks to, probed with a command that fails
+// when the service is unreachable. Hint becomes the remediation hint of the
+// cleaner's daemon_unreachable code.
+type Daemon struct {
+	Name  string
+	Probe []string
+	Hint  string
+}
+
+// Prerequisites declares what a cleaner needs on this system.
+type Prerequisites struct {
+	Binaries []Binary
+	// AnyBinary means one of Binaries is enough (e.g. any Node package manager).
+	AnyBinary bool
+	Daemons   []Daemon
+	// WritesPaths means the cleaner deletes below its AffectedPaths itself,
+	// so it needs write permission there. Cleaners delegating to a tool or
+	// daemon leave permissions to it.
+	WritesPaths bool
+}
+
+// registerHints registers the remediation hints of the cleaner's
+// not_available, optional_missing and daemon_unreachable codes.
+func (p Prerequisites) registerHints(cleanerName string) {
+	var required, optional, daemons []string
+
+	for _, b := range p.Binaries {
+		switch {
+		case b.Hint == "":
+		case b.Optional:
+			optional = append(optional, b.Hint)
+		default:
+			required = append(required, b.Hint)
+		}
+	}
+
+	for _, d := range p.Daemons {
+		if d.Hint != "" {
+			daemons = append(daemons, d.Hint)
+		}
+	}
+
+	if p.AnyBinary && len(required) > 1 {
+		// Any binary will do: suggest the first, most common one.
+		required = required[:1]
+	}
+
+	hints.Register(hints.CleanerCode(cleanerName, hints.NotAvailable), strings.Join(required, "; "))
+	hints.Register(hints.CleanerCode(cleanerName, hints.OptionalMissing), strings.Join(optional, "; "))
+	hints.Register(hints.CleanerCode(cleanerName, hints.DaemonUnreachable), strings.Join(daemons, "; "))
+}
+
+// PrerequisiteDeclarer is implemented by cleaners that declare their own
+// prerequisites (e.g. custom YAML cleaners and plugins).
+type PrerequisiteDeclarer interface {
+	Prerequisites() Prerequisites
+}
+
+// Hints for binaries shared by several cleaners.
+const (
+	trashHint = "Install trash: brew install trash (macOS) or your distribution's trash-cli"
+	pmaHint   = "Install projects-management-automation and make sure it is on PATH"
+)
+
+// defaultPrerequisites holds the prerequisites of the built-in cleaners.
+var defaultPrerequisites = map[string]Prerequisites{ //nolint:gochecknoglobals
+	CleanerNix: {
+		Binaries: []Binary{
+			{Name: "nix", VersionArgs: []string{"--version"}, Hint: "Install Nix: https://nixos.org/download"},
+			{Name: "nix-env", VersionArgs: []string{"--version"}, Hint: "nix-env ships with Nix; reinstall Nix or add its bin directory to PATH"},
+		},
+		Daemons: []Daemon{{
+			Name:  "nix store",
+			Probe: []string{"nix", "--extra-experimental-features", "nix-command", "store", "ping"},
+			Hint:  "Start the Nix daemon: sudo systemctl start nix-daemon (Linux) or sudo launchctl kickstart -k system/org.nixos.nix-daemon (macOS)",
+		}},
+	},
+	CleanerNixGCRoots: {
+		Binaries: []Binary{
+			{Name: "nix-store", VersionArgs: []string{"--version"}, Hint: "nix-store ships with Nix; reinstall Nix or add its bin directory to PATH"},
+		},
+	},
+	CleanerNixOptimise: {
+		Binaries: []Binary{{Name: "nix", VersionArgs: []string{"--version"}, Hint: "Install Nix: https://nixos.org/download"}},
+	},
+	CleanerHomebrew: {
+		Binaries: []Binary{{Name: "brew", VersionArgs: []string{"--version"}, Hint: "Install Homebrew: https://brew.sh"}},
+	},
+	CleanerDocker: {
+		Binaries: []Binary{{Name: "docker", VersionArgs: []string{"--version"}, Hint: "Install Docker: https://docs.docker.com/get-docker/"}},
+		Daemons: []Daemon{{
+			Name:  "docker",
+			Probe: []string{"docker", "info", "--format", "{{.ServerVersion}}"},
+			Hint:  "Start Docker Desktop or the docker service, and check that your user may access the docker socket",
+		}},
+	},
+	CleanerCargo: {
+		Binaries: []Binary{
+			{Name: "cargo", VersionArgs: []string{"--version"}, Hint: "Install Rust: https://rustup.rs"},
+			{Name: "cargo-cache", VersionArgs: []string{"--version"}, Optional: true, Hint: "For finer cleanup: cargo install cargo-cache"},
+		},
+		WritesPaths: true,
+	},
+	CleanerGo: {
+		Binaries:    []Binary{{Name: "go", VersionArgs: []string{"version"}, Hint: "Install Go: https://go.dev/dl/"}},
+		WritesPaths: true,
+	},
+	CleanerNode: {
+		Binaries: []Binary{
+			{Name: "npm", VersionArgs: []string{"--version"}, Hint: "Install Node.js: https://nodejs.org"},
+			{Name: "pnpm", VersionArgs: []string{"--version"}, Hint: "Install pnpm: https://pnpm.io/installation"},
+			{Name: "yarn", VersionArgs: []string{"--version"}, Hint: "Install yarn: https://yarnpkg.com/getting-started/install"},
+			{Name: "bun", VersionArgs: []string{"--version"}, Hint: "Install bun: https://bun.sh"},
+		},
+		AnyBinary: true,
+	},
+	CleanerBuildCache:  {WritesPaths: true},
+	CleanerSystemCache: {WritesPaths: true},
+	CleanerTempFiles:   {WritesPaths: true},
+	CleanerProjects: {
+		Binaries: []Binary{{Name: "projects-management-automation", Hint: pmaHint}},
+	},
+	CleanerProjectExec: {
+		Binaries: []Binary{
+			{Name: "projects-management-automation", Hint: pmaHint},
+			{Name: "trash", Hint: trashHint},
+		},
+	},
+	CleanerCompiledBinaries: {
+		Binaries: []Binary{{Name: "trash", Hint: trashHint}},
+	},
+	CleanerGolangciLint: {
+		Binaries:    []Binary{{Name: "golangci-lint", VersionArgs: []string{"--version"}, Hint: "Install golangci-lint: https://golangci-lint.run/welcome/install/"}},
+		WritesPaths: true,
+	},
+}
+
+// PrerequisitesFor returns the prerequisites of the named cleaner. A
+// cleaner's own declaration wins over the built-in table; unknown cleaners
+// have none.
+func PrerequisitesFor(name string, c Cleaner) Prerequisites {
+	if declarer, ok := c.(PrerequisiteDeclarer); ok {
+		return declarer.Prerequisites()
+	}
+
+	return defaultPrerequisites[name]
+}
+
+// Prerequisites requires trash for the trash action and write access to the
+// roots.
+func (cc *CustomCleaner) Prerequisites() Prerequisites {
+	p := Prerequisites{WritesPaths: true} //nolint:exhaustruct
+	if cc.def.Action == domain.CustomActionTrash {
+		p.Binaries = []Binary{{Name: "trash", Hint: trashHint}} //nolint:exhaustruct
+	}
+
+	return p
+}
+
+// Prerequisites requires the plugin executable.
+func (pc *PluginCleaner) Prerequisites() Prerequisites {
+	return Prerequisites{ //nolint:exhaustruct
+		Binaries: []Binary{{Name: pc.path, Hint: "Reinstall the plugin or remove it from the plugins directory"}}, //nolint:exhaustruct
+	}
+}
+
+// CheckStatus is the outcome of one diagnostic check.
+type CheckStatus string
+
+const (
+	// CheckOK: the prerequisite is met.
+	CheckOK CheckStatus = "ok"
+	// CheckWarn: the cleaner runs, but with reduced capability.
+	CheckWarn CheckStatus = "warn"
+	// CheckFail: the cleaner cannot run, or cannot remove what it finds.
+	CheckFail CheckStatus = "fail"
+	// CheckSkipped: the check does not apply on this system.
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// CheckKind is what a diagnostic check inspected.
+type CheckKind string
+
+const (
+	// CheckKindBinary: an executable on PATH.
+	CheckKindBinary CheckKind = "binary"
+	// CheckKindDaemon: a service reached through a probe command.
+	CheckKindDaemon CheckKind = "daemon"
+	// CheckKindDirectory: permissions on a directory a cleaner deletes below.
+	CheckKindDirectory CheckKind = "directory"
+	// CheckKindConfig: a configuration validation result.
+	CheckKindConfig CheckKind = "config"
+)
+
+// Check is the result of one diagnostic check.
+type Check struct {
+	Kind    CheckKind   `json:"kind"`
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Path    string      `json:"path,omitempty"`
+	Version string      `json:"version,omitempty"`
+	Detail  string      `json:"detail,omitempty"`
+	// Code identifies the problem for failed and warned checks, in the
+	// cleaner.<name>.<problem> form of cleaner error codes. Hint is the
+	// failing binary's or daemon's own hint, else the code's registered one.
+	Code string `json:"code,omitempty"`
+	Hint string `json:"hint,omitempty"`
+}
+
+// Diagnosis is the result of checking one cleaner's prerequisites.
+type Diagnosis struct {
+	Cleaner   string  `json:"cleaner"`
+	Available bool    `json:"available"`
+	Checks    []Check `json:"checks"`
+}
+
+// Doctor runs prerequisite checks. The zero value is not usable; create
+// one with NewDoctor.
+type Doctor struct {
+	lookPath func(string) (string, error)
+	run      commandRunner
+	access   func(path string) error
+}
+
+// NewDoctor creates a doctor checking the local system.
+func NewDoctor() *Doctor {
+	return &Doctor{
+		lookPath: exec.LookPath,
+		run:      runPrerequisiteCommand,
+		access:   func(path string) error { return unix.Access(path, unix.W_OK) },
+	}
+}
+
+func runPrerequisiteCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, prerequisiteCommandTimeout)
+	defer cancel()
+
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// Diagnose checks the prerequisites of the named cleaner.
+func (d *Doctor) Diagnose(ctx context.Context, name string, c Cleaner) Diagnosis {
+	prereq := PrerequisitesFor(name, c)
+	diag := Diagnosis{Cleaner: name, Available: c.IsAvailable(ctx), Checks: nil}
+
+	binaries := make([]Check, 0, len(prereq.Binaries))
+	anyFound := false
+
+	for _, b := range prereq.Binaries {
+		check := d.CheckBinary(ctx, name, b)
+		anyFound = anyFound || check.Status == CheckOK
+		binaries = append(binaries, check)
+	}
+
+	if prereq.AnyBinary && anyFound {
+		// One is enough: the missing ones are not failures.
+		for i := range binaries {
+			if binaries[i].Status == CheckFail {
+				binaries[i].Status = CheckSkipped
+				binaries[i].Code = ""
+			}
+		}
+	}
+
+	diag.Checks = append(diag.Checks, binaries...)
+
+	for _, daemon := range prereq.Daemons {
+		diag.Checks = append(diag.Checks, d.checkDaemon(ctx, name, daemon))
+	}
+
+	if prereq.WritesPaths {
+		for _, path := range AffectedPaths(name, c) {
+			diag.Checks = append(diag.Checks, d.checkDirectory(name, path))
+		}
+	}
+
+	return diag
+}
+
+// CheckBinary looks up b on PATH and probes its version.
+func (d *Doctor) CheckBinary(ctx context.Context, cleanerName string, b Binary) Check {
+	check := Check{Kind: CheckKindBinary, Name: b.Name, Status: CheckOK} //nolint:exhaustruct
+
+	path, err := d.lookPath(b.Name)
+	if err != nil {
+		check.Status = CheckFail
+		check.Code = hints.CleanerCode(cleanerName, hints.NotAvailable)
+
+		if b.Optional {
+			check.Status = CheckWarn
+			check.Code = hints.CleanerCode(cleanerName, hints.OptionalMissing)
+		}
+
+		check.Detail = "not found on PATH"
+		check.Hint = cmp.Or(b.Hint, hints.ForCode(check.Code))
+
+		return check
+	}
+
+	check.Path = path
+
+	if b.VersionArgs != nil {
+		if out, err := d.run(ctx, path, b.VersionArgs...); err == nil {
+			check.Version = firstLine(out)
+		}
+	}
+
+	return check
+}
+
+// checkDaemon runs the daemon's probe.
+func (d *Doctor) checkDaemon(ctx context.Context, cleanerName string, daemon Daemon) Check {
+	check := Check{Kind: CheckKindDaemon, Name: daemon.Name, Status: CheckOK} //nolint:exhaustruct
+
+	path, err := d.lookPath(daemon.Probe[0])
+	if err != nil {
+		check.Status = CheckSkipped
+		check.Detail = daemon.Probe[0] + " is not installed"
+
+		return check
+	}
+
+	out, err := d.run(ctx, path, daemon.Probe[1:]...)
+	if err != nil {
+		check.Status = CheckFail
+		check.Code = hints.CleanerCode(cleanerName, hints.DaemonUnreachable)
+		check.Detail = "unreachable: " + firstLine(out)
+		check.Hint = cmp.Or(daemon.Hint, hints.ForCode(check.Code))
+
+		if check.Detail == "unreachable: " {
+			check.Detail = "unreachable: " + err.Error()
+		}
+
+		return check
+	}
+
+	check.Detail = "reachable"
+	if version := firstLine(out); version != "" {
+		check.Version = version
+	}
+
+	return check
+}
+
+// checkDirectory checks that a cleaner may delete below path.
+func (d *Doctor) checkDirectory(cleanerName, path string) Check {
+	check := Check{Kind: CheckKindDirectory, Name: path, Path: path, Status: CheckOK} //nolint:exhaustruct
+
+	info, err := os.Stat(path)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		check.Status = CheckSkipped
+		check.Detail = "does not exist"
+
+		return check
+	case err != nil:
+		check.Status = CheckFail
+		check.Code = hints.CleanerCode(cleanerName, hints.PermissionDenied)
+		check.Detail = err.Error()
+		check.Hint = hints.ForCode(check.Code)
+
+		return check
+	case !info.IsDir():
+		check.Status = CheckWarn
+		check.Detail = "not a directory"
+
+		return check
+	}
+
+	if err := d.access(path); err != nil {
+		check.Status = CheckFail
+		check.Code = hints.CleanerCode(cleanerName, hints.PermissionDenied)
+		check.Detail = "not writable: " + err.Error()
+		check.Hint = hints.ForCode(check.Code)
+
+		return check
+	}
+
+	check.Detail = "writable"
+
+	return check
+}
+
+// firstLine returns the first non-empty line of out, trimmed.
+func firstLine(out []byte) string {
+	for line := range bytes.SplitSeq(out, []byte("\n")) {
+		if s := strings.TrimSpace(string(line)); s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,149 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
+)
+
+func fakeDoctor(installed []string, failing map[string]string, access error) *Doctor {
+	return &Doctor{
+		lookPath: func(name string) (string, error) {
+			if slices.Contains(installed, name) {
+				return "/usr/bin/" + name, nil
+			}
+
+			return "", exec.ErrNotFound
+		},
+		run: func(_ context.Context, name string, _ ...string) ([]byte, error) {
+			if out, ok := failing[name]; ok {
+				return []byte(out), errors.New("exit status 1")
+			}
+
+			return []byte(name + " 1.2.3\nmore output\n"), nil
+		},
+		access: func(string) error { return access },
+	}
+}
+
+func findCheck(t *testing.T, diag Diagnosis, kind CheckKind, name string) Check {
+	t.Helper()
+
+	for _, check := range diag.Checks {
+		if check.Kind == kind && check.Name == name {
+			return check
+		}
+	}
+
+	t.Fatalf("%s diagnosis has no %s check: %+v", diag.Cleaner, name, diag.Checks)
+
+	return Check{} //nolint:exhaustruct
+}
+
+func TestDoctor_DiagnoseBinariesAndDaemons(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	d := fakeDoctor([]string{"npm", "docker"}, map[string]string{"/usr/bin/docker": "Cannot connect to the Docker daemon"}, nil)
+
+	node := d.Diagnose(ctx, CleanerNode, &mockCleaner{name: CleanerNode, available: true}) //nolint:exhaustruct
+	if npm := findCheck(t, node, CheckKindBinary, "npm"); npm.Status != CheckOK || npm.Version != "/usr/bin/npm 1.2.3" {
+		t.Errorf("npm check = %+v, want ok with the first version line", npm)
+	}
+
+	if bun := findCheck(t, node, CheckKindBinary, "bun"); bun.Status != CheckSkipped || bun.Code != "" {
+		t.Errorf("bun check = %+v, want skipped because npm is enough", bun)
+	}
+
+	docker := d.Diagnose(ctx, CleanerDocker, &mockCleaner{name: CleanerDocker}) //nolint:exhaustruct
+	if daemon := findCheck(t, docker, CheckKindDaemon, "docker"); daemon.Status != CheckFail || daemon.Code != "cleaner.docker.daemon_unreachable" || daemon.Hint == "" {
+		t.Errorf("docker daemon check = %+v, want failed with code and hint", daemon)
+	}
+
+	cargo := d.Diagnose(ctx, CleanerCargo, &mockCleaner{name: CleanerCargo}) //nolint:exhaustruct
+	if c := findCheck(t, cargo, CheckKindBinary, "cargo"); c.Status != CheckFail || c.Code != "cleaner.cargo.not_available" {
+		t.Errorf("cargo check = %+v, want failed as not available", c)
+	}
+
+	if c := findCheck(t, cargo, CheckKindBinary, "cargo-cache"); c.Status != CheckWarn {
+		t.Errorf("cargo-cache check = %+v, want a warning for the optional binary", c)
+	}
+}
+
+func TestDoctor_DiagnoseCustomCleanerPermissions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	def := domain.CustomCleanerConfig{Name: "my-cache", Roots: []string{root}, Action: domain.CustomActionTrash} //nolint:exhaustruct
+
+	c, err := NewCustomCleaner(false, true, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	diag := fakeDoctor(nil, nil, errors.New("permission denied")).Diagnose(context.Background(), "my-cache", c)
+
+	if trash := findCheck(t, diag, CheckKindBinary, "trash"); trash.Status != CheckFail {
+		t.Errorf("trash check = %+v, want failed for the trash action", trash)
+	}
+
+	if dir := findCheck(t, diag, CheckKindDirectory, root); dir.Status != CheckFail || dir.Code != "cleaner.my-cache.permission_denied" {
+		t.Errorf("root check = %+v, want failed as not writable", dir)
+	}
+}
+
+func TestRegistry_RegisterPopulatesHints(t *testing.T) {
+	t.Parallel()
+
+	def := domain.CustomCleanerConfig{Name: "hinted-cache", Roots: []string{t.TempDir()}, Action: domain.CustomActionTrash} //nolint:exhaustruct
+
+	c, err := NewCustomCleaner(false, true, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	NewRegistry().Register(def.Name, c)
+
+	if got := hints.ForCode("cleaner.hinted-cache.not_available"); got != trashHint {
+		t.Errorf("hint for the custom cleaner = %q, want the trash install hint", got)
+	}
+
+	if got := hints.ForCode("cleaner.docker.daemon_unreachable"); got == "" || got == hints.ForCode("cleaner.daemon_unreachable") {
+		t.Errorf("docker daemon hint = %q, want the built-in cleaner's own hint", got)
+	}
+}
+
+func TestRegistry_NotAvailableCodesResolveToHints(t *testing.T) {
+	t.Parallel()
+
+	registry, err := DefaultRegistryWithConfig(false, true)
+	if err != nil {
+		t.Fatalf("DefaultRegistryWithConfig() error = %v", err)
+	}
+
+	generic := hints.ForCode("cleaner." + hints.NotAvailable)
+
+	for _, name := range registry.Names() {
+		c, _ := registry.Get(name)
+		code := NewNotAvailableError(name, "").Code
+
+		hint := hints.ForCode(code)
+		if hint == "" {
+			t.Errorf("%s: %s resolves to no hint", name, code)
+
+			continue
+		}
+
+		declaresHint := slices.ContainsFunc(PrerequisitesFor(name, c).Binaries, func(b Binary) bool {
+			return !b.Optional && b.Hint != ""
+		})
+		if declaresHint && hint == generic {
+			t.Errorf("%s: %s resolves to the generic hint, want the cleaner's own", name, code)
+		}
+	}
+}
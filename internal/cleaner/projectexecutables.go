@@ -7,10 +7,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
 	fileutil "github.com/LarsArtmann/clean-wizard/internal/shared/utils/fileutil"
 )
@@ -149,6 +151,7 @@ func (p *ProjectExecutablesCleaner) Scan(ctx context.Context) result.Result[[]do
 	}
 
 	items := make([]domain.ScanItem, 0)
+	ignores := ignore.FromContext(ctx)
 
 	for _, project := range projects {
 		executables, err := p.fileOperator.FindExecutableFiles(project.Path)
@@ -160,12 +163,17 @@ func (p *ProjectExecutablesCleaner) Scan(ctx context.Context) result.Result[[]do
 			continue
 		}
 
+		executables = slices.DeleteFunc(executables, func(path string) bool { return ignores.Exclude(path, false) })
+
 		for _, execPath := range executables {
-			items = append(items, domain.ScanItem{
+			items = append(items, domain.ScanItem{ //nolint:exhaustruct
 				Path:     execPath,
 				Size:     p.fileOperator.GetFileSize(execPath),
 				Created:  time.Now(),
 				ScanType: domain.ScanTypeSystem,
+				Reasons: []domain.SelectionReason{
+					ruleReason("executable file at the top of project "+project.Name, project.Path),
+				},
 			})
 		}
 
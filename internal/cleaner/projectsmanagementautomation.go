@@ -87,6 +87,7 @@ func (pc *ProjectsManagementAutomationCleaner) Scan(
 		Size:     pc.estimateCacheSize(),
 		Created:  time.Now(),
 		ScanType: domain.ScanTypeSystem,
+		Reasons:  []domain.SelectionReason{locationReason(domain.ScanTypeCache)},
 	})
 
 	if pc.verbose {
@@ -102,7 +103,7 @@ func (pc *ProjectsManagementAutomationCleaner) Clean(
 ) result.Result[domain.CleanResult] {
 	if !pc.IsAvailable(ctx) {
 		return result.Err[domain.CleanResult](
-			NewNotAvailableError("projects-management-automation", ""),
+			NewNotAvailableError(CleanerProjects, ""),
 		)
 	}
 
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
+	errorfamily "github.com/larsartmann/go-error-family"
 )
 
 func TestNewProjectsManagementAutomationCleaner(t *testing.T) {
@@ -115,13 +117,27 @@ func TestProjectsManagementAutomationCleaner_Scan_NotAvailable(t *testing.T) {
 
 func TestProjectsManagementAutomationCleaner_Clean_NoAvailable(t *testing.T) {
 	t.Parallel()
-	// This test would fail if projects-management-automation is installed
-	// We just verify error handling logic exists
+
 	cleaner := NewTestCleaner(NewProjectsManagementAutomationCleaner)()
 
-	// Can't easily test "tool not available" case without mocking
-	// So we just verify IsAvailable is called
-	_ = cleaner.IsAvailable(context.Background())
+	// The not-available error can only be observed without the tool.
+	if cleaner.IsAvailable(context.Background()) {
+		t.Skip("projects-management-automation is installed")
+	}
+
+	result := cleaner.Clean(context.Background())
+	if !result.IsErr() {
+		t.Fatal("Clean() should fail without projects-management-automation")
+	}
+
+	code := errorfamily.Code(result.Error())
+	if code != hints.CleanerCode(CleanerProjects, hints.NotAvailable) {
+		t.Errorf("Clean() error code = %q, want the %s cleaner's not_available code", code, CleanerProjects)
+	}
+
+	if got := hints.ForCode(code); got != pmaHint {
+		t.Errorf("hint for %s = %q, want the install hint", code, got)
+	}
 }
 
 func TestProjectsManagementAutomationCleaner_StandardTests(t *testing.T) {
@@ -0,0 +1,79 @@
+package cleaner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/format"
+)
+
+// locationReason explains an item selected because the cleaner manages its
+// location as a whole.
+func locationReason(scanType domain.ScanType) domain.SelectionReason {
+	return domain.SelectionReason{ //nolint:exhaustruct
+		Kind:    domain.ReasonLocation,
+		Message: "known " + scanType.String() + " location managed by this cleaner",
+	}
+}
+
+// ruleReason explains an item selected by a configured pattern or rule.
+func ruleReason(message, rule string) domain.SelectionReason {
+	return domain.SelectionReason{Kind: domain.ReasonRule, Message: message, Rule: rule} //nolint:exhaustruct
+}
+
+// ageReason explains an item selected because it is older than threshold.
+func ageReason(modTime time.Time, threshold time.Duration) domain.SelectionReason {
+	return domain.SelectionReason{ //nolint:exhaustruct
+		Kind:      domain.ReasonAge,
+		Message:   "not modified recently",
+		Actual:    formatAge(time.Since(modTime)) + " old",
+		Threshold: "older than " + formatAge(threshold),
+	}
+}
+
+// sizeReason explains an item selected because it is at least minSize.
+func sizeReason(size, minSize int64) domain.SelectionReason {
+	return domain.SelectionReason{ //nolint:exhaustruct
+		Kind:      domain.ReasonSize,
+		Message:   "large enough to be worth removing",
+		Actual:    format.Bytes(size),
+		Threshold: "at least " + format.Bytes(minSize),
+	}
+}
+
+// categoryReason explains an item selected because its category is included.
+func categoryReason(category, message string) domain.SelectionReason {
+	return domain.SelectionReason{Kind: domain.ReasonCategory, Message: message, Actual: category} //nolint:exhaustruct
+}
+
+// retentionReason explains a retention policy decision.
+func retentionReason(message, actual, threshold string) domain.SelectionReason {
+	return domain.SelectionReason{ //nolint:exhaustruct
+		Kind:      domain.ReasonRetention,
+		Message:   message,
+		Actual:    actual,
+		Threshold: threshold,
+	}
+}
+
+// riskReason records the declared risk of removing an item.
+func riskReason(level domain.RiskLevelType) domain.SelectionReason {
+	return domain.SelectionReason{ //nolint:exhaustruct
+		Kind:    domain.ReasonRisk,
+		Message: "declared risk level",
+		Actual:  level.String(),
+	}
+}
+
+// formatAge formats an age in the units used by older_than settings.
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 2*domain.HoursPerDay*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/domain.HoursPerDay))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
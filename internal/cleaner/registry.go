@@ -24,6 +24,8 @@ const (
 	CleanerProjectExec      = "project-executables"
 	CleanerCompiledBinaries = "compiled-binaries"
 	CleanerGolangciLint     = "golangci-lint-cache"
+	CleanerNixGCRoots       = "nix-gcroots"
+	CleanerNixOptimise      = "nix-optimise"
 )
 
 // Registry manages all registered cleaners.
@@ -41,9 +43,12 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register adds a cleaner to the registry.
+// Register adds a cleaner to the registry and registers the remediation
+// hints of its prerequisites.
 // If a cleaner with the same name already exists, it will be overwritten.
 func (r *Registry) Register(name string, c Cleaner) {
+	PrerequisitesFor(name, c).registerHints(name)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -29,6 +29,12 @@ func registerAllCleaners(registry *Registry, verbose, dryRun bool) error {
 	// Nix cleaner
 	registry.Register(CleanerNix, NewNixCleaner(verbose, dryRun))
 
+	// Nix GC root cleaner (default: projects untouched for 30 days)
+	registry.Register(CleanerNixGCRoots, NewNixGCRootsCleaner(verbose, dryRun, NixGCRootsDefaultOlderThan))
+
+	// Nix store optimisation (default: at most weekly)
+	registry.Register(CleanerNixOptimise, NewNixOptimiseCleaner(verbose, dryRun, NixOptimiseDefaultEvery))
+
 	// Homebrew cleaner (default mode: all)
 	registry.Register(CleanerHomebrew, NewHomebrewCleaner(verbose, dryRun, domain.HomebrewModeAll))
 
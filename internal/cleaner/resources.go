@@ -0,0 +1,117 @@
+package cleaner
+
+import (
+	"context"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// Resources declares how the workflow scheduler treats a cleaner: the
+// resource class it competes for and how long a single run may take.
+type Resources struct {
+	// Class limits how many cleaners of the same class run concurrently.
+	Class domain.ResourceClass
+	// Timeout bounds one Clean or Scan call; 0 means no timeout.
+	Timeout time.Duration
+}
+
+// ResourceOverride replaces parts of a cleaner's Resources, e.g. from a
+// profile operation. Nil or zero fields keep the cleaner's value.
+type ResourceOverride struct {
+	Class   *domain.ResourceClass
+	Timeout time.Duration
+}
+
+// IsEmpty reports whether the override changes nothing.
+func (o ResourceOverride) IsEmpty() bool {
+	return o.Class == nil && o.Timeout == 0
+}
+
+// Apply returns r with the override's non-empty fields replacing its own.
+func (r Resources) Apply(o ResourceOverride) Resources {
+	if o.Class != nil {
+		r.Class = *o.Class
+	}
+
+	if o.Timeout > 0 {
+		r.Timeout = o.Timeout
+	}
+
+	return r
+}
+
+// ResourceDeclarer is implemented by cleaners that declare their own
+// scheduling resources (e.g. custom YAML cleaners).
+type ResourceDeclarer interface {
+	Resources() Resources
+}
+
+// defaultResources holds the scheduling class and timeout of the built-in
+// cleaners. Timeouts bound a whole cleaner run and are therefore larger than
+// the per-command timeouts the cleaners use internally.
+var defaultResources = map[string]Resources{ //nolint:gochecknoglobals
+	CleanerNix:              {Class: domain.ResourceClassNetwork, Timeout: 30 * time.Minute},
+	CleanerHomebrew:         {Class: domain.ResourceClassNetwork, Timeout: 20 * time.Minute},
+	CleanerDocker:           {Class: domain.ResourceClassNetwork, Timeout: 15 * time.Minute},
+	CleanerCargo:            {Class: domain.ResourceClassDiskIO, Timeout: 15 * time.Minute},
+	CleanerGo:               {Class: domain.ResourceClassDiskIO, Timeout: 10 * time.Minute},
+	CleanerNode:             {Class: domain.ResourceClassDiskIO, Timeout: 10 * time.Minute},
+	CleanerBuildCache:       {Class: domain.ResourceClassDiskIO, Timeout: 10 * time.Minute},
+	CleanerSystemCache:      {Class: domain.ResourceClassDiskIO, Timeout: 10 * time.Minute},
+	CleanerTempFiles:        {Class: domain.ResourceClassDiskIO, Timeout: 10 * time.Minute},
+	CleanerProjects:         {Class: domain.ResourceClassCPU, Timeout: 5 * time.Minute},
+	CleanerProjectExec:      {Class: domain.ResourceClassDiskIO, Timeout: 15 * time.Minute},
+	CleanerCompiledBinaries: {Class: domain.ResourceClassDiskIO, Timeout: 15 * time.Minute},
+	CleanerGolangciLint:     {Class: domain.ResourceClassDiskIO, Timeout: 5 * time.Minute},
+	CleanerNixGCRoots:       {Class: domain.ResourceClassNetwork, Timeout: 15 * time.Minute},
+	CleanerNixOptimise:      {Class: domain.ResourceClassDiskIO, Timeout: 2 * time.Hour},
+}
+
+// ResourcesFor returns the scheduling resources of the named cleaner. A
+// cleaner's own declaration wins over the built-in table; unknown cleaners
+// are treated as DISK_IO without a timeout.
+func ResourcesFor(name string, c Cleaner) Resources {
+	if declarer, ok := c.(ResourceDeclarer); ok {
+		return declarer.Resources()
+	}
+
+	if res, ok := defaultResources[name]; ok {
+		return res
+	}
+
+	return Resources{Class: domain.ResourceClassDiskIO, Timeout: 0}
+}
+
+// commandTimeout returns how long one external command of a cleaner may run
+// in ctx: fallback, cut short by the step deadline, which the workflow
+// derives from the cleaner's resources and a profile timeout override. A
+// single hung command thus cannot use up the whole step.
+func commandTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return min(time.Until(deadline), fallback)
+	}
+
+	return fallback
+}
+
+// ParseResourceOverride converts the resource_class and timeout strings of a
+// profile operation or custom cleaner into an override. Both are validated
+// when the config is loaded, so parse errors leave the field unset.
+func ParseResourceOverride(resourceClass, timeout string) ResourceOverride {
+	var o ResourceOverride
+
+	if resourceClass != "" {
+		if class, err := domain.ParseResourceClass(resourceClass); err == nil {
+			o.Class = &class
+		}
+	}
+
+	if timeout != "" {
+		if d, err := domain.ParseCustomDuration(timeout); err == nil {
+			o.Timeout = d
+		}
+	}
+
+	return o
+}
@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+func TestResourcesFor_BuiltinDeclaredAndOverride(t *testing.T) {
+	t.Parallel()
+
+	if res := ResourcesFor(CleanerDocker, nil); res.Class != domain.ResourceClassNetwork || res.Timeout == 0 {
+		t.Errorf("docker resources = %+v, want NETWORK with a timeout", res)
+	}
+
+	if res := ResourcesFor("unknown", nil); res.Class != domain.ResourceClassDiskIO || res.Timeout != 0 {
+		t.Errorf("unknown cleaner resources = %+v, want DISK_IO without timeout", res)
+	}
+
+	def := domain.CustomCleanerConfig{
+		Name:          "my-cache",
+		Roots:         []string{t.TempDir()},
+		ResourceClass: "cpu",
+		Timeout:       "90s",
+	}
+
+	c, err := NewCustomCleaner(false, true, def, nil)
+	if err != nil {
+		t.Fatalf("NewCustomCleaner() error = %v", err)
+	}
+
+	res := ResourcesFor(def.Name, c)
+	if res.Class != domain.ResourceClassCPU || res.Timeout != 90*time.Second {
+		t.Errorf("custom cleaner resources = %+v, want CPU/90s", res)
+	}
+
+	res = res.Apply(ParseResourceOverride("network", ""))
+	if res.Class != domain.ResourceClassNetwork || res.Timeout != 90*time.Second {
+		t.Errorf("override applied = %+v, want NETWORK/90s", res)
+	}
+}
+
+func TestCommandTimeout_FollowsStepDeadline(t *testing.T) {
+	t.Parallel()
+
+	if got := commandTimeout(context.Background(), TrashPathTimeout); got != TrashPathTimeout {
+		t.Errorf("commandTimeout() without deadline = %v, want %v", got, TrashPathTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	if got := commandTimeout(ctx, TrashPathTimeout); got != TrashPathTimeout {
+		t.Errorf("commandTimeout() with a 1h step deadline = %v, want %v", got, TrashPathTimeout)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShort()
+
+	if got := commandTimeout(short, TrashPathTimeout); got <= 0 || got > time.Second {
+		t.Errorf("commandTimeout() with a 1s step deadline = %v, want up to 1s", got)
+	}
+}
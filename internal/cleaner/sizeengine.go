@@ -0,0 +1,250 @@
+package cleaner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
+)
+
+// statBlockSize is the unit of syscall.Stat_t.Blocks on every supported Unix.
+const statBlockSize = 512
+
+// DiskSize is the size of a file tree as the user sees it (Apparent, the sum
+// of file lengths) and as the disk sees it (Allocated, the blocks actually in
+// use). Sparse files make Allocated smaller; hardlinks are counted once.
+type DiskSize struct {
+	Apparent  int64
+	Allocated int64
+}
+
+// inodeKey identifies a file independently of the path it is reached by.
+type inodeKey struct {
+	dev uint64
+	ino uint64
+}
+
+// inodeState tracks one hardlinked inode seen during a run.
+type inodeState struct {
+	nlink        uint64
+	allocated    int64
+	removedLinks uint64
+	freed        bool
+}
+
+// measuredRoot records what a measured path contains, so that its removal
+// can later be translated into freed bytes.
+type measuredRoot struct {
+	size    DiskSize
+	modTime time.Time
+	// single is the size of the files with a single link; removing the root
+	// frees all of it.
+	single DiskSize
+	// links counts, per hardlinked inode, the links to it below the root.
+	links map[inodeKey]uint64
+}
+
+// SizeEngine measures file trees block- and hardlink-aware. One engine is
+// shared by all cleaners of a workflow run (see WithSizeEngine), so a file
+// hardlinked into several cleaners' trees (Nix store, pnpm store, Go module
+// cache) is counted once for the whole run, by the first tree measured.
+// Trees are read through the shared fswalk walker and its size cache.
+//
+// Freed reports the bytes a removal really released: the allocated size of
+// the inodes whose last link is gone.
+type SizeEngine struct {
+	mu     sync.Mutex
+	inodes map[inodeKey]*inodeState
+	roots  map[string]*measuredRoot
+}
+
+// NewSizeEngine creates an empty engine.
+func NewSizeEngine() *SizeEngine {
+	return &SizeEngine{
+		inodes: make(map[inodeKey]*inodeState),
+		roots:  make(map[string]*measuredRoot),
+	}
+}
+
+type sizeEngineKey struct{}
+
+// WithSizeEngine returns a context carrying e for the cleaners of one run.
+func WithSizeEngine(ctx context.Context, e *SizeEngine) context.Context {
+	return context.WithValue(ctx, sizeEngineKey{}, e)
+}
+
+// SizeEngineFromContext returns the run's engine, or a fresh engine when ctx
+// carries none (hardlinks are then only deduplicated within one call).
+func SizeEngineFromContext(ctx context.Context) *SizeEngine {
+	if e, ok := ctx.Value(sizeEngineKey{}).(*SizeEngine); ok {
+		return e
+	}
+
+	return NewSizeEngine()
+}
+
+// Measure walks path (a file or directory tree) without following symlinks
+// and returns its size and newest modification time. Hardlinked inodes
+// already counted by an earlier measurement of this engine contribute
+// nothing. Measuring the same path again returns the first result. ok is
+// false if path cannot be read or ctx is cancelled.
+func (e *SizeEngine) Measure(ctx context.Context, path string) (size DiskSize, modTime time.Time, ok bool) {
+	path = filepath.Clean(path)
+
+	e.mu.Lock()
+	root, seen := e.roots[path]
+	e.mu.Unlock()
+
+	if seen {
+		return root.size, root.modTime, true
+	}
+
+	summary, err := fswalk.Shared().Summarize(ctx, path)
+	if err != nil {
+		return DiskSize{}, time.Time{}, false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if root, seen := e.roots[path]; seen {
+		return root.size, root.modTime, true
+	}
+
+	root = e.addSummaryLocked(summary)
+	e.roots[path] = root
+
+	return root.size, root.modTime, true
+}
+
+// MeasureFile records a single file already stat'ed by the caller's own walk
+// and returns its contribution to the run's totals.
+func (e *SizeEngine) MeasureFile(path string, info fs.FileInfo) DiskSize {
+	entry := fswalk.Entry{Path: path, Size: info.Size(), Allocated: info.Size(), ModTime: info.ModTime(), Nlink: 1} //nolint:exhaustruct
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		entry.Allocated = int64(st.Blocks) * statBlockSize //nolint:unconvert
+		entry.Dev = uint64(st.Dev)                         //nolint:unconvert // Dev is int32 on darwin
+		entry.Ino = st.Ino
+		entry.Nlink = uint64(st.Nlink) //nolint:unconvert // Nlink is uint16 on darwin
+	}
+
+	return e.MeasureEntry(entry)
+}
+
+// MeasureEntry is MeasureFile for an entry of an fswalk walk.
+func (e *SizeEngine) MeasureEntry(entry fswalk.Entry) DiskSize {
+	path := filepath.Clean(entry.Path)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if root, seen := e.roots[path]; seen {
+		return root.size
+	}
+
+	summary := fswalk.Summary{Files: 1, Apparent: entry.Size, Allocated: entry.Allocated, ModTime: entry.ModTime} //nolint:exhaustruct
+	if entry.Nlink > 1 {
+		summary.Links = []fswalk.Entry{entry}
+	}
+
+	root := e.addSummaryLocked(summary)
+	e.roots[path] = root
+
+	return root.size
+}
+
+// Freed marks every link below a measured path as removed and returns the
+// allocated bytes of the inodes whose last link this removed. ok is false if
+// path was never measured by this engine.
+func (e *SizeEngine) Freed(path string) (freed int64, ok bool) {
+	path = filepath.Clean(path)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	root, seen := e.roots[path]
+	if !seen {
+		return 0, false
+	}
+
+	freed = root.single.Allocated
+
+	for key, links := range root.links {
+		state := e.inodes[key]
+
+		state.removedLinks += links
+		if !state.freed && state.removedLinks >= state.nlink {
+			state.freed = true
+			freed += state.allocated
+		}
+	}
+
+	// A second removal of the same path frees nothing more.
+	root.single = DiskSize{}
+	root.links = map[inodeKey]uint64{}
+
+	return freed, true
+}
+
+// addSummaryLocked turns a walked summary into a measured root, counting
+// hardlinked inodes only the first time the engine sees them.
+func (e *SizeEngine) addSummaryLocked(summary fswalk.Summary) *measuredRoot {
+	root := &measuredRoot{
+		size:    DiskSize{Apparent: summary.Apparent, Allocated: summary.Allocated},
+		modTime: summary.ModTime,
+		single:  DiskSize{Apparent: summary.Apparent, Allocated: summary.Allocated},
+		links:   make(map[inodeKey]uint64),
+	}
+
+	for _, link := range summary.Links {
+		root.single.Apparent -= link.Size
+		root.single.Allocated -= link.Allocated
+		root.size.Apparent -= link.Size
+		root.size.Allocated -= link.Allocated
+
+		key := inodeKey{dev: link.Dev, ino: link.Ino}
+		root.links[key]++
+
+		if _, counted := e.inodes[key]; counted {
+			continue
+		}
+
+		e.inodes[key] = &inodeState{nlink: max(link.Nlink, 1), allocated: link.Allocated} //nolint:exhaustruct
+
+		root.size.Apparent += link.Size
+		root.size.Allocated += link.Allocated
+	}
+
+	return root
+}
+
+// MeasureDirScanItem measures a directory with the run's engine and returns
+// it as a single scan item.
+func MeasureDirScanItem(ctx context.Context, path string, scanType domain.ScanType) domain.ScanItem {
+	size, modTime, _ := SizeEngineFromContext(ctx).Measure(ctx, path)
+
+	return domain.ScanItem{
+		Path:       path,
+		Size:       size.Apparent,
+		OnDiskSize: size.Allocated,
+		Created:    modTime,
+		ScanType:   scanType,
+		Reasons:    []domain.SelectionReason{locationReason(scanType)},
+	}
+}
+
+// freedBytes returns the bytes removing a scanned item released: the
+// engine's hardlink-aware figure when the item was measured by it, otherwise
+// the item's on-disk size, otherwise its apparent size.
+func freedBytes(e *SizeEngine, item domain.ScanItem) int64 {
+	if freed, ok := e.Freed(item.Path); ok {
+		return freed
+	}
+
+	return item.DiskUsage()
+}
@@ -0,0 +1,99 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSizedFile(t *testing.T, path string, size int) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestSizeEngine_HardlinkCountedOnceAcrossRoots(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	first := filepath.Join(base, "first")
+	second := filepath.Join(base, "second")
+
+	writeSizedFile(t, filepath.Join(first, "shared"), 64*1024)
+	writeSizedFile(t, filepath.Join(second, "own"), 1024)
+
+	if err := os.Link(filepath.Join(first, "shared"), filepath.Join(second, "shared")); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+
+	engine := NewSizeEngine()
+
+	firstSize, _, ok := engine.Measure(t.Context(), first)
+	if !ok || firstSize.Apparent != 64*1024 {
+		t.Fatalf("Measure(first) = %+v, %v, want 64 KiB", firstSize, ok)
+	}
+
+	secondSize, _, _ := engine.Measure(t.Context(), second)
+	if secondSize.Apparent != 1024 {
+		t.Errorf("Measure(second).Apparent = %d, want 1024 (shared file counted by first)", secondSize.Apparent)
+	}
+
+	// Removing one link frees nothing of the shared file.
+	if freed, _ := engine.Freed(first); freed != 0 {
+		t.Errorf("Freed(first) = %d, want 0 while second still links the file", freed)
+	}
+
+	freed, ok := engine.Freed(second)
+	if !ok || freed < firstSize.Allocated {
+		t.Errorf("Freed(second) = %d, want at least the shared file's %d bytes", freed, firstSize.Allocated)
+	}
+
+	if again, _ := engine.Freed(second); again != 0 {
+		t.Errorf("second Freed(second) = %d, want 0", again)
+	}
+}
+
+func TestSizeEngine_SparseFileAllocatedBelowApparent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	f, err := os.Create(filepath.Join(dir, "sparse"))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := f.Truncate(64 * 1024 * 1024); err != nil {
+		t.Fatalf("Truncate() error = %v", err)
+	}
+
+	_ = f.Close()
+
+	size, _, ok := NewSizeEngine().Measure(t.Context(), dir)
+	if !ok || size.Apparent != 64*1024*1024 {
+		t.Fatalf("Measure() = %+v, %v, want 64 MiB apparent", size, ok)
+	}
+
+	if size.Allocated >= size.Apparent {
+		t.Errorf("Allocated = %d, want less than apparent %d for a sparse file", size.Allocated, size.Apparent)
+	}
+
+	if got := GetDirDiskUsage(dir); got != size.Allocated {
+		t.Errorf("GetDirDiskUsage() = %d, want %d", got, size.Allocated)
+	}
+}
+
+func TestFreedBytes_FallsBackForUnmeasuredItems(t *testing.T) {
+	t.Parallel()
+
+	item := MeasureDirScanItem(t.Context(), t.TempDir(), "temp_files")
+	if freed := freedBytes(NewSizeEngine(), item); freed != item.DiskUsage() {
+		t.Errorf("freedBytes() = %d, want DiskUsage() %d", freed, item.DiskUsage())
+	}
+}
@@ -0,0 +1,428 @@
+package cleaner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// Copy-on-write filesystems whose snapshots keep deleted data alive.
+const (
+	FilesystemBtrfs = "btrfs"
+	FilesystemZFS   = "zfs"
+)
+
+// snapshotCommandTimeout bounds each btrfs/zfs metadata query.
+const snapshotCommandTimeout = 10 * time.Second
+
+// FilesystemInfo describes the mounted filesystem holding a path.
+type FilesystemInfo struct {
+	Type       string
+	MountPoint string
+	// Source is the mounted device, or the dataset name for ZFS.
+	Source string
+}
+
+// IsSnapshotting reports whether the filesystem supports snapshots that pin
+// deleted data.
+func (fi FilesystemInfo) IsSnapshotting() bool {
+	return fi.Type == FilesystemBtrfs || fi.Type == FilesystemZFS
+}
+
+// SnapshotInfo summarizes the snapshots of one filesystem.
+type SnapshotInfo struct {
+	Count  int
+	Latest time.Time
+	// UsedBySnapshots is the space only snapshots still reference (ZFS
+	// usedbysnapshots); -1 if the filesystem does not report it.
+	UsedBySnapshots int64
+}
+
+// SnapshotWarning reports scanned or cleaned data on a snapshotting
+// filesystem with existing snapshots: deleting it frees little or nothing
+// until the snapshots are gone.
+type SnapshotWarning struct {
+	Filesystem FilesystemInfo
+	Snapshots  SnapshotInfo
+	// Items and ReportedBytes count the items on this filesystem.
+	Items         int
+	ReportedBytes int64
+	// PinnedBytes estimates how much of ReportedBytes stays referenced by
+	// snapshots: items last modified before the latest snapshot are fully
+	// contained in it. Items of unknown age count as pinned.
+	PinnedBytes int64
+}
+
+// commandRunner runs a metadata command and returns its stdout.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, snapshotCommandTimeout)
+	defer cancel()
+
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// SnapshotInspector detects the filesystem of paths and the snapshots that
+// pin data on Btrfs and ZFS, using only the local mount table and the
+// filesystems' own tools. Results are cached per mount point.
+type SnapshotInspector struct {
+	mounts  func() ([]FilesystemInfo, error)
+	run     commandRunner
+	readDir func(string) ([]os.DirEntry, error)
+	mu      sync.Mutex
+	table   []FilesystemInfo
+	loaded  bool
+	cache   map[string]SnapshotInfo
+}
+
+// NewSnapshotInspector creates an inspector reading the system mount table.
+func NewSnapshotInspector() *SnapshotInspector {
+	return &SnapshotInspector{
+		mounts:  readMountTable,
+		run:     runCommand,
+		readDir: os.ReadDir,
+		cache:   make(map[string]SnapshotInfo),
+	}
+}
+
+// Filesystem returns the filesystem holding path: the mount with the
+// longest mount point that is a prefix of path.
+func (s *SnapshotInspector) Filesystem(path string) (FilesystemInfo, bool) {
+	s.mu.Lock()
+	if !s.loaded {
+		s.table, _ = s.mounts()
+		s.loaded = true
+	}
+	table := s.table
+	s.mu.Unlock()
+
+	path = filepath.Clean(path)
+
+	var (
+		best  FilesystemInfo
+		found bool
+	)
+
+	for _, fs := range table {
+		if fs.MountPoint != "/" && !isUnderAny(path, []string{fs.MountPoint}) {
+			continue
+		}
+
+		if !found || len(fs.MountPoint) > len(best.MountPoint) {
+			best, found = fs, true
+		}
+	}
+
+	return best, found
+}
+
+// Snapshots lists the snapshots of a snapshotting filesystem. A filesystem
+// whose snapshots cannot be listed (missing tool, no permission) reports none.
+func (s *SnapshotInspector) Snapshots(ctx context.Context, fs FilesystemInfo) SnapshotInfo {
+	s.mu.Lock()
+	info, ok := s.cache[fs.MountPoint]
+	s.mu.Unlock()
+
+	if ok {
+		return info
+	}
+
+	switch fs.Type {
+	case FilesystemZFS:
+		info = s.zfsSnapshots(ctx, fs)
+	case FilesystemBtrfs:
+		info = s.btrfsSnapshots(ctx, fs)
+	default:
+		info = SnapshotInfo{Count: 0, Latest: time.Time{}, UsedBySnapshots: -1}
+	}
+
+	s.mu.Lock()
+	s.cache[fs.MountPoint] = info
+	s.mu.Unlock()
+
+	return info
+}
+
+// Inspect groups items by filesystem and returns a warning for every
+// snapshotting filesystem with snapshots, ordered by mount point.
+func (s *SnapshotInspector) Inspect(ctx context.Context, items []domain.ScanItem) []SnapshotWarning {
+	byMount := make(map[string]*SnapshotWarning)
+
+	for _, item := range items {
+		fs, ok := s.Filesystem(item.Path)
+		if !ok || !fs.IsSnapshotting() {
+			continue
+		}
+
+		warning, seen := byMount[fs.MountPoint]
+		if !seen {
+			snapshots := s.Snapshots(ctx, fs)
+			if snapshots.Count == 0 {
+				continue
+			}
+
+			warning = &SnapshotWarning{Filesystem: fs, Snapshots: snapshots} //nolint:exhaustruct
+			byMount[fs.MountPoint] = warning
+		}
+
+		size := item.DiskUsage()
+		warning.Items++
+		warning.ReportedBytes += size
+
+		if item.Created.IsZero() || item.Created.Before(warning.Snapshots.Latest) {
+			warning.PinnedBytes += size
+		}
+	}
+
+	warnings := make([]SnapshotWarning, 0, len(byMount))
+	for _, w := range byMount {
+		warnings = append(warnings, *w)
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Filesystem.MountPoint < warnings[j].Filesystem.MountPoint
+	})
+
+	return warnings
+}
+
+// zfsSnapshots lists the snapshots of the dataset mounted at fs and the
+// space only they reference.
+func (s *SnapshotInspector) zfsSnapshots(ctx context.Context, fs FilesystemInfo) SnapshotInfo {
+	info := SnapshotInfo{Count: 0, Latest: time.Time{}, UsedBySnapshots: -1}
+
+	out, err := s.run(ctx, "zfs", "list", "-H", "-p", "-t", "snapshot", "-o", "name,creation", "-d", "1", fs.Source)
+	if err != nil {
+		return info
+	}
+
+	for line := range strings.Lines(string(out)) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		created, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info.Count++
+
+		if t := time.Unix(created, 0); t.After(info.Latest) {
+			info.Latest = t
+		}
+	}
+
+	out, err = s.run(ctx, "zfs", "get", "-H", "-p", "-o", "value", "usedbysnapshots", fs.Source)
+	if err == nil {
+		if used, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err == nil {
+			info.UsedBySnapshots = used
+		}
+	}
+
+	return info
+}
+
+// btrfsSnapshotTimeLayout is the otime format of `btrfs subvolume list`.
+const btrfsSnapshotTimeLayout = "2006-01-02 15:04:05"
+
+// btrfsSnapshots lists the snapshots of the subvolume mounted at fs:
+// `btrfs subvolume list -s` reports the snapshots of every subvolume on the
+// filesystem, so only those whose parent UUID is the mounted subvolume's
+// count. Listing subvolumes usually needs root; without it, snapper's
+// .snapshots directory at the mount point is used instead.
+func (s *SnapshotInspector) btrfsSnapshots(ctx context.Context, fs FilesystemInfo) SnapshotInfo {
+	info := SnapshotInfo{Count: 0, Latest: time.Time{}, UsedBySnapshots: -1}
+
+	if uuid, ok := s.btrfsSubvolumeUUID(ctx, fs.MountPoint); ok {
+		out, err := s.run(ctx, "btrfs", "subvolume", "list", "-s", "-q", fs.MountPoint)
+		if err == nil {
+			for line := range strings.Lines(string(out)) {
+				parent, created, ok := parseBtrfsSnapshot(line)
+				if !ok || parent != uuid {
+					continue
+				}
+
+				info.Count++
+
+				if created.After(info.Latest) {
+					info.Latest = created
+				}
+			}
+
+			return info
+		}
+	}
+
+	entries, err := s.readDir(filepath.Join(fs.MountPoint, ".snapshots"))
+	if err != nil {
+		return info
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		entryInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		info.Count++
+
+		if entryInfo.ModTime().After(info.Latest) {
+			info.Latest = entryInfo.ModTime()
+		}
+	}
+
+	return info
+}
+
+// btrfsSubvolumeUUID returns the UUID of the subvolume at path, from the
+// "UUID:" line of `btrfs subvolume show`.
+func (s *SnapshotInspector) btrfsSubvolumeUUID(ctx context.Context, path string) (string, bool) {
+	out, err := s.run(ctx, "btrfs", "subvolume", "show", path)
+	if err != nil {
+		return "", false
+	}
+
+	for line := range strings.Lines(string(out)) {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || key != "UUID" {
+			continue
+		}
+
+		uuid := strings.TrimSpace(value)
+
+		return uuid, uuid != "" && uuid != "-"
+	}
+
+	return "", false
+}
+
+// parseBtrfsSnapshot parses a line of `btrfs subvolume list -s -q`
+// ("ID 260 gen 30 cgen 30 top level 5 otime 2026-10-01 03:00:00
+// parent_uuid <uuid> path <path>") into the snapshot's parent UUID and
+// creation time; an unparsable otime leaves the time zero.
+func parseBtrfsSnapshot(line string) (string, time.Time, bool) {
+	var (
+		parent  string
+		created time.Time
+	)
+
+	fields := strings.Fields(line)
+	// Stop at the path, which may contain any of the keys.
+	for i := 0; i+1 < len(fields) && fields[i] != "path"; i++ {
+		switch fields[i] {
+		case "parent_uuid":
+			parent = fields[i+1]
+		case "otime":
+			if i+2 < len(fields) {
+				created, _ = time.ParseInLocation(btrfsSnapshotTimeLayout, fields[i+1]+" "+fields[i+2], time.Local)
+			}
+		}
+	}
+
+	return parent, created, parent != "" && parent != "-"
+}
+
+// readMountTable reads /proc/self/mounts on Linux and falls back to the
+// output of mount(8) elsewhere (macOS, BSD).
+func readMountTable() ([]FilesystemInfo, error) {
+	data, err := os.ReadFile("/proc/self/mounts")
+	if err == nil {
+		return parseProcMounts(data), nil
+	}
+
+	out, err := exec.Command("mount").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMountOutput(out), nil
+}
+
+// parseProcMounts parses /proc/self/mounts ("source mountpoint type options 0 0").
+func parseProcMounts(data []byte) []FilesystemInfo {
+	var mounts []FilesystemInfo
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		mounts = append(mounts, FilesystemInfo{
+			Type:       fields[2],
+			MountPoint: unescapeMountField(fields[1]),
+			Source:     unescapeMountField(fields[0]),
+		})
+	}
+
+	return mounts
+}
+
+// parseMountOutput parses BSD mount(8) lines ("source on /path (type, options)").
+func parseMountOutput(out []byte) []FilesystemInfo {
+	var mounts []FilesystemInfo
+
+	for line := range strings.Lines(string(out)) {
+		source, rest, ok := strings.Cut(strings.TrimSpace(line), " on ")
+		if !ok {
+			continue
+		}
+
+		open := strings.LastIndex(rest, " (")
+		if open < 0 {
+			continue
+		}
+
+		fsType, _, _ := strings.Cut(strings.TrimSuffix(rest[open+2:], ")"), ",")
+
+		mounts = append(mounts, FilesystemInfo{
+			Type:       strings.TrimSpace(fsType),
+			MountPoint: rest[:open],
+			Source:     source,
+		})
+	}
+
+	return mounts
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space) of /proc/self/mounts.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if n, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(field[i])
+	}
+
+	return b.String()
+}
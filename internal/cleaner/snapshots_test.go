@@ -0,0 +1,162 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+func TestParseMountTables(t *testing.T) {
+	t.Parallel()
+
+	proc := parseProcMounts([]byte(
+		"/dev/sda2 / ext4 rw 0 0\n" +
+			"/dev/sda3 /home/my\\040data btrfs rw,subvol=/home 0 0\n" +
+			"tank/cache /var/cache zfs rw 0 0\n",
+	))
+	if len(proc) != 3 || proc[1].MountPoint != "/home/my data" || proc[2].Type != FilesystemZFS {
+		t.Errorf("parseProcMounts() = %+v", proc)
+	}
+
+	bsd := parseMountOutput([]byte(
+		"/dev/disk3s1s1 on / (apfs, sealed, local, read-only, journaled)\n" +
+			"tank/home on /Users/me (zfs, local, noatime)\n",
+	))
+	if len(bsd) != 2 || bsd[1].MountPoint != "/Users/me" || bsd[1].Type != FilesystemZFS || bsd[1].Source != "tank/home" {
+		t.Errorf("parseMountOutput() = %+v", bsd)
+	}
+}
+
+func newTestInspector(mounts []FilesystemInfo, run commandRunner) *SnapshotInspector {
+	s := NewSnapshotInspector()
+	s.mounts = func() ([]FilesystemInfo, error) { return mounts, nil }
+	s.run = run
+
+	return s
+}
+
+func TestSnapshotInspector_FilesystemLongestMount(t *testing.T) {
+	t.Parallel()
+
+	s := newTestInspector([]FilesystemInfo{
+		{Type: "ext4", MountPoint: "/", Source: "/dev/sda2"},
+		{Type: FilesystemZFS, MountPoint: "/home", Source: "tank/home"},
+		{Type: "tmpfs", MountPoint: "/home/me/tmp", Source: "tmpfs"},
+	}, nil)
+
+	tests := map[string]string{
+		"/var/cache":         "/",
+		"/home/me/.cache":    "/home",
+		"/home/me/tmp/x":     "/home/me/tmp",
+		"/homework/whatever": "/",
+	}
+
+	for path, want := range tests {
+		fs, ok := s.Filesystem(path)
+		if !ok || fs.MountPoint != want {
+			t.Errorf("Filesystem(%q) = %+v, %v, want mount %s", path, fs, ok, want)
+		}
+	}
+}
+
+func TestSnapshotInspector_InspectZFS(t *testing.T) {
+	t.Parallel()
+
+	latest := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	run := func(_ context.Context, name string, args ...string) ([]byte, error) {
+		if name != "zfs" {
+			return nil, errors.New("unexpected command " + name)
+		}
+
+		if args[0] == "list" {
+			return []byte("tank/home@daily-1\t1759200000\ntank/home@daily-2\t" +
+				strconv.FormatInt(latest.Unix(), 10) + "\n"), nil
+		}
+
+		return []byte("4096\n"), nil
+	}
+
+	s := newTestInspector([]FilesystemInfo{
+		{Type: "ext4", MountPoint: "/", Source: "/dev/sda2"},
+		{Type: FilesystemZFS, MountPoint: "/home", Source: "tank/home"},
+	}, run)
+
+	warnings := s.Inspect(context.Background(), []domain.ScanItem{
+		{Path: "/home/me/.cache/old", Size: 1000, Created: latest.Add(-time.Hour)},
+		{Path: "/home/me/.cache/new", Size: 300, Created: latest.Add(time.Hour)},
+		{Path: "/var/cache/apt", Size: 5000, Created: latest},
+	})
+
+	if len(warnings) != 1 {
+		t.Fatalf("Inspect() = %+v, want one warning for /home", warnings)
+	}
+
+	w := warnings[0]
+	if w.Snapshots.Count != 2 || !w.Snapshots.Latest.Equal(latest) || w.Snapshots.UsedBySnapshots != 4096 {
+		t.Errorf("snapshots = %+v", w.Snapshots)
+	}
+
+	if w.Items != 2 || w.ReportedBytes != 1300 || w.PinnedBytes != 1000 {
+		t.Errorf("warning = %+v, want 2 items, 1300 reported, 1000 pinned", w)
+	}
+}
+
+func TestSnapshotInspector_BtrfsSnapshotsOfMountedSubvolume(t *testing.T) {
+	t.Parallel()
+
+	run := func(_ context.Context, name string, args ...string) ([]byte, error) {
+		if name != "btrfs" {
+			return nil, errors.New("unexpected command " + name)
+		}
+
+		if args[1] == "show" {
+			return []byte("@home\n\tName: \t\t\t@home\n\tUUID: \t\t\thome-uuid\n\tParent UUID: \t\t-\n"), nil
+		}
+
+		return []byte("ID 260 gen 30 cgen 30 top level 5 otime 2026-09-30 03:00:00 parent_uuid home-uuid path @snaps/home-1\n" +
+			"ID 261 gen 31 cgen 31 top level 5 otime 2026-10-01 03:00:00 parent_uuid root-uuid path @snaps/root-1\n" +
+			"ID 262 gen 32 cgen 32 top level 5 otime 2026-10-01 02:00:00 parent_uuid home-uuid path @snaps/home-2\n"), nil
+	}
+
+	fs := FilesystemInfo{Type: FilesystemBtrfs, MountPoint: "/home", Source: "/dev/sdb"}
+	s := newTestInspector([]FilesystemInfo{fs}, run)
+
+	info := s.Snapshots(context.Background(), fs)
+	latest := time.Date(2026, 10, 1, 2, 0, 0, 0, time.Local)
+
+	if info.Count != 2 || !info.Latest.Equal(latest) {
+		t.Errorf("Snapshots() = %+v, want the 2 snapshots of @home, latest %v", info, latest)
+	}
+}
+
+func TestSnapshotInspector_BtrfsSnapperFallback(t *testing.T) {
+	t.Parallel()
+
+	mount := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(mount, ".snapshots", "1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	denied := func(context.Context, string, ...string) ([]byte, error) {
+		return nil, errors.New("permission denied")
+	}
+
+	s := newTestInspector([]FilesystemInfo{{Type: FilesystemBtrfs, MountPoint: mount, Source: "/dev/sdb"}}, denied)
+
+	info := s.Snapshots(context.Background(), FilesystemInfo{Type: FilesystemBtrfs, MountPoint: mount, Source: "/dev/sdb"})
+	if info.Count != 1 || info.Latest.IsZero() {
+		t.Errorf("Snapshots() = %+v, want one snapper snapshot", info)
+	}
+
+	if warnings := s.Inspect(context.Background(), []domain.ScanItem{
+		{Path: filepath.Join(mount, "cache"), Size: 10},
+	}); len(warnings) != 1 || warnings[0].PinnedBytes != 10 {
+		t.Errorf("Inspect() = %+v, want items of unknown age counted as pinned", warnings)
+	}
+}
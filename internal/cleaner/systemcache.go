@@ -371,7 +371,7 @@ func (scc *SystemCacheCleaner) scanSystemCache(
 func (scc *SystemCacheCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
 	if !scc.IsAvailable(ctx) {
 		return result.Err[domain.CleanResult](
-			NewNotAvailableError("systemcache", "requires macOS or Linux"),
+			NewNotAvailableError(CleanerSystemCache, "requires macOS or Linux"),
 		)
 	}
 
@@ -389,7 +389,7 @@ func (scc *SystemCacheCleaner) Clean(ctx context.Context) result.Result[domain.C
 
 			itemsRemoved = len(items)
 			for _, item := range items {
-				totalBytes += item.Size
+				totalBytes += item.DiskUsage()
 			}
 		} else {
 			// Fallback to counting cache types if scan fails
@@ -469,8 +469,8 @@ func (scc *SystemCacheCleaner) removeCachePath(
 		))
 	}
 
-	// Measure size before removal
-	bytesFreed := GetDirSize(path)
+	// Measure on-disk size before removal
+	bytesFreed := GetDirDiskUsage(path)
 
 	err := os.RemoveAll(path)
 	if err != nil && !os.IsNotExist(err) {
@@ -492,11 +492,12 @@ func (scc *SystemCacheCleaner) removeCachePath(
 
 // scanCachePathWithConfig scans a cache directory using configuration and returns scan items.
 func (scc *SystemCacheCleaner) scanCachePathWithConfig(
-	_ context.Context,
+	ctx context.Context,
 	homeDir string,
 	config cacheTypeConfig,
 ) result.Result[[]domain.ScanItem] {
 	scanResult := ScanPath(
+		ctx,
 		homeDir,
 		config.scanType,
 		config.displayName,
@@ -510,7 +511,7 @@ func (scc *SystemCacheCleaner) scanCachePathWithConfig(
 
 // cleanSystemCache cleans cache for a specific system cache type.
 func (scc *SystemCacheCleaner) cleanSystemCache(
-	_ context.Context,
+	ctx context.Context,
 	cacheType domain.CacheType,
 	homeDir string,
 ) result.Result[domain.CleanResult] {
@@ -523,6 +524,14 @@ func (scc *SystemCacheCleaner) cleanSystemCache(
 
 	path := filepath.Join(append([]string{homeDir}, config.pathComponents...)...)
 
+	if keptByIgnoreRules(ctx, path) {
+		if scc.verbose {
+			fmt.Printf("  Skipping %s: excluded by an ignore file\n", path)
+		}
+
+		return result.Ok(conversions.NewCleanResult(domain.StrategyConservativeType, 0, 0))
+	}
+
 	return scc.removeCachePath(path, config.displayName+" cleaned")
 }
 
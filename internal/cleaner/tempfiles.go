@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/conversions"
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/fswalk"
 	"github.com/LarsArtmann/clean-wizard/internal/result"
 )
 
@@ -93,6 +95,7 @@ func validateTempFilesSettings(tf *domain.TempFilesSettings) error {
 func (tfc *TempFilesCleaner) Scan(ctx context.Context) result.Result[[]domain.ScanItem] {
 	items := make([]domain.ScanItem, 0)
 	cutoffTime := time.Now().Add(-tfc.olderThan)
+	engine := SizeEngineFromContext(ctx)
 
 	// Scan each base path
 	for _, basePath := range tfc.basePaths {
@@ -101,30 +104,31 @@ func (tfc *TempFilesCleaner) Scan(ctx context.Context) result.Result[[]domain.Sc
 			continue
 		}
 
-		// Walk the directory tree
-		err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				// Skip files we can't access
-				return nil //nolint:nilerr
-			}
-
+		// Walk the directory tree with the shared walker
+		err := fswalk.Shared().Walk(ctx, basePath, func(entry fswalk.Entry) error {
 			// Skip directories
-			if info.IsDir() {
+			if entry.IsDir() {
 				return nil
 			}
 
 			// Check if path is excluded
-			if tfc.isExcluded(path) {
+			if tfc.isExcluded(entry.Path) {
 				return nil
 			}
 
 			// Check if file is older than cutoff
-			if info.ModTime().Before(cutoffTime) {
+			if entry.ModTime.Before(cutoffTime) {
+				size := engine.MeasureEntry(entry)
 				items = append(items, domain.ScanItem{
-					Path:     path,
-					Size:     info.Size(),
-					Created:  info.ModTime(),
-					ScanType: domain.ScanTypeTemp,
+					Path:       entry.Path,
+					Size:       size.Apparent,
+					OnDiskSize: size.Allocated,
+					Created:    entry.ModTime,
+					ScanType:   domain.ScanTypeTemp,
+					Reasons: []domain.SelectionReason{
+						ruleReason("file below a temp directory", basePath),
+						ageReason(entry.ModTime, tfc.olderThan),
+					},
 				})
 			}
 
@@ -136,6 +140,9 @@ func (tfc *TempFilesCleaner) Scan(ctx context.Context) result.Result[[]domain.Sc
 		}
 	}
 
+	// The walker visits directories in parallel; keep the output stable.
+	slices.SortFunc(items, func(a, b domain.ScanItem) int { return strings.Compare(a.Path, b.Path) })
+
 	return result.Ok(items)
 }
 
@@ -164,7 +171,7 @@ func (tfc *TempFilesCleaner) Clean(ctx context.Context) result.Result[domain.Cle
 		// Calculate total bytes that would be freed
 		var totalBytes int64
 		for _, item := range items {
-			totalBytes += item.Size
+			totalBytes += item.DiskUsage()
 		}
 
 		cleanResult := conversions.NewCleanResult(
@@ -181,6 +188,7 @@ func (tfc *TempFilesCleaner) Clean(ctx context.Context) result.Result[domain.Cle
 	itemsRemoved := 0
 	itemsFailed := 0
 	bytesFreed := int64(0)
+	engine := SizeEngineFromContext(ctx)
 
 	for _, item := range items {
 		err := os.Remove(item.Path)
@@ -195,7 +203,7 @@ func (tfc *TempFilesCleaner) Clean(ctx context.Context) result.Result[domain.Cle
 		}
 
 		itemsRemoved++
-		bytesFreed += item.Size
+		bytesFreed += freedBytes(engine, item)
 	}
 
 	duration := time.Since(startTime)
@@ -15,6 +15,7 @@ import (
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 	errorfamily "github.com/larsartmann/go-error-family"
+	goyaml "gopkg.in/yaml.v3"
 )
 
 // ErrConfigShouldUnmarshal is returned when the config file was read successfully
@@ -22,8 +23,10 @@ import (
 var ErrConfigShouldUnmarshal = errors.New("config file read successfully, proceed to unmarshal")
 
 const (
-	configName = ".clean-wizard"
-	configType = "yaml"
+	configName        = ".clean-wizard"
+	configType        = "yaml"
+	customCleanersKey = "custom_cleaners"
+	secretRulesKey    = "secret_rules"
 )
 
 // setupKoanf creates and configures a koanf instance with defaults.
@@ -93,10 +96,24 @@ func unmarshalConfig(k *koanf.Koanf) (*domain.Config, error) {
 	}
 
 	// Fix risk levels and settings after unmarshaling
-	fixProfileSettings(k, &config)
+	err := fixProfileSettings(k, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal custom cleaner definitions
+	err = unmarshalCustomCleaners(k, &config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Validate configuration
-	err := validateLoadedConfig(&config)
+	err = unmarshalSecretRules(k, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateLoadedConfig(&config)
 	if err != nil {
 		return nil, err
 	}
@@ -105,14 +122,60 @@ func unmarshalConfig(k *koanf.Koanf) (*domain.Config, error) {
 }
 
 // fixProfileSettings fixes risk levels and settings after unmarshaling.
-func fixProfileSettings(k *koanf.Koanf, config *domain.Config) {
+// It fails when an operation's settings cannot be decoded.
+func fixProfileSettings(k *koanf.Koanf, config *domain.Config) error {
 	for name, profile := range config.Profiles {
 		for i := range profile.Operations {
 			op := &profile.Operations[i]
 			op.RiskLevel = parseRiskLevel(k, name, i)
-			unmarshalOperationSettings(k, name, i, op)
+
+			err := unmarshalOperationSettings(k, name, i, op)
+			if err != nil {
+				return err
+			}
+
+			op.ExclusiveGroups = parseExclusiveGroups(k, name, i)
+			op.ResourceClass = parseOperationString(k, name, i, "resource_class")
 		}
 	}
+
+	return nil
+}
+
+// unmarshalCustomCleaners decodes the custom_cleaners section. The raw section
+// is re-encoded and decoded with yaml.v3 so that the domain enums (match,
+// action, risk_level) are parsed by their UnmarshalYAML methods, accepting the
+// same string forms as the rest of the config file.
+func unmarshalCustomCleaners(k *koanf.Koanf, config *domain.Config) error {
+	if !k.Exists(customCleanersKey) {
+		return nil
+	}
+
+	raw, err := goyaml.Marshal(k.Get(customCleanersKey))
+	if err != nil {
+		return errorfamily.WrapRejection(err, "config.load", "failed to read custom_cleaners")
+	}
+
+	err = goyaml.Unmarshal(raw, &config.CustomCleaners)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "config.load", "failed to unmarshal custom_cleaners")
+	}
+
+	return nil
+}
+
+// unmarshalSecretRules decodes the secret_rules section.
+func unmarshalSecretRules(k *koanf.Koanf, config *domain.Config) error {
+	if !k.Exists(secretRulesKey) {
+		return nil
+	}
+
+	err := k.Unmarshal(secretRulesKey, &config.SecretRules)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "config.load", "failed to unmarshal secret_rules")
+	}
+
+	return nil
 }
 
 // validateLoadedConfig validates the loaded configuration.
@@ -221,6 +284,26 @@ func Save(config *domain.Config) error {
 				opMap["settings"] = op.Settings
 			}
 
+			if len(op.After) > 0 {
+				opMap["after"] = op.After
+			}
+
+			if len(op.Requires) > 0 {
+				opMap["requires"] = op.Requires
+			}
+
+			if len(op.ExclusiveGroups) > 0 {
+				opMap["exclusive_groups"] = op.ExclusiveGroups
+			}
+
+			if op.ResourceClass != "" {
+				opMap["resource_class"] = op.ResourceClass
+			}
+
+			if op.Timeout != "" {
+				opMap["timeout"] = op.Timeout
+			}
+
 			operations[i] = opMap
 		}
 
@@ -230,6 +313,14 @@ func Save(config *domain.Config) error {
 
 	configMap["profiles"] = profilesMap
 
+	if len(config.CustomCleaners) > 0 {
+		configMap[customCleanersKey] = config.CustomCleaners
+	}
+
+	if len(config.SecretRules) > 0 {
+		configMap[secretRulesKey] = config.SecretRules
+	}
+
 	// Ensure config directory exists
 	configDir := filepath.Dir(configPath)
 
@@ -289,36 +380,77 @@ func parseRiskLevel(k *koanf.Koanf, profileName string, operationIndex int) doma
 	}
 }
 
-// unmarshalOperationSettings extracts operation settings from koanf and populates the operation.
+// rawOperation returns the raw map of a profile operation as loaded by koanf.
+func rawOperation(k *koanf.Koanf, profileName string, operationIndex int) map[string]any {
+	operations, ok := k.Get("profiles." + profileName + ".operations").([]any)
+	if !ok || operationIndex >= len(operations) {
+		return nil
+	}
+
+	op, _ := operations[operationIndex].(map[string]any)
+
+	return op
+}
+
+// parseOperationString reads a string field of an operation whose key the
+// case-insensitive field matching of k.Unmarshal cannot map (underscore).
+func parseOperationString(k *koanf.Koanf, profileName string, operationIndex int, key string) string {
+	value, _ := rawOperation(k, profileName, operationIndex)[key].(string)
+
+	return value
+}
+
+// parseExclusiveGroups reads an operation's exclusive_groups list, which the
+// case-insensitive field matching of k.Unmarshal cannot map (underscore).
+func parseExclusiveGroups(k *koanf.Koanf, profileName string, operationIndex int) []string {
+	rawGroups, ok := rawOperation(k, profileName, operationIndex)["exclusive_groups"].([]any)
+	if !ok {
+		return nil
+	}
+
+	groups := make([]string, 0, len(rawGroups))
+	for _, g := range rawGroups {
+		if group, isString := g.(string); isString && group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// unmarshalOperationSettings decodes an operation's settings map. koanf
+// cannot address keys below a list index, so the raw map is re-encoded and
+// decoded with yaml.v3, which also parses the domain enums (optimize, dry_run).
 func unmarshalOperationSettings(
 	k *koanf.Koanf,
 	profileName string,
 	operationIndex int,
 	op *domain.CleanupOperation,
-) {
-	settingsKey := fmt.Sprintf("profiles.%s.operations.%d.settings", profileName, operationIndex)
+) error {
+	rawSettings, ok := rawOperation(k, profileName, operationIndex)["settings"]
+	if !ok || rawSettings == nil {
+		logger.Debug("No settings map found", "profile", profileName, "operation", operationIndex)
 
-	if !k.Exists(settingsKey) {
-		logger.Debug("No settings map found")
+		return nil
+	}
 
-		return
+	raw, err := goyaml.Marshal(rawSettings)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "config.load",
+			fmt.Sprintf("failed to read settings of profiles.%s.operations.%d", profileName, operationIndex))
 	}
 
-	// Check if nix_generations settings exist
-	nixGenKey := settingsKey + ".nix_generations"
-	if k.Exists(nixGenKey) {
-		nixGenSettings := &domain.NixGenerationsSettings{} //nolint:exhaustruct
+	settings := &domain.OperationSettings{} //nolint:exhaustruct
 
-		err := k.Unmarshal(nixGenKey, nixGenSettings)
-		if err == nil {
-			op.Settings = &domain.OperationSettings{} //nolint:exhaustruct
-			op.Settings.NixGenerations = nixGenSettings
-		} else {
-			logger.Error("Failed to unmarshal nix_generations settings", "error", err)
-		}
-	} else {
-		logger.Debug("No nix_generations settings found")
+	err = goyaml.Unmarshal(raw, settings)
+	if err != nil {
+		return errorfamily.WrapRejection(err, "config.load",
+			fmt.Sprintf("failed to unmarshal settings of profiles.%s.operations.%d", profileName, operationIndex))
 	}
+
+	op.Settings = settings
+
+	return nil
 }
 
 // newCleanupOperation creates a cleanup operation with the specified parameters.
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+)
+
+// writeTestConfig writes a config file with a single nix profile whose
+// operation has the given settings and returns its path.
+func writeTestConfig(t *testing.T, operation, settings string) string {
+	t.Helper()
+
+	content := `version: "1.0.0"
+safe_mode: true
+max_disk_usage_percent: 50
+protected:
+  - "/System"
+profiles:
+  nix:
+    name: "nix"
+    description: "Nix cleanup"
+    enabled: true
+    operations:
+      - name: "` + operation + `"
+        description: "Nix cleanup operation"
+        risk_level: "low"
+        enabled: true
+        settings:
+` + settings
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+// loadTestConfig loads a config file written by writeTestConfig.
+func loadTestConfig(t *testing.T, operation, settings string) *domain.Config {
+	t.Helper()
+
+	cfg, err := LoadFromPath(writeTestConfig(t, operation, settings))
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+
+	return cfg
+}
+
+func TestLoadFromPath_NixGenerationsSettings(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadTestConfig(t, "nix-generations", `          nix_generations:
+            generations: 3
+            keep_last: 5
+            keep_newer_than: "14d"
+            profiles:
+              - "/nix/var/nix/profiles/per-user/dev/home-manager"
+`)
+
+	settings := cfg.Profiles["nix"].Operations[0].Settings
+	if settings == nil || settings.NixGenerations == nil {
+		t.Fatal("nix_generations settings were not loaded")
+	}
+
+	got := settings.NixGenerations
+	if got.KeepLast != 5 {
+		t.Errorf("KeepLast = %d, want 5", got.KeepLast)
+	}
+
+	if got.KeepNewerThan != "14d" {
+		t.Errorf("KeepNewerThan = %q, want %q", got.KeepNewerThan, "14d")
+	}
+
+	if len(got.Profiles) != 1 || got.Profiles[0] != "/nix/var/nix/profiles/per-user/dev/home-manager" {
+		t.Errorf("Profiles = %v", got.Profiles)
+	}
+}
+
+func TestLoadFromPath_NixOptimiseSettings(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadTestConfig(t, "nix-generations", `          nix_generations:
+            generations: 3
+            optimize: true
+            optimize_every: "30d"
+`)
+
+	got := cfg.Profiles["nix"].Operations[0].Settings.NixGenerations
+	if got == nil {
+		t.Fatal("nix_generations settings were not loaded")
+	}
+
+	if !got.Optimize.IsEnabled() {
+		t.Errorf("Optimize = %v, want %v", got.Optimize, domain.OptimizationModeEnabled)
+	}
+
+	if got.OptimizeEvery != "30d" {
+		t.Errorf("OptimizeEvery = %q, want %q", got.OptimizeEvery, "30d")
+	}
+}
+
+func TestLoadFromPath_InvalidOperationSettings(t *testing.T) {
+	t.Parallel()
+
+	path := writeTestConfig(t, "nix-generations", `          nix_generations:
+            keep_last: "many"
+`)
+
+	_, err := LoadFromPath(path)
+	if err == nil {
+		t.Error("LoadFromPath() accepted a non-numeric keep_last")
+	}
+}
+
+func TestLoadFromPath_NixGCRootsSettings(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadTestConfig(t, "nix-gcroots", `          nix_gcroots:
+            older_than: "7d"
+`)
+
+	settings := cfg.Profiles["nix"].Operations[0].Settings
+	if settings == nil || settings.NixGCRoots == nil {
+		t.Fatal("nix_gcroots settings were not loaded")
+	}
+
+	if settings.NixGCRoots.OlderThan != "7d" {
+		t.Errorf("OlderThan = %q, want %q", settings.NixGCRoots.OlderThan, "7d")
+	}
+}
@@ -40,7 +40,7 @@ func (cs *ConfigSanitizer) sanitizeOperationSettings(
 
 	// Type-aware sanitization based on operation type
 	switch opType {
-	case domain.OperationTypeNixGenerations:
+	case domain.OperationTypeNixGenerations, domain.OperationTypeNixOptimise:
 		cs.sanitizeNixGenerationsSettings(fieldPrefix, settings.NixGenerations, result)
 
 	case domain.OperationTypeTempFiles:
@@ -62,7 +62,8 @@ func (cs *ConfigSanitizer) sanitizeOperationSettings(
 		domain.OperationTypeProjectExecutables,
 		domain.OperationTypeCompiledBinaries,
 		domain.OperationTypeGitHistory,
-		domain.OperationTypeGolangciLintCache:
+		domain.OperationTypeGolangciLintCache,
+		domain.OperationTypeNixGCRoots:
 		// These operation types have no specific sanitization logic yet
 		// Fall through to default handling
 
@@ -30,7 +30,9 @@ var CleanerPackage = do.Package( //nolint:gochecknoglobals
 )
 
 // registerCleanerRegistry provides a *cleaner.Registry as a lazy singleton.
-// The registry is created with the verbose/dryRun flags resolved from RunSettings,
+// The registry is created with the verbose/dryRun flags resolved from RunSettings
+// and extended with the custom cleaners declared in the loaded config and any
+// external plugins discovered in the plugins directory or on PATH,
 // eliminating the former dual-registry pattern where cleaners were instantiated
 // twice (once for discovery, once for execution).
 func registerCleanerRegistry(injector do.Injector) {
@@ -49,6 +51,28 @@ func registerCleanerRegistry(injector do.Injector) {
 			return nil, errorfamily.WrapRejection(err, "di.create_registry", "failed to create cleaner registry")
 		}
 
+		cfg, err := do.Invoke[*domain.Config](i)
+		if err == nil && cfg != nil {
+			err = cleaner.RegisterCustomCleaners(
+				registry, cfg.CustomCleaners, cfg.Protected, settings.Verbose, settings.DryRun,
+			)
+			if err != nil {
+				return nil, errorfamily.WrapRejection(
+					err,
+					"di.register_custom_cleaners",
+					"failed to register custom cleaners",
+				)
+			}
+
+			cleaner.RegisterPlugins(
+				registry,
+				cleaner.DiscoverPlugins(cleaner.PluginSearchDirs()),
+				cfg.Protected,
+				settings.Verbose,
+				settings.DryRun,
+			)
+		}
+
 		return registry, nil
 	})
 }
@@ -16,6 +16,11 @@ type Config struct {
 	CurrentProfile string              `json:"current_profile,omitempty" yaml:"current_profile,omitempty"`
 	LastClean      time.Time           `json:"last_clean"                yaml:"last_clean"`
 	Updated        time.Time           `json:"updated"                   yaml:"updated"`
+
+	CustomCleaners []CustomCleanerConfig `json:"custom_cleaners,omitempty" yaml:"custom_cleaners,omitempty"`
+
+	// SecretRules extend the built-in rules of git-history --secrets.
+	SecretRules []GitHistorySecretRule `json:"secret_rules,omitempty" yaml:"secret_rules,omitempty"`
 }
 
 // IsValid validates configuration.
@@ -68,6 +73,55 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	err := c.validateCustomCleaners()
+	if err != nil {
+		return err
+	}
+
+	return c.validateSecretRules()
+}
+
+// validateCustomCleaners validates each custom cleaner definition and rejects duplicate names.
+func (c *Config) validateCustomCleaners() error {
+	seen := make(map[string]bool, len(c.CustomCleaners))
+
+	for _, cc := range c.CustomCleaners {
+		err := cc.Validate()
+		if err != nil {
+			return err
+		}
+
+		if seen[cc.Name] {
+			return fmt.Errorf("custom cleaner %q is defined more than once", cc.Name)
+		}
+
+		seen[cc.Name] = true
+	}
+
+	return nil
+}
+
+// validateSecretRules validates each secret rule and rejects duplicate names,
+// including the names of the built-in rules.
+func (c *Config) validateSecretRules() error {
+	seen := make(map[string]bool, len(DefaultGitHistorySecretRules)+len(c.SecretRules))
+	for _, rule := range DefaultGitHistorySecretRules {
+		seen[rule.Name] = true
+	}
+
+	for _, rule := range c.SecretRules {
+		err := rule.Validate()
+		if err != nil {
+			return err
+		}
+
+		if seen[rule.Name] {
+			return fmt.Errorf("secret rule %q is already defined", rule.Name)
+		}
+
+		seen[rule.Name] = true
+	}
+
 	return nil
 }
 
@@ -133,6 +187,22 @@ type CleanupOperation struct {
 	RiskLevel   RiskLevelType      `json:"risk_level"         yaml:"risk_level"`
 	Enabled     ProfileStatus      `json:"enabled"            yaml:"enabled"`
 	Settings    *OperationSettings `json:"settings,omitempty" yaml:"settings,omitempty"`
+
+	// After orders this operation after the listed operations when they are
+	// selected in the same run; their outcome does not matter.
+	After []string `json:"after,omitempty" yaml:"after,omitempty"`
+	// Requires lists operations that must be selected and succeed first;
+	// if one fails, this operation is skipped.
+	Requires []string `json:"requires,omitempty" yaml:"requires,omitempty"`
+	// ExclusiveGroups names mutual-exclusion groups; operations sharing a
+	// group never run concurrently.
+	ExclusiveGroups []string `json:"exclusive_groups,omitempty" yaml:"exclusive_groups,omitempty"`
+
+	// ResourceClass overrides the cleaner's scheduling class (DISK_IO,
+	// NETWORK or CPU); empty keeps the cleaner's default.
+	ResourceClass string `json:"resource_class,omitempty" yaml:"resource_class,omitempty"`
+	// Timeout overrides the cleaner's default timeout (e.g. "10m").
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 }
 
 // IsValid validates cleanup operation.
@@ -178,6 +248,11 @@ func (op CleanupOperation) Validate() error {
 		)
 	}
 
+	err := validateResourceOverrides(op.ResourceClass, op.Timeout)
+	if err != nil {
+		return fmt.Errorf("operation %s: %w", op.Name, err)
+	}
+
 	// Validate settings if present
 	if op.Settings != nil {
 		opType := GetOperationType(op.Name)
@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// customCleanerNamePattern restricts custom cleaner names to the same
+// kebab-case shape used by the built-in registry names.
+var customCleanerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`) //nolint:gochecknoglobals
+
+// IsValidCleanerName reports whether name is a valid name for a runtime-defined
+// (custom or plugin) cleaner.
+func IsValidCleanerName(name string) bool {
+	return customCleanerNamePattern.MatchString(name)
+}
+
+// CustomMatchMode selects whether a custom cleaner collects files or whole directories.
+//
+//nolint:recvcheck
+type CustomMatchMode int
+
+const (
+	// CustomMatchFiles matches individual regular files anywhere below the roots.
+	CustomMatchFiles CustomMatchMode = iota
+	// CustomMatchDirectories matches directories; a matched directory is removed as a unit.
+	CustomMatchDirectories
+)
+
+var customMatchModeStrings = []string{"FILES", "DIRECTORIES"} //nolint:gochecknoglobals
+
+func (m CustomMatchMode) String() string { return EnumString(m, customMatchModeStrings) }
+func (m CustomMatchMode) IsValid() bool  { return EnumIsValid(m, CustomMatchDirectories) }
+func (m CustomMatchMode) Values() []CustomMatchMode {
+	return EnumValues[CustomMatchMode](CustomMatchDirectories)
+}
+
+func (m CustomMatchMode) MarshalJSON() ([]byte, error) {
+	return EnumMarshalJSON(m, customMatchModeStrings)
+}
+
+func (m *CustomMatchMode) UnmarshalJSON(data []byte) error {
+	return EnumUnmarshalJSON(data, (*int)(m), customMatchModeStrings, "custom match mode")
+}
+
+func (m CustomMatchMode) MarshalYAML() (any, error) {
+	return EnumMarshalYAML(m, customMatchModeStrings)
+}
+
+func (m *CustomMatchMode) UnmarshalYAML(value *yaml.Node) error {
+	return EnumUnmarshalYAML(value, (*int)(m), customMatchModeStrings, "custom match mode")
+}
+
+// CustomCleanAction selects how a custom cleaner disposes of matched items.
+//
+//nolint:recvcheck
+type CustomCleanAction int
+
+const (
+	// CustomActionTrash moves matched items to the system trash (recoverable).
+	CustomActionTrash CustomCleanAction = iota
+	// CustomActionDelete removes matched items permanently.
+	CustomActionDelete
+)
+
+var customCleanActionStrings = []string{"TRASH", "DELETE"} //nolint:gochecknoglobals
+
+func (a CustomCleanAction) String() string { return EnumString(a, customCleanActionStrings) }
+func (a CustomCleanAction) IsValid() bool  { return EnumIsValid(a, CustomActionDelete) }
+func (a CustomCleanAction) Values() []CustomCleanAction {
+	return EnumValues[CustomCleanAction](CustomActionDelete)
+}
+
+func (a CustomCleanAction) MarshalJSON() ([]byte, error) {
+	return EnumMarshalJSON(a, customCleanActionStrings)
+}
+
+func (a *CustomCleanAction) UnmarshalJSON(data []byte) error {
+	return EnumUnmarshalJSON(data, (*int)(a), customCleanActionStrings, "custom clean action")
+}
+
+func (a CustomCleanAction) MarshalYAML() (any, error) {
+	return EnumMarshalYAML(a, customCleanActionStrings)
+}
+
+func (a *CustomCleanAction) UnmarshalYAML(value *yaml.Node) error {
+	return EnumUnmarshalYAML(value, (*int)(a), customCleanActionStrings, "custom clean action")
+}
+
+// CustomCleanerConfig declares a filesystem cleaner in YAML instead of Go.
+// Each entry in the custom_cleaners config section becomes a cleaner in the
+// registry under Name, and can be referenced by that name from profiles.
+type CustomCleanerConfig struct {
+	// Name is the registry name and profile operation name (kebab-case).
+	Name string `json:"name" yaml:"name"`
+	// Description is shown in the TUI selection list.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Icon is shown next to the description in the TUI (optional).
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty"`
+	// Roots are the directories to search; "~/" is expanded to the home directory.
+	Roots []string `json:"roots" yaml:"roots"`
+	// Include globs select items. Globs without a "/" match the base name,
+	// globs with a "/" match the path relative to the root.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	// Exclude globs reject items (and prune excluded directories from the walk).
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// MinSizeMB skips items smaller than this size (0 = any size).
+	MinSizeMB int `json:"min_size_mb,omitempty" yaml:"min_size_mb,omitempty"`
+	// OlderThan skips items modified more recently than this (e.g. "7d"; empty = any age).
+	OlderThan string `json:"older_than,omitempty" yaml:"older_than,omitempty"`
+	// Match selects files or directories.
+	Match CustomMatchMode `json:"match" yaml:"match"`
+	// Action selects trash (default) or permanent deletion.
+	Action CustomCleanAction `json:"action" yaml:"action"`
+	// RiskLevel classifies the cleaner like a profile operation.
+	RiskLevel RiskLevelType `json:"risk_level" yaml:"risk_level"`
+	// After, Requires and ExclusiveGroups order the cleaner relative to
+	// other cleaners by registry name, like the profile operation fields.
+	After           []string `json:"after,omitempty"            yaml:"after,omitempty"`
+	Requires        []string `json:"requires,omitempty"         yaml:"requires,omitempty"`
+	ExclusiveGroups []string `json:"exclusive_groups,omitempty" yaml:"exclusive_groups,omitempty"`
+	// ResourceClass and Timeout override the scheduling defaults (DISK_IO,
+	// no timeout), like the profile operation fields.
+	ResourceClass string `json:"resource_class,omitempty" yaml:"resource_class,omitempty"`
+	Timeout       string `json:"timeout,omitempty"        yaml:"timeout,omitempty"`
+}
+
+// Validate returns errors for an invalid custom cleaner definition.
+func (c CustomCleanerConfig) Validate() error {
+	if !IsValidCleanerName(c.Name) {
+		return fmt.Errorf(
+			"custom cleaner name %q must be lowercase letters, digits and dashes",
+			c.Name,
+		)
+	}
+
+	if _, builtin := nameToOperationType[c.Name]; builtin {
+		return fmt.Errorf("custom cleaner name %q collides with a built-in operation", c.Name)
+	}
+
+	if len(c.Roots) == 0 {
+		return fmt.Errorf("custom cleaner %s: at least one root is required", c.Name)
+	}
+
+	for _, root := range c.Roots {
+		err := validateCustomCleanerRoot(root)
+		if err != nil {
+			return fmt.Errorf("custom cleaner %s: %w", c.Name, err)
+		}
+	}
+
+	for _, pattern := range slices.Concat(c.Include, c.Exclude) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("custom cleaner %s: invalid glob %q: %w", c.Name, pattern, err)
+		}
+	}
+
+	if c.MinSizeMB < 0 {
+		return fmt.Errorf("custom cleaner %s: min_size_mb must be >= 0, got %d", c.Name, c.MinSizeMB)
+	}
+
+	if c.OlderThan != "" {
+		if _, err := ParseCustomDuration(c.OlderThan); err != nil {
+			return fmt.Errorf("custom cleaner %s: invalid older_than: %w", c.Name, err)
+		}
+	}
+
+	if !c.Match.IsValid() {
+		return fmt.Errorf("custom cleaner %s: invalid match mode (must be FILES or DIRECTORIES)", c.Name)
+	}
+
+	if !c.Action.IsValid() {
+		return fmt.Errorf("custom cleaner %s: invalid action (must be TRASH or DELETE)", c.Name)
+	}
+
+	for _, name := range slices.Concat(c.After, c.Requires) {
+		if name == c.Name || !IsValidCleanerName(name) {
+			return fmt.Errorf("custom cleaner %s: invalid dependency %q", c.Name, name)
+		}
+	}
+
+	if err := validateResourceOverrides(c.ResourceClass, c.Timeout); err != nil {
+		return fmt.Errorf("custom cleaner %s: %w", c.Name, err)
+	}
+
+	if !c.RiskLevel.IsValid() {
+		return fmt.Errorf(
+			"custom cleaner %s: invalid risk level (must be LOW, MEDIUM, HIGH, or CRITICAL)",
+			c.Name,
+		)
+	}
+
+	return nil
+}
+
+// validateCustomCleanerRoot rejects roots that are relative, contain parent
+// references, are the home directory itself, or lie in a protected system
+// directory.
+func validateCustomCleanerRoot(root string) error {
+	if strings.Contains(root, "..") {
+		return fmt.Errorf("root %q must not contain '..'", root)
+	}
+
+	if filepath.Clean(root) == "~" {
+		return fmt.Errorf("root %q is the whole home directory; name a directory below it", root)
+	}
+
+	if strings.HasPrefix(root, "~/") {
+		return nil
+	}
+
+	if !filepath.IsAbs(root) {
+		return fmt.Errorf("root %q must be absolute or start with ~/", root)
+	}
+
+	if isUnderProtectedSystemPath(filepath.Clean(root)) {
+		return fmt.Errorf("root %q is in a protected system path", root)
+	}
+
+	return nil
+}
+
+// isUnderProtectedSystemPath reports whether path is the file system root
+// or lies in one of the other protected system paths.
+func isUnderProtectedSystemPath(path string) bool {
+	return slices.ContainsFunc(AllProtectedSystemPaths(), func(protected string) bool {
+		if protected == PathRoot {
+			return path == PathRoot
+		}
+
+		return path == protected || strings.HasPrefix(path, protected+string(filepath.Separator))
+	})
+}
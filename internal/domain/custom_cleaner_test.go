@@ -0,0 +1,52 @@
+package domain
+
+import "testing"
+
+func TestCustomCleanerConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := CustomCleanerConfig{
+		Name:      "old-downloads",
+		Roots:     []string{"~/Downloads"},
+		Include:   []string{"*.dmg"},
+		OlderThan: "30d",
+		Match:     CustomMatchFiles,
+		Action:    CustomActionTrash,
+		RiskLevel: RiskLevelMediumType,
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *CustomCleanerConfig)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(*CustomCleanerConfig) {}, wantErr: false},
+		{name: "uppercase name", mutate: func(c *CustomCleanerConfig) { c.Name = "Downloads" }, wantErr: true},
+		{name: "built-in name", mutate: func(c *CustomCleanerConfig) { c.Name = "docker" }, wantErr: true},
+		{name: "no roots", mutate: func(c *CustomCleanerConfig) { c.Roots = nil }, wantErr: true},
+		{name: "relative root", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"tmp"} }, wantErr: true},
+		{name: "parent reference", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"~/../etc"} }, wantErr: true},
+		{name: "protected root", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"/etc"} }, wantErr: true},
+		{name: "below protected root", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"/var/lib"} }, wantErr: true},
+		{name: "home directory", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"~/"} }, wantErr: true},
+		{name: "absolute root", mutate: func(c *CustomCleanerConfig) { c.Roots = []string{"/srv/cache"} }, wantErr: false},
+		{name: "bad glob", mutate: func(c *CustomCleanerConfig) { c.Exclude = []string{"[a-"} }, wantErr: true},
+		{name: "negative size", mutate: func(c *CustomCleanerConfig) { c.MinSizeMB = -1 }, wantErr: true},
+		{name: "bad duration", mutate: func(c *CustomCleanerConfig) { c.OlderThan = "soon" }, wantErr: true},
+		{name: "bad action", mutate: func(c *CustomCleanerConfig) { c.Action = CustomCleanAction(9) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := valid
+			tt.mutate(&c)
+
+			err := c.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -150,3 +150,25 @@ func EnumUnmarshalYAML[T ~int](
 
 	return fmt.Errorf("cannot parse %s: expected string or int", name)
 }
+
+// EnumUnmarshalYAMLBool is EnumUnmarshalYAML for enums also written as a YAML
+// boolean, such as "optimize: true": true and false map to onTrue and onFalse.
+func EnumUnmarshalYAMLBool[T ~int](
+	value *yaml.Node,
+	target *T,
+	stringsMap []string,
+	name string,
+	onFalse, onTrue T,
+) error {
+	var b bool
+	if value.Kind == yaml.ScalarNode && value.ShortTag() == "!!bool" && value.Decode(&b) == nil {
+		*target = onFalse
+		if b {
+			*target = onTrue
+		}
+
+		return nil
+	}
+
+	return EnumUnmarshalYAML(value, target, stringsMap, name)
+}
@@ -673,3 +673,28 @@ func findSubstring(s, substr string) int {
 
 	return -1
 }
+
+// TestEnumYAMLUnmarshalingFromBool tests the enums that also accept a boolean.
+func TestEnumYAMLUnmarshalingFromBool(t *testing.T) {
+	t.Parallel()
+
+	var settings NixGenerationsSettings
+
+	err := yaml.Unmarshal([]byte("optimize: true\ndry_run: false\n"), &settings)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if settings.Optimize != OptimizationModeEnabled {
+		t.Errorf("Optimize = %v, want %v", settings.Optimize, OptimizationModeEnabled)
+	}
+
+	if settings.DryRun != ExecutionModeNormal {
+		t.Errorf("DryRun = %v, want %v", settings.DryRun, ExecutionModeNormal)
+	}
+
+	err = yaml.Unmarshal([]byte("optimize: \"true\"\n"), &settings)
+	if err == nil {
+		t.Error("Unmarshal() accepted the string \"true\" as an optimization mode")
+	}
+}
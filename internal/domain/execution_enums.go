@@ -24,7 +24,8 @@ func (em ExecutionMode) MarshalYAML() (any, error) {
 }
 
 func (em *ExecutionMode) UnmarshalYAML(value *yaml.Node) error {
-	return EnumUnmarshalYAML(value, (*int)(em), executionModeStrings, "execution mode")
+	return EnumUnmarshalYAMLBool(value, em, executionModeStrings, "execution mode",
+		ExecutionModeNormal, ExecutionModeDryRun)
 }
 
 //nolint:recvcheck
@@ -97,7 +98,8 @@ func (om OptimizationMode) MarshalYAML() (any, error) {
 }
 
 func (om *OptimizationMode) UnmarshalYAML(value *yaml.Node) error {
-	return EnumUnmarshalYAML(value, (*int)(om), optimizationModeStrings, "optimization mode")
+	return EnumUnmarshalYAMLBool(value, om, optimizationModeStrings, "optimization mode",
+		OptimizationModeDisabled, OptimizationModeEnabled)
 }
 
 //nolint:recvcheck
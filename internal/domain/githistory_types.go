@@ -4,6 +4,7 @@ import (
 	"encoding/json/v2"
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -29,6 +30,8 @@ type GitHistoryFile struct {
 	Extension string `json:"extension"`
 	// Selected indicates if this file is selected for removal (for TUI)
 	Selected bool `json:"selected,omitempty"`
+	// Secrets are the secrets found in the blob by a secrets scan
+	Secrets []GitHistorySecret `json:"secrets,omitempty"`
 }
 
 // IsValid validates the GitHistoryFile.
@@ -58,6 +61,62 @@ func (f GitHistoryFile) SizeMB() float64 {
 	return float64(f.SizeBytes) / (1024 * 1024)
 }
 
+// GitHistorySecret is a secret found in a blob in git history.
+type GitHistorySecret struct {
+	// Rule is the name of the rule that matched
+	Rule string `json:"rule"`
+	// Line is the line of the blob the secret starts on
+	Line int `json:"line"`
+	// Redacted shows enough of the secret to recognise it
+	Redacted string `json:"redacted"`
+	// Value is the secret itself; it is never serialized
+	Value string `json:"-"`
+}
+
+// GitHistorySecretRule is a regular expression matching secrets in the
+// content of blobs. If the expression has a capture group, the first group
+// is the secret; otherwise the whole match is.
+type GitHistorySecretRule struct {
+	// Name identifies the rule in findings (kebab-case)
+	Name string `json:"name" yaml:"name"`
+	// Pattern is the regular expression, in Go RE2 syntax
+	Pattern string `json:"pattern" yaml:"pattern"`
+}
+
+// Validate returns errors for an invalid secret rule.
+func (r GitHistorySecretRule) Validate() error {
+	if !IsValidCleanerName(r.Name) {
+		return fmt.Errorf("secret rule name %q must be lowercase letters, digits and dashes", r.Name)
+	}
+
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("secret rule %s: invalid pattern: %w", r.Name, err)
+	}
+
+	if re.MatchString("") {
+		return fmt.Errorf("secret rule %s: pattern must not match an empty string", r.Name)
+	}
+
+	return nil
+}
+
+// DefaultGitHistorySecretRules are the built-in rules of a secrets scan:
+// credentials with a recognisable format.
+var DefaultGitHistorySecretRules = []GitHistorySecretRule{ //nolint:gochecknoglobals
+	{Name: "aws-access-key-id", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "aws-secret-access-key", Pattern: `(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`},
+	{Name: "github-token", Pattern: `\bgh[pousr]_[A-Za-z0-9]{36,255}\b`},
+	{Name: "github-fine-grained-token", Pattern: `\bgithub_pat_[A-Za-z0-9_]{82}\b`},
+	{Name: "gitlab-token", Pattern: `\bglpat-[A-Za-z0-9_-]{20}\b`},
+	{Name: "slack-token", Pattern: `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+	{Name: "slack-webhook", Pattern: `https://hooks\.slack\.com/services/[A-Za-z0-9/]{40,}`},
+	{Name: "stripe-secret-key", Pattern: `\b[sr]k_live_[A-Za-z0-9]{24,}\b`},
+	{Name: "google-api-key", Pattern: `\bAIza[0-9A-Za-z_-]{35}\b`},
+	{Name: "npm-token", Pattern: `\bnpm_[A-Za-z0-9]{36}\b`},
+	{Name: "private-key", Pattern: `-----BEGIN[A-Z ]*PRIVATE KEY-----[\s\S]*?-----END[A-Z ]*PRIVATE KEY-----`},
+}
+
 // GitHistoryScanResult contains the results of scanning git history.
 type GitHistoryScanResult struct {
 	// Files is the list of binary files found
@@ -79,12 +138,67 @@ func (r GitHistoryScanResult) IsValid() bool {
 	return r.RepoPath != "" && !r.ScannedAt.IsZero()
 }
 
+// GitHistoryDirectory is the history size of a directory prefix: every blob
+// ever committed below it.
+type GitHistoryDirectory struct {
+	// Prefix is the directory with a trailing slash, e.g. .reports/html/
+	Prefix string `json:"prefix"`
+	// SizeBytes is the total size of the blobs ever committed below Prefix
+	SizeBytes int64 `json:"size_bytes"`
+	// Blobs is the number of those blobs
+	Blobs int `json:"blobs"`
+	// Paths is the number of distinct file paths below Prefix
+	Paths int `json:"paths"`
+	// InHEAD indicates if the directory still exists in HEAD
+	InHEAD bool `json:"in_head"`
+}
+
+// GitHistoryRepoReport is the history bloat of one repository found by a
+// batch scan.
+type GitHistoryRepoReport struct {
+	// RepoPath is the path to the repository
+	RepoPath string `json:"repo_path"`
+	// RepoSize is the size of the .git directory
+	RepoSize int64 `json:"repo_size"`
+	// Files are the largest binary blobs in history, largest first
+	Files []GitHistoryFile `json:"files,omitempty"`
+	// ReclaimableBytes is the size of the blobs of files no longer in HEAD,
+	// which a rewrite removes without touching the working tree
+	ReclaimableBytes int64 `json:"reclaimable_bytes"`
+	// LFSCandidates are the binaries still in HEAD, better moved to Git LFS
+	// than removed
+	LFSCandidates []GitHistoryFile `json:"lfs_candidates,omitempty"`
+	// LFSCandidateBytes is the total size of LFSCandidates
+	LFSCandidateBytes int64 `json:"lfs_candidate_bytes"`
+	// DurationMs is how long the scan took in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+	// Error is why the repository could not be scanned
+	Error string `json:"error,omitempty"`
+}
+
+// HasFindings returns true if the scan found binaries in history.
+func (r GitHistoryRepoReport) HasFindings() bool {
+	return len(r.Files) > 0
+}
+
 // GitHistoryRewriteResult contains the results of rewriting git history.
 type GitHistoryRewriteResult struct {
-	// FilesRemoved is the list of files that were removed
+	// Action is what the rewrite did with the selected files
+	Action GitHistoryAction `json:"action"`
+	// FilesRemoved is the list of files that were removed, or with
+	// GitHistoryActionLFS, replaced by LFS pointers, or with
+	// GitHistoryActionPurge, had their secrets redacted
 	FilesRemoved []GitHistoryFile `json:"files_removed"`
 	// BytesRemoved is the total bytes removed
 	BytesRemoved int64 `json:"bytes_removed"`
+	// RemovePaths are the directory prefixes and globs removed from history,
+	// empty when only the selected files were removed
+	RemovePaths []string `json:"remove_paths,omitempty"`
+	// LFSPatterns are the .gitattributes patterns tracked by Git LFS
+	LFSPatterns []string `json:"lfs_patterns,omitempty"`
+	// LFSObjectBytes is the size of the LFS objects stored in .git/lfs, not
+	// counted in OldRepoSize and NewRepoSize
+	LFSObjectBytes int64 `json:"lfs_object_bytes,omitempty"`
 	// CommitsAffected is the number of commits that were modified
 	CommitsAffected int `json:"commits_affected"`
 	// OldRepoSize is the repository size before rewrite
@@ -101,6 +215,11 @@ type GitHistoryRewriteResult struct {
 	ExecutedAt time.Time `json:"executed_at"`
 	// Duration is how long the rewrite took
 	Duration time.Duration `json:"duration"`
+	// Verification is the check of the rewritten repository against its
+	// state before the rewrite
+	Verification *GitHistoryVerification `json:"verification,omitempty"`
+	// HandoffPaths are the Markdown and JSON handoff reports for collaborators
+	HandoffPaths []string `json:"handoff_paths,omitempty"`
 }
 
 // IsValid validates the GitHistoryRewriteResult.
@@ -108,6 +227,120 @@ func (r GitHistoryRewriteResult) IsValid() bool {
 	return !r.ExecutedAt.IsZero()
 }
 
+// GitHistoryVerification compares a repository after a history rewrite with
+// its state before.
+type GitHistoryVerification struct {
+	// CommitsBefore is the number of commits reachable from any ref before
+	CommitsBefore int `json:"commits_before"`
+	// CommitsAfter is the number of commits reachable from any ref after
+	CommitsAfter int `json:"commits_after"`
+	// IntendedChanges are the HEAD paths removed, migrated to LFS or redacted
+	IntendedChanges []string `json:"intended_changes,omitempty"`
+	// UnexpectedChanges are the other HEAD paths that changed
+	UnexpectedChanges []string `json:"unexpected_changes,omitempty"`
+	// MissingRefs are the branches and tags that no longer exist
+	MissingRefs []string `json:"missing_refs,omitempty"`
+	// ReachableBlobs are the selected blobs still reachable from a ref
+	ReachableBlobs []string `json:"reachable_blobs,omitempty"`
+	// FsckPassed indicates if git fsck found no errors
+	FsckPassed bool `json:"fsck_passed"`
+	// FsckOutput is the output of a failed git fsck
+	FsckOutput string `json:"fsck_output,omitempty"`
+}
+
+// Problems returns a description of everything the verification found
+// wrong; none means the rewrite changed only what was intended.
+func (v GitHistoryVerification) Problems() []string {
+	var problems []string
+
+	for _, p := range v.UnexpectedChanges {
+		problems = append(problems, "unexpected change in HEAD: "+p)
+	}
+
+	for _, r := range v.MissingRefs {
+		problems = append(problems, "ref disappeared: "+r)
+	}
+
+	for _, b := range v.ReachableBlobs {
+		problems = append(problems, "blob still reachable: "+b)
+	}
+
+	if !v.FsckPassed {
+		problems = append(problems, "git fsck failed: "+v.FsckOutput)
+	}
+
+	return problems
+}
+
+// OK returns true if the verification found no problems.
+func (v GitHistoryVerification) OK() bool {
+	return len(v.Problems()) == 0
+}
+
+// GitHistoryCommitMapping is a commit rewritten by a history rewrite.
+type GitHistoryCommitMapping struct {
+	// Old is the commit before the rewrite
+	Old string `json:"old"`
+	// New is the commit it became, empty if the rewrite dropped it
+	New string `json:"new,omitempty"`
+}
+
+// GitHistoryRefUpdate is a branch or tag a history rewrite moved.
+type GitHistoryRefUpdate struct {
+	// Ref is the full ref name, e.g. refs/heads/main
+	Ref string `json:"ref"`
+	// Old is the object the ref pointed to before the rewrite
+	Old string `json:"old"`
+	// New is the object the ref points to after the rewrite
+	New string `json:"new"`
+}
+
+// GitHistoryHandoff is the report handed to collaborators after a history
+// rewrite: what changed, what to force-push and how to re-sync.
+type GitHistoryHandoff struct {
+	// RepoPath is the rewritten repository
+	RepoPath string `json:"repo_path"`
+	// Action is what the rewrite did with the selected files
+	Action GitHistoryAction `json:"action"`
+	// Files are the files removed or migrated to LFS
+	Files []string `json:"files"`
+	// RemovePaths are the directory prefixes and globs removed from history
+	RemovePaths []string `json:"remove_paths,omitempty"`
+	// GeneratedAt is when the report was made
+	GeneratedAt time.Time `json:"generated_at"`
+	// BackupPath is the backup made before the rewrite
+	BackupPath string `json:"backup_path,omitempty"`
+	// Remote is the remote to force-push to
+	Remote string `json:"remote,omitempty"`
+	// RemoteURL is the URL of Remote
+	RemoteURL string `json:"remote_url,omitempty"`
+	// Refs are the branches and tags that need force-pushing
+	Refs []GitHistoryRefUpdate `json:"refs"`
+	// Commits map the rewritten commits to their new hashes
+	Commits []GitHistoryCommitMapping `json:"commits"`
+	// MaintainerCommands publish the rewrite
+	MaintainerCommands []string `json:"maintainer_commands"`
+	// CollaboratorCommands re-sync an existing clone with the rewrite
+	CollaboratorCommands []string `json:"collaborator_commands"`
+	// Verification is the check of the rewritten repository
+	Verification GitHistoryVerification `json:"verification"`
+}
+
+// GitHistoryBackup is a mirror backup made before a history rewrite.
+type GitHistoryBackup struct {
+	// RepoPath is the repository that was backed up
+	RepoPath string `json:"repo_path"`
+	// Path is the backup, a bare mirror clone
+	Path string `json:"path"`
+	// CreatedAt is when the backup was made
+	CreatedAt time.Time `json:"created_at"`
+	// Action is the rewrite the backup was made for
+	Action GitHistoryAction `json:"action"`
+	// Remotes are the URLs of the repository's remotes, which
+	// git-filter-repo removes
+	Remotes map[string]string `json:"remotes,omitempty"`
+}
+
 // GitHistorySafetyReport contains safety check results.
 type GitHistorySafetyReport struct {
 	// IsGitRepo indicates if the path is a git repository
@@ -132,6 +365,10 @@ type GitHistorySafetyReport struct {
 	FilterRepoAvailable bool `json:"filter_repo_available"`
 	// FilterRepoProvider indicates how git-filter-repo is available ("system", "nix", or "")
 	FilterRepoProvider string `json:"filter_repo_provider,omitempty"`
+	// Action is the rewrite the checks were run for
+	Action GitHistoryAction `json:"action"`
+	// LFSInstalled indicates if the git lfs extension is installed
+	LFSInstalled bool `json:"lfs_installed"`
 	// HasLFS indicates if Git LFS is configured for this repository
 	HasLFS bool `json:"has_lfs"`
 	// HasSubmodules indicates if the repository contains submodules
@@ -146,9 +383,47 @@ type GitHistorySafetyReport struct {
 	Blockers []string `json:"blockers,omitempty"`
 }
 
-// CanProceed returns true if there are no blockers.
+// CanProceed returns true if there are no blockers and the tool the action
+// rewrites history with is installed.
 func (r GitHistorySafetyReport) CanProceed() bool {
-	return r.IsGitRepo && !r.HasUncommittedChanges && r.FilterRepoAvailable && len(r.Blockers) == 0
+	toolAvailable := r.FilterRepoAvailable
+	if r.Action == GitHistoryActionLFS {
+		toolAvailable = r.LFSInstalled
+	}
+
+	return r.IsGitRepo && !r.HasUncommittedChanges && toolAvailable && len(r.Blockers) == 0
+}
+
+// GitHistoryAction is what a history rewrite does with the selected files.
+//
+//nolint:recvcheck
+type GitHistoryAction int
+
+const (
+	// GitHistoryActionRemove deletes the files from every commit with git-filter-repo.
+	GitHistoryActionRemove GitHistoryAction = iota
+	// GitHistoryActionLFS replaces the files with Git LFS pointers in every
+	// commit with git lfs migrate import, keeping their content in LFS.
+	GitHistoryActionLFS
+	// GitHistoryActionPurge replaces the secrets found in the files with
+	// ***REMOVED*** in every commit with git-filter-repo --replace-text.
+	GitHistoryActionPurge
+)
+
+var gitHistoryActionStrings = []string{"remove", "lfs", "purge"} //nolint:gochecknoglobals
+
+func (a GitHistoryAction) String() string { return EnumString(a, gitHistoryActionStrings) }
+func (a GitHistoryAction) IsValid() bool  { return EnumIsValid(a, GitHistoryActionPurge) }
+func (a GitHistoryAction) Values() []GitHistoryAction {
+	return EnumValues[GitHistoryAction](GitHistoryActionPurge)
+}
+
+func (a GitHistoryAction) MarshalJSON() ([]byte, error) {
+	return EnumMarshalJSON(a, gitHistoryActionStrings)
+}
+
+func (a *GitHistoryAction) UnmarshalJSON(data []byte) error {
+	return EnumUnmarshalJSON(data, (*int)(a), gitHistoryActionStrings, "git history action")
 }
 
 // GitHistoryMode represents the mode of operation for git history cleaning.
@@ -200,6 +475,9 @@ type GitHistorySettings struct {
 	IncludeExtensions []string `json:"include_extensions,omitempty" yaml:"include_extensions,omitempty"`
 	// ExcludePaths are path patterns to exclude
 	ExcludePaths []string `json:"exclude_paths,omitempty" yaml:"exclude_paths,omitempty"`
+	// RemovePaths are directory prefixes (vendor/) and globs (reports/*.txt)
+	// removed from all history regardless of blob size
+	RemovePaths []string `json:"remove_paths,omitempty" yaml:"remove_paths,omitempty"`
 	// CreateBackup indicates if a backup should be created before rewrite
 	CreateBackup bool `json:"create_backup,omitempty" yaml:"create_backup,omitempty"`
 	// SkipConfirmation skips the interactive confirmation (dangerous)
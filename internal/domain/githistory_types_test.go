@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+func TestGitHistorySecretRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    GitHistorySecretRule
+		wantErr bool
+	}{
+		{name: "valid", rule: GitHistorySecretRule{Name: "db-password", Pattern: `DB_PASSWORD=(\S+)`}, wantErr: false},
+		{name: "uppercase name", rule: GitHistorySecretRule{Name: "DB", Pattern: `x+`}, wantErr: true},
+		{name: "bad pattern", rule: GitHistorySecretRule{Name: "db", Pattern: `(x`}, wantErr: true},
+		{name: "matches empty", rule: GitHistorySecretRule{Name: "db", Pattern: `x*`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.rule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultGitHistorySecretRules_Valid(t *testing.T) {
+	t.Parallel()
+
+	for _, rule := range DefaultGitHistorySecretRules {
+		if err := rule.Validate(); err != nil {
+			t.Errorf("built-in rule %s: %v", rule.Name, err)
+		}
+	}
+}
+
+func TestConfig_ValidateSecretRules(t *testing.T) {
+	t.Parallel()
+
+	c := Config{SecretRules: []GitHistorySecretRule{{Name: "github-token", Pattern: `ghp_\w+`}}} //nolint:exhaustruct
+
+	if err := c.validateSecretRules(); err == nil {
+		t.Error("validateSecretRules() accepted a rule named like a built-in rule")
+	}
+}
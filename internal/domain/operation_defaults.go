@@ -22,6 +22,10 @@ var settingsFactory = map[OperationType]func() *OperationSettings{ //nolint:goch
 	OperationTypeCompiledBinaries:             defaultCompiledBinariesSettings,
 	OperationTypeGitHistory:                   func() *OperationSettings { return &OperationSettings{GitHistory: &GitHistorySettings{}} }, //nolint:exhaustruct
 	OperationTypeGolangciLintCache:            func() *OperationSettings { return &OperationSettings{} },                                  //nolint:exhaustruct
+	OperationTypeNixGCRoots: func() *OperationSettings {
+		return &OperationSettings{NixGCRoots: &NixGCRootsSettings{OlderThan: "30d"}}
+	}, //nolint:exhaustruct
+	OperationTypeNixOptimise: defaultNixOptimiseSettings,
 }
 
 // DefaultSettings returns default settings for given operation type.
@@ -51,6 +55,15 @@ func defaultNixGenerationsSettings() *OperationSettings {
 	}
 }
 
+// defaultNixOptimiseSettings enables the store optimisation at most weekly.
+func defaultNixOptimiseSettings() *OperationSettings {
+	settings := defaultNixGenerationsSettings()
+	settings.NixGenerations.Optimize = OptimizationModeEnabled
+	settings.NixGenerations.OptimizeEvery = "7d"
+
+	return settings
+}
+
 func defaultTempFilesSettings() *OperationSettings {
 	return &OperationSettings{ //nolint:exhaustruct
 		TempFiles: &TempFilesSettings{
@@ -43,13 +43,38 @@ type OperationSettings struct {
 
 	// Git History Settings
 	GitHistory *GitHistorySettings `json:"git_history,omitempty" yaml:"git_history,omitempty"`
+
+	// Nix GC Roots Settings
+	NixGCRoots *NixGCRootsSettings `json:"nix_gcroots,omitempty" yaml:"nix_gcroots,omitempty"`
 }
 
 // NixGenerationsSettings provides type-safe settings for Nix generations cleanup.
+// Retention applies to each profile on its own: a generation is removed only
+// when it is not current, not among the KeepLast newest and not newer than
+// KeepNewerThan.
 type NixGenerationsSettings struct {
 	Generations int              `json:"generations"       yaml:"generations"`
 	Optimize    OptimizationMode `json:"optimize"          yaml:"optimize"`
 	DryRun      ExecutionMode    `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+	// KeepLast is the number of newest generations kept per profile;
+	// Generations is used when it is 0.
+	KeepLast int `json:"keep_last,omitempty" yaml:"keep_last,omitempty"`
+	// KeepNewerThan keeps generations younger than this duration ("14d").
+	KeepNewerThan string `json:"keep_newer_than,omitempty" yaml:"keep_newer_than,omitempty"`
+	// Profiles are extra profile links to clean besides the discovered ones.
+	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	// OptimizeEvery is how long after the last store optimisation the next
+	// one runs when Optimize is enabled ("7d"); empty keeps the weekly default
+	// and "0" runs it after every GC.
+	OptimizeEvery string `json:"optimize_every,omitempty" yaml:"optimize_every,omitempty"`
+}
+
+// NixGCRootsSettings provides type-safe settings for removing stale
+// indirect Nix GC roots.
+type NixGCRootsSettings struct {
+	// OlderThan is how long a project directory and its root link must be
+	// untouched before the root counts as stale ("30d").
+	OlderThan string `json:"older_than" yaml:"older_than"`
 }
 
 // TempFilesSettings provides type-safe settings for temporary files cleanup.
@@ -154,6 +179,8 @@ const (
 	OperationTypeCompiledBinaries             OperationType = "compiled-binaries"
 	OperationTypeGitHistory                   OperationType = "git-history"
 	OperationTypeGolangciLintCache            OperationType = "golangci-lint-cache"
+	OperationTypeNixGCRoots                   OperationType = "nix-gcroots"
+	OperationTypeNixOptimise                  OperationType = "nix-optimise"
 )
 
 // nameToOperationType maps operation names to their corresponding OperationType.
@@ -173,6 +200,8 @@ var nameToOperationType = map[string]OperationType{ //nolint:gochecknoglobals
 	"compiled-binaries":              OperationTypeCompiledBinaries,
 	"git-history":                    OperationTypeGitHistory,
 	"golangci-lint-cache":            OperationTypeGolangciLintCache,
+	"nix-gcroots":                    OperationTypeNixGCRoots,
+	"nix-optimise":                   OperationTypeNixOptimise,
 }
 
 // GetOperationType returns the operation type from operation name.
@@ -206,7 +235,9 @@ func (ot OperationType) IsValid() bool {
 		OperationTypeProjectExecutables,
 		OperationTypeCompiledBinaries,
 		OperationTypeGitHistory,
-		OperationTypeGolangciLintCache:
+		OperationTypeGolangciLintCache,
+		OperationTypeNixGCRoots,
+		OperationTypeNixOptimise:
 		return true
 	default:
 		return false
@@ -231,5 +262,7 @@ func (OperationType) Values() []OperationType {
 		OperationTypeCompiledBinaries,
 		OperationTypeGitHistory,
 		OperationTypeGolangciLintCache,
+		OperationTypeNixGCRoots,
+		OperationTypeNixOptimise,
 	}
 }
@@ -63,8 +63,10 @@ func (os *OperationSettings) ValidateSettings(opType OperationType) error {
 	}
 
 	switch opType {
-	case OperationTypeNixGenerations:
+	case OperationTypeNixGenerations, OperationTypeNixOptimise:
 		return os.validateNixGenerationsSettings()
+	case OperationTypeNixGCRoots:
+		return os.validateNixGCRootsSettings()
 	case OperationTypeTempFiles:
 		return os.validateTempFilesSettings()
 	case OperationTypeHomebrew:
@@ -105,6 +107,50 @@ func (os *OperationSettings) validateNixGenerationsSettings() error {
 		}
 	}
 
+	if os.NixGenerations.KeepLast < 0 || os.NixGenerations.KeepLast > 10 {
+		return &ValidationError{ //nolint:exhaustruct
+			Field:   "nix_generations.keep_last",
+			Message: "keep_last must be between 0 and 10 (0 = use generations)",
+			Value:   os.NixGenerations.KeepLast,
+		}
+	}
+
+	if os.NixGenerations.KeepNewerThan != "" {
+		if _, err := ParseCustomDuration(os.NixGenerations.KeepNewerThan); err != nil {
+			return &ValidationError{ //nolint:exhaustruct
+				Field:   "nix_generations.keep_newer_than",
+				Message: "keep_newer_than must be a valid duration (e.g., '14d', '72h')",
+				Value:   os.NixGenerations.KeepNewerThan,
+			}
+		}
+	}
+
+	if os.NixGenerations.OptimizeEvery != "" {
+		if _, err := ParseCustomDuration(os.NixGenerations.OptimizeEvery); err != nil {
+			return &ValidationError{ //nolint:exhaustruct
+				Field:   "nix_generations.optimize_every",
+				Message: "optimize_every must be a valid duration (e.g., '7d', '72h')",
+				Value:   os.NixGenerations.OptimizeEvery,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (os *OperationSettings) validateNixGCRootsSettings() error {
+	if os.NixGCRoots == nil || os.NixGCRoots.OlderThan == "" {
+		return nil
+	}
+
+	if _, err := ParseCustomDuration(os.NixGCRoots.OlderThan); err != nil {
+		return &ValidationError{ //nolint:exhaustruct
+			Field:   "nix_gcroots.older_than",
+			Message: "older_than must be a valid duration (e.g., '30d', '72h')",
+			Value:   os.NixGCRoots.OlderThan,
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceClass names the resource a cleaner mostly waits on. The workflow
+// scheduler limits how many cleaners of one class run at the same time.
+//
+//nolint:recvcheck
+type ResourceClass int
+
+const (
+	// ResourceClassDiskIO covers cleaners that walk and delete large file trees.
+	ResourceClassDiskIO ResourceClass = iota
+	// ResourceClassNetwork covers cleaners that talk to a daemon or the network
+	// (Docker, Nix daemon, Homebrew) and mostly wait on it.
+	ResourceClassNetwork
+	// ResourceClassCPU covers cleaners dominated by computation in a child process.
+	ResourceClassCPU
+)
+
+var resourceClassStrings = []string{"DISK_IO", "NETWORK", "CPU"} //nolint:gochecknoglobals
+
+func (c ResourceClass) String() string { return EnumString(c, resourceClassStrings) }
+func (c ResourceClass) IsValid() bool  { return EnumIsValid(c, ResourceClassCPU) }
+func (c ResourceClass) Values() []ResourceClass {
+	return EnumValues[ResourceClass](ResourceClassCPU)
+}
+
+func (c ResourceClass) MarshalJSON() ([]byte, error) {
+	return EnumMarshalJSON(c, resourceClassStrings)
+}
+
+func (c *ResourceClass) UnmarshalJSON(data []byte) error {
+	return EnumUnmarshalJSON(data, (*int)(c), resourceClassStrings, "resource class")
+}
+
+func (c ResourceClass) MarshalYAML() (any, error) {
+	return EnumMarshalYAML(c, resourceClassStrings)
+}
+
+func (c *ResourceClass) UnmarshalYAML(value *yaml.Node) error {
+	return EnumUnmarshalYAML(value, (*int)(c), resourceClassStrings, "resource class")
+}
+
+// ParseResourceClass parses a resource class name case-insensitively,
+// accepting dashes for underscores ("disk-io") and "daemon" for NETWORK.
+func ParseResourceClass(s string) (ResourceClass, error) {
+	normalized := strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(s)), "-", "_")
+	if normalized == "DAEMON" {
+		return ResourceClassNetwork, nil
+	}
+
+	for i, name := range resourceClassStrings {
+		if name == normalized {
+			return ResourceClass(i), nil
+		}
+	}
+
+	return ResourceClassDiskIO, fmt.Errorf(
+		"invalid resource class %q (must be DISK_IO, NETWORK, or CPU)", s,
+	)
+}
+
+// validateResourceOverrides checks the optional resource_class and timeout
+// fields shared by profile operations and custom cleaners.
+func validateResourceOverrides(resourceClass, timeout string) error {
+	if resourceClass != "" {
+		if _, err := ParseResourceClass(resourceClass); err != nil {
+			return err
+		}
+	}
+
+	if timeout != "" {
+		d, err := ParseCustomDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+	}
+
+	return nil
+}
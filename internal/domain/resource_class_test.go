@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+func TestParseResourceClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    ResourceClass
+		wantErr bool
+	}{
+		{input: "DISK_IO", want: ResourceClassDiskIO},
+		{input: "disk-io", want: ResourceClassDiskIO},
+		{input: "network", want: ResourceClassNetwork},
+		{input: "daemon", want: ResourceClassNetwork},
+		{input: " cpu ", want: ResourceClassCPU},
+		{input: "gpu", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseResourceClass(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseResourceClass(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+
+			continue
+		}
+
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseResourceClass(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCleanupOperation_ValidateResourceOverrides(t *testing.T) {
+	t.Parallel()
+
+	op := CleanupOperation{
+		Name:          "docker",
+		Description:   "Docker cleanup",
+		RiskLevel:     RiskLow,
+		Enabled:       ProfileStatusEnabled,
+		ResourceClass: "network",
+		Timeout:       "10m",
+	}
+	if err := op.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	op.Timeout = "soon"
+	if err := op.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid timeout")
+	}
+
+	op.Timeout = ""
+	op.ResourceClass = "gpu"
+	if err := op.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown resource class")
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// ReasonKind classifies why a cleaner selected a scan item.
+type ReasonKind string
+
+const (
+	// ReasonLocation: the item is a location the cleaner manages, such as a
+	// cache directory.
+	ReasonLocation ReasonKind = "location"
+	// ReasonRule: the item matched a configured pattern or rule.
+	ReasonRule ReasonKind = "rule"
+	// ReasonAge: the item is older than the configured threshold.
+	ReasonAge ReasonKind = "age"
+	// ReasonSize: the item is at least the configured minimum size.
+	ReasonSize ReasonKind = "size"
+	// ReasonCategory: the item was classified into a selected category.
+	ReasonCategory ReasonKind = "category"
+	// ReasonRetention: a retention policy decided to remove or keep the item.
+	ReasonRetention ReasonKind = "retention"
+	// ReasonRisk: the risk level declared for removing the item.
+	ReasonRisk ReasonKind = "risk"
+)
+
+// SelectionReason is one structured reason for selecting a scan item.
+// Actual and Threshold carry the compared values where there are any.
+type SelectionReason struct {
+	Kind      ReasonKind `json:"kind"`
+	Message   string     `json:"message"`
+	Rule      string     `json:"rule,omitempty"`
+	Actual    string     `json:"actual,omitempty"`
+	Threshold string     `json:"threshold,omitempty"`
+}
+
+// String formats the reason for display.
+func (r SelectionReason) String() string {
+	var b strings.Builder
+
+	b.WriteString(string(r.Kind))
+	b.WriteString(": ")
+	b.WriteString(r.Message)
+
+	switch {
+	case r.Actual != "" && r.Threshold != "":
+		b.WriteString(" (" + r.Actual + " vs " + r.Threshold + ")")
+	case r.Actual != "":
+		b.WriteString(" (" + r.Actual + ")")
+	}
+
+	if r.Rule != "" {
+		b.WriteString(" [" + r.Rule + "]")
+	}
+
+	return b.String()
+}
@@ -59,6 +59,9 @@ type NixGeneration struct {
 	Path    string           `json:"path"`
 	Date    time.Time        `json:"date"`
 	Current GenerationStatus `json:"current"`
+	// Profile is the profile link the generation belongs to, e.g.
+	// /nix/var/nix/profiles/system; Path is its "-<id>-link".
+	Profile string `json:"profile,omitempty"`
 }
 
 // IsValid validates generation.
@@ -154,10 +157,26 @@ func (sr ScanRequest) Validate() error {
 
 // ScanItem represents item found during scanning.
 type ScanItem struct {
-	Path     string    `json:"path"`
-	Size     int64     `json:"size"`
-	Created  time.Time `json:"created"`
-	ScanType ScanType  `json:"scan_type"`
+	Path string `json:"path"`
+	// Size is the apparent size (sum of file lengths, hardlinks counted once).
+	Size int64 `json:"size"`
+	// OnDiskSize is the allocated size (st_blocks), with hardlinks shared
+	// with items measured earlier in the run counted there; 0 if unknown.
+	OnDiskSize int64     `json:"on_disk_size,omitempty"`
+	Created    time.Time `json:"created"`
+	ScanType   ScanType  `json:"scan_type"`
+	// Reasons explain why the cleaner selected the item.
+	Reasons []SelectionReason `json:"reasons,omitempty"`
+}
+
+// DiskUsage returns the bytes the item occupies on disk, falling back to
+// the apparent size when the on-disk size was not measured.
+func (si ScanItem) DiskUsage() int64 {
+	if si.OnDiskSize > 0 {
+		return si.OnDiskSize
+	}
+
+	return si.Size
 }
 
 // CleanRequest represents cleaning command.
@@ -249,13 +268,17 @@ func (se SizeEstimate) String() string {
 
 // CleanResult represents successful clean outcome.
 type CleanResult struct {
-	SizeEstimate SizeEstimate      `json:"size_estimate"`
-	FreedBytes   uint64            `json:"freed_bytes"` // Deprecated: Use SizeEstimate instead
-	ItemsRemoved uint              `json:"items_removed"`
-	ItemsFailed  uint              `json:"items_failed"`
-	CleanTime    time.Duration     `json:"clean_time"`
-	CleanedAt    time.Time         `json:"cleaned_at"`
-	Strategy     CleanStrategyType `json:"strategy"`
+	SizeEstimate SizeEstimate `json:"size_estimate"`
+	FreedBytes   uint64       `json:"freed_bytes"` // Deprecated: Use SizeEstimate instead
+	// MeasuredFreedBytes is the growth of free space on the affected
+	// filesystems while the cleaner ran, as sampled by the execution layer;
+	// nil when not measured. It may be negative if other writers filled the disk.
+	MeasuredFreedBytes *int64            `json:"measured_freed_bytes,omitempty"`
+	ItemsRemoved       uint              `json:"items_removed"`
+	ItemsFailed        uint              `json:"items_failed"`
+	CleanTime          time.Duration     `json:"clean_time"`
+	CleanedAt          time.Time         `json:"cleaned_at"`
+	Strategy           CleanStrategyType `json:"strategy"`
 }
 
 // IsValid checks if clean result is valid.
@@ -15,19 +15,34 @@ import (
 type CompiledWorkflow struct {
 	Workflow  *flow.Workflow
 	Collector *resultCollector
+
+	plans map[string]stepPlan
 }
 
 // Builder compiles a cleaner registry into a go-workflow DAG.
 // It is DI-agnostic — it receives a *cleaner.Registry and selected names
 // as plain parameters, matching BuildFlow's execution package design.
 type Builder struct {
-	verbose bool
-	retry   *RetryConfig
+	verbose      bool
+	retry        *RetryConfig
+	dependencies map[string]cleaner.Dependencies
+	satisfied    []string
+	resources    map[string]cleaner.ResourceOverride
+	classLimits  map[domain.ResourceClass]int
+	meter        *spaceMeter
 }
 
 // NewBuilder creates a Builder with the given options.
 func NewBuilder(verbose bool) *Builder {
-	return &Builder{verbose: verbose, retry: nil}
+	return &Builder{
+		verbose:      verbose,
+		retry:        nil,
+		dependencies: nil,
+		satisfied:    nil,
+		resources:    nil,
+		classLimits:  nil,
+		meter:        nil,
+	}
 }
 
 // WithRetryConfig enables per-step retry on the builder.
@@ -37,10 +52,62 @@ func (b *Builder) WithRetryConfig(cfg *RetryConfig) *Builder {
 	return b
 }
 
+// WithDependencies adds ordering constraints (keyed by cleaner name) on top of
+// those declared by the cleaners themselves, e.g. from a profile.
+func (b *Builder) WithDependencies(deps map[string]cleaner.Dependencies) *Builder {
+	b.dependencies = deps
+
+	return b
+}
+
+// WithResourceOverrides replaces the resource class or timeout of cleaners
+// (keyed by cleaner name), e.g. from a profile.
+func (b *Builder) WithResourceOverrides(overrides map[string]cleaner.ResourceOverride) *Builder {
+	b.resources = overrides
+
+	return b
+}
+
+// WithClassLimits sets per-resource-class concurrency limits on top of
+// DefaultClassLimits; 0 means unlimited.
+func (b *Builder) WithClassLimits(limits map[domain.ResourceClass]int) *Builder {
+	b.classLimits = limits
+
+	return b
+}
+
+// WithSpaceMeasurement makes clean steps sample free space on their
+// filesystems before and after running and report the difference as
+// CleanResult.MeasuredFreedBytes. accurate serializes steps that share a
+// filesystem so that their measurements do not include each other.
+func (b *Builder) WithSpaceMeasurement(accurate bool) *Builder {
+	b.meter = newSpaceMeter(accurate)
+
+	return b
+}
+
+// WithSatisfied marks cleaners that already succeeded in an earlier run (see
+// Checkpoint). Requirements on them count as met although they are not
+// selected again.
+func (b *Builder) WithSatisfied(names []string) *Builder {
+	b.satisfied = names
+
+	return b
+}
+
 // BuildClean compiles a clean workflow from the given registry and selected cleaner names.
-// Each selected cleaner becomes a parallel flow.FuncIO step with BeforeStep/AfterStep hooks.
+// Each selected cleaner becomes a flow.FuncIO step with BeforeStep/AfterStep hooks.
+// Steps run in parallel unless their declared dependencies (see cleaner.Dependencies)
+// order them; dependency cycles and unsatisfiable requirements are rejected here.
+// Each step runs under its cleaner's timeout and waits for a slot of its
+// resource class (see cleaner.Resources).
 func (b *Builder) BuildClean(registry *cleaner.Registry, selected []string) (*CompiledWorkflow, error) {
 	collector := newResultCollector()
+	if b.retry != nil {
+		collector.maxAttempts = b.retry.MaxAttempts
+	}
+
+	limiter := newClassLimiter(b.classLimits)
 	wf := &flow.Workflow{
 		DontPanic: true,
 	}
@@ -48,6 +115,9 @@ func (b *Builder) BuildClean(registry *cleaner.Registry, selected []string) (*Co
 	before := makeBeforeHook(b.verbose)
 	after := makeAfterHook(b.verbose)
 
+	steps := make(map[string]flow.Steper, len(selected))
+	deps := make(map[string]cleaner.Dependencies, len(selected))
+
 	for i, name := range selected {
 		c, ok := registry.Get(name)
 		if !ok {
@@ -59,15 +129,43 @@ func (b *Builder) BuildClean(registry *cleaner.Registry, selected []string) (*Co
 
 		collector.register(name, i)
 
-		step := flow.FuncIO(
+		steps[name] = flow.FuncIO(
 			name,
-			makeCleanStepFunc(name, c, collector),
+			makeCleanStepFunc(name, c, b.resourcesFor(name, c), limiter, b.meter, collector),
 		)
+		deps[name] = withoutSatisfied(
+			cleaner.DependenciesFor(name, c).Merge(b.dependencies[name]),
+			b.satisfied,
+		)
+	}
+
+	plans, err := planDependencies(selected, deps)
+	if err != nil {
+		return nil, err
+	}
 
-		stepBuilder := flow.Step(step).
+	for _, name := range selected {
+		stepBuilder := flow.Step(steps[name]).
 			BeforeStep(before).
 			AfterStep(after)
 
+		if plan := plans[name]; len(plan.upstream) > 0 {
+			ups := make([]flow.Steper, 0, len(plan.upstream))
+			required := make(map[flow.Steper]string, len(plan.required))
+
+			for _, up := range plan.upstream {
+				ups = append(ups, steps[up])
+
+				if plan.required[up] {
+					required[steps[up]] = up
+				}
+			}
+
+			stepBuilder = stepBuilder.
+				DependsOn(ups...).
+				When(requirementCondition(name, required, collector))
+		}
+
 		if b.retry != nil {
 			if opts := retryOptions(*b.retry); len(opts) > 0 {
 				stepBuilder = stepBuilder.Retry(opts...)
@@ -80,13 +178,16 @@ func (b *Builder) BuildClean(registry *cleaner.Registry, selected []string) (*Co
 	return &CompiledWorkflow{
 		Workflow:  wf,
 		Collector: collector,
+		plans:     plans,
 	}, nil
 }
 
 // BuildScan compiles a scan workflow from the given registry and selected cleaner names.
-// Each selected cleaner becomes a parallel flow.FuncIO step.
+// Each selected cleaner becomes a parallel flow.FuncIO step, limited by
+// resource class and timeout like BuildClean.
 func (b *Builder) BuildScan(registry *cleaner.Registry, selected []string) (*CompiledWorkflow, error) {
 	collector := newResultCollector()
+	limiter := newClassLimiter(b.classLimits)
 	wf := &flow.Workflow{
 		DontPanic: true,
 	}
@@ -104,7 +205,7 @@ func (b *Builder) BuildScan(registry *cleaner.Registry, selected []string) (*Com
 
 		step := flow.FuncIO(
 			name,
-			makeScanStepFunc(name, c, collector),
+			makeScanStepFunc(name, c, b.resourcesFor(name, c), limiter, collector),
 		)
 
 		wf.Add(flow.Step(step))
@@ -113,20 +214,29 @@ func (b *Builder) BuildScan(registry *cleaner.Registry, selected []string) (*Com
 	return &CompiledWorkflow{
 		Workflow:  wf,
 		Collector: collector,
+		plans:     nil,
 	}, nil
 }
 
+// resourcesFor returns the cleaner's resources with any override applied.
+func (b *Builder) resourcesFor(name string, c cleaner.Cleaner) cleaner.Resources {
+	return cleaner.ResourcesFor(name, c).Apply(b.resources[name])
+}
+
 // makeCleanStepFunc creates a step function that wraps a cleaner's Clean method,
 // recording the result in the collector. Panics are recovered and recorded as
 // errors so that a panicking cleaner doesn't silently disappear from results.
 //
-// IMPORTANT: The collector.record() call is in the defer block, NOT in the
-// function body. This ensures only the FINAL outcome is recorded — when
-// go-workflow retries, only the last attempt's result is kept, preventing
-// duplicate entries in the WorkflowResult.
+// IMPORTANT: The collector.recordFinal() call is in the defer block, NOT in
+// the function body. Every attempt is recorded there, but when go-workflow
+// retries only the last attempt's result is kept and reported to observers,
+// preventing duplicate entries in the WorkflowResult.
 func makeCleanStepFunc(
 	name string,
 	c cleaner.Cleaner,
+	res cleaner.Resources,
+	limiter *classLimiter,
+	meter *spaceMeter,
 	collector *resultCollector,
 ) func(context.Context, struct{}) (domain.CleanResult, error) {
 	return func(ctx context.Context, _ struct{}) (result domain.CleanResult, err error) {
@@ -137,27 +247,54 @@ func makeCleanStepFunc(
 
 			if r := recover(); r != nil {
 				panicErr := fmt.Errorf("cleaner %s panicked: %v", name, r)
-				collector.recordFinal(name, domain.CleanResult{}, panicErr, duration)
+				collector.recordFinal(ctx, name, domain.CleanResult{}, panicErr, duration)
 				err = panicErr
 
 				return
 			}
 
 			if err != nil {
-				collector.recordFinal(name, domain.CleanResult{}, err, duration)
+				collector.recordFinal(ctx, name, domain.CleanResult{}, err, duration)
 
 				return
 			}
 
-			collector.recordFinal(name, result, nil, duration)
+			collector.recordFinal(ctx, name, result, nil, duration)
 		}()
 
-		res := c.Clean(ctx)
-		if res.IsErr() {
-			return domain.CleanResult{}, res.Error()
+		release, err := limiter.acquire(ctx, res.Class)
+		if err != nil {
+			return domain.CleanResult{}, err
+		}
+		defer release()
+
+		var sample *spaceSample
+		if meter != nil {
+			sample, err = meter.begin(ctx, cleaner.AffectedPaths(name, c))
+			if err != nil {
+				return domain.CleanResult{}, err
+			}
+			defer sample.release()
 		}
 
-		result = res.Value()
+		stepCtx, cancel := withStepTimeout(ctx, res.Timeout)
+		defer cancel()
+
+		cleanRes := c.Clean(stepCtx)
+
+		var measured *int64
+		if sample != nil {
+			if freed, ok := sample.end(); ok {
+				measured = &freed
+			}
+		}
+
+		if cleanRes.IsErr() {
+			return domain.CleanResult{}, classifyStepTimeout(ctx, stepCtx, name, res.Timeout, cleanRes.Error())
+		}
+
+		result = cleanRes.Value()
+		result.MeasuredFreedBytes = measured
 
 		return result, nil
 	}
@@ -169,6 +306,8 @@ func makeCleanStepFunc(
 func makeScanStepFunc(
 	name string,
 	c cleaner.Cleaner,
+	res cleaner.Resources,
+	limiter *classLimiter,
 	collector *resultCollector,
 ) func(context.Context, struct{}) ([]domain.ScanItem, error) {
 	return func(ctx context.Context, _ struct{}) (items []domain.ScanItem, err error) {
@@ -179,35 +318,50 @@ func makeScanStepFunc(
 
 			if r := recover(); r != nil {
 				panicErr := fmt.Errorf("scanner %s panicked: %v", name, r)
-				collector.recordFinal(name, domain.CleanResult{}, panicErr, duration)
+				collector.recordFinal(ctx, name, domain.CleanResult{}, panicErr, duration)
 				err = panicErr
 
 				return
 			}
 
 			if err != nil {
-				collector.recordFinal(name, domain.CleanResult{}, err, duration)
+				collector.recordFinal(ctx, name, domain.CleanResult{}, err, duration)
 
 				return
 			}
 
 			var totalSize uint64
 			for _, item := range items {
-				totalSize += uint64(item.Size)
+				totalSize += uint64(item.DiskUsage())
 			}
 
-			collector.recordFinal(name, domain.CleanResult{
-				FreedBytes:   totalSize,
-				ItemsRemoved: uint(len(items)),
-			}, nil, duration)
+			collector.recordStep(ctx, StepResult{
+				Name: name,
+				Clean: domain.CleanResult{
+					FreedBytes:   totalSize,
+					ItemsRemoved: uint(len(items)),
+				},
+				Err:      nil,
+				Duration: duration,
+				Items:    items,
+			})
 		}()
 
-		res := c.Scan(ctx)
-		if res.IsErr() {
-			return nil, res.Error()
+		release, err := limiter.acquire(ctx, res.Class)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		stepCtx, cancel := withStepTimeout(ctx, res.Timeout)
+		defer cancel()
+
+		scanRes := c.Scan(stepCtx)
+		if scanRes.IsErr() {
+			return nil, classifyStepTimeout(ctx, stepCtx, name, res.Timeout, scanRes.Error())
 		}
 
-		items = res.Value()
+		items = scanRes.Value()
 
 		return items, nil
 	}
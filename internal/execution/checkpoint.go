@@ -0,0 +1,274 @@
+package execution
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// CheckpointVersion is the on-disk format version of Checkpoint.
+const CheckpointVersion = 1
+
+// CheckpointFileName is the checkpoint file name inside the state directory.
+const CheckpointFileName = "clean-checkpoint.json"
+
+// Checkpoint is the persisted progress of a clean run. It is rewritten as
+// each step finishes, so after an interruption it lists exactly the steps
+// that still have to run. It holds no items: a cleaner selects its items
+// when its step runs, so a resumed run re-scans every pending cleaner
+// rather than trusting a list of paths that may have changed meanwhile.
+type Checkpoint struct {
+	Version   int       `json:"version"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Options is an opaque snapshot of the caller's run options, so that a
+	// resumed run can reuse them.
+	Options jsontext.Value `json:"options,omitempty"`
+
+	// Selected lists all cleaners of the run in selection order.
+	Selected []string `json:"selected"`
+	// Plan records the step ordering the run was compiled with; it is the
+	// only plan persisted, the items of pending steps are re-scanned.
+	Plan []CheckpointPlanStep `json:"plan,omitempty"`
+	// Completed holds the final result of every finished step.
+	Completed []CheckpointStep `json:"completed"`
+	// Pending lists the steps that have not finished yet.
+	Pending []string `json:"pending"`
+}
+
+// CheckpointPlanStep is one step of the compiled plan with its upstream steps.
+type CheckpointPlanStep struct {
+	Name     string   `json:"name"`
+	Upstream []string `json:"upstream,omitempty"`
+	Required []string `json:"required,omitempty"`
+}
+
+// CheckpointStep is the serializable form of a finished StepResult.
+type CheckpointStep struct {
+	Name               string                   `json:"name"`
+	FreedBytes         uint64                   `json:"freed_bytes"`
+	MeasuredFreedBytes *int64                   `json:"measured_freed_bytes,omitempty"`
+	ItemsRemoved       uint                     `json:"items_removed"`
+	ItemsFailed        uint                     `json:"items_failed"`
+	SizeEstimate       domain.SizeEstimate      `json:"size_estimate"`
+	Strategy           domain.CleanStrategyType `json:"strategy"`
+	CleanedAt          time.Time                `json:"cleaned_at"`
+	DurationMs         int64                    `json:"duration_ms"`
+	Error              string                   `json:"error,omitempty"`
+	Family             string                   `json:"family,omitempty"`
+	Code               string                   `json:"code,omitempty"`
+}
+
+// NewCheckpoint starts a checkpoint for a run of the selected cleaners.
+func NewCheckpoint(selected []string, options jsontext.Value) Checkpoint {
+	now := time.Now()
+
+	return Checkpoint{
+		Version:   CheckpointVersion,
+		StartedAt: now,
+		UpdatedAt: now,
+		Options:   options,
+		Selected:  slices.Clone(selected),
+		Plan:      nil,
+		Completed: []CheckpointStep{},
+		Pending:   slices.Clone(selected),
+	}
+}
+
+// LoadCheckpoint reads the checkpoint at path. A checkpoint written by an
+// incompatible version is reported as a Conflict.
+func LoadCheckpoint(path string) (Checkpoint, error) {
+	var cp Checkpoint
+
+	err := state.ReadJSON(path, &cp)
+	if err != nil {
+		return cp, err
+	}
+
+	if cp.Version != CheckpointVersion {
+		return cp, errorfamily.NewConflict(
+			"execution.checkpoint_version",
+			"checkpoint was written by an incompatible clean-wizard version",
+		)
+	}
+
+	return cp, nil
+}
+
+// Results converts the completed steps back into StepResults. Step errors
+// are restored with their original family and code.
+func (cp Checkpoint) Results() []StepResult {
+	results := make([]StepResult, 0, len(cp.Completed))
+
+	for _, s := range cp.Completed {
+		var err error
+		if s.Error != "" {
+			err = errorfamily.New(errorfamily.ParseFamily(s.Family), s.Code, s.Error)
+		}
+
+		results = append(results, StepResult{
+			Name: s.Name,
+			Clean: domain.CleanResult{
+				SizeEstimate:       s.SizeEstimate,
+				FreedBytes:         s.FreedBytes,
+				MeasuredFreedBytes: s.MeasuredFreedBytes,
+				ItemsRemoved:       s.ItemsRemoved,
+				ItemsFailed:        s.ItemsFailed,
+				CleanTime:          time.Duration(s.DurationMs) * time.Millisecond,
+				CleanedAt:          s.CleanedAt,
+				Strategy:           s.Strategy,
+			},
+			Err:      err,
+			Duration: time.Duration(s.DurationMs) * time.Millisecond,
+		})
+	}
+
+	return results
+}
+
+// Checkpointer keeps a Checkpoint in sync with a running workflow and
+// persists it after every finished step.
+type Checkpointer struct {
+	path string
+
+	mu  sync.Mutex
+	cp  Checkpoint
+	err error
+}
+
+// NewCheckpointer creates a Checkpointer persisting cp to path. For a
+// resumed run, cp is the loaded checkpoint: its completed steps are kept and
+// its pending steps are the ones being run.
+func NewCheckpointer(path string, cp Checkpoint) *Checkpointer {
+	return &Checkpointer{path: path, cp: cp} //nolint:exhaustruct
+}
+
+// Path returns the checkpoint file path.
+func (c *Checkpointer) Path() string {
+	return c.path
+}
+
+// Checkpoint returns a copy of the current checkpoint.
+func (c *Checkpointer) Checkpoint() Checkpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cp := c.cp
+	cp.Completed = slices.Clone(c.cp.Completed)
+	cp.Pending = slices.Clone(c.cp.Pending)
+
+	return cp
+}
+
+// Err returns the first error encountered while persisting the checkpoint.
+func (c *Checkpointer) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// begin records the compiled plan and writes the initial checkpoint.
+func (c *Checkpointer) begin(plans map[string]stepPlan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cp.Plan = make([]CheckpointPlanStep, 0, len(c.cp.Pending))
+	for _, name := range c.cp.Pending {
+		plan := plans[name]
+
+		var required []string
+
+		for _, up := range plan.upstream {
+			if plan.required[up] {
+				required = append(required, up)
+			}
+		}
+
+		c.cp.Plan = append(c.cp.Plan, CheckpointPlanStep{
+			Name:     name,
+			Upstream: slices.Clone(plan.upstream),
+			Required: required,
+		})
+	}
+
+	c.saveLocked()
+}
+
+// record moves a finished step from Pending to Completed and persists the
+// checkpoint. A step that ended because the run was interrupted (finished is
+// false) stays, or becomes again, pending.
+func (c *Checkpointer) record(step StepResult, finished bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := CheckpointStep{
+		Name:               step.Name,
+		FreedBytes:         step.Clean.FreedBytes,
+		MeasuredFreedBytes: step.Clean.MeasuredFreedBytes,
+		ItemsRemoved:       step.Clean.ItemsRemoved,
+		ItemsFailed:        step.Clean.ItemsFailed,
+		SizeEstimate:       step.Clean.SizeEstimate,
+		Strategy:           step.Clean.Strategy,
+		CleanedAt:          step.Clean.CleanedAt,
+		DurationMs:         step.Duration.Milliseconds(),
+		Error:              "",
+		Family:             "",
+		Code:               "",
+	}
+
+	if step.Err != nil {
+		entry.Error = step.Err.Error()
+		entry.Family = errorfamily.Classify(step.Err).String()
+		entry.Code = errorfamily.Code(step.Err)
+	}
+
+	c.cp.Completed = slices.DeleteFunc(c.cp.Completed, func(s CheckpointStep) bool { return s.Name == step.Name })
+	c.cp.Pending = slices.DeleteFunc(c.cp.Pending, func(name string) bool { return name == step.Name })
+
+	if finished {
+		c.cp.Completed = append(c.cp.Completed, entry)
+	} else {
+		c.cp.Pending = append(c.cp.Pending, step.Name)
+	}
+
+	c.saveLocked()
+}
+
+// finish removes the checkpoint file once nothing is pending; otherwise the
+// checkpoint is left in place for a later resume.
+func (c *Checkpointer) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.cp.Pending) > 0 {
+		c.saveLocked()
+
+		return
+	}
+
+	if err := state.Remove(c.path); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *Checkpointer) saveLocked() {
+	c.cp.UpdatedAt = time.Now()
+
+	if err := state.WriteJSON(c.path, c.cp); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// isCancellation reports whether err stems from context cancellation.
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
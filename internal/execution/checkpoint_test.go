@@ -0,0 +1,105 @@
+package execution
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// interruptingCleaner simulates Ctrl+C while it runs: it cancels the run's
+// context and returns the cancellation error.
+type interruptingCleaner struct {
+	recordingCleaner
+
+	cancel context.CancelFunc
+}
+
+func (c *interruptingCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	c.cancel()
+	<-ctx.Done()
+
+	return result.Err[domain.CleanResult](ctx.Err())
+}
+
+func TestRunCleaners_CheckpointInterruptAndResume(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), CheckpointFileName)
+	chain := map[string]cleaner.Dependencies{
+		"interrupting": {After: []string{"first"}},
+		"last":         {Requires: []string{"first"}, After: []string{"interrupting"}},
+	}
+	selected := []string{"first", "interrupting", "last"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "first", "last")
+	registry.Register("interrupting", &interruptingCleaner{
+		recordingCleaner: recordingCleaner{name: "interrupting", recorder: recorder},
+		cancel:           cancel,
+	})
+
+	wr, err := RunCleaners(ctx, registry, selected,
+		WithDependencies(chain),
+		WithCheckpoint(NewCheckpointer(path, NewCheckpoint(selected, nil))),
+	)
+	require.NoError(t, err)
+
+	assert.True(t, wr.Interrupted)
+	assert.Equal(t, []string{"interrupting", "last"}, wr.Pending)
+	require.Len(t, wr.Steps, 1)
+	assert.Equal(t, "first", wr.Steps[0].Name)
+
+	cp, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"interrupting", "last"}, cp.Pending)
+	require.Len(t, cp.Completed, 1)
+	assert.Equal(t, "first", cp.Completed[0].Name)
+	assert.Len(t, cp.Plan, 3)
+
+	// Resume: "last" requires "first", which already succeeded and is not
+	// selected again.
+	recorder = &orderRecorder{}
+	registry = newRecordingRegistry(recorder, "first", "interrupting", "last")
+
+	wr, err = RunCleaners(context.Background(), registry, cp.Pending,
+		WithDependencies(chain),
+		WithCheckpoint(NewCheckpointer(path, cp)),
+	)
+	require.NoError(t, err)
+
+	assert.False(t, wr.Interrupted)
+	assert.Len(t, wr.Succeeded(), 3)
+	assert.NotContains(t, recorder.events, "start:first")
+
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist, "checkpoint is removed once every step finished")
+}
+
+func TestCheckpoint_ResultsRestoreErrors(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), CheckpointFileName)
+	c := NewCheckpointer(path, NewCheckpoint([]string{"a"}, nil))
+
+	c.record(StepResult{Name: "a", Err: errorfamily.NewInfrastructure("cleaner.unavailable", "not installed")}, true)
+
+	cp, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+
+	results := cp.Results()
+	require.Len(t, results, 1)
+	assert.Equal(t, StepStatusSkipped, results[0].Status())
+	assert.Empty(t, cp.Pending)
+}
@@ -0,0 +1,175 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	flow "github.com/Azure/go-workflow"
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// stepPlan is the resolved ordering of one step: every step it waits for,
+// and the subset of those that must succeed for it to run.
+type stepPlan struct {
+	upstream []string
+	required map[string]bool
+}
+
+// planDependencies resolves the declared constraints of the selected cleaners
+// into DAG edges. After edges to unselected cleaners are dropped; a Requires
+// on an unselected cleaner and any cycle are rejected. Members of an exclusive
+// group are chained in a topological order of the explicit edges, so the
+// chaining never introduces a cycle and the members run one at a time.
+func planDependencies(selected []string, deps map[string]cleaner.Dependencies) (map[string]stepPlan, error) {
+	plans := make(map[string]stepPlan, len(selected))
+	for _, name := range selected {
+		plans[name] = stepPlan{required: make(map[string]bool)}
+	}
+
+	addEdge := func(from, to string) {
+		p := plans[to]
+		if from != to && !slices.Contains(p.upstream, from) {
+			p.upstream = append(p.upstream, from)
+			plans[to] = p
+		}
+	}
+
+	for _, name := range selected {
+		d := deps[name]
+
+		for _, after := range d.After {
+			if _, ok := plans[after]; ok {
+				addEdge(after, name)
+			}
+		}
+
+		for _, req := range d.Requires {
+			if _, ok := plans[req]; !ok {
+				return nil, errorfamily.NewRejection(
+					"execution.requirement_not_selected",
+					fmt.Sprintf("cleaner %q requires %q, which is not selected", name, req),
+				)
+			}
+
+			addEdge(req, name)
+			plans[name].required[req] = true
+		}
+	}
+
+	order, err := topologicalOrder(selected, plans)
+	if err != nil {
+		return nil, err
+	}
+
+	lastInGroup := make(map[string]string)
+
+	for _, name := range order {
+		for _, group := range deps[name].ExclusiveGroups {
+			if prev, ok := lastInGroup[group]; ok {
+				addEdge(prev, name)
+			}
+
+			lastInGroup[group] = name
+		}
+	}
+
+	return plans, nil
+}
+
+// withoutSatisfied drops requirements on cleaners that already succeeded in
+// an earlier run, so a resumed run does not reject them as unselected.
+func withoutSatisfied(d cleaner.Dependencies, satisfied []string) cleaner.Dependencies {
+	if len(satisfied) == 0 {
+		return d
+	}
+
+	d.Requires = slices.DeleteFunc(slices.Clone(d.Requires), func(req string) bool {
+		return slices.Contains(satisfied, req)
+	})
+
+	return d
+}
+
+// topologicalOrder returns the selected names ordered so that every step
+// follows its upstream steps, breaking ties by selection order. It returns a
+// rejection naming the involved cleaners if the edges contain a cycle.
+func topologicalOrder(selected []string, plans map[string]stepPlan) ([]string, error) {
+	pending := make(map[string]int, len(selected))
+	for _, name := range selected {
+		pending[name] = len(plans[name].upstream)
+	}
+
+	order := make([]string, 0, len(selected))
+
+	for len(order) < len(selected) {
+		next := ""
+
+		for _, name := range selected {
+			if remaining, ok := pending[name]; ok && remaining == 0 {
+				next = name
+
+				break
+			}
+		}
+
+		if next == "" {
+			var cycle []string
+
+			for _, name := range selected {
+				if _, ok := pending[name]; ok {
+					cycle = append(cycle, name)
+				}
+			}
+
+			return nil, errorfamily.NewRejection(
+				"execution.dependency_cycle",
+				"dependency cycle between cleaners: "+strings.Join(cycle, ", "),
+			)
+		}
+
+		delete(pending, next)
+		order = append(order, next)
+
+		for _, name := range selected {
+			if _, ok := pending[name]; ok && slices.Contains(plans[name].upstream, next) {
+				pending[name]--
+			}
+		}
+	}
+
+	return order, nil
+}
+
+// requirementCondition lets a step run once its upstream steps terminated,
+// unless a required upstream did not succeed. In that case the step is
+// skipped and recorded with an Infrastructure error, which StepResult.Status
+// reports as skipped.
+func requirementCondition(
+	name string,
+	required map[flow.Steper]string,
+	collector *resultCollector,
+) flow.Condition {
+	return func(ctx context.Context, ups map[flow.Steper]flow.StepResult) flow.StepStatus {
+		if flow.DefaultIsCanceled(ctx.Err()) {
+			return flow.Canceled
+		}
+
+		for step, up := range ups {
+			reqName, ok := required[step]
+			if ok && up.Status != flow.Succeeded {
+				collector.recordFinal(ctx, name, domain.CleanResult{}, errorfamily.NewInfrastructure(
+					"execution.requirement_failed",
+					fmt.Sprintf("skipped: required cleaner %s did not succeed", reqName),
+				), 0)
+
+				return flow.Skipped
+			}
+		}
+
+		return flow.Running
+	}
+}
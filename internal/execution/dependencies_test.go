@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	"github.com/larsartmann/go-error-family/errorfamilytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderRecorder records the order in which cleaners start and finish.
+type orderRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *orderRecorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+// recordingCleaner records start/end events around a short sleep.
+type recordingCleaner struct {
+	name     string
+	recorder *orderRecorder
+	err      error
+}
+
+func (c *recordingCleaner) Name() string                       { return c.name }
+func (c *recordingCleaner) Type() domain.OperationType         { return domain.OperationType(c.name) }
+func (c *recordingCleaner) IsAvailable(_ context.Context) bool { return true }
+func (c *recordingCleaner) Scan(context.Context) result.Result[[]domain.ScanItem] {
+	return result.Ok([]domain.ScanItem{})
+}
+
+func (c *recordingCleaner) Clean(_ context.Context) result.Result[domain.CleanResult] {
+	c.recorder.add("start:" + c.name)
+	time.Sleep(20 * time.Millisecond)
+	c.recorder.add("end:" + c.name)
+
+	if c.err != nil {
+		return result.Err[domain.CleanResult](c.err)
+	}
+
+	return result.Ok(domain.CleanResult{CleanedAt: time.Now(), Strategy: domain.StrategyDryRunType})
+}
+
+func newRecordingRegistry(recorder *orderRecorder, names ...string) *cleaner.Registry {
+	registry := cleaner.NewRegistry()
+	for _, name := range names {
+		registry.Register(name, &recordingCleaner{name: name, recorder: recorder})
+	}
+
+	return registry
+}
+
+func TestPlanDependencies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("after edges to unselected cleaners are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		plans, err := planDependencies([]string{"a", "b"}, map[string]cleaner.Dependencies{
+			"b": {After: []string{"a", "missing"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, plans["b"].upstream)
+		assert.Empty(t, plans["a"].upstream)
+	})
+
+	t.Run("requires on unselected cleaner is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := planDependencies([]string{"b"}, map[string]cleaner.Dependencies{
+			"b": {Requires: []string{"a"}},
+		})
+		errorfamilytest.AssertCode(t, err, "execution.requirement_not_selected")
+	})
+
+	t.Run("cycle is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := planDependencies([]string{"a", "b", "c"}, map[string]cleaner.Dependencies{
+			"a": {After: []string{"b"}},
+			"b": {Requires: []string{"a"}},
+		})
+		errorfamilytest.AssertCode(t, err, "execution.dependency_cycle")
+		assert.Contains(t, err.Error(), "cleaners: a, b")
+		assert.NotContains(t, err.Error(), "a, b, c")
+	})
+
+	t.Run("exclusive group follows explicit order", func(t *testing.T) {
+		t.Parallel()
+
+		plans, err := planDependencies([]string{"a", "b", "c"}, map[string]cleaner.Dependencies{
+			"a": {After: []string{"c"}, ExclusiveGroups: []string{"g"}},
+			"b": {ExclusiveGroups: []string{"g"}},
+			"c": {ExclusiveGroups: []string{"g"}},
+		})
+		require.NoError(t, err)
+
+		// Topological order is b, c, a: b has no upstream, c waits for b, a for c.
+		assert.Empty(t, plans["b"].upstream)
+		assert.Equal(t, []string{"b"}, plans["c"].upstream)
+		assert.Equal(t, []string{"c"}, plans["a"].upstream)
+	})
+}
+
+func TestRunCleaners_AfterOrdersSteps(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "first", "second")
+
+	wr, err := RunCleaners(context.Background(), registry, []string{"second", "first"},
+		WithDependencies(map[string]cleaner.Dependencies{"second": {After: []string{"first"}}}),
+	)
+	require.NoError(t, err)
+	require.Len(t, wr.Steps, 2)
+
+	assert.Equal(t, []string{"start:first", "end:first", "start:second", "end:second"}, recorder.events)
+	assert.Equal(t, "second", wr.Steps[0].Name, "results keep selection order")
+}
+
+func TestRunCleaners_ExclusiveGroupSerializes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "a", "b")
+
+	group := cleaner.Dependencies{ExclusiveGroups: []string{"cache"}}
+
+	_, err := RunCleaners(context.Background(), registry, []string{"a", "b"},
+		WithDependencies(map[string]cleaner.Dependencies{"a": group, "b": group}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"start:a", "end:a", "start:b", "end:b"}, recorder.events)
+}
+
+func TestRunCleaners_FailedRequirementSkipsDependent(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := cleaner.NewRegistry()
+	registry.Register("base", &recordingCleaner{name: "base", recorder: recorder, err: errors.New("boom")})
+	registry.Register("dependent", &recordingCleaner{name: "dependent", recorder: recorder})
+
+	wr, err := RunCleaners(context.Background(), registry, []string{"base", "dependent"},
+		WithDependencies(map[string]cleaner.Dependencies{"dependent": {Requires: []string{"base"}}}),
+	)
+	require.NoError(t, err)
+	require.Len(t, wr.Steps, 2)
+
+	assert.Equal(t, StepStatusFailed, wr.Steps[0].Status())
+	assert.Equal(t, StepStatusSkipped, wr.Steps[1].Status())
+	assert.NotContains(t, recorder.events, "start:dependent")
+}
+
+func TestBuildClean_RejectsCycle(t *testing.T) {
+	t.Parallel()
+
+	registry := newRecordingRegistry(&orderRecorder{}, "a", "b")
+
+	_, err := NewBuilder(false).
+		WithDependencies(map[string]cleaner.Dependencies{
+			"a": {After: []string{"b"}},
+			"b": {After: []string{"a"}},
+		}).
+		BuildClean(registry, []string{"a", "b"})
+	errorfamilytest.AssertCode(t, err, "execution.dependency_cycle")
+}
@@ -88,6 +88,29 @@ func TestRunCleaners_MixedResults(t *testing.T) {
 	errorfamilytest.AssertFamily(t, skipped[0].Err, errorfamily.Infrastructure)
 }
 
+func TestRunCleaners_StepObserver(t *testing.T) {
+	t.Parallel()
+
+	registry := cleaner.NewRegistry()
+
+	for _, name := range []string{"first", "second", "third"} {
+		registry.Register(name, &mockCleaner{
+			name:     name,
+			avail:    true,
+			cleanRes: result.Ok(domain.CleanResult{FreedBytes: 1, ItemsRemoved: 1}),
+		})
+	}
+
+	var observed []string
+
+	wr, err := RunCleaners(context.Background(), registry, []string{"first", "second", "third"},
+		WithStepObserver(func(step StepResult) { observed = append(observed, step.Name) }))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"first", "second", "third"}, observed, "every finished step is observed once")
+	assert.Len(t, wr.Steps, 3)
+}
+
 func TestRunCleaners_EmptySelection(t *testing.T) {
 	t.Parallel()
 
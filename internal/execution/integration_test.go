@@ -136,6 +136,71 @@ func TestRunCleaners_Retry(t *testing.T) {
 	assert.Equal(t, int32(3), failingThenSucceeding.attempts.Load())
 }
 
+// TestRunCleaners_RetryObservedOnce verifies that observers see one result
+// per step, the final attempt's, however often the step was retried.
+func TestRunCleaners_RetryObservedOnce(t *testing.T) {
+	t.Parallel()
+
+	retryCfg := &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name      string
+		failCount int32
+		status    StepStatus
+	}{
+		{"succeeds on the last attempt", 2, StepStatusSucceeded},
+		{"fails every attempt", 5, StepStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := cleaner.NewRegistry()
+			flaky := &retryableMockCleaner{name: "retry-me", avail: true, failCount: tt.failCount}
+			registry.Register("retry-me", flaky)
+
+			var observed []StepResult
+
+			_, err := RunCleaners(context.Background(), registry, []string{"retry-me"},
+				WithRetry(retryCfg),
+				WithStepObserver(func(step StepResult) { observed = append(observed, step) }),
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, int32(3), flaky.attempts.Load())
+			require.Len(t, observed, 1, "a retried step is observed once")
+			assert.Equal(t, tt.status, observed[0].Status())
+		})
+	}
+}
+
+func TestResultCollector_FinishReportsRetriedAttempt(t *testing.T) {
+	t.Parallel()
+
+	collector := newResultCollector()
+	collector.maxAttempts = 3
+
+	var observed []StepResult
+	collector.onFinal = func(step StepResult) { observed = append(observed, step) }
+
+	// go-workflow gave up retrying a Transient error before the attempts
+	// were used up, e.g. because its backoff stopped.
+	timeout := errorfamily.NewTransient("execution.step_timeout", "timed out")
+	collector.recordFinal(context.Background(), "nix", domain.CleanResult{}, timeout, time.Minute)
+	require.Empty(t, observed)
+
+	collector.finish()
+	collector.finish()
+
+	require.Len(t, observed, 1, "the last attempt is reported once the workflow returned")
+	assert.ErrorIs(t, observed[0].Err, timeout)
+}
+
 // TestRunScans_RealRegistry_DryRun is an integration test for the scan workflow path.
 func TestRunScans_RealRegistry_DryRun(t *testing.T) {
 	t.Parallel()
@@ -1,5 +1,11 @@
 package execution
 
+import (
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+)
+
 // RunOption configures a RunCleaners invocation.
 type RunOption func(*runConfig)
 
@@ -7,6 +13,14 @@ type runConfig struct {
 	maxConcurrency int
 	verbose        bool
 	retry          *RetryConfig
+	dependencies   map[string]cleaner.Dependencies
+	checkpoint     *Checkpointer
+	resources      map[string]cleaner.ResourceOverride
+	classLimits    map[domain.ResourceClass]int
+	measureSpace   bool
+	accurate       bool
+	ignores        *ignore.Matcher
+	observer       func(StepResult)
 }
 
 // WithMaxConcurrency sets the maximum number of cleaners that may run
@@ -26,6 +40,56 @@ func WithRetry(cfg *RetryConfig) RunOption {
 	return func(c *runConfig) { c.retry = cfg }
 }
 
+// WithDependencies adds ordering constraints keyed by cleaner name (e.g. from
+// a profile) on top of those the cleaners declare themselves.
+func WithDependencies(deps map[string]cleaner.Dependencies) RunOption {
+	return func(c *runConfig) { c.dependencies = deps }
+}
+
+// WithResourceOverrides replaces the resource class or timeout of cleaners
+// keyed by cleaner name (e.g. from a profile).
+func WithResourceOverrides(overrides map[string]cleaner.ResourceOverride) RunOption {
+	return func(c *runConfig) { c.resources = overrides }
+}
+
+// WithClassLimits sets how many cleaners of a resource class may run
+// concurrently, on top of DefaultClassLimits. 0 means unlimited.
+func WithClassLimits(limits map[domain.ResourceClass]int) RunOption {
+	return func(c *runConfig) { c.classLimits = limits }
+}
+
+// WithIgnoreMatcher replaces the run's ignore matcher, which by default
+// applies the global and discovered .cleanwizardignore files.
+func WithIgnoreMatcher(m *ignore.Matcher) RunOption {
+	return func(c *runConfig) { c.ignores = m }
+}
+
+// WithSpaceMeasurement makes every clean step report the free space it
+// really released, measured via statfs before and after the step. accurate
+// serializes steps that share a filesystem, at the cost of parallelism.
+func WithSpaceMeasurement(accurate bool) RunOption {
+	return func(c *runConfig) {
+		c.measureSpace = true
+		c.accurate = accurate
+	}
+}
+
+// WithCheckpoint persists the run's progress through cp after every finished
+// step. Steps the checkpoint already records as completed are reported in
+// the result without running again; the file is removed once all steps
+// finished.
+func WithCheckpoint(cp *Checkpointer) RunOption {
+	return func(c *runConfig) { c.checkpoint = cp }
+}
+
+// WithStepObserver calls observe with every step's final result as soon as
+// the step finished, e.g. to stream results while the run continues. Steps
+// a checkpoint already records are observed when the run starts; steps an
+// interruption cut short are not observed. Calls are serialized.
+func WithStepObserver(observe func(StepResult)) RunOption {
+	return func(c *runConfig) { c.observer = observe }
+}
+
 func resolveRunOptions(opts []RunOption) runConfig {
 	var c runConfig
 	for _, opt := range opts {
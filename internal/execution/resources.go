@@ -0,0 +1,92 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// DefaultClassLimits returns the default number of cleaners per resource
+// class that may run at the same time. Tree-walking cleaners compete for the
+// same disk, so only two run at once; daemon-bound cleaners mostly wait and
+// are unlimited; CPU-bound cleaners get one slot per CPU. 0 means unlimited.
+func DefaultClassLimits() map[domain.ResourceClass]int {
+	return map[domain.ResourceClass]int{
+		domain.ResourceClassDiskIO:  2,
+		domain.ResourceClassNetwork: 0,
+		domain.ResourceClassCPU:     runtime.NumCPU(),
+	}
+}
+
+// classLimiter bounds how many steps of each resource class run concurrently.
+// It complements Workflow.MaxConcurrency, which bounds all steps together.
+type classLimiter struct {
+	slots map[domain.ResourceClass]chan struct{}
+}
+
+// newClassLimiter creates a limiter from DefaultClassLimits with the given
+// per-class limits applied on top.
+func newClassLimiter(overrides map[domain.ResourceClass]int) *classLimiter {
+	limits := DefaultClassLimits()
+	maps.Copy(limits, overrides)
+
+	l := &classLimiter{slots: make(map[domain.ResourceClass]chan struct{}, len(limits))}
+
+	for class, limit := range limits {
+		if limit > 0 {
+			l.slots[class] = make(chan struct{}, limit)
+		}
+	}
+
+	return l
+}
+
+// acquire waits for a free slot of the class and returns the function that
+// releases it. It fails only when ctx is cancelled while waiting.
+func (l *classLimiter) acquire(ctx context.Context, class domain.ResourceClass) (func(), error) {
+	slots, ok := l.slots[class]
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// withStepTimeout derives the context for one step run; 0 means no timeout.
+func withStepTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// classifyStepTimeout turns an error caused by the step's own timeout into a
+// Transient "execution.step_timeout" error. The original error is not
+// wrapped, so the timeout is not mistaken for an interruption of the run.
+func classifyStepTimeout(
+	parent, stepCtx context.Context,
+	name string,
+	timeout time.Duration,
+	err error,
+) error {
+	if parent.Err() != nil || !errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return errorfamily.NewTransient(
+		"execution.step_timeout",
+		fmt.Sprintf("cleaner %s timed out after %s: %v", name, timeout, err),
+	)
+}
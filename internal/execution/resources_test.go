@@ -0,0 +1,83 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/result"
+	"github.com/larsartmann/go-error-family/errorfamilytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingCleaner waits until its context is done.
+type blockingCleaner struct {
+	recordingCleaner
+}
+
+func (c *blockingCleaner) Clean(ctx context.Context) result.Result[domain.CleanResult] {
+	<-ctx.Done()
+
+	return result.Err[domain.CleanResult](ctx.Err())
+}
+
+func classOverride(class domain.ResourceClass) cleaner.ResourceOverride {
+	return cleaner.ResourceOverride{Class: &class, Timeout: 0}
+}
+
+func TestRunCleaners_ClassLimitSerializes(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "a", "b")
+
+	_, err := RunCleaners(context.Background(), registry, []string{"a", "b"},
+		WithClassLimits(map[domain.ResourceClass]int{domain.ResourceClassDiskIO: 1}),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.events, 4)
+
+	first := recorder.events[0][len("start:"):]
+	assert.Equal(t, "end:"+first, recorder.events[1], "the second cleaner waits for the first")
+}
+
+func TestRunCleaners_ClassesRunAlongside(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "disk", "daemon")
+
+	_, err := RunCleaners(context.Background(), registry, []string{"disk", "daemon"},
+		WithClassLimits(map[domain.ResourceClass]int{domain.ResourceClassDiskIO: 1}),
+		WithResourceOverrides(map[string]cleaner.ResourceOverride{
+			"daemon": classOverride(domain.ResourceClassNetwork),
+		}),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.events, 4)
+	assert.ElementsMatch(t, []string{"start:disk", "start:daemon"}, recorder.events[:2],
+		"cleaners of different classes start before either finishes")
+}
+
+func TestRunCleaners_StepTimeout(t *testing.T) {
+	t.Parallel()
+
+	registry := cleaner.NewRegistry()
+	registry.Register("slow", &blockingCleaner{recordingCleaner{name: "slow", recorder: &orderRecorder{}}})
+
+	wr, err := RunCleaners(context.Background(), registry, []string{"slow"},
+		WithResourceOverrides(map[string]cleaner.ResourceOverride{
+			"slow": {Class: nil, Timeout: 20 * time.Millisecond},
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.False(t, wr.Interrupted, "a step timeout is not an interruption")
+	require.Len(t, wr.Failed(), 1)
+	errorfamilytest.AssertCode(t, wr.Failed()[0].Err, "execution.step_timeout")
+}
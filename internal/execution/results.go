@@ -1,12 +1,14 @@
 package execution
 
 import (
+	"context"
 	"slices"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
 	errorfamily "github.com/larsartmann/go-error-family"
 )
 
@@ -16,6 +18,9 @@ type StepResult struct {
 	Clean    domain.CleanResult
 	Err      error
 	Duration time.Duration
+
+	// Items holds the items a scan step found; nil for clean steps.
+	Items []domain.ScanItem
 }
 
 // Status classifies a step result as succeeded, skipped, or failed.
@@ -50,6 +55,14 @@ type WorkflowResult struct {
 	TotalItemsRemoved uint
 	TotalItemsFailed  uint
 	Duration          time.Duration
+
+	// Interrupted is set when the run was cancelled before every step
+	// finished; Pending then lists the steps that did not finish.
+	Interrupted bool
+	Pending     []string
+
+	// Excluded lists the paths .cleanwizardignore rules kept out of the run.
+	Excluded []ignore.Exclusion
 }
 
 // Succeeded returns only steps that completed successfully.
@@ -113,10 +126,25 @@ type resultCollector struct {
 	mu         sync.Mutex
 	results    []StepResult
 	orderIndex map[string]int
+
+	// attempts counts the recorded attempts of each step; maxAttempts is
+	// how many the retry configuration allows (0 = no retries). reported
+	// marks the steps whose final attempt was passed to onFinal.
+	attempts    map[string]int
+	maxAttempts int
+	reported    map[string]bool
+
+	// onFinal, if set, is called outside the lock once per step, with the
+	// result of its final attempt.
+	onFinal func(StepResult)
 }
 
 func newResultCollector() *resultCollector {
-	return &resultCollector{orderIndex: make(map[string]int)}
+	return &resultCollector{
+		orderIndex: make(map[string]int),
+		attempts:   make(map[string]int),
+		reported:   make(map[string]bool),
+	}
 }
 
 func (rc *resultCollector) register(name string, index int) {
@@ -126,26 +154,95 @@ func (rc *resultCollector) register(name string, index int) {
 	rc.orderIndex[name] = index
 }
 
-// recordFinal stores the result of a step, replacing any previous entry for
-// the same step name. This prevents duplicate entries when go-workflow retries
-// a step — only the final outcome is kept.
-func (rc *resultCollector) recordFinal(name string, clean domain.CleanResult, err error, duration time.Duration) {
+// recordFinal stores the result of a step attempt, replacing any previous
+// entry for the same step name. This prevents duplicate entries when
+// go-workflow retries a step — only the final outcome is kept, and only the
+// final outcome is passed to onFinal.
+func (rc *resultCollector) recordFinal(
+	ctx context.Context,
+	name string,
+	clean domain.CleanResult,
+	err error,
+	duration time.Duration,
+) {
+	rc.recordStep(ctx, StepResult{Name: name, Clean: clean, Err: err, Duration: duration, Items: nil})
+}
+
+// recordStep is recordFinal for a complete StepResult, e.g. one carrying
+// the items of a scan step.
+func (rc *resultCollector) recordStep(ctx context.Context, step StepResult) {
+	if rc.store(ctx, step) && rc.onFinal != nil {
+		rc.onFinal(step)
+	}
+}
+
+// store keeps the step's latest attempt and reports whether it is known to
+// be the final one: it succeeded, or go-workflow does not retry it because
+// the error is permanent, the attempts are used up or ctx ended (see
+// retryOptions). Any other attempt is reported by finish once go-workflow
+// has terminated the step.
+func (rc *resultCollector) store(ctx context.Context, step StepResult) bool {
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 
-	for _, v := range slices.Backward(rc.results) {
-		if v.Name == name {
-			v = StepResult{
-				Name: name, Clean: clean, Err: err, Duration: duration,
-			}
+	rc.attempts[step.Name]++
+
+	final := step.Err == nil || ctx.Err() != nil || !errorfamily.IsRetryable(step.Err) ||
+		rc.attempts[step.Name] >= max(rc.maxAttempts, 1)
+	if final {
+		rc.reported[step.Name] = true
+	}
+
+	for i, v := range slices.Backward(rc.results) {
+		if v.Name == step.Name {
+			rc.results[i] = step
 
-			return
+			return final
 		}
 	}
 
-	rc.results = append(rc.results, StepResult{
-		Name: name, Clean: clean, Err: err, Duration: duration,
-	})
+	rc.results = append(rc.results, step)
+
+	return final
+}
+
+// finish passes to onFinal the steps whose final attempt was not reported
+// yet, e.g. one whose retry go-workflow gave up while backing off. It is
+// called once the workflow returned, when every step has terminated and the
+// last stored attempt of each step is its final one.
+func (rc *resultCollector) finish() {
+	rc.mu.Lock()
+
+	var unreported []StepResult
+
+	for _, step := range rc.results {
+		if !rc.reported[step.Name] {
+			rc.reported[step.Name] = true
+			unreported = append(unreported, step)
+		}
+	}
+	rc.mu.Unlock()
+
+	if rc.onFinal == nil {
+		return
+	}
+
+	for _, step := range unreported {
+		rc.onFinal(step)
+	}
+}
+
+// registeredNames returns the registered step names in registration order.
+func (rc *resultCollector) registeredNames() []string {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	names := make([]string, len(rc.orderIndex))
+	for name, i := range rc.orderIndex {
+		names[i] = name
+	}
+
+	return names
 }
 
 // sortedByRegistration returns results ordered by their original registration
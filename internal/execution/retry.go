@@ -125,6 +125,9 @@ func retryOptions(cfg RetryConfig) []func(*flow.RetryOption) {
 			expBackoff := backoff.NewExponentialBackOff(
 				backoff.WithInitialInterval(initial),
 				backoff.WithMaxInterval(maxInterval),
+				// Attempts, not elapsed time, bound the retries; the step
+				// timeouts bound each attempt.
+				backoff.WithMaxElapsedTime(0),
 			)
 			opt.Backoff = expBackoff
 
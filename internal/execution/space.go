@@ -0,0 +1,185 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+)
+
+// freeSpaceFunc returns the free bytes of the filesystem holding path.
+type freeSpaceFunc func(path string) (int64, error)
+
+// diskFree reads free space via statfs.
+func diskFree(path string) (int64, error) {
+	usage, err := cleaner.GetDiskUsage(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return usage.Free, nil
+}
+
+// spaceMeter measures the space a step really freed by sampling free space
+// on the filesystems the step affects (see cleaner.AffectedPaths) before and
+// after it runs. Without accuracy mode, steps on the same filesystem may run
+// alongside and their measurements include each other's effects; in accuracy
+// mode such steps are serialized.
+type spaceMeter struct {
+	accurate bool
+	free     freeSpaceFunc
+
+	mu    sync.Mutex
+	locks map[uint64]chan struct{}
+}
+
+// newSpaceMeter creates a meter; accurate serializes steps per filesystem.
+func newSpaceMeter(accurate bool) *spaceMeter {
+	return &spaceMeter{
+		accurate: accurate,
+		free:     diskFree,
+		locks:    make(map[uint64]chan struct{}),
+	}
+}
+
+// filesystem is one filesystem a step affects, identified by its device and
+// sampled through one of the step's paths on it.
+type filesystem struct {
+	dev   uint64
+	probe string
+}
+
+// spaceSample holds the free space of a step's filesystems before it ran.
+// release gives up the filesystems held in accuracy mode.
+type spaceSample struct {
+	meter   *spaceMeter
+	fs      []filesystem
+	before  []int64
+	release func()
+}
+
+// resolveFilesystems maps paths to distinct filesystems, sorted by device.
+// Paths that do not exist are skipped.
+func resolveFilesystems(paths []string) []filesystem {
+	seen := make(map[uint64]bool)
+
+	var out []filesystem
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			continue
+		}
+
+		dev := uint64(st.Dev) //nolint:unconvert // Dev is int32 on darwin
+		if seen[dev] {
+			continue
+		}
+
+		seen[dev] = true
+
+		out = append(out, filesystem{dev: dev, probe: path})
+	}
+
+	slices.SortFunc(out, func(a, b filesystem) int {
+		switch {
+		case a.dev < b.dev:
+			return -1
+		case a.dev > b.dev:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return out
+}
+
+// lock returns the per-filesystem semaphore for dev.
+func (m *spaceMeter) lock(dev uint64) chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.locks[dev]
+	if !ok {
+		l = make(chan struct{}, 1)
+		m.locks[dev] = l
+	}
+
+	return l
+}
+
+// begin samples free space before a step. In accuracy mode it first waits
+// for exclusive use of the step's filesystems, taken in device order so that
+// steps sharing several filesystems cannot deadlock. It fails only when ctx
+// is cancelled while waiting.
+func (m *spaceMeter) begin(ctx context.Context, paths []string) (*spaceSample, error) {
+	sample := &spaceSample{meter: m, fs: resolveFilesystems(paths), release: func() {}}
+
+	if m.accurate {
+		held := make([]chan struct{}, 0, len(sample.fs))
+		sample.release = func() {
+			for _, l := range held {
+				<-l
+			}
+		}
+
+		for _, fs := range sample.fs {
+			l := m.lock(fs.dev)
+
+			select {
+			case l <- struct{}{}:
+				held = append(held, l)
+			case <-ctx.Done():
+				sample.release()
+
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	sample.before = make([]int64, len(sample.fs))
+
+	for i, fs := range sample.fs {
+		free, err := m.free(fs.probe)
+		if err != nil {
+			sample.before[i] = -1
+
+			continue
+		}
+
+		sample.before[i] = free
+	}
+
+	return sample, nil
+}
+
+// end samples free space after the step and returns the growth of free
+// space summed over its filesystems. ok is false when no filesystem could be
+// sampled both times. The result may be negative when other writers filled
+// the disk during the step. The caller releases the filesystems afterwards.
+func (s *spaceSample) end() (freed int64, ok bool) {
+	for i, fs := range s.fs {
+		if s.before[i] < 0 {
+			continue
+		}
+
+		after, err := s.meter.free(fs.probe)
+		if err != nil {
+			continue
+		}
+
+		freed += after - s.before[i]
+		ok = true
+	}
+
+	return freed, ok
+}
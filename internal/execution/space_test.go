@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpaceMeter_ReportsFreeSpaceGrowth(t *testing.T) {
+	t.Parallel()
+
+	free := int64(1000)
+	meter := newSpaceMeter(false)
+	meter.free = func(string) (int64, error) { return free, nil }
+
+	sample, err := meter.begin(context.Background(), []string{t.TempDir(), t.TempDir(), "/does/not/exist"})
+	require.NoError(t, err)
+	require.Len(t, sample.fs, 1, "paths on the same filesystem are sampled once")
+
+	free += 600
+
+	freed, ok := sample.end()
+	sample.release()
+
+	require.True(t, ok)
+	assert.Equal(t, int64(600), freed)
+}
+
+func TestSpaceMeter_AccurateSerializesSameFilesystem(t *testing.T) {
+	t.Parallel()
+
+	meter := newSpaceMeter(true)
+	meter.free = func(string) (int64, error) { return 0, nil }
+	dir := t.TempDir()
+
+	first, err := meter.begin(context.Background(), []string{dir})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = meter.begin(ctx, []string{dir})
+	require.ErrorIs(t, err, context.DeadlineExceeded, "a second step on the filesystem waits for the first")
+
+	first.release()
+
+	second, err := meter.begin(context.Background(), []string{dir})
+	require.NoError(t, err)
+	second.release()
+}
+
+func TestRunCleaners_SpaceMeasurement(t *testing.T) {
+	t.Parallel()
+
+	recorder := &orderRecorder{}
+	registry := newRecordingRegistry(recorder, "a", "b")
+
+	wr, err := RunCleaners(context.Background(), registry, []string{"a", "b"},
+		WithClassLimits(map[domain.ResourceClass]int{domain.ResourceClassDiskIO: 0}),
+		WithSpaceMeasurement(true),
+	)
+	require.NoError(t, err)
+
+	for _, step := range wr.Steps {
+		assert.NotNil(t, step.Clean.MeasuredFreedBytes, "step %s is measured", step.Name)
+	}
+
+	require.Len(t, recorder.events, 4)
+
+	first := recorder.events[0][len("start:"):]
+	assert.Equal(t, "end:"+first, recorder.events[1], "accuracy mode serializes cleaners on one filesystem")
+}
@@ -2,9 +2,11 @@ package execution
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/cleaner"
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
 	errorfamily "github.com/larsartmann/go-error-family"
 )
 
@@ -12,7 +14,9 @@ import (
 // It resolves cleaners from the registry, compiles them into a go-workflow DAG,
 // executes it with the configured options, and returns aggregated results.
 //
-// The workflow runs steps in parallel up to maxConcurrency. Step errors are
+// The workflow runs steps in parallel up to maxConcurrency and the per-class
+// limits of the cleaners' resource classes, except where cleaner
+// dependencies order them. Step errors are
 // collected per-step (not short-circuited) so that one cleaner failure does
 // not prevent others from running.
 func RunCleaners(
@@ -23,11 +27,22 @@ func RunCleaners(
 ) (*WorkflowResult, error) {
 	cfg := resolveRunOptions(opts)
 
-	builder := NewBuilder(cfg.verbose)
+	builder := NewBuilder(cfg.verbose).
+		WithDependencies(cfg.dependencies).
+		WithResourceOverrides(cfg.resources).
+		WithClassLimits(cfg.classLimits)
+	if cfg.checkpoint != nil {
+		builder.WithSatisfied(succeededNames(cfg.checkpoint.Checkpoint().Results()))
+	}
+
 	if cfg.retry != nil {
 		builder.WithRetryConfig(cfg.retry)
 	}
 
+	if cfg.measureSpace {
+		builder.WithSpaceMeasurement(cfg.accurate)
+	}
+
 	compiled, err := builder.BuildClean(registry, selected)
 	if err != nil {
 		return nil, err
@@ -46,7 +61,9 @@ func RunScans(
 ) (*WorkflowResult, error) {
 	cfg := resolveRunOptions(opts)
 
-	builder := NewBuilder(cfg.verbose)
+	builder := NewBuilder(cfg.verbose).
+		WithResourceOverrides(cfg.resources).
+		WithClassLimits(cfg.classLimits)
 
 	compiled, err := builder.BuildScan(registry, selected)
 	if err != nil {
@@ -60,18 +77,95 @@ func RunScans(
 // Workflow-level errors (e.g. panics recovered by DontPanic) are attached to the
 // WorkflowResult rather than silently dropped, so partial successes are preserved
 // while still surfacing failures.
+//
+// When ctx is cancelled mid-run, steps that did not finish are reported in
+// WorkflowResult.Pending instead of as failures, and with a checkpoint they
+// remain pending on disk for a later resume.
+//
+// All steps share one cleaner.SizeEngine, so files hardlinked into several
+// cleaners' trees are counted once for the whole run, and one ignore.Matcher,
+// so ignore files are read once and exclusions are reported together.
 func executeWorkflow(ctx context.Context, compiled *CompiledWorkflow, cfg runConfig) (*WorkflowResult, error) {
+	ctx = cleaner.WithSizeEngine(ctx, cleaner.NewSizeEngine())
+
+	ignores := cfg.ignores
+	if ignores == nil {
+		ignores = ignore.NewDefaultMatcher()
+	}
+
+	ctx = ignore.WithMatcher(ctx, ignores)
+
 	if cfg.maxConcurrency > 0 {
 		compiled.Workflow.MaxConcurrency = cfg.maxConcurrency
 	}
 
+	var (
+		previous   []StepResult
+		unfinished sync.Map
+		observeMu  sync.Mutex
+	)
+
+	observe := func(step StepResult) {
+		if cfg.observer == nil {
+			return
+		}
+
+		observeMu.Lock()
+		defer observeMu.Unlock()
+
+		cfg.observer(step)
+	}
+
+	if cfg.checkpoint != nil {
+		previous = cfg.checkpoint.Checkpoint().Results()
+		cfg.checkpoint.begin(compiled.plans)
+	}
+
+	for _, step := range previous {
+		observe(step)
+	}
+
+	compiled.Collector.onFinal = func(step StepResult) {
+		finished := step.Err == nil || (ctx.Err() == nil && !isCancellation(step.Err))
+		if finished {
+			unfinished.Delete(step.Name)
+			observe(step)
+		} else {
+			unfinished.Store(step.Name, true)
+		}
+
+		if cfg.checkpoint != nil {
+			cfg.checkpoint.record(step, finished)
+		}
+	}
+
 	startTime := time.Now()
 	runErr := compiled.Workflow.Do(ctx)
 	duration := time.Since(startTime)
 
+	compiled.Collector.finish()
+
+	steps := compiled.Collector.sortedByRegistration()
+
+	var pending []string
+	if ctx.Err() != nil {
+		steps, pending = splitUnfinished(compiled.Collector.registeredNames(), steps, func(name string) bool {
+			_, ok := unfinished.Load(name)
+
+			return ok
+		})
+	}
+
+	if cfg.checkpoint != nil {
+		cfg.checkpoint.finish()
+	}
+
 	result := &WorkflowResult{
-		Steps:    compiled.Collector.sortedByRegistration(),
-		Duration: duration,
+		Steps:       append(previous, steps...),
+		Duration:    duration,
+		Interrupted: len(pending) > 0,
+		Pending:     pending,
+		Excluded:    ignores.Exclusions(),
 	}
 
 	for _, step := range result.Steps {
@@ -83,7 +177,7 @@ func executeWorkflow(ctx context.Context, compiled *CompiledWorkflow, cfg runCon
 		result.TotalItemsFailed += step.Clean.ItemsFailed
 	}
 
-	if runErr != nil && len(result.Steps) == 0 {
+	if runErr != nil && len(result.Steps) == 0 && !result.Interrupted {
 		return nil, errorfamily.WrapTransient(
 			runErr,
 			"execution.workflow_failed",
@@ -93,3 +187,47 @@ func executeWorkflow(ctx context.Context, compiled *CompiledWorkflow, cfg runCon
 
 	return result, nil
 }
+
+// splitUnfinished separates the steps of an interrupted run into finished
+// results and the names of steps that never finished, in registration order.
+// A step that failed because of the interruption counts as unfinished.
+func splitUnfinished(
+	names []string,
+	steps []StepResult,
+	interrupted func(name string) bool,
+) ([]StepResult, []string) {
+	finished := make([]StepResult, 0, len(steps))
+	done := make(map[string]bool, len(steps))
+
+	for _, step := range steps {
+		if interrupted(step.Name) {
+			continue
+		}
+
+		finished = append(finished, step)
+		done[step.Name] = true
+	}
+
+	var pending []string
+
+	for _, name := range names {
+		if !done[name] {
+			pending = append(pending, name)
+		}
+	}
+
+	return finished, pending
+}
+
+// succeededNames returns the names of the succeeded steps.
+func succeededNames(steps []StepResult) []string {
+	var names []string
+
+	for _, step := range steps {
+		if step.Status() == StepStatusSucceeded {
+			names = append(names, step.Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,59 @@
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// csvHeader is the header row of CSV output, one column per CleanerReport
+// field except the scan items.
+//
+//nolint:gochecknoglobals
+var csvHeader = []string{
+	"command", "name", "status", "items_removed", "items_failed", "freed_bytes",
+	"measured_freed_bytes", "duration_ms", "family", "code", "error", "hint",
+}
+
+// csvFormatter renders one row per cleaner, for spreadsheets.
+type csvFormatter struct{}
+
+func (csvFormatter) Name() string { return "csv" }
+
+func (csvFormatter) Render(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, c := range r.Cleaners {
+		measured := ""
+		if c.MeasuredFreedBytes != nil {
+			measured = strconv.FormatInt(*c.MeasuredFreedBytes, 10)
+		}
+
+		row := []string{
+			string(r.Command), c.Name, string(c.Status),
+			strconv.FormatUint(uint64(c.ItemsRemoved), 10),
+			strconv.FormatUint(uint64(c.ItemsFailed), 10),
+			strconv.FormatUint(c.FreedBytes, 10),
+			measured,
+			strconv.FormatInt(c.DurationMs, 10),
+			c.Family, c.Code, c.Error, c.Hint,
+		}
+
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %w", c.Name, err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV output: %w", err)
+	}
+
+	return nil
+}
@@ -1,2 +1,6 @@
-// Package format provides ...
+// Package format provides the human-readable formatting of sizes, durations
+// and dates, and the output formats of scan and clean: a Report model and a
+// registry of formatters (table, json, ndjson, yaml, csv, markdown) that
+// render it. The machine-readable formats follow the versioned JSON schemas
+// in schema/, available at runtime through Schema.
 package format
@@ -0,0 +1,87 @@
+package format
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// ErrUnknownFormat is returned by Get for names no formatter is registered
+// under.
+var ErrUnknownFormat = errorfamily.NewRejection("format.unknown", "unknown output format")
+
+// Formatter renders a report in one output format.
+type Formatter interface {
+	// Name is the name the formatter is selected by, e.g. with --output.
+	Name() string
+	// Render writes the complete report to w.
+	Render(w io.Writer, r *Report) error
+}
+
+// Streamer is implemented by formatters that can write a report while it is
+// produced: Start before the first cleaner runs, Cleaner as each cleaner
+// finishes, and Finish once the report is complete. Calls are not
+// concurrent.
+type Streamer interface {
+	Formatter
+	Start(w io.Writer, command Command, dryRun bool) error
+	Cleaner(w io.Writer, c CleanerReport) error
+	Finish(w io.Writer, r *Report) error
+}
+
+// registry maps names to formatters.
+type registry struct {
+	mu         sync.RWMutex
+	formatters map[string]Formatter
+}
+
+//nolint:gochecknoglobals
+var defaultRegistry = &registry{formatters: map[string]Formatter{
+	"table":    tableFormatter{},
+	"json":     jsonFormatter{},
+	"ndjson":   ndjsonFormatter{},
+	"yaml":     yamlFormatter{},
+	"csv":      csvFormatter{},
+	"markdown": markdownFormatter{},
+}}
+
+// Register adds f under its name, replacing any formatter registered under
+// the same name.
+func Register(f Formatter) {
+	defaultRegistry.mu.Lock()
+	defaultRegistry.formatters[f.Name()] = f
+	defaultRegistry.mu.Unlock()
+}
+
+// Get returns the formatter registered under name.
+func Get(name string) (Formatter, error) {
+	defaultRegistry.mu.RLock()
+	f, ok := defaultRegistry.formatters[name]
+	defaultRegistry.mu.RUnlock()
+
+	if !ok {
+		return nil, errorfamily.WrapRejectionf(
+			ErrUnknownFormat, "format.unknown", "%q (available: %s)", name, strings.Join(Names(), ", "),
+		)
+	}
+
+	return f, nil
+}
+
+// Names returns the names of all registered formatters, sorted.
+func Names() []string {
+	defaultRegistry.mu.RLock()
+	defer defaultRegistry.mu.RUnlock()
+
+	names := make([]string, 0, len(defaultRegistry.formatters))
+	for name := range defaultRegistry.formatters {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,123 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json/v2"
+	"strings"
+	"testing"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func sampleReport() *Report {
+	report := NewReport(CommandScan, true, 0, []CleanerReport{
+		NewCleanerReport("go", domain.CleanResult{FreedBytes: 2048, ItemsRemoved: 2}, 0, nil), //nolint:exhaustruct
+		NewCleanerReport("cargo", domain.CleanResult{}, 0, //nolint:exhaustruct
+			errorfamily.NewInfrastructure("cleaner.cargo.not_available", "cargo | missing")),
+	})
+	report.Snapshots = []Snapshot{{MountPoint: "/", Filesystem: "btrfs", Snapshots: 2, Items: 1, PinnedBytes: 1024}} //nolint:exhaustruct
+
+	return report
+}
+
+func render(t *testing.T, name string, r *Report) string {
+	t.Helper()
+
+	f, err := Get(name)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Render(&buf, r))
+
+	return buf.String()
+}
+
+func TestRegistry_NamesAndUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	assert.Subset(t, Names(), []string{"csv", "json", "markdown", "ndjson", "table", "yaml"})
+
+	_, err := Get("xml")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	assert.Contains(t, err.Error(), "ndjson", "the error lists the available formats")
+}
+
+func TestNDJSONFormatter_Events(t *testing.T) {
+	t.Parallel()
+
+	var types []EventType
+
+	scanner := bufio.NewScanner(strings.NewReader(render(t, "ndjson", sampleReport())))
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e), "every line is one JSON event")
+		assert.Equal(t, SchemaVersion, e.SchemaVersion)
+
+		types = append(types, e.Type)
+	}
+
+	assert.Equal(t, []EventType{EventStart, EventCleaner, EventCleaner, EventSnapshot, EventSummary}, types)
+}
+
+func TestYAMLFormatter_UsesJSONFieldNames(t *testing.T) {
+	t.Parallel()
+
+	report := sampleReport()
+	report.Pending = []string{"true"}
+
+	out := render(t, "yaml", report)
+	assert.NotContains(t, out, "{", "output is block YAML")
+
+	var decoded map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out), &decoded))
+	assert.Equal(t, "scan", decoded["command"])
+	assert.Contains(t, decoded, "schema_version")
+	assert.Equal(t, []any{"true"}, decoded["pending"], "strings that look like booleans stay strings")
+}
+
+func TestCSVFormatter_OneRowPerCleaner(t *testing.T) {
+	t.Parallel()
+
+	records, err := csv.NewReader(strings.NewReader(render(t, "csv", sampleReport()))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"scan", "cargo", "skipped"}, records[1][:3])
+	assert.Equal(t, "2048", records[2][5])
+}
+
+func TestTextFormatters(t *testing.T) {
+	t.Parallel()
+
+	table := render(t, "table", sampleReport())
+	assert.Contains(t, table, "Cleanable")
+	assert.Contains(t, table, "Total: 2 items, 2.0 KiB cleanable")
+	assert.Contains(t, table, "[dry run]")
+
+	markdown := render(t, "markdown", sampleReport())
+	assert.Contains(t, markdown, "| go | success | 2 | 2.0 KiB |")
+	assert.Contains(t, markdown, `cargo \| missing`, "pipes are escaped")
+	assert.Contains(t, markdown, "### Snapshots")
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range SchemaNames {
+		data, err := Schema(name)
+		require.NoError(t, err)
+
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(data, &schema))
+		assert.Contains(t, schema["$id"], name+".v"+SchemaVersion+".json")
+	}
+
+	_, err := Schema("missing")
+	assert.Equal(t, "format.unknown_schema", errorfamily.Code(err))
+}
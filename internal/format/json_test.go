@@ -1,41 +1,126 @@
 package format
 
 import (
+	"bytes"
 	"encoding/json/v2"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
 	errorfamily "github.com/larsartmann/go-error-family"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCleanResultsToJSON_IncludesFamilyAndCode(t *testing.T) {
-	t.Parallel()
+// cleanJSON is the shape `clean --json` published before scan and clean
+// shared the report model. The json formatter must keep decoding into it:
+// these tests are regression checks of that contract, not of Report.
+type cleanJSON struct {
+	Success            bool               `json:"success"`
+	CleanedAt          time.Time          `json:"cleaned_at"`
+	DurationMs         int64              `json:"duration_ms"`
+	ItemsRemoved       uint               `json:"items_removed"`
+	ItemsFailed        uint               `json:"items_failed"`
+	FreedBytes         uint64             `json:"freed_bytes"`
+	FreedHuman         string             `json:"freed_human"`
+	MeasuredFreedBytes *int64             `json:"measured_freed_bytes,omitempty"`
+	Cleaners           []cleanJSONCleaner `json:"cleaners"`
+	DryRun             bool               `json:"dry_run,omitempty"`
+	Errors             []string           `json:"errors,omitempty"`
+}
 
-	naErr := errorfamily.NewInfrastructure("cleaner.cargo.not_available", "cargo not available")
-	results := map[string]domain.CleanResult{
-		"nix": {FreedBytes: 1024, ItemsRemoved: 3},
-	}
-	skipped := map[string]error{
-		"cargo": naErr,
-	}
-	failed := map[string]error{
-		"docker": errors.New("docker daemon not running"),
-	}
+type cleanJSONCleaner struct {
+	Name               string `json:"name"`
+	ItemsRemoved       uint   `json:"items_removed"`
+	ItemsFailed        uint   `json:"items_failed"`
+	FreedBytes         uint64 `json:"freed_bytes"`
+	FreedHuman         string `json:"freed_human"`
+	MeasuredFreedBytes *int64 `json:"measured_freed_bytes,omitempty"`
+	Status             string `json:"status"`
+	Error              string `json:"error,omitempty"`
+	Family             string `json:"family,omitempty"`
+	Code               string `json:"code,omitempty"`
+	Hint               string `json:"hint,omitempty"`
+	Retryable          bool   `json:"retryable,omitempty"`
+}
+
+// renderJSON renders a clean report of the given cleaners with the json
+// formatter and returns the raw output.
+func renderJSON(t *testing.T, cleaners ...CleanerReport) []byte {
+	t.Helper()
 
-	data, err := CleanResultsToJSON(results, 0, false, skipped, failed)
+	f, err := Get("json")
 	require.NoError(t, err)
 
-	var output JSONOutput
-	require.NoError(t, json.Unmarshal(data, &output))
+	var buf bytes.Buffer
+	require.NoError(t, f.Render(&buf, NewReport(CommandClean, false, 0, cleaners)))
+
+	return buf.Bytes()
+}
+
+// renderCleanJSON renders like renderJSON and decodes the published shape.
+func renderCleanJSON(t *testing.T, cleaners ...CleanerReport) cleanJSON {
+	t.Helper()
+
+	var output cleanJSON
+	require.NoError(t, json.Unmarshal(renderJSON(t, cleaners...), &output))
 
-	byName := make(map[string]CleanerResult, len(output.Cleaners))
+	return output
+}
+
+func byCleanerName(output cleanJSON) map[string]cleanJSONCleaner {
+	byName := make(map[string]cleanJSONCleaner, len(output.Cleaners))
 	for _, c := range output.Cleaners {
 		byName[c.Name] = c
 	}
 
+	return byName
+}
+
+func TestJSONFormatter_KeepsCleanFieldNames(t *testing.T) {
+	t.Parallel()
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(renderJSON(t,
+		NewCleanerReport("nix", domain.CleanResult{FreedBytes: 1024, ItemsRemoved: 3}, 0, nil), //nolint:exhaustruct
+	), &output))
+
+	for _, key := range []string{
+		"success", "cleaned_at", "duration_ms", "items_removed", "items_failed", "freed_bytes", "freed_human", "cleaners",
+	} {
+		assert.Contains(t, output, key)
+	}
+
+	cleaners, ok := output["cleaners"].([]any)
+	require.True(t, ok)
+	require.Len(t, cleaners, 1)
+
+	for _, key := range []string{"name", "items_removed", "items_failed", "freed_bytes", "freed_human", "status"} {
+		assert.Contains(t, cleaners[0], key)
+	}
+
+	assert.Equal(t, "1.0 KiB", output["freed_human"])
+	assert.Equal(t, SchemaVersion, output["schema_version"])
+}
+
+func TestJSONFormatter_IncludesFamilyAndCode(t *testing.T) {
+	t.Parallel()
+
+	naErr := errorfamily.NewInfrastructure("cleaner.cargo.not_available", "cargo not available")
+
+	output := renderCleanJSON(t,
+		NewCleanerReport("nix", domain.CleanResult{FreedBytes: 1024, ItemsRemoved: 3}, 0, nil),       //nolint:exhaustruct
+		NewCleanerReport("cargo", domain.CleanResult{}, 0, naErr),                                    //nolint:exhaustruct
+		NewCleanerReport("docker", domain.CleanResult{}, 0, errors.New("docker daemon not running")), //nolint:exhaustruct
+	)
+
+	assert.False(t, output.Success, "a failed cleaner fails the report")
+	assert.Equal(t, uint64(1024), output.FreedBytes)
+
+	byName := byCleanerName(output)
+
 	// Skipped cleaner should carry family/code/retryable from errorfamily classification.
 	cargo := byName["cargo"]
 	assert.Equal(t, "skipped", cargo.Status)
@@ -50,23 +135,19 @@ func TestCleanResultsToJSON_IncludesFamilyAndCode(t *testing.T) {
 	assert.True(t, docker.Retryable)
 }
 
-func TestCleanResultsToJSON_DeterministicOrdering(t *testing.T) {
+func TestJSONFormatter_DeterministicOrdering(t *testing.T) {
 	t.Parallel()
 
-	results := map[string]domain.CleanResult{
-		"zebra":  {FreedBytes: 1},
-		"alpha":  {FreedBytes: 2},
-		"middle": {FreedBytes: 3},
+	cleaners := []CleanerReport{
+		NewCleanerReport("zebra", domain.CleanResult{FreedBytes: 1}, 0, nil),  //nolint:exhaustruct
+		NewCleanerReport("alpha", domain.CleanResult{FreedBytes: 2}, 0, nil),  //nolint:exhaustruct
+		NewCleanerReport("middle", domain.CleanResult{FreedBytes: 3}, 0, nil), //nolint:exhaustruct
 	}
 
 	// Run multiple times — the cleaners array must always be sorted by name.
 	// (The cleaned_at timestamp varies, so we compare structurally, not raw bytes.)
 	for range 5 {
-		data, err := CleanResultsToJSON(results, 0, false, nil, nil)
-		require.NoError(t, err)
-
-		var output JSONOutput
-		require.NoError(t, json.Unmarshal(data, &output))
+		output := renderCleanJSON(t, cleaners...)
 
 		require.Len(t, output.Cleaners, 3)
 		assert.Equal(t, "alpha", output.Cleaners[0].Name)
@@ -74,3 +155,42 @@ func TestCleanResultsToJSON_DeterministicOrdering(t *testing.T) {
 		assert.Equal(t, "zebra", output.Cleaners[2].Name)
 	}
 }
+
+func TestJSONFormatter_MeasuredFreedBytes(t *testing.T) {
+	t.Parallel()
+
+	measured := int64(512)
+
+	output := renderCleanJSON(t,
+		NewCleanerReport("docker", domain.CleanResult{FreedBytes: 2048, MeasuredFreedBytes: &measured}, 0, nil), //nolint:exhaustruct
+		NewCleanerReport("go", domain.CleanResult{FreedBytes: 100}, 0, nil),                                     //nolint:exhaustruct
+	)
+
+	require.NotNil(t, output.MeasuredFreedBytes)
+	assert.Equal(t, int64(512), *output.MeasuredFreedBytes)
+
+	require.Len(t, output.Cleaners, 2)
+	require.NotNil(t, output.Cleaners[0].MeasuredFreedBytes)
+	assert.Equal(t, uint64(2048), output.Cleaners[0].FreedBytes, "claimed bytes are kept next to measured")
+	assert.Nil(t, output.Cleaners[1].MeasuredFreedBytes, "unmeasured cleaners omit the field")
+}
+
+func TestJSONFormatter_Hint(t *testing.T) {
+	t.Parallel()
+
+	hints.Register("cleaner.json-test.daemon_unreachable", "Start the json-test daemon")
+
+	output := renderCleanJSON(t,
+		NewCleanerReport("json-test", domain.CleanResult{}, 0, //nolint:exhaustruct
+			errorfamily.NewInfrastructure("cleaner.json-test.daemon_unreachable", "daemon down")),
+		NewCleanerReport("other", domain.CleanResult{}, 0, //nolint:exhaustruct
+			errorfamily.NewInfrastructure("cleaner.other.not_available", "missing")),
+		NewCleanerReport("plain", domain.CleanResult{}, 0, errors.New("boom")), //nolint:exhaustruct
+	)
+
+	byName := byCleanerName(output)
+
+	assert.Equal(t, "Start the json-test daemon", byName["json-test"].Hint)
+	assert.NotEmpty(t, byName["other"].Hint, "unknown cleaner codes fall back to the generic hint")
+	assert.Empty(t, byName["plain"].Hint)
+}
@@ -0,0 +1,69 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// markdownFormatter renders the report as a Markdown section, for issues,
+// pull requests and wikis.
+type markdownFormatter struct{}
+
+func (markdownFormatter) Name() string { return "markdown" }
+
+func (markdownFormatter) Render(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## clean-wizard %s\n\n", r.Command)
+
+	writeMarkdownRow(&b, reportColumns(r))
+	b.WriteString("|---|---|--:|--:|\n")
+
+	for _, row := range reportRows(r) {
+		writeMarkdownRow(&b, row)
+	}
+
+	fmt.Fprintf(&b, "\n**%s**\n", totalsLine(r))
+
+	if problems := problemCleaners(r); len(problems) > 0 {
+		b.WriteString("\n### Problems\n\n")
+
+		for _, c := range problems {
+			fmt.Fprintf(&b, "- **%s** %s: %s\n", c.Name, c.Status, markdownEscape(c.Error))
+
+			if c.Hint != "" {
+				fmt.Fprintf(&b, "  - %s\n", markdownEscape(c.Hint))
+			}
+		}
+	}
+
+	if len(r.Snapshots) > 0 {
+		b.WriteString("\n### Snapshots\n\n")
+
+		for _, s := range r.Snapshots {
+			fmt.Fprintf(&b, "- %s\n", snapshotLine(s))
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write Markdown output: %w", err)
+	}
+
+	return nil
+}
+
+// writeMarkdownRow writes one Markdown table row.
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	for i, cell := range cells {
+		cells[i] = markdownEscape(cell)
+	}
+
+	b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
+
+// markdownEscape escapes the characters that would break a table cell or
+// list item.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,111 @@
+package format
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EventType is the kind of an NDJSON event.
+type EventType string
+
+const (
+	// EventStart opens the stream before the first cleaner runs.
+	EventStart EventType = "start"
+	// EventCleaner carries one cleaner's result as soon as it finished.
+	EventCleaner EventType = "cleaner"
+	// EventSnapshot carries one snapshot warning.
+	EventSnapshot EventType = "snapshot"
+	// EventSummary closes the stream with the totals.
+	EventSummary EventType = "summary"
+)
+
+// Event is one line of NDJSON output, following schema/event.v1.json.
+// Exactly one of Cleaner, Snapshot and Summary is set for the matching
+// types; start events carry Command and DryRun.
+type Event struct {
+	SchemaVersion string         `json:"schema_version"`
+	Type          EventType      `json:"type"`
+	Time          time.Time      `json:"time"`
+	Command       Command        `json:"command,omitempty"`
+	DryRun        bool           `json:"dry_run,omitzero"`
+	Cleaner       *CleanerReport `json:"cleaner,omitempty"`
+	Snapshot      *Snapshot      `json:"snapshot,omitempty"`
+	Summary       *Summary       `json:"summary,omitempty"`
+}
+
+// Summary is the part of a report the summary event carries: everything
+// but the cleaners and snapshots, which have their own events.
+type Summary struct {
+	Success     bool     `json:"success"`
+	DurationMs  int64    `json:"duration_ms"`
+	Interrupted bool     `json:"interrupted,omitzero"`
+	Pending     []string `json:"pending,omitempty"`
+	Totals      Totals   `json:"totals"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// ndjsonFormatter streams the report as newline-delimited JSON events.
+type ndjsonFormatter struct{}
+
+func (ndjsonFormatter) Name() string { return "ndjson" }
+
+func (f ndjsonFormatter) Render(w io.Writer, r *Report) error {
+	if err := f.Start(w, r.Command, r.DryRun); err != nil {
+		return err
+	}
+
+	for _, c := range r.Cleaners {
+		if err := f.Cleaner(w, c); err != nil {
+			return err
+		}
+	}
+
+	return f.Finish(w, r)
+}
+
+func (ndjsonFormatter) Start(w io.Writer, command Command, dryRun bool) error {
+	return writeEvent(w, Event{Type: EventStart, Command: command, DryRun: dryRun}) //nolint:exhaustruct
+}
+
+func (ndjsonFormatter) Cleaner(w io.Writer, c CleanerReport) error {
+	return writeEvent(w, Event{Type: EventCleaner, Cleaner: &c}) //nolint:exhaustruct
+}
+
+func (ndjsonFormatter) Finish(w io.Writer, r *Report) error {
+	for _, s := range r.Snapshots {
+		if err := writeEvent(w, Event{Type: EventSnapshot, Snapshot: &s}); err != nil { //nolint:exhaustruct
+			return err
+		}
+	}
+
+	return writeEvent(w, Event{ //nolint:exhaustruct
+		Type: EventSummary,
+		Summary: &Summary{
+			Success:     r.Success,
+			DurationMs:  r.DurationMs,
+			Interrupted: r.Interrupted,
+			Pending:     r.Pending,
+			Totals:      r.Totals,
+			Errors:      r.Errors,
+		},
+	})
+}
+
+// writeEvent stamps the event and writes it as one line.
+func writeEvent(w io.Writer, e Event) error {
+	e.SchemaVersion = SchemaVersion
+	e.Time = time.Now()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", e.Type, err)
+	}
+
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("failed to write %s event: %w", e.Type, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package format
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/domain"
+	"github.com/LarsArtmann/clean-wizard/internal/hints"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// SchemaVersion is the version of the report and event schemas. It changes
+// whenever a field is renamed or removed; added fields keep the version.
+const SchemaVersion = "1"
+
+// Command names the command that produced a report.
+type Command string
+
+const (
+	CommandScan  Command = "scan"
+	CommandClean Command = "clean"
+)
+
+// Status is the outcome of one cleaner in a report.
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	// StatusSkipped: the cleaner could not run here (infrastructure errors
+	// such as a missing binary).
+	StatusSkipped Status = "skipped"
+	StatusFailed  Status = "failed"
+)
+
+// Report is the result model every output format renders, for scan and
+// clean alike. For scans, ItemsRemoved and FreedBytes are what a clean would
+// remove, as in a dry-run clean; for cleans, what was removed.
+//
+// The totals sit at the top level and the clean-specific fields keep the
+// names `clean --json` published before scan and clean shared this model
+// (items_removed, freed_bytes, freed_human, cleaned_at), so existing
+// consumers keep working under schema version 1.
+type Report struct {
+	SchemaVersion string    `json:"schema_version"`
+	Command       Command   `json:"command"`
+	DryRun        bool      `json:"dry_run,omitzero"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	// CleanedAt repeats GeneratedAt for clean reports only.
+	CleanedAt  time.Time `json:"cleaned_at,omitzero"`
+	DurationMs int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	// Interrupted is set when the run was cancelled; Pending then lists the
+	// cleaners that did not finish.
+	Interrupted bool     `json:"interrupted,omitzero"`
+	Pending     []string `json:"pending,omitempty"`
+	Totals      `json:",inline"`
+	Cleaners    []CleanerReport `json:"cleaners"`
+	Snapshots   []Snapshot      `json:"snapshots,omitempty"`
+	Errors      []string        `json:"errors,omitempty"`
+}
+
+// Totals sums the successful cleaners of a report.
+type Totals struct {
+	ItemsRemoved uint   `json:"items_removed"`
+	ItemsFailed  uint   `json:"items_failed"`
+	FreedBytes   uint64 `json:"freed_bytes"`
+	FreedHuman   string `json:"freed_human"`
+	// MeasuredFreedBytes sums the cleaners' measured freed space; it is
+	// omitted when no cleaner was measured (e.g. in dry-run mode).
+	MeasuredFreedBytes *int64 `json:"measured_freed_bytes,omitempty"`
+}
+
+// CleanerReport is the outcome of one cleaner.
+type CleanerReport struct {
+	Name         string `json:"name"`
+	Status       Status `json:"status"`
+	ItemsRemoved uint   `json:"items_removed"`
+	ItemsFailed  uint   `json:"items_failed"`
+	FreedBytes   uint64 `json:"freed_bytes"`
+	FreedHuman   string `json:"freed_human"`
+	// MeasuredFreedBytes is the free-space growth sampled via statfs while
+	// the cleaner ran, next to the FreedBytes the cleaner claimed.
+	MeasuredFreedBytes *int64 `json:"measured_freed_bytes,omitempty"`
+	DurationMs         int64  `json:"duration_ms"`
+	Error              string `json:"error,omitempty"`
+	Family             string `json:"family,omitempty"` // errorfamily classification (e.g. "infrastructure", "transient")
+	Code               string `json:"code,omitempty"`   // machine-readable error code (e.g. "cleaner.cargo.not_available")
+	Hint               string `json:"hint,omitempty"`   // remediation hint for Code (see package hints)
+	Retryable          bool   `json:"retryable,omitzero"`
+	// ScanItems are the items a scan selected, with their selection reasons.
+	ScanItems []domain.ScanItem `json:"scan_items,omitempty"`
+}
+
+// Snapshot reports data a cleaner would delete that filesystem snapshots
+// keep on disk.
+type Snapshot struct {
+	MountPoint      string    `json:"mount_point"`
+	Filesystem      string    `json:"filesystem"`
+	Snapshots       int       `json:"snapshots"`
+	LatestSnapshot  time.Time `json:"latest_snapshot,omitzero"`
+	Items           int       `json:"items"`
+	ReportedBytes   int64     `json:"reported_bytes"`
+	PinnedBytes     int64     `json:"pinned_bytes"`
+	UsedBySnapshots int64     `json:"used_by_snapshots,omitzero"`
+}
+
+// NewCleanerReport describes the outcome of the named cleaner. A nil err is
+// a success; infrastructure errors are skips and all others failures, as in
+// execution.StepResult.Status.
+func NewCleanerReport(name string, result domain.CleanResult, duration time.Duration, err error) CleanerReport {
+	if err != nil {
+		family := errorfamily.Classify(err)
+
+		status := StatusFailed
+		if family == errorfamily.Infrastructure {
+			status = StatusSkipped
+		}
+
+		return CleanerReport{ //nolint:exhaustruct
+			Name:        name,
+			Status:      status,
+			ItemsFailed: result.ItemsFailed,
+			FreedHuman:  Bytes(0),
+			DurationMs:  duration.Milliseconds(),
+			Error:       err.Error(),
+			Family:      family.String(),
+			Code:        errorfamily.Code(err),
+			Hint:        hints.ForError(name, err),
+			Retryable:   family == errorfamily.Transient,
+		}
+	}
+
+	cr := CleanerReport{ //nolint:exhaustruct
+		Name:         name,
+		Status:       StatusSuccess,
+		ItemsRemoved: result.ItemsRemoved,
+		ItemsFailed:  result.ItemsFailed,
+		FreedBytes:   result.FreedBytes,
+		FreedHuman:   Bytes(int64(result.FreedBytes)),
+		DurationMs:   duration.Milliseconds(),
+	}
+
+	if result.MeasuredFreedBytes != nil {
+		measured := *result.MeasuredFreedBytes
+		cr.MeasuredFreedBytes = &measured
+	}
+
+	return cr
+}
+
+// NewReport assembles a report from its cleaners, sorting them by name and
+// computing the totals, the success flag and the error list.
+func NewReport(command Command, dryRun bool, duration time.Duration, cleaners []CleanerReport) *Report {
+	report := &Report{ //nolint:exhaustruct
+		SchemaVersion: SchemaVersion,
+		Command:       command,
+		DryRun:        dryRun,
+		GeneratedAt:   time.Now(),
+		DurationMs:    duration.Milliseconds(),
+		Success:       true,
+		Cleaners:      slices.Clone(cleaners),
+	}
+
+	if command == CommandClean {
+		report.CleanedAt = report.GeneratedAt
+	}
+
+	// Sort cleaners by name for deterministic output regardless of the
+	// order in which they finished.
+	slices.SortFunc(report.Cleaners, func(a, b CleanerReport) int { return cmp.Compare(a.Name, b.Name) })
+
+	for _, c := range report.Cleaners {
+		report.Totals.ItemsFailed += c.ItemsFailed
+
+		switch c.Status {
+		case StatusSuccess:
+			report.Totals.ItemsRemoved += c.ItemsRemoved
+			report.Totals.FreedBytes += c.FreedBytes
+
+			if c.MeasuredFreedBytes != nil {
+				if report.Totals.MeasuredFreedBytes == nil {
+					report.Totals.MeasuredFreedBytes = new(int64)
+				}
+
+				*report.Totals.MeasuredFreedBytes += *c.MeasuredFreedBytes
+			}
+		case StatusFailed:
+			report.Success = false
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", c.Name, c.Error))
+		case StatusSkipped:
+		}
+	}
+
+	report.Totals.FreedHuman = Bytes(int64(report.Totals.FreedBytes))
+
+	return report
+}
@@ -0,0 +1,34 @@
+package format
+
+import (
+	"embed"
+	"strings"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// The published schemas of the machine-readable formats: report for json
+// and yaml, event for each ndjson line. Their file names carry
+// SchemaVersion.
+//
+//go:embed schema/*.json
+//nolint:gochecknoglobals
+var schemas embed.FS
+
+// SchemaNames are the names Schema accepts.
+//
+//nolint:gochecknoglobals
+var SchemaNames = []string{"report", "event"}
+
+// Schema returns the JSON schema with the given name for the current
+// SchemaVersion.
+func Schema(name string) ([]byte, error) {
+	data, err := schemas.ReadFile("schema/" + name + ".v" + SchemaVersion + ".json")
+	if err != nil {
+		return nil, errorfamily.WrapRejectionf(
+			err, "format.unknown_schema", "unknown schema %q (available: %s)", name, strings.Join(SchemaNames, ", "),
+		)
+	}
+
+	return data, nil
+}
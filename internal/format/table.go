@@ -0,0 +1,113 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+)
+
+// tableFormatter renders the report as a plain, uncoloured text table for
+// logs and terminals that are not interactive.
+type tableFormatter struct{}
+
+func (tableFormatter) Name() string { return "table" }
+
+func (tableFormatter) Render(w io.Writer, r *Report) error {
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		Headers(reportColumns(r)...).
+		Rows(reportRows(r)...)
+
+	var b strings.Builder
+
+	b.WriteString(t.String())
+	b.WriteString("\n")
+	b.WriteString(totalsLine(r) + "\n")
+
+	for _, c := range problemCleaners(r) {
+		fmt.Fprintf(&b, "%s %s: %s\n", c.Name, c.Status, c.Error)
+
+		if c.Hint != "" {
+			fmt.Fprintf(&b, "  → %s\n", c.Hint)
+		}
+	}
+
+	for _, s := range r.Snapshots {
+		b.WriteString(snapshotLine(s) + "\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write table output: %w", err)
+	}
+
+	return nil
+}
+
+// reportColumns returns the column headers shared by the table and
+// Markdown output.
+func reportColumns(r *Report) []string {
+	if r.Command == CommandScan {
+		return []string{"Cleaner", "Status", "Items", "Cleanable"}
+	}
+
+	return []string{"Cleaner", "Status", "Items", "Freed"}
+}
+
+// reportRows returns one row per cleaner for the table and Markdown output.
+func reportRows(r *Report) [][]string {
+	rows := make([][]string, 0, len(r.Cleaners))
+
+	for _, c := range r.Cleaners {
+		rows = append(rows, []string{c.Name, string(c.Status), strconv.FormatUint(uint64(c.ItemsRemoved), 10), c.FreedHuman})
+	}
+
+	return rows
+}
+
+// totalsLine summarises the report's totals in one line.
+func totalsLine(r *Report) string {
+	verb := "freed"
+	if r.Command == CommandScan {
+		verb = "cleanable"
+	}
+
+	line := fmt.Sprintf("Total: %d items, %s %s in %s", r.Totals.ItemsRemoved, r.Totals.FreedHuman, verb, Duration(time.Duration(r.DurationMs)*time.Millisecond))
+
+	if r.Totals.MeasuredFreedBytes != nil {
+		line += fmt.Sprintf(" (measured %s)", Bytes(*r.Totals.MeasuredFreedBytes))
+	}
+
+	if r.DryRun {
+		line += " [dry run]"
+	}
+
+	if r.Interrupted {
+		line += " [interrupted, pending: " + strings.Join(r.Pending, ", ") + "]"
+	}
+
+	return line
+}
+
+// snapshotLine describes a snapshot warning in one line.
+func snapshotLine(s Snapshot) string {
+	return fmt.Sprintf("Snapshots on %s (%s) keep %s of %s reported across %d item(s)",
+		s.MountPoint, s.Filesystem, Bytes(s.PinnedBytes), Bytes(s.ReportedBytes), s.Items)
+}
+
+// problemCleaners returns the skipped and failed cleaners.
+func problemCleaners(r *Report) []CleanerReport {
+	var out []CleanerReport
+
+	for _, c := range r.Cleaners {
+		if c.Status != StatusSuccess {
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,50 @@
+package format
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlFormatter renders the report as YAML with the field names and order
+// of the JSON output, so both follow schema/report.v1.json.
+type yamlFormatter struct{}
+
+func (yamlFormatter) Name() string { return "yaml" }
+
+func (yamlFormatter) Render(w io.Writer, r *Report) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML output (command=%s): %w", r.Command, err)
+	}
+
+	// JSON is YAML: decoding it into a node keeps the key order, and
+	// clearing the flow and quoting styles turns it into block YAML.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert JSON output to YAML: %w", err)
+	}
+
+	clearStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to write YAML output: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// clearStyle resets the style of node and its children to block YAML. The
+// encoder still quotes strings that would otherwise read as another type.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
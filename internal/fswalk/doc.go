@@ -0,0 +1,18 @@
+// Package fswalk is the filesystem walker shared by all scanners.
+//
+// Directories are read in batches with getdents (getdirentries on macOS) and
+// every entry is stat'ed relative to its open directory, so no path is
+// resolved twice. A bounded worker pool reads directories in parallel, and
+// walks stop promptly when their context is cancelled.
+//
+// Directory sizes are cached in process, keyed by directory path and
+// modification time: a cleaner's Clean re-measuring what its Scan just
+// measured, or two cleaners measuring overlapping trees, costs one stat per
+// directory instead of re-reading every file. A directory whose entries
+// change gets a new modification time and is read again; files rewritten in
+// place are not noticed until their directory changes.
+//
+// An Index attached to a walker persists these summaries between runs, with
+// the directory's device and inode added to the fingerprint and a maximum
+// age after which a directory is read again regardless.
+package fswalk
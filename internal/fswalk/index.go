@@ -0,0 +1,269 @@
+package fswalk
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"hash/crc32"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/state"
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// Index file settings.
+const (
+	// IndexFileName is the name of the size index in the state directory.
+	IndexFileName = "size-index.json"
+	// IndexVersion is the format version of the size index.
+	IndexVersion = 1
+	// IndexMaxAge is how long a directory summary is trusted before the
+	// directory is read again, bounding how long files rewritten in place
+	// (which do not change their directory's mtime) can be reported stale.
+	IndexMaxAge = 7 * 24 * time.Hour
+)
+
+// indexFile is the on-disk form of an Index.
+type indexFile struct {
+	Version int `json:"version"`
+	// Checksum is the CRC-32 of the encoded Dirs, so that a damaged file
+	// that still parses is detected.
+	Checksum uint32       `json:"checksum"`
+	Dirs     []indexedDir `json:"dirs"`
+}
+
+// indexedDir is the summary of one directory's direct entries together with
+// the fingerprint it was read under.
+type indexedDir struct {
+	Path      string        `json:"path"`
+	Dev       uint64        `json:"dev"`
+	Ino       uint64        `json:"ino"`
+	MTime     int64         `json:"mtime"`
+	Read      int64         `json:"read"`
+	Files     int           `json:"files"`
+	Apparent  int64         `json:"apparent"`
+	Allocated int64         `json:"allocated"`
+	ModTime   int64         `json:"mod_time"`
+	Subdirs   []string      `json:"subdirs,omitempty"`
+	Links     []indexedLink `json:"links,omitempty"`
+}
+
+// indexedLink is a hardlinked file of an indexed directory; it lives on the
+// directory's device.
+type indexedLink struct {
+	Name      string `json:"name"`
+	Ino       uint64 `json:"ino"`
+	Nlink     uint64 `json:"nlink"`
+	Size      int64  `json:"size"`
+	Allocated int64  `json:"allocated"`
+}
+
+// Index persists directory summaries between runs. A directory whose device,
+// inode and mtime still match its entry, and whose entry is younger than
+// IndexMaxAge, is summarized from the index instead of being read, so a
+// repeat scan stats each directory once but reads only the changed ones.
+// It is safe for concurrent use.
+type Index struct {
+	mu     sync.Mutex
+	dirs   map[string]indexedDir
+	reused int
+	read   int
+}
+
+// NewIndex creates an empty index; summarizing with it reads every directory
+// and records the result.
+func NewIndex() *Index {
+	return &Index{dirs: make(map[string]indexedDir)} //nolint:exhaustruct
+}
+
+// LoadIndex reads the index at path. It always returns a usable index: on
+// error it is empty, so the next walk is a full one. A missing file matches
+// fs.ErrNotExist, a damaged file is a Corruption and a file of another
+// format version a Conflict.
+func LoadIndex(path string) (*Index, error) {
+	var file indexFile
+
+	err := state.ReadJSON(path, &file)
+	if err != nil {
+		return NewIndex(), err
+	}
+
+	if file.Version != IndexVersion {
+		return NewIndex(), errorfamily.NewConflict(
+			"fswalk.index_version",
+			"size index was written by an incompatible clean-wizard version",
+		)
+	}
+
+	sum, err := checksum(file.Dirs)
+	if err != nil || sum != file.Checksum {
+		return NewIndex(), errorfamily.NewCorruption("fswalk.index_checksum", "size index "+path+" is damaged")
+	}
+
+	x := NewIndex()
+	for _, d := range file.Dirs {
+		if d.Path == "" || !filepath.IsAbs(d.Path) {
+			continue
+		}
+
+		x.dirs[d.Path] = d
+	}
+
+	return x, nil
+}
+
+// Save atomically writes the index to path, dropping entries too old to be
+// trusted.
+func (x *Index) Save(path string) error {
+	x.mu.Lock()
+
+	cutoff := time.Now().Add(-IndexMaxAge).UnixNano()
+	file := indexFile{Version: IndexVersion, Checksum: 0, Dirs: make([]indexedDir, 0, len(x.dirs))}
+
+	for _, d := range x.dirs {
+		if d.Read >= cutoff {
+			file.Dirs = append(file.Dirs, d)
+		}
+	}
+
+	x.mu.Unlock()
+
+	slices.SortFunc(file.Dirs, func(a, b indexedDir) int { return strings.Compare(a.Path, b.Path) })
+
+	sum, err := checksum(file.Dirs)
+	if err != nil {
+		return fmt.Errorf("failed to encode size index: %w", err)
+	}
+
+	file.Checksum = sum
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode size index: %w", err)
+	}
+
+	return state.WriteFile(path, data)
+}
+
+// Len returns the number of indexed directories.
+func (x *Index) Len() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return len(x.dirs)
+}
+
+// Stats returns how many directory summaries were reused without reading
+// the directory and how many directories were read since the index was
+// created.
+func (x *Index) Stats() (reused, read int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return x.reused, x.read
+}
+
+// lookup returns the indexed summary of dir if its fingerprint is unchanged
+// and the entry is recent enough.
+func (x *Index) lookup(dir Entry) (*dirSummary, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	d, ok := x.dirs[dir.Path]
+	if !ok || d.Dev != dir.Dev || d.Ino != dir.Ino || d.MTime != dir.ModTime.UnixNano() {
+		return nil, false
+	}
+
+	read := time.Unix(0, d.Read)
+	if time.Since(read) > IndexMaxAge {
+		return nil, false
+	}
+
+	x.reused++
+
+	summary := &dirSummary{
+		own: Summary{
+			Files:     d.Files,
+			Apparent:  d.Apparent,
+			Allocated: d.Allocated,
+			ModTime:   time.Unix(0, d.ModTime),
+			Links:     make([]Entry, 0, len(d.Links)),
+			Ignored:   0,
+		},
+		subdirs: make([]string, 0, len(d.Subdirs)),
+		read:    read,
+	}
+
+	for _, name := range d.Subdirs {
+		summary.subdirs = append(summary.subdirs, filepath.Join(dir.Path, name))
+	}
+
+	for _, l := range d.Links {
+		summary.own.Links = append(summary.own.Links, Entry{ //nolint:exhaustruct
+			Path:      filepath.Join(dir.Path, l.Name),
+			Size:      l.Size,
+			Allocated: l.Allocated,
+			Dev:       dir.Dev,
+			Ino:       l.Ino,
+			Nlink:     l.Nlink,
+		})
+	}
+
+	return summary, true
+}
+
+// record stores the summary of dir; fresh reports whether the directory was
+// just read rather than taken from the in-process cache.
+func (x *Index) record(dir Entry, summary *dirSummary, fresh bool) {
+	d := indexedDir{
+		Path:      dir.Path,
+		Dev:       dir.Dev,
+		Ino:       dir.Ino,
+		MTime:     dir.ModTime.UnixNano(),
+		Read:      summary.read.UnixNano(),
+		Files:     summary.own.Files,
+		Apparent:  summary.own.Apparent,
+		Allocated: summary.own.Allocated,
+		ModTime:   summary.own.ModTime.UnixNano(),
+		Subdirs:   make([]string, 0, len(summary.subdirs)),
+		Links:     make([]indexedLink, 0, len(summary.own.Links)),
+	}
+
+	for _, sub := range summary.subdirs {
+		d.Subdirs = append(d.Subdirs, filepath.Base(sub))
+	}
+
+	for _, l := range summary.own.Links {
+		d.Links = append(d.Links, indexedLink{
+			Name:      filepath.Base(l.Path),
+			Ino:       l.Ino,
+			Nlink:     l.Nlink,
+			Size:      l.Size,
+			Allocated: l.Allocated,
+		})
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.dirs[dir.Path] = d
+
+	if fresh {
+		x.read++
+	} else {
+		x.reused++
+	}
+}
+
+// checksum returns the CRC-32 of the encoded directory entries.
+func checksum(dirs []indexedDir) (uint32, error) {
+	data, err := json.Marshal(dirs)
+	if err != nil {
+		return 0, err
+	}
+
+	return crc32.ChecksumIEEE(data), nil
+}
@@ -0,0 +1,119 @@
+package fswalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/larsartmann/go-error-family/errorfamilytest"
+)
+
+func TestIndex_RepeatScanReadsOnlyChangedDirectories(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{
+		"a.txt":          100,
+		"sub/b.txt":      200,
+		"sub/deep/c.txt": 300,
+	})
+
+	indexPath := filepath.Join(t.TempDir(), IndexFileName)
+
+	first := New(2)
+	first.UseIndex(NewIndex())
+
+	want, err := first.Summarize(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if err := first.index.Load().Save(indexPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A new process: empty in-memory cache, index loaded from disk.
+	index, err := LoadIndex(indexPath)
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	second := New(2)
+	second.UseIndex(index)
+
+	got, err := second.Summarize(context.Background(), root)
+	if err != nil || got.Apparent != want.Apparent || got.Files != 3 {
+		t.Fatalf("Summarize() from index = %+v, %v, want %+v", got, err, want)
+	}
+
+	if reused, read := index.Stats(); reused != 3 || read != 0 {
+		t.Errorf("Stats() = %d reused, %d read, want 3 reused, 0 read", reused, read)
+	}
+
+	writeTree(t, root, map[string]int{"sub/deep/d.txt": 50})
+
+	// Keep the mtime change visible on filesystems with coarse timestamps.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "sub", "deep"), future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	third := New(2)
+	third.UseIndex(index)
+
+	got, _ = third.Summarize(context.Background(), root)
+	if got.Apparent != want.Apparent+50 {
+		t.Errorf("Apparent after change = %d, want %d", got.Apparent, want.Apparent+50)
+	}
+
+	if reused, read := index.Stats(); reused != 5 || read != 1 {
+		t.Errorf("Stats() = %d reused, %d read, want only sub/deep read again", reused, read)
+	}
+}
+
+func TestLoadIndex_FallsBackToEmptyIndex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	index, err := LoadIndex(filepath.Join(dir, "missing.json"))
+	if index == nil || index.Len() != 0 || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadIndex(missing) = %v, %v, want empty index and fs.ErrNotExist", index, err)
+	}
+
+	garbage := filepath.Join(dir, "garbage.json")
+	if err := os.WriteFile(garbage, []byte(`{"version":1,"dirs":[{"pa`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err = LoadIndex(garbage)
+	errorfamilytest.AssertFamily(t, err, errorfamily.Corruption)
+
+	if index.Len() != 0 {
+		t.Errorf("LoadIndex(garbage).Len() = %d, want 0", index.Len())
+	}
+
+	// Parseable, but a size was altered after the checksum was computed.
+	tampered := filepath.Join(dir, "tampered.json")
+
+	x := NewIndex()
+	x.dirs["/data"] = indexedDir{Path: "/data", Read: time.Now().UnixNano(), Apparent: 10} //nolint:exhaustruct
+
+	if err := x.Save(tampered); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(tampered)
+	if err := os.WriteFile(tampered, []byte(strings.Replace(string(data), `"apparent":10`, `"apparent":99`, 1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LoadIndex(tampered)
+	errorfamilytest.AssertFamily(t, err, errorfamily.Corruption)
+}
@@ -0,0 +1,460 @@
+package fswalk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+	"github.com/maypok86/otter/v2"
+	"golang.org/x/sys/unix"
+)
+
+// Walker defaults.
+const (
+	// DefaultCacheSize is the number of directories whose listing summary is cached.
+	DefaultCacheSize = 200_000
+	// direntBufferSize is the size of one batched directory read.
+	direntBufferSize = 32 * 1024
+	// statBlockSize is the unit of Stat_t.Blocks on every supported Unix.
+	statBlockSize = 512
+)
+
+// SkipDir returned by a VisitFunc for a directory skips its contents.
+var SkipDir = fs.SkipDir //nolint:gochecknoglobals
+
+// Entry is one directory entry, stat'ed without following symlinks.
+type Entry struct {
+	Path      string
+	Mode      fs.FileMode
+	Size      int64
+	Allocated int64
+	ModTime   time.Time
+	Dev       uint64
+	Ino       uint64
+	Nlink     uint64
+}
+
+// IsDir reports whether the entry is a directory.
+func (e Entry) IsDir() bool { return e.Mode.IsDir() }
+
+// VisitFunc is called for every entry below the walked root. Returning
+// SkipDir for a directory skips its contents; any other error stops the walk.
+type VisitFunc func(Entry) error
+
+// Summary is the size of a tree. Links lists the files with more than one
+// hardlink, which callers deduplicating across trees need; all other files
+// are only summed. Ignored counts the entries left out by ignore rules,
+// including the ignore files themselves.
+type Summary struct {
+	Files     int
+	Apparent  int64
+	Allocated int64
+	ModTime   time.Time
+	Links     []Entry
+	Ignored   int
+}
+
+func (s *Summary) add(o Summary) {
+	s.Files += o.Files
+	s.Ignored += o.Ignored
+	s.Apparent += o.Apparent
+	s.Allocated += o.Allocated
+	s.Links = append(s.Links, o.Links...)
+
+	if o.ModTime.After(s.ModTime) {
+		s.ModTime = o.ModTime
+	}
+}
+
+// dirKey identifies a directory listing: its path and modification time.
+type dirKey struct {
+	path  string
+	mtime int64
+}
+
+// dirSummary is the cached summary of a directory's direct entries.
+type dirSummary struct {
+	own     Summary
+	subdirs []string
+	// read is when the directory was read.
+	read time.Time
+}
+
+// Walker walks trees with a bounded pool of directory readers and caches
+// directory summaries. It is safe for concurrent use.
+type Walker struct {
+	slots chan struct{}
+	cache *otter.Cache[dirKey, *dirSummary]
+	index atomic.Pointer[Index]
+}
+
+// New creates a walker reading up to workers directories at once
+// (runtime.NumCPU() if workers <= 0).
+func New(workers int) *Walker {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &Walker{ //nolint:exhaustruct
+		slots: make(chan struct{}, workers),
+		cache: otter.Must(&otter.Options[dirKey, *dirSummary]{ //nolint:exhaustruct
+			MaximumSize: DefaultCacheSize,
+		}),
+	}
+}
+
+// UseIndex makes Summarize consult and update x, a persistent index of
+// directory summaries; nil detaches the current index.
+func (w *Walker) UseIndex(x *Index) {
+	w.index.Store(x)
+}
+
+// Shared returns the process-wide walker used by all scanners, so that
+// they share one worker pool and one size cache.
+var Shared = sync.OnceValue(func() *Walker { return New(0) }) //nolint:gochecknoglobals
+
+// Lstat stats a single path without following symlinks.
+func Lstat(path string) (Entry, error) {
+	var st unix.Stat_t
+	if err := unix.Lstat(path, &st); err != nil {
+		return Entry{}, &fs.PathError{Op: "lstat", Path: path, Err: err}
+	}
+
+	return entryFromStat(path, &st), nil
+}
+
+// ReadDir reads the entries of one directory in batches and stats each of
+// them relative to the open directory.
+func ReadDir(dir string) ([]Entry, error) {
+	fd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: dir, Err: err}
+	}
+	defer unix.Close(fd)
+
+	var (
+		entries []Entry
+		names   []string
+		buf     = make([]byte, direntBufferSize)
+	)
+
+	for {
+		n, err := unix.ReadDirent(fd, buf)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+
+		if err != nil {
+			return entries, &fs.PathError{Op: "readdirent", Path: dir, Err: err}
+		}
+
+		if n <= 0 {
+			break
+		}
+
+		names = names[:0]
+		_, _, names = unix.ParseDirent(buf[:n], -1, names)
+
+		for _, name := range names {
+			var st unix.Stat_t
+			if err := unix.Fstatat(fd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+				continue // removed meanwhile or not accessible
+			}
+
+			entries = append(entries, entryFromStat(filepath.Join(dir, name), &st))
+		}
+	}
+
+	return entries, nil
+}
+
+// Walk calls fn for every entry below root (not root itself), reading
+// directories in parallel. Calls of fn are serialized, but their order
+// between directories is not deterministic. Unreadable directories and
+// entries excluded by the context's ignore matcher (see package ignore) are
+// skipped. Walk returns ctx.Err() when cancelled, or the first error fn
+// returned other than SkipDir.
+func (w *Walker) Walk(ctx context.Context, root string, fn VisitFunc) error {
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+		ignores  = ignore.FromContext(ctx)
+	)
+
+	if ignores.Exclude(root, true) {
+		return nil
+	}
+
+	stop := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return firstErr != nil
+	}
+
+	var visit func(dir string)
+	visit = func(dir string) {
+		if ctx.Err() != nil || stop() {
+			return
+		}
+
+		entries, _ := ReadDir(dir)
+		entries = slices.DeleteFunc(entries, func(e Entry) bool { return ignores.Exclude(e.Path, e.IsDir()) })
+
+		var descend []string
+
+		mu.Lock()
+		for _, e := range entries {
+			if firstErr != nil {
+				break
+			}
+
+			err := fn(e)
+
+			switch {
+			case err == nil:
+				if e.IsDir() {
+					descend = append(descend, e.Path)
+				}
+			case errors.Is(err, SkipDir):
+			default:
+				firstErr = err
+			}
+		}
+		mu.Unlock()
+
+		for _, sub := range descend {
+			w.spawn(&wg, func() { visit(sub) })
+		}
+	}
+
+	visit(root)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return firstErr
+}
+
+// Summarize returns the size of the tree at root, a file or a directory,
+// using cached directory summaries where the directory is unchanged.
+// Entries excluded by the context's ignore matcher are left out like in
+// Walk; directories some ignore rule applies to are read without the cache,
+// whose summaries do not depend on the rules.
+func (w *Walker) Summarize(ctx context.Context, root string) (Summary, error) {
+	rootEntry, err := Lstat(root)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	if !rootEntry.IsDir() {
+		return fileSummary(rootEntry), nil
+	}
+
+	var (
+		mu      sync.Mutex
+		total   Summary
+		wg      sync.WaitGroup
+		ignores = ignore.FromContext(ctx)
+	)
+
+	total.ModTime = rootEntry.ModTime
+
+	var summarize func(dir Entry)
+	summarize = func(dir Entry) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		summary := w.dirSummary(dir)
+		if ignores.HasRules(dir.Path) {
+			summary = keptEntries(dir, ignores)
+		}
+
+		mu.Lock()
+		total.add(summary.own)
+		mu.Unlock()
+
+		for _, sub := range summary.subdirs {
+			w.spawn(&wg, func() {
+				subEntry, err := Lstat(sub)
+				if err != nil || !subEntry.IsDir() {
+					return
+				}
+
+				summarize(subEntry)
+			})
+		}
+	}
+
+	summarize(rootEntry)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	return total, nil
+}
+
+// dirSummary returns the summary of dir's direct entries, from the cache or
+// the attached index when dir has not been modified since it was read.
+func (w *Walker) dirSummary(dir Entry) *dirSummary {
+	index := w.index.Load()
+
+	key := dirKey{path: dir.Path, mtime: dir.ModTime.UnixNano()}
+	if cached, ok := w.cache.GetIfPresent(key); ok {
+		if index != nil {
+			index.record(dir, cached, false)
+		}
+
+		return cached
+	}
+
+	if index != nil {
+		if indexed, ok := index.lookup(dir); ok {
+			w.cache.Set(key, indexed)
+
+			return indexed
+		}
+	}
+
+	entries, _ := ReadDir(dir.Path)
+
+	summary := &dirSummary{own: Summary{ModTime: dir.ModTime}, read: time.Now()} //nolint:exhaustruct
+
+	for _, e := range entries {
+		if e.IsDir() {
+			summary.subdirs = append(summary.subdirs, e.Path)
+
+			if e.ModTime.After(summary.own.ModTime) {
+				summary.own.ModTime = e.ModTime
+			}
+
+			continue
+		}
+
+		summary.own.add(fileSummary(e))
+	}
+
+	w.cache.Set(key, summary)
+
+	if index != nil {
+		index.record(dir, summary, true)
+	}
+
+	return summary
+}
+
+// keptEntries summarizes the direct entries of dir that ignores does not
+// exclude. Ignore files are counted as ignored: removing the tree would
+// remove the rules that protect it.
+func keptEntries(dir Entry, ignores *ignore.Matcher) *dirSummary {
+	entries, _ := ReadDir(dir.Path)
+
+	summary := &dirSummary{own: Summary{ModTime: dir.ModTime}, read: time.Now()} //nolint:exhaustruct
+
+	for _, e := range entries {
+		if filepath.Base(e.Path) == ignore.FileName || ignores.Exclude(e.Path, e.IsDir()) {
+			summary.own.Ignored++
+
+			continue
+		}
+
+		if e.IsDir() {
+			summary.subdirs = append(summary.subdirs, e.Path)
+
+			if e.ModTime.After(summary.own.ModTime) {
+				summary.own.ModTime = e.ModTime
+			}
+
+			continue
+		}
+
+		summary.own.add(fileSummary(e))
+	}
+
+	return summary
+}
+
+// spawn runs task on a free worker, or inline when all workers are busy,
+// so that nested walks never wait for each other.
+func (w *Walker) spawn(wg *sync.WaitGroup, task func()) {
+	select {
+	case w.slots <- struct{}{}:
+		wg.Go(func() {
+			defer func() { <-w.slots }()
+
+			task()
+		})
+	default:
+		task()
+	}
+}
+
+// fileSummary is the summary of a single non-directory entry.
+func fileSummary(e Entry) Summary {
+	s := Summary{Files: 1, Apparent: e.Size, Allocated: e.Allocated, ModTime: e.ModTime, Links: nil, Ignored: 0}
+	if e.Nlink > 1 {
+		s.Links = []Entry{e}
+	}
+
+	return s
+}
+
+func entryFromStat(path string, st *unix.Stat_t) Entry {
+	return Entry{
+		Path:      path,
+		Mode:      fileMode(uint32(st.Mode)), //nolint:unconvert // Mode is uint16 on darwin
+		Size:      st.Size,
+		Allocated: int64(st.Blocks) * statBlockSize,                   //nolint:unconvert
+		ModTime:   time.Unix(int64(st.Mtim.Sec), int64(st.Mtim.Nsec)), //nolint:unconvert
+		Dev:       uint64(st.Dev),                                     //nolint:unconvert // Dev is int32 on darwin
+		Ino:       st.Ino,
+		Nlink:     uint64(st.Nlink), //nolint:unconvert // Nlink is uint16 on darwin
+	}
+}
+
+// fileMode converts st_mode to an fs.FileMode, as os.Lstat does.
+func fileMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0o777)
+
+	switch mode & unix.S_IFMT {
+	case unix.S_IFDIR:
+		m |= fs.ModeDir
+	case unix.S_IFLNK:
+		m |= fs.ModeSymlink
+	case unix.S_IFIFO:
+		m |= fs.ModeNamedPipe
+	case unix.S_IFSOCK:
+		m |= fs.ModeSocket
+	case unix.S_IFBLK:
+		m |= fs.ModeDevice
+	case unix.S_IFCHR:
+		m |= fs.ModeDevice | fs.ModeCharDevice
+	}
+
+	if mode&unix.S_ISUID != 0 {
+		m |= fs.ModeSetuid
+	}
+
+	if mode&unix.S_ISGID != 0 {
+		m |= fs.ModeSetgid
+	}
+
+	if mode&unix.S_ISVTX != 0 {
+		m |= fs.ModeSticky
+	}
+
+	return m
+}
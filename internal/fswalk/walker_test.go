@@ -0,0 +1,231 @@
+package fswalk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/LarsArtmann/clean-wizard/internal/ignore"
+)
+
+func writeTree(t *testing.T, root string, files map[string]int) {
+	t.Helper()
+
+	for rel, size := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWalker_WalkVisitsAllAndSkipsDirs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{
+		"a.txt":           1,
+		"sub/b.txt":       2,
+		"sub/deep/c.txt":  3,
+		"skip/hidden.txt": 4,
+	})
+
+	var visited []string
+
+	err := New(2).Walk(context.Background(), root, func(e Entry) error {
+		rel, _ := filepath.Rel(root, e.Path)
+		visited = append(visited, rel)
+
+		if e.IsDir() && rel == "skip" {
+			return SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	slices.Sort(visited)
+
+	want := []string{"a.txt", "skip", "sub", "sub/b.txt", "sub/deep", "sub/deep/c.txt"}
+	if !slices.Equal(visited, want) {
+		t.Errorf("visited = %v, want %v", visited, want)
+	}
+}
+
+func TestWalker_WalkStopsOnErrorAndCancellation(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{"a": 1, "b": 1, "c/d": 1})
+
+	errStop := errors.New("stop")
+
+	err := New(0).Walk(context.Background(), root, func(Entry) error { return errStop })
+	if !errors.Is(err, errStop) {
+		t.Errorf("Walk() error = %v, want %v", err, errStop)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := New(0).Walk(ctx, root, func(Entry) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Walk() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWalker_SummarizeUsesCacheUntilDirectoryChanges(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{"a": 100, "sub/b": 200})
+
+	w := New(0)
+
+	summary, err := w.Summarize(context.Background(), root)
+	if err != nil || summary.Files != 2 || summary.Apparent != 300 {
+		t.Fatalf("Summarize() = %+v, %v, want 2 files / 300 bytes", summary, err)
+	}
+
+	// Rewriting a file in place keeps its directory's mtime: the cached
+	// summary is used.
+	sub := filepath.Join(root, "sub")
+	subInfo, _ := os.Stat(sub)
+
+	if err := os.WriteFile(filepath.Join(sub, "b"), make([]byte, 5000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(sub, subInfo.ModTime(), subInfo.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+
+	if cached, _ := w.Summarize(context.Background(), root); cached.Apparent != 300 {
+		t.Errorf("cached Summarize().Apparent = %d, want 300", cached.Apparent)
+	}
+
+	// Adding an entry changes the directory's mtime: it is read again.
+	writeTree(t, root, map[string]int{"sub/c": 1})
+
+	if err := os.Chtimes(sub, time.Now().Add(time.Minute), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if fresh, _ := w.Summarize(context.Background(), root); fresh.Apparent != 100+5000+1 || fresh.Files != 3 {
+		t.Errorf("fresh Summarize() = %+v, want 3 files / 5101 bytes", fresh)
+	}
+}
+
+func TestWalker_SummarizeReportsHardlinks(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{"a": 10})
+
+	if err := os.Link(filepath.Join(root, "a"), filepath.Join(root, "b")); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+
+	summary, err := New(0).Summarize(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Links) != 2 || summary.Links[0].Ino != summary.Links[1].Ino {
+		t.Errorf("Links = %+v, want both links of one inode", summary.Links)
+	}
+}
+
+func TestWalker_WalkHonoursIgnoreFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{
+		"a.tmp":          1,
+		"keep.tmp":       1,
+		"precious/b.tmp": 1,
+		"nested/c.tmp":   1,
+		"nested/d.tmp":   1,
+		ignore.FileName:  0,
+	})
+
+	rules := "precious/\nkeep.tmp\n"
+	if err := os.WriteFile(filepath.Join(root, ignore.FileName), []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "nested", ignore.FileName), []byte("d.tmp\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := ignore.NewMatcher(nil)
+	ctx := ignore.WithMatcher(context.Background(), m)
+
+	var visited []string
+
+	err := New(2).Walk(ctx, root, func(e Entry) error {
+		if filepath.Ext(e.Path) == ".tmp" {
+			rel, _ := filepath.Rel(root, e.Path)
+			visited = append(visited, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	slices.Sort(visited)
+
+	if want := []string{"a.tmp", "nested/c.tmp"}; !slices.Equal(visited, want) {
+		t.Errorf("visited = %v, want %v", visited, want)
+	}
+
+	if got := len(m.Exclusions()); got != 3 {
+		t.Errorf("Exclusions() = %d entries, want precious, keep.tmp and nested/d.tmp", got)
+	}
+}
+
+func TestWalker_SummarizeHonoursIgnoreFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]int{
+		"a.bin":          10,
+		"precious/b.bin": 100,
+		"nested/c.bin":   1000,
+	})
+
+	if err := os.WriteFile(filepath.Join(root, ignore.FileName), []byte("precious/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(2)
+
+	summary, err := w.Summarize(ignore.WithMatcher(context.Background(), ignore.NewMatcher(nil)), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Apparent != 1010 || summary.Files != 2 || summary.Ignored != 2 {
+		t.Errorf("Summarize() = %+v, want a.bin and nested/c.bin, with precious and the ignore file ignored", summary)
+	}
+
+	summary, err = w.Summarize(ignore.WithMatcher(context.Background(), ignore.NewNopMatcher()), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Files != 4 || summary.Ignored != 0 {
+		t.Errorf("Summarize() without ignore rules = %+v, want every file", summary)
+	}
+}
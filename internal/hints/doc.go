@@ -0,0 +1,9 @@
+// Package hints is the registry of remediation hints: one concrete sentence
+// telling the user how to fix a problem, keyed by the go-error-family code of
+// the error reporting it (e.g. cleaner.docker.daemon_unreachable).
+//
+// Cleaners populate the registry from their declared prerequisites when they
+// are registered; the CLI looks hints up for skipped and failed cleaners, in
+// JSON results, and in the doctor report. Codes of the form
+// cleaner.<name>.<problem> fall back to the generic cleaner.<problem> hint.
+package hints
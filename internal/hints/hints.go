@@ -0,0 +1,110 @@
+package hints
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// Generic cleaner problems, the last segment of cleaner.<name>.<problem>
+// codes.
+const (
+	// NotAvailable: a required binary or runtime is missing.
+	NotAvailable = "not_available"
+	// OptionalMissing: a binary that improves a cleaner is missing.
+	OptionalMissing = "optional_missing"
+	// DaemonUnreachable: a service the cleaner talks to does not answer.
+	DaemonUnreachable = "daemon_unreachable"
+	// PermissionDenied: the cleaner may not read or delete what it found.
+	PermissionDenied = "permission_denied"
+)
+
+// CleanerCode returns the code of a cleaner problem: cleaner.<name>.<problem>.
+func CleanerCode(cleanerName, problem string) string {
+	return "cleaner." + cleanerName + "." + problem
+}
+
+// registry maps codes to functions producing their hints, so that hints
+// that are expensive to compute are only computed when shown.
+type registry struct {
+	mu    sync.RWMutex
+	hints map[string]func() string
+}
+
+//nolint:gochecknoglobals
+var defaultRegistry = &registry{hints: map[string]func() string{
+	"cleaner." + NotAvailable:      func() string { return "Install the tool the cleaner needs; 'clean-wizard doctor' shows which one" },
+	"cleaner." + DaemonUnreachable: func() string { return "Start the service the cleaner talks to; 'clean-wizard doctor' shows which one" },
+	"cleaner." + PermissionDenied: func() string {
+		return "Run clean-wizard as the owner of the files, with sudo for system directories such as /var/cache, or add them to .cleanwizardignore"
+	},
+	"config.load": func() string {
+		return "Fix the reported field, or create a valid configuration with: clean-wizard init --minimal"
+	},
+}}
+
+// Register sets the hint for code, replacing any earlier one. An empty hint
+// is ignored.
+func Register(code, hint string) {
+	if hint == "" {
+		return
+	}
+
+	RegisterFunc(code, func() string { return hint })
+}
+
+// RegisterFunc sets a hint computed when it is looked up, for hints that
+// depend on the system (e.g. which package manager is installed).
+func RegisterFunc(code string, hint func() string) {
+	defaultRegistry.mu.Lock()
+	defaultRegistry.hints[code] = hint
+	defaultRegistry.mu.Unlock()
+}
+
+// ForCode returns the hint registered for code, falling back from
+// cleaner.<name>.<problem> to cleaner.<problem>; "" if there is none.
+func ForCode(code string) string {
+	if code == "" {
+		return ""
+	}
+
+	defaultRegistry.mu.RLock()
+	hint, ok := defaultRegistry.hints[code]
+
+	if !ok {
+		if rest, found := strings.CutPrefix(code, "cleaner."); found {
+			if i := strings.LastIndexByte(rest, '.'); i >= 0 {
+				hint, ok = defaultRegistry.hints["cleaner."+rest[i+1:]]
+			}
+		}
+	}
+	defaultRegistry.mu.RUnlock()
+
+	if !ok {
+		return ""
+	}
+
+	return hint()
+}
+
+// ForError returns the hint for an error reported by the named cleaner: the
+// hint of the error's code or, for errors without a code, of the problem
+// the error indicates. "" if there is none.
+func ForError(cleanerName string, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if code := errorfamily.Code(err); code != "" {
+		return ForCode(code)
+	}
+
+	if errors.Is(err, fs.ErrPermission) {
+		return ForCode(CleanerCode(cleanerName, PermissionDenied))
+	}
+
+	return ""
+}
@@ -0,0 +1,68 @@
+package hints
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+func TestForCode_FallsBackToGenericProblem(t *testing.T) {
+	t.Parallel()
+
+	Register("cleaner.hints-test.not_available", "Install hints-test")
+
+	if got := ForCode("cleaner.hints-test.not_available"); got != "Install hints-test" {
+		t.Errorf("ForCode(specific) = %q, want the registered hint", got)
+	}
+
+	if got, want := ForCode("cleaner.unknown.daemon_unreachable"), ForCode("cleaner.daemon_unreachable"); got == "" || got != want {
+		t.Errorf("ForCode(unknown cleaner) = %q, want the generic hint %q", got, want)
+	}
+
+	if got := ForCode("state.corrupt"); got != "" {
+		t.Errorf("ForCode(unregistered) = %q, want none", got)
+	}
+}
+
+func TestRegisterFunc_ComputesOnLookup(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	RegisterFunc("cleaner.hints-lazy.not_available", func() string {
+		calls++
+
+		return "computed"
+	})
+
+	if calls != 0 {
+		t.Fatalf("hint computed %d times at registration, want 0", calls)
+	}
+
+	if got := ForCode("cleaner.hints-lazy.not_available"); got != "computed" || calls != 1 {
+		t.Errorf("ForCode() = %q after %d calls, want computed after 1", got, calls)
+	}
+}
+
+func TestForError(t *testing.T) {
+	t.Parallel()
+
+	Register("cleaner.hints-err.permission_denied", "Run with sudo")
+
+	coded := fmt.Errorf("wrapped: %w", errorfamily.NewInfrastructure("cleaner.hints-test.not_available", "missing"))
+	if got := ForError("hints-test", coded); got == "" {
+		t.Error("ForError(coded) = \"\", want the code's hint")
+	}
+
+	denied := &fs.PathError{Op: "remove", Path: "/var/cache/x", Err: fs.ErrPermission}
+	if got := ForError("hints-err", denied); got != "Run with sudo" {
+		t.Errorf("ForError(permission) = %q, want the cleaner's permission hint", got)
+	}
+
+	if got := ForError("hints-err", errors.New("boom")); got != "" {
+		t.Errorf("ForError(plain) = %q, want none", got)
+	}
+}
@@ -0,0 +1,15 @@
+// Package ignore implements .cleanwizardignore files: gitignore-syntax
+// exclusions honoured by every filesystem-based cleaner.
+//
+// An ignore file applies to the directory it is in and everything below it,
+// like a .gitignore; files in deeper directories take precedence over files
+// closer to the root, and later lines over earlier ones. A global ignore file
+// in the user config directory (see GlobalFile) applies everywhere, with
+// anchored patterns relative to the home directory.
+//
+// Supported syntax: blank lines and # comments, ! negation, a trailing / to
+// match directories only, a / anywhere else to anchor the pattern to the
+// ignore file's directory, the wildcards *, ? and [...], and ** matching any
+// number of directories. As in git, nothing inside an ignored directory can
+// be re-included.
+package ignore
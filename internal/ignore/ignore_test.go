@@ -0,0 +1,117 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRule_GitignoreSyntax(t *testing.T) {
+	t.Parallel()
+
+	base := "/home/me/projects"
+
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "/home/me/projects/a/b/debug.log", false, true},
+		{"*.log", "/home/me/projects/a/debug.txt", false, false},
+		{"build/", "/home/me/projects/x/build", true, true},
+		{"build/", "/home/me/projects/x/build", false, false},
+		{"/dist", "/home/me/projects/dist", true, true},
+		{"/dist", "/home/me/projects/app/dist", true, false},
+		{"app/target", "/home/me/projects/app/target", true, true},
+		{"app/target", "/home/me/projects/other/app/target", true, false},
+		{"**/target", "/home/me/projects/other/app/target", true, true},
+		{"app/**/cache", "/home/me/projects/app/cache", true, true},
+		{"app/**/cache", "/home/me/projects/app/a/b/cache", true, true},
+		{"keep/**", "/home/me/projects/keep/x", false, true},
+		{"keep/**", "/home/me/projects/keep", true, false},
+		{"ba?.[ch]", "/home/me/projects/bar.c", false, true},
+		{`\#notes`, "/home/me/projects/#notes", false, true},
+		{"trailing   ", "/home/me/projects/trailing", false, true},
+	}
+
+	for _, tt := range tests {
+		rules := Parse([]byte(tt.pattern), "test", base)
+		if len(rules) != 1 {
+			t.Fatalf("Parse(%q) = %d rules, want 1", tt.pattern, len(rules))
+		}
+
+		if got := rules[0].matches(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("%q matches %s (dir=%v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	if rules := Parse([]byte("# comment\n\n[unclosed\n"), "test", base); len(rules) != 0 {
+		t.Errorf("Parse() of comments and invalid patterns = %+v, want none", rules)
+	}
+}
+
+func writeIgnoreFile(t *testing.T, dir, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatcher_NestedFilesAndNegation(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeIgnoreFile(t, root, "*.bin\nvendor/\n")
+	writeIgnoreFile(t, filepath.Join(root, "tools"), "!keep.bin\n")
+
+	m := NewMatcher(Parse([]byte("*.iso\n"), "global", root))
+
+	tests := []struct {
+		path   string
+		isDir  bool
+		want   bool
+		source string
+	}{
+		{filepath.Join(root, "app", "out.bin"), false, true, filepath.Join(root, FileName)},
+		{filepath.Join(root, "tools", "keep.bin"), false, false, ""},
+		{filepath.Join(root, "tools", "other.bin"), false, true, filepath.Join(root, FileName)},
+		{filepath.Join(root, "app", "vendor", "deep", "file.go"), false, true, filepath.Join(root, FileName)},
+		{filepath.Join(root, "disk.iso"), false, true, "global"},
+		{filepath.Join(root, "app", "main.go"), false, false, ""},
+	}
+
+	for _, tt := range tests {
+		rule, got := m.Match(tt.path, tt.isDir)
+		if got != tt.want || rule.Source != tt.source {
+			t.Errorf("Match(%s) = %v by %q, want %v by %q", tt.path, got, rule.Source, tt.want, tt.source)
+		}
+	}
+}
+
+func TestMatcher_ExcludeRecordsExclusions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeIgnoreFile(t, root, "# keep these\nimportant/\n")
+
+	m := NewMatcher(nil)
+
+	if !m.Exclude(filepath.Join(root, "important"), true) {
+		t.Fatal("Exclude(important) = false, want true")
+	}
+
+	if m.Exclude(filepath.Join(root, "junk"), true) {
+		t.Error("Exclude(junk) = true, want false")
+	}
+
+	got := m.Exclusions()
+	if len(got) != 1 || got[0].Path != filepath.Join(root, "important") || got[0].Rule.Line != 2 {
+		t.Errorf("Exclusions() = %+v, want important excluded by line 2", got)
+	}
+}
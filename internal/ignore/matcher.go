@@ -0,0 +1,216 @@
+package ignore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Exclusion records a path excluded by an ignore rule.
+type Exclusion struct {
+	Path string `json:"path"`
+	Rule Rule   `json:"rule"`
+}
+
+// dirDecision is the cached ignore decision for a directory.
+type dirDecision struct {
+	rule    Rule
+	ignored bool
+}
+
+// Matcher decides whether paths are ignored, discovering ignore files in
+// every directory it is asked about and its ancestors. Ignore files are read
+// once per matcher, so create one per run. It is safe for concurrent use.
+type Matcher struct {
+	global   []Rule
+	disabled bool
+
+	mu       sync.Mutex
+	chains   map[string][]Rule
+	dirs     map[string]dirDecision
+	excluded map[string]Rule
+}
+
+// NewMatcher creates a matcher applying global everywhere, in addition to
+// the ignore files it discovers.
+func NewMatcher(global []Rule) *Matcher {
+	return &Matcher{ //nolint:exhaustruct
+		global:   global,
+		chains:   make(map[string][]Rule),
+		dirs:     make(map[string]dirDecision),
+		excluded: make(map[string]Rule),
+	}
+}
+
+// NewNopMatcher creates a matcher that ignores nothing, for callers that
+// need to see what ignore files would hide.
+func NewNopMatcher() *Matcher {
+	m := NewMatcher(nil)
+	m.disabled = true
+
+	return m
+}
+
+// GlobalFile returns the path of the global ignore file:
+// clean-wizard/ignore below the user config directory.
+func GlobalFile() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "clean-wizard", "ignore")
+}
+
+// NewDefaultMatcher creates a matcher with the rules of GlobalFile, whose
+// anchored patterns are relative to the home directory.
+func NewDefaultMatcher() *Matcher {
+	file := GlobalFile()
+	if file == "" {
+		return NewMatcher(nil)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = string(filepath.Separator)
+	}
+
+	return NewMatcher(LoadFile(file, home))
+}
+
+type matcherKey struct{}
+
+// WithMatcher returns a context carrying m for the cleaners of one run.
+func WithMatcher(ctx context.Context, m *Matcher) context.Context {
+	return context.WithValue(ctx, matcherKey{}, m)
+}
+
+// fallback is the matcher used by code running outside a workflow run.
+var fallback = sync.OnceValue(NewDefaultMatcher) //nolint:gochecknoglobals
+
+// FromContext returns the run's matcher, or a process-wide default matcher
+// when ctx carries none.
+func FromContext(ctx context.Context) *Matcher {
+	if m, ok := ctx.Value(matcherKey{}).(*Matcher); ok {
+		return m
+	}
+
+	return fallback()
+}
+
+// Match reports whether path is ignored and by which rule. A path inside an
+// ignored directory is ignored by the directory's rule.
+func (m *Matcher) Match(path string, isDir bool) (Rule, bool) {
+	if m.disabled {
+		return Rule{}, false //nolint:exhaustruct
+	}
+
+	path = filepath.Clean(path)
+
+	parent := filepath.Dir(path)
+	if parent != path {
+		if d := m.dirDecision(parent); d.ignored {
+			return d.rule, true
+		}
+	}
+
+	rules := m.chain(parent)
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].matches(path, isDir) {
+			if rules[i].negate {
+				return Rule{}, false //nolint:exhaustruct
+			}
+
+			return rules[i], true
+		}
+	}
+
+	return Rule{}, false //nolint:exhaustruct
+}
+
+// HasRules reports whether any rule applies to the entries of dir, from the
+// global rules or an ignore file in dir or one of its ancestors.
+func (m *Matcher) HasRules(dir string) bool {
+	return !m.disabled && len(m.chain(filepath.Clean(dir))) > 0
+}
+
+// Exclude is Match that also records the exclusion for Exclusions.
+func (m *Matcher) Exclude(path string, isDir bool) bool {
+	rule, ignored := m.Match(path, isDir)
+	if !ignored {
+		return false
+	}
+
+	m.mu.Lock()
+	m.excluded[filepath.Clean(path)] = rule
+	m.mu.Unlock()
+
+	return true
+}
+
+// Exclusions returns the paths excluded through Exclude, sorted by path.
+// Paths inside an excluded directory are only listed when they were asked
+// about themselves.
+func (m *Matcher) Exclusions() []Exclusion {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Exclusion, 0, len(m.excluded))
+	for path, rule := range m.excluded {
+		out = append(out, Exclusion{Path: path, Rule: rule})
+	}
+
+	slices.SortFunc(out, func(a, b Exclusion) int { return strings.Compare(a.Path, b.Path) })
+
+	return out
+}
+
+// dirDecision returns the cached ignore decision for dir.
+func (m *Matcher) dirDecision(dir string) dirDecision {
+	m.mu.Lock()
+	d, ok := m.dirs[dir]
+	m.mu.Unlock()
+
+	if ok {
+		return d
+	}
+
+	d.rule, d.ignored = m.Match(dir, true)
+
+	m.mu.Lock()
+	m.dirs[dir] = d
+	m.mu.Unlock()
+
+	return d
+}
+
+// chain returns the rules applying to the entries of dir: the global rules,
+// then those of the ignore files from the root down to dir.
+func (m *Matcher) chain(dir string) []Rule {
+	m.mu.Lock()
+	rules, ok := m.chains[dir]
+	m.mu.Unlock()
+
+	if ok {
+		return rules
+	}
+
+	inherited := m.global
+	if parent := filepath.Dir(dir); parent != dir {
+		inherited = m.chain(parent)
+	}
+
+	rules = inherited
+	if own := LoadFile(filepath.Join(dir, FileName), dir); len(own) > 0 {
+		rules = slices.Concat(inherited, own)
+	}
+
+	m.mu.Lock()
+	m.chains[dir] = rules
+	m.mu.Unlock()
+
+	return rules
+}
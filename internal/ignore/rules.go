@@ -0,0 +1,192 @@
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of ignore files discovered in scanned directories.
+const FileName = ".cleanwizardignore"
+
+// Rule is one pattern line of an ignore file.
+type Rule struct {
+	// Source is the ignore file the rule was read from.
+	Source string `json:"source"`
+	// Line is the rule's 1-based line number in Source.
+	Line int `json:"line"`
+	// Pattern is the line as written.
+	Pattern string `json:"pattern"`
+
+	base     string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+	segments []string
+}
+
+// String formats the rule as source:line (pattern).
+func (r Rule) String() string {
+	return fmt.Sprintf("%s:%d (%s)", r.Source, r.Line, r.Pattern)
+}
+
+// Parse reads gitignore-syntax rules from data. Patterns are relative to
+// base; source is recorded in every rule. Lines with invalid patterns are
+// skipped.
+func Parse(data []byte, source, base string) []Rule {
+	var rules []Rule
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		rule, ok := parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		rule.Source = source
+		rule.Line = line
+		rule.base = filepath.Clean(base)
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// LoadFile reads the ignore file at path with patterns relative to base. A
+// missing or unreadable file has no rules.
+func LoadFile(path, base string) []Rule {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	return Parse(data, path, base)
+}
+
+// parseLine parses one line of an ignore file; ok is false for blank lines,
+// comments and invalid patterns.
+func parseLine(text string) (Rule, bool) {
+	pattern := trimTrailingSpaces(strings.TrimSuffix(text, "\r"))
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return Rule{}, false //nolint:exhaustruct
+	}
+
+	rule := Rule{Pattern: pattern} //nolint:exhaustruct
+
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		rule.negate = true
+		pattern = pattern[1:]
+	case strings.HasPrefix(pattern, `\!`), strings.HasPrefix(pattern, `\#`):
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return Rule{}, false //nolint:exhaustruct
+	}
+
+	if strings.Contains(pattern, "/") {
+		rule.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	for seg := range strings.SplitSeq(pattern, "/") {
+		if seg == "" {
+			continue
+		}
+
+		if seg != "**" {
+			if _, err := path.Match(seg, ""); err != nil {
+				return Rule{}, false //nolint:exhaustruct
+			}
+		}
+
+		rule.segments = append(rule.segments, seg)
+	}
+
+	return rule, len(rule.segments) > 0
+}
+
+// trimTrailingSpaces removes trailing spaces that are not escaped with a
+// backslash.
+func trimTrailingSpaces(s string) string {
+	for strings.HasSuffix(s, " ") && !strings.HasSuffix(s, `\ `) {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
+
+// matches reports whether the rule's pattern matches p. Unanchored patterns
+// match the base name at any depth; callers only consult a directory's rules
+// for paths below it.
+func (r Rule) matches(p string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], filepath.Base(p))
+
+		return ok
+	}
+
+	rel, err := filepath.Rel(r.base, p)
+	if err != nil || rel == "." || !isBelow(p, r.base) {
+		return false
+	}
+
+	return matchSegments(r.segments, strings.Split(filepath.ToSlash(rel), "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where **
+// matches zero or more segments (one or more at the end of the pattern).
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return len(parts) > 0
+		}
+
+		for i := range len(parts) + 1 {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(pattern[0], parts[0])
+
+	return ok && matchSegments(pattern[1:], parts[1:])
+}
+
+// isBelow reports whether p is strictly inside dir.
+func isBelow(p, dir string) bool {
+	if dir == string(filepath.Separator) {
+		return p != dir && strings.HasPrefix(p, dir)
+	}
+
+	return strings.HasPrefix(p, dir+string(filepath.Separator))
+}
@@ -0,0 +1,8 @@
+// Package state manages clean-wizard's persistent state directory, which
+// holds data that must survive between runs, such as the checkpoint of an
+// interrupted clean run.
+//
+// Files are written atomically (temp file + rename) so that an interrupted
+// write never leaves a truncated file behind; a file that still fails to
+// parse is reported as a Corruption error so callers can discard it.
+package state
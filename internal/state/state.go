@@ -0,0 +1,132 @@
+package state
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+)
+
+// DirEnv overrides the state directory.
+const DirEnv = "CLEAN_WIZARD_STATE_DIR"
+
+const (
+	// DirPermission restricts the state directory to the current user.
+	DirPermission = 0o700
+	// FilePermission restricts state files to the current user.
+	FilePermission = 0o600
+)
+
+// Dir returns the state directory: $CLEAN_WIZARD_STATE_DIR if set, otherwise
+// $XDG_STATE_HOME/clean-wizard, otherwise ~/.local/state/clean-wizard.
+func Dir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "clean-wizard"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errorfamily.WrapInfrastructure(err, "state.no_home", "cannot determine state directory")
+	}
+
+	return filepath.Join(home, ".local", "state", "clean-wizard"), nil
+}
+
+// Path returns the path of the named file inside the state directory.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// WriteJSON atomically writes v as indented JSON to path, creating the
+// parent directory if needed.
+func WriteJSON(path string, v any) error {
+	data, err := json.Marshal(v, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+	if err != nil {
+		return fmt.Errorf("failed to encode state file %s: %w", path, err)
+	}
+
+	return WriteFile(path, data)
+}
+
+// WriteFile atomically writes data to path, creating the parent directory if
+// needed.
+func WriteFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	err := os.MkdirAll(dir, DirPermission)
+	if err != nil {
+		return fmt.Errorf("failed to create state directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) //nolint:errcheck // no-op after a successful rename
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmpPath, FilePermission)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", path, err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace state file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ReadJSON decodes the JSON file at path into v. A missing file is returned
+// as an error matching fs.ErrNotExist; an unparseable file as Corruption.
+func ReadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return errorfamily.WrapCorruption(err, "state.corrupt", "state file "+path+" is corrupt")
+	}
+
+	return nil
+}
+
+// Remove deletes the file at path; a missing file is not an error.
+func Remove(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove state file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	errorfamily "github.com/larsartmann/go-error-family"
+	"github.com/larsartmann/go-error-family/errorfamilytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestWriteReadJSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "sample.json")
+
+	require.NoError(t, WriteJSON(path, sample{Name: "a", Count: 2}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(FilePermission), info.Mode().Perm())
+
+	var got sample
+	require.NoError(t, ReadJSON(path, &got))
+	assert.Equal(t, sample{Name: "a", Count: 2}, got)
+
+	require.NoError(t, Remove(path))
+	require.NoError(t, Remove(path), "removing a missing file is not an error")
+}
+
+func TestReadJSON_MissingAndCorrupt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var got sample
+
+	err := ReadJSON(filepath.Join(dir, "missing.json"), &got)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte("{not json"), FilePermission))
+
+	err = ReadJSON(corrupt, &got)
+	errorfamilytest.AssertFamily(t, err, errorfamily.Corruption)
+}
+
+func TestDir_EnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DirEnv, dir)
+
+	got, err := Path("x.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "x.json"), got)
+}